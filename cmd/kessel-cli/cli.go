@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	v2 "github.com/project-kessel/kessel-sdk-go/kessel/rbac/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+type connectionOptions struct {
+	endpoint     string
+	insecure     bool
+	issuerUrl    string
+	clientId     string
+	clientSecret string
+}
+
+type dialFunc func(ctx context.Context, options connectionOptions) (v1beta2.KesselInventoryServiceClient, io.Closer, error)
+
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	dial   dialFunc
+}
+
+type command struct {
+	usage string
+	run   func(c *cli, ctx context.Context, client v1beta2.KesselInventoryServiceClient, flags *flag.FlagSet) error
+	flags func(flags *flag.FlagSet)
+}
+
+var commands = map[string]command{
+	"check": {
+		usage: "check a relation; reads a CheckRequest from stdin",
+		run: func(c *cli, ctx context.Context, client v1beta2.KesselInventoryServiceClient, _ *flag.FlagSet) error {
+			return unary(c, ctx, &v1beta2.CheckRequest{}, client.Check)
+		},
+	},
+	"check-bulk": {
+		usage: "check several relations; reads a CheckBulkRequest from stdin",
+		run: func(c *cli, ctx context.Context, client v1beta2.KesselInventoryServiceClient, _ *flag.FlagSet) error {
+			return unary(c, ctx, &v1beta2.CheckBulkRequest{}, client.CheckBulk)
+		},
+	},
+	"report": {
+		usage: "report a resource; reads a ReportResourceRequest from stdin",
+		run: func(c *cli, ctx context.Context, client v1beta2.KesselInventoryServiceClient, _ *flag.FlagSet) error {
+			return unary(c, ctx, &v1beta2.ReportResourceRequest{}, client.ReportResource)
+		},
+	},
+	"delete": {
+		usage: "delete a resource; reads a DeleteResourceRequest from stdin",
+		run: func(c *cli, ctx context.Context, client v1beta2.KesselInventoryServiceClient, _ *flag.FlagSet) error {
+			return unary(c, ctx, &v1beta2.DeleteResourceRequest{}, client.DeleteResource)
+		},
+	},
+	"list-workspaces": {
+		usage: "list the workspaces a principal has a relation to, one per line",
+		flags: func(flags *flag.FlagSet) {
+			flags.String("principal", "", "principal ID (required)")
+			flags.String("domain", "redhat", "principal domain")
+			flags.String("relation", v2.RelationMember, "relation to the workspaces")
+		},
+		run: listWorkspaces,
+	},
+}
+
+func (c *cli) run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		c.usage()
+		return exitUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		_, _ = fmt.Fprintf(c.stderr, "unknown command %q\n\n", args[0])
+		c.usage()
+		return exitUsage
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	var options connectionOptions
+	flags.StringVar(&options.endpoint, "endpoint", os.Getenv("KESSEL_ENDPOINT"), "Kessel Inventory gRPC endpoint")
+	flags.BoolVar(&options.insecure, "insecure", false, "use a plaintext, unauthenticated connection")
+	flags.StringVar(&options.issuerUrl, "issuer", os.Getenv("AUTH_DISCOVERY_ISSUER_URL"), "OIDC issuer URL for client credentials")
+	flags.StringVar(&options.clientId, "client-id", os.Getenv("AUTH_CLIENT_ID"), "OAuth2 client ID")
+	flags.StringVar(&options.clientSecret, "client-secret", os.Getenv("AUTH_CLIENT_SECRET"), "OAuth2 client secret")
+	if cmd.flags != nil {
+		cmd.flags(flags)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	client, closer, err := c.dial(ctx, options)
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "failed to connect: %v\n", err)
+		return exitError
+	}
+	defer func() { _ = closer.Close() }()
+
+	if err := cmd.run(c, ctx, client, flags); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "%s: %v\n", args[0], err)
+		return exitError
+	}
+	return exitOK
+}
+
+func (c *cli) usage() {
+	_, _ = fmt.Fprintln(c.stderr, "usage: kessel-cli <command> [flags]")
+	_, _ = fmt.Fprintln(c.stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(c.stderr, "  %-16s %s\n", name, commands[name].usage)
+	}
+	_, _ = fmt.Fprintln(c.stderr, "\nrun 'kessel-cli <command> -h' for flags")
+}
+
+// unary reads request from stdin, calls call and prints the response.
+func unary[Req proto.Message, Resp proto.Message](c *cli, ctx context.Context, request Req, call func(context.Context, Req, ...grpc.CallOption) (Resp, error)) error {
+	input, err := io.ReadAll(c.stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	if err := protojson.Unmarshal(input, request); err != nil {
+		return fmt.Errorf("failed to parse request: %w", err)
+	}
+
+	response, err := call(ctx, request)
+	if err != nil {
+		return err
+	}
+	return c.print(response, true)
+}
+
+func listWorkspaces(c *cli, ctx context.Context, client v1beta2.KesselInventoryServiceClient, flags *flag.FlagSet) error {
+	principal := flags.Lookup("principal").Value.String()
+	if principal == "" {
+		return errors.New("-principal is required")
+	}
+	subject := v2.PrincipalSubject(principal, flags.Lookup("domain").Value.String())
+
+	for response, err := range v2.ListWorkspaces(ctx, client, subject, flags.Lookup("relation").Value.String(), "") {
+		if err != nil {
+			return err
+		}
+		if err := c.print(response, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cli) print(message proto.Message, multiline bool) error {
+	output, err := protojson.MarshalOptions{Multiline: multiline}.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.stdout, string(output))
+	return err
+}
+
+func dial(ctx context.Context, options connectionOptions) (v1beta2.KesselInventoryServiceClient, io.Closer, error) {
+	if options.endpoint == "" {
+		return nil, nil, errors.New("-endpoint or KESSEL_ENDPOINT is required")
+	}
+
+	builder := v1beta2.NewClientBuilder(options.endpoint)
+	switch {
+	case options.insecure:
+		builder.Insecure().AllowInsecureAuth()
+	case options.clientId != "":
+		discovered, err := auth.FetchOIDCDiscovery(ctx, options.issuerUrl, auth.FetchOIDCDiscoveryOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		credentials := auth.NewOAuth2ClientCredentials(options.clientId, options.clientSecret, discovered.TokenEndpoint)
+		builder.OAuth2ClientAuthenticated(&credentials, nil)
+	default:
+		builder.Unauthenticated(nil)
+	}
+
+	client, conn, err := builder.Build()
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, conn, nil
+}
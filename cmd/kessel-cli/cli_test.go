@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type mockClient struct {
+	v1beta2.KesselInventoryServiceClient
+	checkRequest *v1beta2.CheckRequest
+	workspaces   []string
+}
+
+func (m *mockClient) Check(ctx context.Context, in *v1beta2.CheckRequest, opts ...grpc.CallOption) (*v1beta2.CheckResponse, error) {
+	m.checkRequest = in
+	return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}, nil
+}
+
+type mockStream struct {
+	grpc.ServerStreamingClient[v1beta2.StreamedListObjectsResponse]
+	responses []*v1beta2.StreamedListObjectsResponse
+}
+
+func (m *mockStream) Recv() (*v1beta2.StreamedListObjectsResponse, error) {
+	if len(m.responses) == 0 {
+		return nil, io.EOF
+	}
+	response := m.responses[0]
+	m.responses = m.responses[1:]
+	return response, nil
+}
+
+func (m *mockClient) StreamedListObjects(ctx context.Context, in *v1beta2.StreamedListObjectsRequest, opts ...grpc.CallOption) (v1beta2.KesselInventoryService_StreamedListObjectsClient, error) {
+	stream := &mockStream{}
+	for _, id := range m.workspaces {
+		stream.responses = append(stream.responses, &v1beta2.StreamedListObjectsResponse{
+			Object: &v1beta2.ResourceReference{ResourceType: "workspace", ResourceId: id},
+		})
+	}
+	return stream, nil
+}
+
+func newTestCLI(client *mockClient, stdin string) (*cli, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	return &cli{
+		stdin:  strings.NewReader(stdin),
+		stdout: &stdout,
+		stderr: &stderr,
+		dial: func(ctx context.Context, options connectionOptions) (v1beta2.KesselInventoryServiceClient, io.Closer, error) {
+			return client, io.NopCloser(nil), nil
+		},
+	}, &stdout, &stderr
+}
+
+func TestRun_check(t *testing.T) {
+	client := &mockClient{}
+	c, stdout, _ := newTestCLI(client, `{"object": {"resourceType": "host", "resourceId": "1"}, "relation": "view"}`)
+
+	code := c.run(context.Background(), []string{"check", "-endpoint", "localhost:9000"})
+
+	require.Equal(t, exitOK, code)
+	require.NotNil(t, client.checkRequest)
+	assert.Equal(t, "view", client.checkRequest.Relation)
+	assert.Equal(t, "1", client.checkRequest.Object.ResourceId)
+	assert.Contains(t, stdout.String(), "ALLOWED_TRUE")
+}
+
+func TestRun_errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		stdin        string
+		expectedCode int
+		expectedErr  string
+	}{
+		{name: "no command", args: nil, expectedCode: exitUsage, expectedErr: "usage"},
+		{name: "unknown command", args: []string{"nope"}, expectedCode: exitUsage, expectedErr: "unknown command"},
+		{name: "unknown flag", args: []string{"check", "-nope"}, expectedCode: exitUsage},
+		{name: "invalid payload", args: []string{"check"}, stdin: "{", expectedCode: exitError, expectedErr: "failed to parse request"},
+		{name: "missing principal", args: []string{"list-workspaces"}, expectedCode: exitError, expectedErr: "-principal is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, stderr := newTestCLI(&mockClient{}, tt.stdin)
+
+			code := c.run(context.Background(), tt.args)
+
+			assert.Equal(t, tt.expectedCode, code)
+			assert.Contains(t, stderr.String(), tt.expectedErr)
+		})
+	}
+}
+
+func TestRun_listWorkspaces(t *testing.T) {
+	c, stdout, _ := newTestCLI(&mockClient{workspaces: []string{"ws-1", "ws-2"}}, "")
+
+	code := c.run(context.Background(), []string{"list-workspaces", "-principal", "alice"})
+
+	require.Equal(t, exitOK, code)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "ws-1")
+	assert.Contains(t, lines[1], "ws-2")
+}
+
+func TestDial_requiresEndpoint(t *testing.T) {
+	_, _, err := dial(context.Background(), connectionOptions{})
+	assert.Error(t, err)
+}
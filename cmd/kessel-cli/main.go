@@ -0,0 +1,24 @@
+// Command kessel-cli makes ad-hoc Kessel Inventory calls for debugging and
+// end-to-end verification. Request payloads are read as protojson from stdin
+// and responses are written as protojson to stdout.
+//
+//	echo '{"object": {...}, "relation": "view", "subject": {...}}' | kessel-cli check -endpoint localhost:9000 -insecure
+//	kessel-cli list-workspaces -principal alice -relation view_widget
+//
+// Connection flags default to the KESSEL_ENDPOINT, AUTH_DISCOVERY_ISSUER_URL,
+// AUTH_CLIENT_ID and AUTH_CLIENT_SECRET environment variables.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, dial: dial}
+	os.Exit(c.run(ctx, os.Args[1:]))
+}
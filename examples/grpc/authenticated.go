@@ -7,7 +7,7 @@ import (
 	"os"
 
 	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
-	kesselgrpc "github.com/project-kessel/kessel-sdk-go/kessel/grpc"
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth/grpccreds"
 	"github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -27,7 +27,7 @@ func authenticated() {
 	oauthCredentials := auth.NewOAuth2ClientCredentials(os.Getenv("AUTH_CLIENT_ID"), os.Getenv("AUTH_CLIENT_SECRET"), discovered.TokenEndpoint)
 
 	inventoryClient, conn, err := v1beta2.NewClientBuilder(os.Getenv("KESSEL_ENDPOINT")).
-		Authenticated(kesselgrpc.OAuth2CallCredentials(&oauthCredentials), nil).
+		Authenticated(grpccreds.NewOAuth2(&oauthCredentials), nil).
 		Build()
 	if err != nil {
 		log.Fatal("Failed to create gRPC client:", err)
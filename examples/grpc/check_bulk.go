@@ -29,14 +29,14 @@ func checkBulk() {
 	// Item 1: Check if bob can view widgets in workspace_123
 	item1 := &v1beta2.CheckBulkRequestItem{
 		Object:   v2.WorkspaceResource("workspace_123"),
-		Relation: "view_widget",
+		Relation: v2.RelationViewWidget,
 		Subject:  v2.PrincipalSubject("bob", "redhat"),
 	}
 
 	// Item 2: Check if bob can use widgets in workspace_456
 	item2 := &v1beta2.CheckBulkRequestItem{
 		Object:   v2.WorkspaceResource("workspace_456"),
-		Relation: "use_widget",
+		Relation: v2.RelationUseWidget,
 		Subject:  v2.PrincipalSubject("bob", "redhat"),
 	}
 
@@ -49,7 +49,7 @@ func checkBulk() {
 				Type: "rbac",
 			},
 		},
-		Relation: "view_widget",
+		Relation: v2.RelationViewWidget,
 		Subject:  v2.PrincipalSubject("alice", "redhat"),
 	}
 
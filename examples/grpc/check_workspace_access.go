@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	v2 "github.com/project-kessel/kessel-sdk-go/kessel/rbac/v2"
+)
+
+func checkWorkspaceAccess() {
+	ctx := context.Background()
+	inventoryClient, conn, err := v1beta2.NewClientBuilder(os.Getenv("KESSEL_ENDPOINT")).
+		Insecure().
+		Build()
+	if err != nil {
+		log.Fatal("Failed to create gRPC client:", err)
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			log.Printf("Failed to close gRPC client: %v", closeErr)
+		}
+	}()
+
+	// Check if bob can view widgets in workspace_123
+	allowed, token, err := v2.CheckWorkspaceAccess(ctx, inventoryClient, "bob", "redhat", v2.RelationViewWidget, "workspace_123")
+	if err != nil {
+		log.Fatal("Check failed: ", err)
+	}
+	fmt.Printf("bob can view widgets in workspace_123: %t (consistency token %q)\n", allowed, token.GetToken())
+
+	// Check for a write with the latest state
+	allowed, _, err = v2.CheckWorkspaceAccessForUpdate(ctx, inventoryClient, "bob", "redhat", v2.RelationUseWidget, "workspace_123")
+	if err != nil {
+		log.Fatal("CheckForUpdate failed: ", err)
+	}
+	fmt.Printf("bob can use widgets in workspace_123: %t\n", allowed)
+}
+
+func main() { checkWorkspaceAccess() }
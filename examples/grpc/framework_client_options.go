@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	_ "github.com/joho/godotenv/autoload"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth/grpccreds"
+)
+
+// frameworkClientOptions shows the options to hand to a framework that builds
+// its own clients, e.g. Kratos:
+//
+//	kratosgrpc.Dial(ctx, kratosgrpc.WithEndpoint(endpoint), kratosgrpc.WithOptions(tokenOption))
+//	kratoshttp.NewClient(ctx, kratoshttp.WithTransport(httpClient.Transport))
+//
+// Both fetch a cached token for every request, so no per-call header options
+// are needed.
+func frameworkClientOptions() {
+	ctx := context.Background()
+	discovered, err := auth.FetchOIDCDiscovery(ctx, os.Getenv("AUTH_DISCOVERY_ISSUER_URL"), auth.FetchOIDCDiscoveryOptions{})
+	if err != nil {
+		panic(err)
+	}
+
+	oauthCredentials := auth.NewOAuth2ClientCredentials(os.Getenv("AUTH_CLIENT_ID"), os.Getenv("AUTH_CLIENT_SECRET"), discovered.TokenEndpoint)
+	tokenOption := grpccreds.DialOption(&oauthCredentials)
+	httpClient := &http.Client{Transport: auth.NewAuthenticatedTransport(&oauthCredentials, nil)}
+	fmt.Printf("HTTP client with token transport: %T\n", httpClient.Transport)
+
+	conn, err := grpc.NewClient(os.Getenv("KESSEL_ENDPOINT"),
+		grpc.WithTransportCredentials(credentials.NewTLS(nil)),
+		tokenOption)
+	if err != nil {
+		log.Fatal("Failed to create gRPC client:", err)
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			log.Printf("Failed to close gRPC client: %v", closeErr)
+		}
+	}()
+	inventoryClient := v1beta2.NewKesselInventoryServiceClient(conn)
+
+	checkRequest := &v1beta2.CheckRequest{
+		Object: &v1beta2.ResourceReference{
+			ResourceType: "host",
+			ResourceId:   "1213",
+			Reporter: &v1beta2.ReporterReference{
+				Type: "hbi",
+			},
+		},
+		Relation: "view",
+		Subject: &v1beta2.SubjectReference{
+			Resource: &v1beta2.ResourceReference{
+				ResourceType: "principal",
+				ResourceId:   "redhat/tim",
+				Reporter: &v1beta2.ReporterReference{
+					Type: "rbac",
+				},
+			},
+		},
+	}
+
+	response, err := inventoryClient.Check(ctx, checkRequest)
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			switch st.Code() {
+			case codes.Unavailable:
+				log.Fatal("Service unavailable: ", err)
+			case codes.PermissionDenied:
+				log.Fatal("Permission denied: ", err)
+			default:
+				log.Fatal("gRPC connection error: ", err)
+			}
+		} else {
+			log.Fatal("Unknown error: ", err)
+		}
+	}
+	fmt.Printf("Check response: %+v\n", response)
+}
+
+func main() { frameworkClientOptions() }
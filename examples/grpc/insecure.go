@@ -13,8 +13,11 @@ import (
 
 func insecure() {
 	ctx := context.Background()
+	// Without credentials, only allow the read-only Check call. Endpoints
+	// other than localhost are rejected unless AllowInsecureAuth() is added.
 	inventoryClient, conn, err := v1beta2.NewClientBuilder(os.Getenv("KESSEL_ENDPOINT")).
 		Insecure().
+		WithUnauthenticatedMethods(v1beta2.KesselInventoryService_Check_FullMethodName).
 		Build()
 	if err != nil {
 		log.Fatal("Failed to create gRPC client:", err)
@@ -6,10 +6,9 @@ import (
 	"log"
 	"os"
 
-	"google.golang.org/protobuf/types/known/structpb"
-
 	_ "github.com/joho/godotenv/autoload"
 
+	"github.com/project-kessel/kessel-sdk-go/kessel/inventory"
 	"github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -30,31 +29,32 @@ func reportResource() {
 		}
 	}()
 
+	representations, err := inventory.NewRepresentations(
+		&v1beta2.RepresentationMetadata{
+			LocalResourceId: "854589f0-3be7-4cad-8bcd-45e18f33cb81",
+			ApiHref:         "https://apiHref.com/",
+			ConsoleHref:     addr("https://www.consoleHref.com/"),
+			ReporterVersion: addr("0.2.11"),
+		},
+		inventory.HostCommon{
+			WorkspaceId: "6eb10953-4ec9-4feb-838f-ba43a60880bf",
+		},
+		inventory.HostReporter{
+			SatelliteId:         "ca234d8f-9861-4659-a033-e80460b2801c",
+			SubManagerId:        "e9b7d65f-3f81-4c26-b86c-2db663376eed",
+			InsightsInventoryId: "c4b9b5e7-a82a-467a-b382-024a2f18c129",
+			AnsibleHost:         "host-1",
+		},
+	)
+	if err != nil {
+		log.Fatal("Failed to build representations:", err)
+	}
+
 	reportResourceRequest := &v1beta2.ReportResourceRequest{
 		Type:               "host",
 		ReporterType:       "hbi",
 		ReporterInstanceId: "0a2a430e-1ad9-4304-8e75-cc6fd3b5441a",
-		Representations: &v1beta2.ResourceRepresentations{
-			Metadata: &v1beta2.RepresentationMetadata{
-				LocalResourceId: "854589f0-3be7-4cad-8bcd-45e18f33cb81",
-				ApiHref:         "https://apiHref.com/",
-				ConsoleHref:     addr("https://www.consoleHref.com/"),
-				ReporterVersion: addr("0.2.11"),
-			},
-			Common: &structpb.Struct{
-				Fields: map[string]*structpb.Value{
-					"workspace_id": structpb.NewStringValue("6eb10953-4ec9-4feb-838f-ba43a60880bf"),
-				},
-			},
-			Reporter: &structpb.Struct{
-				Fields: map[string]*structpb.Value{
-					"satellite_id":          structpb.NewStringValue("ca234d8f-9861-4659-a033-e80460b2801c"),
-					"sub_manager_id":        structpb.NewStringValue("e9b7d65f-3f81-4c26-b86c-2db663376eed"),
-					"insights_inventory_id": structpb.NewStringValue("c4b9b5e7-a82a-467a-b382-024a2f18c129"),
-					"ansible_host":          structpb.NewStringValue("host-1"),
-				},
-			},
-		},
+		Representations:    representations,
 	}
 
 	fmt.Println("Making report resource request:")
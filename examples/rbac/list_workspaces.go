@@ -8,7 +8,7 @@ import (
 
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
-	kesselgrpc "github.com/project-kessel/kessel-sdk-go/kessel/grpc"
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth/grpccreds"
 	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
 	v2 "github.com/project-kessel/kessel-sdk-go/kessel/rbac/v2"
 )
@@ -27,7 +27,7 @@ func listWorkspaces() {
 
 	// Setup client
 	inventoryClient, conn, err := v1beta2.NewClientBuilder(os.Getenv("KESSEL_ENDPOINT")).
-		Authenticated(kesselgrpc.OAuth2CallCredentials(&oauthCredentials), nil).
+		Authenticated(grpccreds.NewOAuth2(&oauthCredentials), nil).
 		Build()
 	if err != nil {
 		log.Fatal("Failed to create gRPC client:", err)
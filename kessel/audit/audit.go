@@ -0,0 +1,197 @@
+// Package audit emits a structured record of every authorization decision
+// returned by the Kessel Inventory API, for consumers that must keep an audit
+// trail of access checks.
+//
+// Add the interceptor to a client and choose where records go:
+//
+//	client, conn, err := v1beta2.NewClientBuilder(target).
+//		WithRequestIds().
+//		WithUnaryInterceptor(audit.UnaryClientInterceptor(audit.LoggerSink(logger))).
+//		Build()
+//
+// Check, CheckForUpdate and CheckSelf produce one record per call; their bulk
+// variants produce one record per item. A call that fails produces one record
+// per item with DecisionError.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/callmetadata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Decision is the outcome of one authorization check.
+type Decision string
+
+const (
+	DecisionAllowed     Decision = "allowed"
+	DecisionDenied      Decision = "denied"
+	DecisionUnspecified Decision = "unspecified"
+	DecisionError       Decision = "error"
+)
+
+// Record describes one authorization decision.
+type Record struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	// RequestId is the call's request ID, set when the client attaches one
+	// (see WithRequestIds on the client builder).
+	RequestId string `json:"request_id,omitempty"`
+	// Subject and Object are formatted as reporter/type:id, and Subject has a
+	// #relation suffix for subject sets. Subject is empty for CheckSelf calls,
+	// whose subject is the authenticated caller.
+	Subject  string   `json:"subject,omitempty"`
+	Relation string   `json:"relation"`
+	Object   string   `json:"object"`
+	Decision Decision `json:"decision"`
+	// Error is the failure message when Decision is DecisionError.
+	Error string `json:"error,omitempty"`
+	// Latency is the duration of the whole call, shared by every item of a
+	// bulk call.
+	Latency time.Duration `json:"latency"`
+	// Consistency is the requested consistency: minimize_latency,
+	// at_least_as_fresh or at_least_as_acknowledged, or empty when the
+	// server default applies.
+	Consistency      string `json:"consistency,omitempty"`
+	ConsistencyToken string `json:"consistency_token,omitempty"`
+}
+
+// Sink receives audit records. Emit is called synchronously on the calling
+// goroutine once the call completes, so it should not block for long.
+type Sink interface {
+	Emit(ctx context.Context, record Record)
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(ctx context.Context, record Record)
+
+func (f SinkFunc) Emit(ctx context.Context, record Record) {
+	f(ctx, record)
+}
+
+// MultiSink returns a Sink that emits every record to each of sinks in order.
+func MultiSink(sinks ...Sink) Sink {
+	return SinkFunc(func(ctx context.Context, record Record) {
+		for _, sink := range sinks {
+			sink.Emit(ctx, record)
+		}
+	})
+}
+
+// LoggerSink returns a Sink that logs every record at info level to logger
+// (slog.Default() when nil).
+func LoggerSink(logger *slog.Logger) Sink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return SinkFunc(func(ctx context.Context, record Record) {
+		attrs := []slog.Attr{
+			slog.String("method", record.Method),
+			slog.String("subject", record.Subject),
+			slog.String("relation", record.Relation),
+			slog.String("object", record.Object),
+			slog.String("decision", string(record.Decision)),
+			slog.Duration("latency", record.Latency),
+		}
+		if record.RequestId != "" {
+			attrs = append(attrs, slog.String("request_id", record.RequestId))
+		}
+		if record.Consistency != "" {
+			attrs = append(attrs, slog.String("consistency", record.Consistency))
+		}
+		if record.ConsistencyToken != "" {
+			attrs = append(attrs, slog.String("consistency_token", record.ConsistencyToken))
+		}
+		if record.Error != "" {
+			attrs = append(attrs, slog.String("error", record.Error))
+		}
+		logger.LogAttrs(ctx, slog.LevelInfo, "kessel authorization decision", attrs...)
+	})
+}
+
+// ChannelSink returns a Sink that sends every record to records without
+// blocking. Records are dropped while the channel is full, so give it a buffer
+// sized for the consumer's latency.
+func ChannelSink(records chan<- Record) Sink {
+	return SinkFunc(func(ctx context.Context, record Record) {
+		select {
+		case records <- record:
+		default:
+		}
+	})
+}
+
+// Producer publishes a message to a topic, such as a Kafka producer.
+type Producer interface {
+	Produce(ctx context.Context, topic string, key []byte, value []byte) error
+}
+
+// ProducerSink returns a Sink that publishes every record to topic as JSON,
+// keyed by request ID. Publishing errors are passed to onError, or ignored
+// when onError is nil.
+func ProducerSink(producer Producer, topic string, onError func(Record, error)) Sink {
+	return SinkFunc(func(ctx context.Context, record Record) {
+		value, err := json.Marshal(record)
+		if err == nil {
+			err = producer.Produce(ctx, topic, []byte(record.RequestId), value)
+		}
+		if err != nil && onError != nil {
+			onError(record, err)
+		}
+	})
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that emits a Record to
+// sink for every decision of the Inventory API's check methods. Other methods
+// pass through untouched. Sinks get a context that is not canceled with the
+// call.
+func UnaryClientInterceptor(sink Sink) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		checks, ok := requestChecks(method, req)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		latency := time.Since(start)
+
+		base := Record{
+			Time:      start,
+			Method:    method,
+			RequestId: callmetadata.RequestId(ctx),
+			Latency:   latency,
+		}
+		if err == nil {
+			base.ConsistencyToken = responseToken(reply)
+		}
+		results := responseResults(reply, len(checks))
+
+		emitCtx := context.WithoutCancel(ctx)
+		for i, check := range checks {
+			record := base
+			record.Subject = check.subject
+			record.Relation = check.relation
+			record.Object = check.object
+			record.Consistency = check.consistency
+			switch {
+			case err != nil:
+				record.Decision = DecisionError
+				record.Error = status.Convert(err).Message()
+			case results == nil:
+				record.Decision = DecisionError
+				record.Error = "no result for item"
+			default:
+				record.Decision = results[i].decision
+				record.Error = results[i].error
+			}
+			sink.Emit(emitCtx, record)
+		}
+		return err
+	}
+}
@@ -0,0 +1,233 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/callmetadata"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+func workspace(id string) *v1beta2.ResourceReference {
+	return &v1beta2.ResourceReference{ResourceType: "workspace", ResourceId: id, Reporter: &v1beta2.ReporterReference{Type: "rbac"}}
+}
+
+func principal(id string) *v1beta2.SubjectReference {
+	return &v1beta2.SubjectReference{Resource: &v1beta2.ResourceReference{ResourceType: "principal", ResourceId: id, Reporter: &v1beta2.ReporterReference{Type: "rbac"}}}
+}
+
+// collect returns a Sink appending to records.
+func collect(records *[]Record) Sink {
+	return SinkFunc(func(ctx context.Context, record Record) {
+		*records = append(*records, record)
+	})
+}
+
+// replying returns an invoker that fills reply with response or fails with err.
+func replying(response proto.Message, err error) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if err != nil {
+			return err
+		}
+		proto.Merge(reply.(proto.Message), response)
+		return nil
+	}
+}
+
+func TestUnaryClientInterceptor_check(t *testing.T) {
+	var records []Record
+	interceptor := UnaryClientInterceptor(collect(&records))
+	ctx := callmetadata.WithRequestId(context.Background(), "req-1")
+	request := &v1beta2.CheckRequest{
+		Object:      workspace("w1"),
+		Relation:    "view",
+		Subject:     principal("alice"),
+		Consistency: &v1beta2.Consistency{Requirement: &v1beta2.Consistency_MinimizeLatency{MinimizeLatency: true}},
+	}
+	response := &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE, ConsistencyToken: &v1beta2.ConsistencyToken{Token: "tok"}}
+
+	err := interceptor(ctx, v1beta2.KesselInventoryService_Check_FullMethodName, request, &v1beta2.CheckResponse{}, nil, replying(response, nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if record.Subject != "rbac/principal:alice" || record.Relation != "view" || record.Object != "rbac/workspace:w1" {
+		t.Errorf("Unexpected tuple: %+v", record)
+	}
+	if record.Decision != DecisionAllowed {
+		t.Errorf("Expected allowed, got %s", record.Decision)
+	}
+	if record.RequestId != "req-1" {
+		t.Errorf("Expected request ID req-1, got %q", record.RequestId)
+	}
+	if record.Consistency != "minimize_latency" || record.ConsistencyToken != "tok" {
+		t.Errorf("Unexpected consistency %q, token %q", record.Consistency, record.ConsistencyToken)
+	}
+	if record.Method != v1beta2.KesselInventoryService_Check_FullMethodName || record.Time.IsZero() {
+		t.Errorf("Unexpected method %q or time %v", record.Method, record.Time)
+	}
+}
+
+func TestUnaryClientInterceptor_checkBulk(t *testing.T) {
+	var records []Record
+	interceptor := UnaryClientInterceptor(collect(&records))
+	subjectSet := principal("g1")
+	subjectSet.Relation = proto.String("member")
+	items := []*v1beta2.CheckBulkRequestItem{
+		{Object: workspace("w1"), Relation: "view", Subject: principal("alice")},
+		{Object: workspace("w2"), Relation: "edit", Subject: subjectSet},
+		{Object: workspace("w3"), Relation: "view", Subject: principal("bob")},
+	}
+	response := &v1beta2.CheckBulkResponse{Pairs: []*v1beta2.CheckBulkResponsePair{
+		{Request: items[0], Response: &v1beta2.CheckBulkResponsePair_Item{Item: &v1beta2.CheckBulkResponseItem{Allowed: v1beta2.Allowed_ALLOWED_TRUE}}},
+		{Request: items[1], Response: &v1beta2.CheckBulkResponsePair_Item{Item: &v1beta2.CheckBulkResponseItem{Allowed: v1beta2.Allowed_ALLOWED_FALSE}}},
+		{Request: items[2], Response: &v1beta2.CheckBulkResponsePair_Error{Error: &status.Status{Code: int32(codes.NotFound), Message: "no such subject"}}},
+	}}
+
+	err := interceptor(context.Background(), v1beta2.KesselInventoryService_CheckBulk_FullMethodName, &v1beta2.CheckBulkRequest{Items: items}, &v1beta2.CheckBulkResponse{}, nil, replying(response, nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		subject  string
+		decision Decision
+		error    string
+	}{
+		{"rbac/principal:alice", DecisionAllowed, ""},
+		{"rbac/principal:g1#member", DecisionDenied, ""},
+		{"rbac/principal:bob", DecisionError, "no such subject"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records, got %d", len(expected), len(records))
+	}
+	for i, want := range expected {
+		if records[i].Subject != want.subject || records[i].Decision != want.decision || records[i].Error != want.error {
+			t.Errorf("Record %d: expected %+v, got %+v", i, want, records[i])
+		}
+	}
+}
+
+func TestUnaryClientInterceptor_error(t *testing.T) {
+	var records []Record
+	interceptor := UnaryClientInterceptor(collect(&records))
+	request := &v1beta2.CheckSelfBulkRequest{Items: []*v1beta2.CheckSelfBulkRequestItem{
+		{Object: workspace("w1"), Relation: "view"},
+		{Object: workspace("w2"), Relation: "view"},
+	}}
+	callErr := grpcstatus.Error(codes.Unavailable, "down")
+
+	err := interceptor(context.Background(), v1beta2.KesselInventoryService_CheckSelfBulk_FullMethodName, request, &v1beta2.CheckSelfBulkResponse{}, nil, replying(nil, callErr))
+	if !errors.Is(err, callErr) {
+		t.Fatalf("Expected call error, got %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	for _, record := range records {
+		if record.Decision != DecisionError || record.Error != "down" || record.Subject != "" {
+			t.Errorf("Unexpected record: %+v", record)
+		}
+	}
+}
+
+func TestUnaryClientInterceptor_otherMethods(t *testing.T) {
+	var records []Record
+	interceptor := UnaryClientInterceptor(collect(&records))
+
+	err := interceptor(context.Background(), v1beta2.KesselInventoryService_ReportResource_FullMethodName, &v1beta2.ReportResourceRequest{}, &v1beta2.ReportResourceResponse{}, nil, replying(&v1beta2.ReportResourceResponse{}, nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("Expected no records, got %d", len(records))
+	}
+}
+
+func TestLoggerSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := LoggerSink(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	sink.Emit(context.Background(), Record{Subject: "rbac/principal:alice", Relation: "view", Object: "rbac/workspace:w1", Decision: DecisionDenied, RequestId: "req-1"})
+
+	for _, want := range []string{`"decision":"denied"`, `"request_id":"req-1"`, `"subject":"rbac/principal:alice"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %s in %s", want, buf.String())
+		}
+	}
+}
+
+func TestChannelSink(t *testing.T) {
+	records := make(chan Record, 1)
+	sink := ChannelSink(records)
+
+	sink.Emit(context.Background(), Record{Object: "first"})
+	sink.Emit(context.Background(), Record{Object: "dropped"})
+
+	if got := (<-records).Object; got != "first" {
+		t.Errorf("Expected first record, got %s", got)
+	}
+	if len(records) != 0 {
+		t.Error("Expected record to be dropped while the channel was full")
+	}
+}
+
+type fakeProducer struct {
+	topic string
+	key   string
+	value []byte
+	err   error
+}
+
+func (p *fakeProducer) Produce(ctx context.Context, topic string, key []byte, value []byte) error {
+	p.topic, p.key, p.value = topic, string(key), value
+	return p.err
+}
+
+func TestProducerSink(t *testing.T) {
+	producer := &fakeProducer{}
+	ProducerSink(producer, "audit", nil).Emit(context.Background(), Record{RequestId: "req-1", Decision: DecisionAllowed})
+
+	if producer.topic != "audit" || producer.key != "req-1" {
+		t.Errorf("Unexpected topic %q or key %q", producer.topic, producer.key)
+	}
+	var record Record
+	if err := json.Unmarshal(producer.value, &record); err != nil {
+		t.Fatalf("Failed to decode record: %v", err)
+	}
+	if record.Decision != DecisionAllowed {
+		t.Errorf("Expected allowed, got %s", record.Decision)
+	}
+
+	produceErr := errors.New("broker down")
+	var reported error
+	ProducerSink(&fakeProducer{err: produceErr}, "audit", func(record Record, err error) {
+		reported = err
+	}).Emit(context.Background(), Record{})
+	if !errors.Is(reported, produceErr) {
+		t.Errorf("Expected produce error to be reported, got %v", reported)
+	}
+}
+
+func TestMultiSink(t *testing.T) {
+	var first, second []Record
+	MultiSink(collect(&first), collect(&second)).Emit(context.Background(), Record{})
+
+	if len(first) != 1 || len(second) != 1 {
+		t.Errorf("Expected each sink to get the record, got %d and %d", len(first), len(second))
+	}
+}
@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+)
+
+type check struct {
+	subject     string
+	relation    string
+	object      string
+	consistency string
+}
+
+type result struct {
+	decision Decision
+	error    string
+}
+
+// requestChecks returns the checks made by req, or false when method is not
+// an authorization check.
+func requestChecks(method string, req any) ([]check, bool) {
+	switch method {
+	case v1beta2.KesselInventoryService_Check_FullMethodName:
+		r, ok := req.(*v1beta2.CheckRequest)
+		if !ok {
+			return nil, false
+		}
+		return []check{{
+			subject:     formatSubject(r.GetSubject()),
+			relation:    r.GetRelation(),
+			object:      formatObject(r.GetObject()),
+			consistency: formatConsistency(r.GetConsistency()),
+		}}, true
+	case v1beta2.KesselInventoryService_CheckForUpdate_FullMethodName:
+		r, ok := req.(*v1beta2.CheckForUpdateRequest)
+		if !ok {
+			return nil, false
+		}
+		return []check{{
+			subject:  formatSubject(r.GetSubject()),
+			relation: r.GetRelation(),
+			object:   formatObject(r.GetObject()),
+		}}, true
+	case v1beta2.KesselInventoryService_CheckSelf_FullMethodName:
+		r, ok := req.(*v1beta2.CheckSelfRequest)
+		if !ok {
+			return nil, false
+		}
+		return []check{{
+			relation:    r.GetRelation(),
+			object:      formatObject(r.GetObject()),
+			consistency: formatConsistency(r.GetConsistency()),
+		}}, true
+	case v1beta2.KesselInventoryService_CheckBulk_FullMethodName:
+		r, ok := req.(*v1beta2.CheckBulkRequest)
+		if !ok {
+			return nil, false
+		}
+		return bulkChecks(r.GetItems(), formatConsistency(r.GetConsistency())), true
+	case v1beta2.KesselInventoryService_CheckForUpdateBulk_FullMethodName:
+		r, ok := req.(*v1beta2.CheckForUpdateBulkRequest)
+		if !ok {
+			return nil, false
+		}
+		return bulkChecks(r.GetItems(), ""), true
+	case v1beta2.KesselInventoryService_CheckSelfBulk_FullMethodName:
+		r, ok := req.(*v1beta2.CheckSelfBulkRequest)
+		if !ok {
+			return nil, false
+		}
+		consistency := formatConsistency(r.GetConsistency())
+		checks := make([]check, len(r.GetItems()))
+		for i, item := range r.GetItems() {
+			checks[i] = check{
+				relation:    item.GetRelation(),
+				object:      formatObject(item.GetObject()),
+				consistency: consistency,
+			}
+		}
+		return checks, true
+	}
+	return nil, false
+}
+
+func bulkChecks(items []*v1beta2.CheckBulkRequestItem, consistency string) []check {
+	checks := make([]check, len(items))
+	for i, item := range items {
+		checks[i] = check{
+			subject:     formatSubject(item.GetSubject()),
+			relation:    item.GetRelation(),
+			object:      formatObject(item.GetObject()),
+			consistency: consistency,
+		}
+	}
+	return checks
+}
+
+// responseResults returns the n decisions in reply, or nil when reply does not
+// hold exactly n.
+func responseResults(reply any, n int) []result {
+	var results []result
+	switch r := reply.(type) {
+	case *v1beta2.CheckResponse:
+		results = []result{{decision: decision(r.GetAllowed())}}
+	case *v1beta2.CheckForUpdateResponse:
+		results = []result{{decision: decision(r.GetAllowed())}}
+	case *v1beta2.CheckSelfResponse:
+		results = []result{{decision: decision(r.GetAllowed())}}
+	case *v1beta2.CheckBulkResponse:
+		for _, pair := range r.GetPairs() {
+			results = append(results, pairResult(pair.GetItem().GetAllowed(), pair.GetError()))
+		}
+	case *v1beta2.CheckForUpdateBulkResponse:
+		for _, pair := range r.GetPairs() {
+			results = append(results, pairResult(pair.GetItem().GetAllowed(), pair.GetError()))
+		}
+	case *v1beta2.CheckSelfBulkResponse:
+		for _, pair := range r.GetPairs() {
+			results = append(results, pairResult(pair.GetItem().GetAllowed(), pair.GetError()))
+		}
+	}
+	if len(results) != n {
+		return nil
+	}
+	return results
+}
+
+func responseToken(reply any) string {
+	if r, ok := reply.(interface {
+		GetConsistencyToken() *v1beta2.ConsistencyToken
+	}); ok {
+		return r.GetConsistencyToken().GetToken()
+	}
+	return ""
+}
+
+func pairResult(allowed v1beta2.Allowed, err *rpcstatus.Status) result {
+	if err != nil {
+		return result{decision: DecisionError, error: err.GetMessage()}
+	}
+	return result{decision: decision(allowed)}
+}
+
+func decision(allowed v1beta2.Allowed) Decision {
+	switch allowed {
+	case v1beta2.Allowed_ALLOWED_TRUE:
+		return DecisionAllowed
+	case v1beta2.Allowed_ALLOWED_FALSE:
+		return DecisionDenied
+	}
+	return DecisionUnspecified
+}
+
+func formatObject(object *v1beta2.ResourceReference) string {
+	if object == nil {
+		return ""
+	}
+	formatted := object.GetResourceType() + ":" + object.GetResourceId()
+	if reporter := object.GetReporter().GetType(); reporter != "" {
+		formatted = reporter + "/" + formatted
+	}
+	return formatted
+}
+
+func formatSubject(subject *v1beta2.SubjectReference) string {
+	formatted := formatObject(subject.GetResource())
+	if relation := subject.GetRelation(); relation != "" {
+		formatted += "#" + relation
+	}
+	return formatted
+}
+
+func formatConsistency(consistency *v1beta2.Consistency) string {
+	switch consistency.GetRequirement().(type) {
+	case *v1beta2.Consistency_MinimizeLatency:
+		return "minimize_latency"
+	case *v1beta2.Consistency_AtLeastAsFresh:
+		return "at_least_as_fresh"
+	case *v1beta2.Consistency_AtLeastAsAcknowledged:
+		return "at_least_as_acknowledged"
+	}
+	return ""
+}
@@ -2,17 +2,27 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
+	"maps"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/project-kessel/kessel-sdk-go/kessel/debuglog"
 	"github.com/zitadel/oidc/v3/pkg/client"
+	httphelper "github.com/zitadel/oidc/v3/pkg/http"
 )
 
 const expirationWindow = 300  // 5 minutes in second
 const defaultExpiresIn = 3600 // 1 hour in seconds
 
+// defaultTokenFetchTimeout bounds token requests made with contexts that have
+// no earlier deadline.
+const defaultTokenFetchTimeout = 30 * time.Second
+
 type OIDCDiscoveryMetadata struct {
 	TokenEndpoint string
 }
@@ -29,18 +39,98 @@ type OAuth2ClientCredentials struct {
 	cachedToken   RefreshTokenResponse
 	tokenMutex    sync.RWMutex
 	generation    uint64
+	failureStreak int
+	hooks         refreshHooks
+	parameters    tokenParameters
+}
+
+// CredentialsOption configures optional parameters of the token request made
+// by OAuth2ClientCredentials.
+type CredentialsOption func(*tokenParameters)
+
+type tokenParameters struct {
+	scopes       []string
+	audience     string
+	extra        url.Values
+	assertion    func(ctx context.Context) (string, error)
+	fetchTimeout *time.Duration
+	retry        *TokenRetryPolicy
+}
+
+// WithScopes requests the given scopes, sent space-separated as "scope".
+func WithScopes(scopes ...string) CredentialsOption {
+	return func(p *tokenParameters) {
+		p.scopes = scopes
+	}
+}
+
+// WithAudience sets the "audience" parameter required by some identity
+// providers.
+func WithAudience(audience string) CredentialsOption {
+	return func(p *tokenParameters) {
+		p.audience = audience
+	}
+}
+
+// WithTokenParameter adds an arbitrary form parameter to the token request,
+// e.g. "resource" for Azure AD. It cannot replace client_id, client_secret or
+// grant_type.
+func WithTokenParameter(key string, value string) CredentialsOption {
+	return func(p *tokenParameters) {
+		if p.extra == nil {
+			p.extra = url.Values{}
+		}
+		p.extra.Add(key, value)
+	}
+}
+
+// WithTokenFetchTimeout bounds every token request, including fetching a
+// client assertion, to timeout (30 seconds by default) so a hung identity
+// provider cannot stall calls whose context has no deadline. The request
+// context still applies, so an earlier RPC deadline wins. A timeout of zero or
+// less leaves token requests bounded only by the context.
+func WithTokenFetchTimeout(timeout time.Duration) CredentialsOption {
+	return func(p *tokenParameters) {
+		p.fetchTimeout = &timeout
+	}
+}
+
+// fetchContext derives the context for one token request from ctx.
+func (p tokenParameters) fetchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := defaultTokenFetchTimeout
+	if p.fetchTimeout != nil {
+		timeout = *p.fetchTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
 }
 
 type FetchOIDCDiscoveryOptions struct {
 	// Optionally specify an http.Client or use http.DefaultClient
 	HttpClient *http.Client
+	// Optionally cache documents by issuer URL (see NewDiscoveryCache)
+	Cache *DiscoveryCache
+	// Optionally override the path of the discovery document, appended to the
+	// issuer URL; defaults to DefaultWellKnownPath
+	WellKnownPath string
 }
 
+// DefaultWellKnownPath is the OpenID Connect discovery document path.
+const DefaultWellKnownPath = "/.well-known/openid-configuration"
+
 type GetTokenOptions struct {
 	// Whether the token should be refreshed regardless if it is expired or not
 	ForceRefresh bool
 	// Optionally specify an http.Client or use http.DefaultClient
 	HttpClient *http.Client
+	// Optionally override the scopes, audience or extra parameters given at
+	// construction. A token requested with any override is fetched directly
+	// from the token endpoint and is not cached.
+	Scopes          []string
+	Audience        string
+	ExtraParameters url.Values
 }
 
 type oauth2TokenEndpointCaller struct {
@@ -54,7 +144,11 @@ type requestToken struct {
 	GrantType    string `schema:"grant_type"`
 }
 
-func NewOAuth2ClientCredentials(clientId string, clientSecret string, tokenEndpoint string) OAuth2ClientCredentials {
+func NewOAuth2ClientCredentials(clientId string, clientSecret string, tokenEndpoint string, options ...CredentialsOption) OAuth2ClientCredentials {
+	var parameters tokenParameters
+	for _, option := range options {
+		option(&parameters)
+	}
 	return OAuth2ClientCredentials{
 		clientId:      clientId,
 		clientSecret:  clientSecret,
@@ -62,7 +156,25 @@ func NewOAuth2ClientCredentials(clientId string, clientSecret string, tokenEndpo
 		cachedToken:   RefreshTokenResponse{},
 		tokenMutex:    sync.RWMutex{},
 		generation:    0,
+		parameters:    parameters,
+	}
+}
+
+// Sanitized returns the credentials configuration as JSON with the client
+// secret replaced by debuglog.Redacted, for startup logs and support bundles.
+func (o *OAuth2ClientCredentials) Sanitized() ([]byte, error) {
+	secret := ""
+	if o.clientSecret != "" {
+		secret = debuglog.Redacted
 	}
+	return json.Marshal(struct {
+		ClientId        string   `json:"client_id"`
+		ClientSecret    string   `json:"client_secret"`
+		TokenEndpoint   string   `json:"token_endpoint"`
+		Scopes          []string `json:"scopes,omitempty"`
+		Audience        string   `json:"audience,omitempty"`
+		ClientAssertion bool     `json:"client_assertion,omitempty"`
+	}{o.clientId, secret, o.tokenEndpoint, o.parameters.scopes, o.parameters.audience, o.parameters.assertion != nil})
 }
 
 func FetchOIDCDiscovery(ctx context.Context, issuerUrl string, options FetchOIDCDiscoveryOptions) (OIDCDiscoveryMetadata, error) {
@@ -70,8 +182,12 @@ func FetchOIDCDiscovery(ctx context.Context, issuerUrl string, options FetchOIDC
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
+	wellKnownUrl := wellKnownUrl(issuerUrl, options.WellKnownPath)
+	if options.Cache != nil {
+		return options.Cache.fetch(ctx, issuerUrl, wellKnownUrl, httpClient)
+	}
 
-	discoveryConfig, err := client.Discover(ctx, issuerUrl, httpClient)
+	discoveryConfig, err := client.Discover(ctx, issuerUrl, httpClient, wellKnownUrl)
 	if err != nil {
 		return OIDCDiscoveryMetadata{}, err
 	}
@@ -79,12 +195,23 @@ func FetchOIDCDiscovery(ctx context.Context, issuerUrl string, options FetchOIDC
 	return OIDCDiscoveryMetadata{TokenEndpoint: discoveryConfig.TokenEndpoint}, nil
 }
 
+func wellKnownUrl(issuerUrl string, path string) string {
+	if path == "" {
+		path = DefaultWellKnownPath
+	}
+	return strings.TrimSuffix(issuerUrl, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
 func (o *OAuth2ClientCredentials) GetToken(ctx context.Context, options GetTokenOptions) (RefreshTokenResponse, error) {
 	httpClient := options.HttpClient
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
+	if len(options.Scopes) > 0 || options.Audience != "" || len(options.ExtraParameters) > 0 {
+		return o.refreshToken(ctx, httpClient, o.parameters.override(options))
+	}
+
 	// Snapshot generation before any lock so all concurrent callers that
 	// decide to refresh see the same value, regardless of lock ordering.
 	generation := atomic.LoadUint64(&o.generation)
@@ -98,35 +225,49 @@ func (o *OAuth2ClientCredentials) GetToken(ctx context.Context, options GetToken
 	o.tokenMutex.RUnlock()
 
 	o.tokenMutex.Lock()
-	defer o.tokenMutex.Unlock()
 
 	if atomic.LoadUint64(&o.generation) != generation && o.isTokenValid() {
-		return o.cachedToken, nil
+		token := o.cachedToken
+		o.tokenMutex.Unlock()
+		return token, nil
+	}
+
+	start := time.Now()
+	token, err := o.refreshToken(ctx, httpClient, o.parameters)
+	o.cachedToken = token
+	event := o.recordRefresh(time.Since(start), token, err)
+	if err == nil {
+		atomic.AddUint64(&o.generation, 1)
 	}
+	hooks := o.hooks
+	o.tokenMutex.Unlock()
 
-	var err error
-	o.cachedToken, err = o.refreshToken(ctx, httpClient)
+	// Hooks run after the lock is released so they may use the credentials.
+	hooks.notify(event)
 	if err != nil {
 		return RefreshTokenResponse{}, err
 	}
-	atomic.AddUint64(&o.generation, 1)
 
-	return o.cachedToken, nil
+	return token, nil
 }
 
-func (o *OAuth2ClientCredentials) refreshToken(ctx context.Context, httpClient *http.Client) (RefreshTokenResponse, error) {
+func (o *OAuth2ClientCredentials) refreshToken(ctx context.Context, httpClient *http.Client, parameters tokenParameters) (RefreshTokenResponse, error) {
 	request := requestToken{
 		ClientID:     o.clientId,
 		ClientSecret: o.clientSecret,
 		GrantType:    "client_credentials",
 	}
-
-	tokenEndpointCaller := oauth2TokenEndpointCaller{
-		tokenEndpoint: o.tokenEndpoint,
-		httpClient:    httpClient,
+	if parameters.assertion != nil {
+		request.ClientSecret = ""
+	}
+	ctx, cancel := parameters.fetchContext(ctx)
+	defer cancel()
+	parameters, err := parameters.withAssertion(ctx)
+	if err != nil {
+		return RefreshTokenResponse{}, err
 	}
 
-	token, err := client.CallTokenEndpoint(ctx, request, tokenEndpointCaller)
+	token, err := parameters.callTokenEndpoint(ctx, request, o.tokenEndpoint, httpClient)
 
 	if err != nil {
 		return RefreshTokenResponse{}, err
@@ -143,12 +284,54 @@ func (o *OAuth2ClientCredentials) refreshToken(ctx context.Context, httpClient *
 	}, nil
 }
 
+// override returns p with the per-call values in options replacing it; extra
+// parameters are merged with the per-call values winning.
+func (p tokenParameters) override(options GetTokenOptions) tokenParameters {
+	if len(options.Scopes) > 0 {
+		p.scopes = options.Scopes
+	}
+	if options.Audience != "" {
+		p.audience = options.Audience
+	}
+	if len(options.ExtraParameters) > 0 {
+		extra := url.Values{}
+		maps.Copy(extra, p.extra)
+		maps.Copy(extra, options.ExtraParameters)
+		p.extra = extra
+	}
+	return p
+}
+
+func (p tokenParameters) formAuthorization() httphelper.FormAuthorization {
+	return func(form url.Values) {
+		for key, values := range p.extra {
+			switch key {
+			case "client_id", "client_secret", "grant_type":
+				continue
+			}
+			form[key] = values
+		}
+		if len(p.scopes) > 0 {
+			form.Set("scope", strings.Join(p.scopes, " "))
+		}
+		if p.audience != "" {
+			form.Set("audience", p.audience)
+		}
+	}
+}
+
 func (o *OAuth2ClientCredentials) isTokenValid() bool {
-	if o.cachedToken.AccessToken == "" {
+	return tokenValid(o.cachedToken)
+}
+
+// tokenValid reports whether token is set and not within expirationWindow of
+// expiry.
+func tokenValid(token RefreshTokenResponse) bool {
+	if token.AccessToken == "" {
 		return false
 	}
 
-	return time.Now().Add(time.Duration(expirationWindow) * time.Second).Before(o.cachedToken.ExpiresAt)
+	return time.Now().Add(time.Duration(expirationWindow) * time.Second).Before(token.ExpiresAt)
 }
 
 func (o oauth2TokenEndpointCaller) TokenEndpoint() string {
@@ -23,7 +23,7 @@ func OAuth2AuthRequest(credentials *OAuth2ClientCredentials, options OAuth2AuthR
 }
 
 func (o oauth2Auth) ConfigureRequest(ctx context.Context, request *http.Request) error {
-	token, err := o.credentials.GetToken(ctx, GetTokenOptions{
+	token, err := TokenForContext(ctx, o.credentials, GetTokenOptions{
 		HttpClient: o.httpClient,
 	})
 
@@ -31,6 +31,6 @@ func (o oauth2Auth) ConfigureRequest(ctx context.Context, request *http.Request)
 		return err
 	}
 
-	request.Header.Set("authorization", "Bearer "+token.AccessToken)
+	request.Header.Set("authorization", "Bearer "+token)
 	return nil
 }
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -31,6 +33,125 @@ func TestMakeOAuth2ClientCredentials(t *testing.T) {
 	}
 }
 
+func TestOAuth2ClientCredentials_Sanitized(t *testing.T) {
+	tests := []struct {
+		name         string
+		clientSecret string
+		expected     string
+	}{
+		{
+			name:         "secret redacted",
+			clientSecret: "test-client-secret",
+			expected:     `{"client_id":"test-client-id","client_secret":"[REDACTED]","token_endpoint":"https://example.com/token"}`,
+		},
+		{
+			name:     "empty secret",
+			expected: `{"client_id":"test-client-id","client_secret":"","token_endpoint":"https://example.com/token"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credentials := NewOAuth2ClientCredentials("test-client-id", tt.clientSecret, "https://example.com/token")
+
+			data, err := credentials.Sanitized()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, data)
+			}
+		})
+	}
+}
+
+func TestOAuth2ClientCredentials_tokenParameters(t *testing.T) {
+	tests := []struct {
+		name           string
+		options        []CredentialsOption
+		getTokenOpts   GetTokenOptions
+		expectedForm   url.Values
+		expectedCached bool
+	}{
+		{
+			name:           "no parameters",
+			expectedForm:   url.Values{"grant_type": {"client_credentials"}, "client_id": {"client"}, "client_secret": {"secret"}},
+			expectedCached: true,
+		},
+		{
+			name: "construction parameters",
+			options: []CredentialsOption{
+				WithScopes("api.console", "offline_access"),
+				WithAudience("kessel"),
+				WithTokenParameter("resource", "https://kessel.example.com"),
+				WithTokenParameter("grant_type", "password"),
+			},
+			expectedForm: url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {"client"},
+				"client_secret": {"secret"},
+				"scope":         {"api.console offline_access"},
+				"audience":      {"kessel"},
+				"resource":      {"https://kessel.example.com"},
+			},
+			expectedCached: true,
+		},
+		{
+			name: "per-call override",
+			options: []CredentialsOption{
+				WithScopes("api.console"),
+				WithTokenParameter("resource", "a"),
+				WithTokenParameter("tenant", "t1"),
+			},
+			getTokenOpts: GetTokenOptions{
+				Scopes:          []string{"api.rbac"},
+				ExtraParameters: url.Values{"resource": {"b"}},
+			},
+			expectedForm: url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {"client"},
+				"client_secret": {"secret"},
+				"scope":         {"api.rbac"},
+				"resource":      {"b"},
+				"tenant":        {"t1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("Failed to parse form: %v", err)
+				}
+				form = r.PostForm
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(map[string]any{
+					"access_token": "token",
+					"token_type":   "Bearer",
+					"expires_in":   3600,
+				}); err != nil {
+					t.Errorf("Failed to encode test response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			credentials := NewOAuth2ClientCredentials("client", "secret", server.URL, tt.options...)
+			if _, err := credentials.GetToken(context.Background(), tt.getTokenOpts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(form, tt.expectedForm) {
+				t.Errorf("Expected form %v, got %v", tt.expectedForm, form)
+			}
+			if cached := credentials.cachedToken.AccessToken != ""; cached != tt.expectedCached {
+				t.Errorf("Expected cached token %v, got %v", tt.expectedCached, cached)
+			}
+		})
+	}
+}
+
 func TestFetchOIDCDiscovery(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -77,6 +198,49 @@ func TestFetchOIDCDiscovery_DefaultValues(t *testing.T) {
 	// The main thing we're testing is that it doesn't panic with nil values
 }
 
+func TestFetchOIDCDiscovery_WellKnownPath(t *testing.T) {
+	tests := []struct {
+		name          string
+		issuerSuffix  string
+		wellKnownPath string
+		expectedPath  string
+	}{
+		{name: "default path", expectedPath: DefaultWellKnownPath},
+		{name: "issuer with trailing slash", issuerSuffix: "/", expectedPath: DefaultWellKnownPath},
+		{name: "custom path", wellKnownPath: "/.well-known/oauth-authorization-server", expectedPath: "/.well-known/oauth-authorization-server"},
+		{name: "custom path without leading slash", wellKnownPath: "custom/discovery", expectedPath: "/custom/discovery"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requestedPath string
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestedPath = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]string{
+					"issuer":         server.URL + tt.issuerSuffix,
+					"token_endpoint": server.URL + "/token",
+				})
+			}))
+			defer server.Close()
+
+			metadata, err := FetchOIDCDiscovery(context.Background(), server.URL+tt.issuerSuffix, FetchOIDCDiscoveryOptions{
+				WellKnownPath: tt.wellKnownPath,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if requestedPath != tt.expectedPath {
+				t.Errorf("Expected discovery request to %s, got %s", tt.expectedPath, requestedPath)
+			}
+			if metadata.TokenEndpoint != server.URL+"/token" {
+				t.Errorf("Expected token endpoint %s/token, got %s", server.URL, metadata.TokenEndpoint)
+			}
+		})
+	}
+}
+
 func TestOAuth2ClientCredentials_GetToken(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -349,7 +513,7 @@ func TestOAuth2ClientCredentials_refreshToken(t *testing.T) {
 
 			credentials := NewOAuth2ClientCredentials("test-client", "test-secret", server.URL)
 
-			result, err := credentials.refreshToken(context.Background(), http.DefaultClient)
+			result, err := credentials.refreshToken(context.Background(), http.DefaultClient, credentials.parameters)
 
 			if tt.expectError {
 				if err == nil {
@@ -626,3 +790,76 @@ func TestConcurrentForceRefresh(t *testing.T) {
 		t.Errorf("Thundering herd: SSO was called %d times, expected exactly 1", finalCallCount)
 	}
 }
+
+func TestWithTokenFetchTimeout(t *testing.T) {
+	// The server never answers while the test runs.
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		ctx     func() (context.Context, context.CancelFunc)
+	}{
+		{
+			name:    "timeout bounds a context without deadline",
+			timeout: 50 * time.Millisecond,
+			ctx:     func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+		},
+		{
+			name:    "earlier RPC deadline wins",
+			timeout: time.Minute,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			credentials := NewOAuth2ClientCredentials("client", "secret", server.URL, WithTokenFetchTimeout(tt.timeout))
+			start := time.Now()
+			if _, err := credentials.GetToken(ctx, GetTokenOptions{}); err == nil {
+				t.Fatal("Expected error from hung token endpoint")
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Expected token fetch to be cut short, took %s", elapsed)
+			}
+
+			exchange := NewTokenExchangeCredentials("client", "secret", server.URL, WithTokenFetchTimeout(tt.timeout))
+			start = time.Now()
+			if _, err := exchange.Exchange(ctx, "subject", GetTokenOptions{}); err == nil {
+				t.Fatal("Expected error from hung token endpoint")
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Expected token exchange to be cut short, took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestTokenParameters_fetchContext(t *testing.T) {
+	ctx, cancel := tokenParameters{}.fetchContext(context.Background())
+	deadline, ok := ctx.Deadline()
+	cancel()
+	if !ok || time.Until(deadline) > defaultTokenFetchTimeout {
+		t.Errorf("Expected default timeout of %s, got deadline %v (%v)", defaultTokenFetchTimeout, deadline, ok)
+	}
+
+	disabled := time.Duration(0)
+	ctx, cancel = tokenParameters{fetchTimeout: &disabled}.fetchContext(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected no deadline when the timeout is disabled")
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import "context"
+
+type tokenOverrideKey struct{}
+type credentialsOverrideKey struct{}
+
+// WithToken makes calls made with ctx send token as the bearer token instead
+// of the configured credentials' token, e.g. to act as an impersonated user
+// without building a second client. It is honoured by the SDK's OAuth2
+// adapters: OAuth2AuthRequest, NewAuthenticatedTransport,
+// grpccreds.NewOAuth2 and ClientBuilder.OAuth2ClientAuthenticated.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenOverrideKey{}, token)
+}
+
+// WithCredentials makes calls made with ctx fetch their token from
+// credentials instead of the configured ones. A token set with WithToken takes
+// precedence.
+func WithCredentials(ctx context.Context, credentials *OAuth2ClientCredentials) context.Context {
+	return context.WithValue(ctx, credentialsOverrideKey{}, credentials)
+}
+
+// OverridesFromContext returns the token set with WithToken and the
+// credentials set with WithCredentials, or zero values. Code that shares one
+// call between several contexts can use it to keep callers with different
+// overrides apart.
+func OverridesFromContext(ctx context.Context) (string, *OAuth2ClientCredentials) {
+	token, _ := ctx.Value(tokenOverrideKey{}).(string)
+	credentials, _ := ctx.Value(credentialsOverrideKey{}).(*OAuth2ClientCredentials)
+	return token, credentials
+}
+
+// TokenForContext returns the bearer token for a call made with ctx: the
+// token set with WithToken, or one from the credentials set with
+// WithCredentials, or otherwise one from credentials. Per-call credential
+// implementations outside the SDK can use it to honour the same overrides.
+func TokenForContext(ctx context.Context, credentials *OAuth2ClientCredentials, options GetTokenOptions) (string, error) {
+	if token, ok := ctx.Value(tokenOverrideKey{}).(string); ok && token != "" {
+		return token, nil
+	}
+	if override, ok := ctx.Value(credentialsOverrideKey{}).(*OAuth2ClientCredentials); ok && override != nil {
+		credentials = override
+	}
+
+	token, err := credentials.GetToken(ctx, options)
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTokenForContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse form: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "token-` + r.PostForm.Get("client_id") + `", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	configured := NewOAuth2ClientCredentials("service", "secret", server.URL)
+	impersonated := NewOAuth2ClientCredentials("user", "secret", server.URL)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{name: "configured credentials", ctx: context.Background(), expected: "token-service"},
+		{name: "token override", ctx: WithToken(context.Background(), "static"), expected: "static"},
+		{name: "credentials override", ctx: WithCredentials(context.Background(), &impersonated), expected: "token-user"},
+		{name: "token wins over credentials", ctx: WithToken(WithCredentials(context.Background(), &impersonated), "static"), expected: "static"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := TokenForContext(tt.ctx, &configured, GetTokenOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if token != tt.expected {
+				t.Errorf("Expected token %q, got %q", tt.expected, token)
+			}
+		})
+	}
+}
+
+func TestOAuth2Auth_ConfigureRequest_tokenOverride(t *testing.T) {
+	// The token endpoint is unreachable, so the override must not fetch a token.
+	credentials := NewOAuth2ClientCredentials("client", "secret", "invalid-url")
+	request := httptest.NewRequest(http.MethodGet, "https://example.com", nil)
+
+	ctx := WithToken(context.Background(), "impersonated")
+	if err := OAuth2AuthRequest(&credentials, OAuth2AuthRequestOptions{}).ConfigureRequest(ctx, request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := request.Header.Get("authorization"); got != "Bearer impersonated" {
+		t.Errorf("Expected the override token, got %q", got)
+	}
+}
+
+func TestOverridesFromContext(t *testing.T) {
+	impersonated := NewOAuth2ClientCredentials("user", "secret", "invalid-url")
+	token, credentials := OverridesFromContext(WithToken(WithCredentials(context.Background(), &impersonated), "static"))
+	if token != "static" || credentials != &impersonated {
+		t.Errorf("Expected both overrides, got %q and %p", token, credentials)
+	}
+	if token, credentials := OverridesFromContext(context.Background()); token != "" || credentials != nil {
+		t.Errorf("Expected no overrides, got %q and %p", token, credentials)
+	}
+}
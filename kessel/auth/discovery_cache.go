@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zitadel/oidc/v3/pkg/client"
+)
+
+const defaultDiscoveryTTL = time.Hour
+
+// DiscoveryCache caches OIDC discovery documents by issuer URL so code that
+// builds clients per request does not fetch the issuer's well-known document
+// every time. Pass it as FetchOIDCDiscoveryOptions.Cache and share one cache
+// per process. It is safe for concurrent use; concurrent fetches of the same
+// issuer share a single request.
+type DiscoveryCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]discoveryEntry
+	calls   map[string]*discoveryCall
+}
+
+type discoveryEntry struct {
+	metadata  OIDCDiscoveryMetadata
+	expiresAt time.Time
+}
+
+type discoveryCall struct {
+	done     chan struct{}
+	metadata OIDCDiscoveryMetadata
+	err      error
+}
+
+// NewDiscoveryCache returns a cache that keeps documents for ttl (one hour if
+// ttl is not positive). A Cache-Control max-age on the discovery response
+// takes precedence over ttl, and no-store or no-cache responses are not
+// cached.
+func NewDiscoveryCache(ttl time.Duration) *DiscoveryCache {
+	if ttl <= 0 {
+		ttl = defaultDiscoveryTTL
+	}
+	return &DiscoveryCache{
+		ttl:     ttl,
+		entries: map[string]discoveryEntry{},
+		calls:   map[string]*discoveryCall{},
+	}
+}
+
+// Set stores a pre-fetched document for issuerUrl, e.g. one loaded from
+// configuration. It does not expire.
+func (c *DiscoveryCache) Set(issuerUrl string, metadata OIDCDiscoveryMetadata) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[issuerUrl] = discoveryEntry{metadata: metadata}
+}
+
+// Invalidate removes the document for issuerUrl so the next fetch goes to the
+// issuer.
+func (c *DiscoveryCache) Invalidate(issuerUrl string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, issuerUrl)
+}
+
+func (c *DiscoveryCache) fetch(ctx context.Context, issuerUrl string, wellKnownUrl string, httpClient *http.Client) (OIDCDiscoveryMetadata, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[issuerUrl]; ok && (entry.expiresAt.IsZero() || time.Now().Before(entry.expiresAt)) {
+		c.mu.Unlock()
+		return entry.metadata, nil
+	}
+	if call, ok := c.calls[issuerUrl]; ok {
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.metadata, call.err
+		case <-ctx.Done():
+			return OIDCDiscoveryMetadata{}, ctx.Err()
+		}
+	}
+	call := &discoveryCall{done: make(chan struct{})}
+	c.calls[issuerUrl] = call
+	c.mu.Unlock()
+
+	var ttl time.Duration
+	call.metadata, ttl, call.err = discover(ctx, issuerUrl, wellKnownUrl, httpClient, c.ttl)
+
+	c.mu.Lock()
+	delete(c.calls, issuerUrl)
+	if call.err == nil && ttl > 0 {
+		c.entries[issuerUrl] = discoveryEntry{metadata: call.metadata, expiresAt: time.Now().Add(ttl)}
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.metadata, call.err
+}
+
+// discover runs client.Discover and returns how long the document may be
+// cached. The response headers are read through a shallow copy of httpClient
+// whose transport records them; the underlying transport and its connections
+// are shared.
+func discover(ctx context.Context, issuerUrl string, wellKnownUrl string, httpClient *http.Client, ttl time.Duration) (OIDCDiscoveryMetadata, time.Duration, error) {
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	recorder := &headerRecorder{base: base}
+	recording := *httpClient
+	recording.Transport = recorder
+
+	discoveryConfig, err := client.Discover(ctx, issuerUrl, &recording, wellKnownUrl)
+	if err != nil {
+		return OIDCDiscoveryMetadata{}, 0, err
+	}
+
+	return OIDCDiscoveryMetadata{TokenEndpoint: discoveryConfig.TokenEndpoint}, cacheTTL(recorder.header, ttl), nil
+}
+
+type headerRecorder struct {
+	base   http.RoundTripper
+	header http.Header
+}
+
+func (r *headerRecorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := r.base.RoundTrip(request)
+	if err == nil {
+		r.header = response.Header
+	}
+	return response, err
+}
+
+// cacheTTL applies the response's Cache-Control directives to ttl.
+func cacheTTL(header http.Header, ttl time.Duration) time.Duration {
+	for directive := range strings.SplitSeq(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache":
+			return 0
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return ttl
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newDiscoveryServer serves a discovery document with the given Cache-Control
+// header, counting requests. Requests wait for gate when it is non-nil.
+func newDiscoveryServer(t *testing.T, cacheControl string, gate chan struct{}) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if gate != nil {
+			<-gate
+		}
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":         server.URL,
+			"token_endpoint": server.URL + "/token",
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestDiscoveryCache(t *testing.T) {
+	tests := []struct {
+		name             string
+		cacheControl     string
+		expectedRequests int32
+	}{
+		{name: "cached", expectedRequests: 1},
+		{name: "max-age", cacheControl: "public, max-age=600", expectedRequests: 1},
+		{name: "max-age zero", cacheControl: "max-age=0", expectedRequests: 2},
+		{name: "no-store", cacheControl: "no-store", expectedRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newDiscoveryServer(t, tt.cacheControl, nil)
+			options := FetchOIDCDiscoveryOptions{Cache: NewDiscoveryCache(time.Minute)}
+
+			for range 2 {
+				metadata, err := FetchOIDCDiscovery(context.Background(), server.URL, options)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if metadata.TokenEndpoint != server.URL+"/token" {
+					t.Errorf("Expected token endpoint %s/token, got %s", server.URL, metadata.TokenEndpoint)
+				}
+			}
+			if got := requests.Load(); got != tt.expectedRequests {
+				t.Errorf("Expected %d discovery requests, got %d", tt.expectedRequests, got)
+			}
+		})
+	}
+}
+
+func TestDiscoveryCache_expiry(t *testing.T) {
+	server, requests := newDiscoveryServer(t, "", nil)
+	cache := NewDiscoveryCache(time.Minute)
+	options := FetchOIDCDiscoveryOptions{Cache: cache}
+
+	if _, err := FetchOIDCDiscovery(context.Background(), server.URL, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cache.mu.Lock()
+	entry := cache.entries[server.URL]
+	entry.expiresAt = time.Now().Add(-time.Second)
+	cache.entries[server.URL] = entry
+	cache.mu.Unlock()
+
+	if _, err := FetchOIDCDiscovery(context.Background(), server.URL, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected an expired entry to be refetched, got %d requests", got)
+	}
+
+	cache.Invalidate(server.URL)
+	if _, err := FetchOIDCDiscovery(context.Background(), server.URL, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("Expected an invalidated entry to be refetched, got %d requests", got)
+	}
+}
+
+func TestDiscoveryCache_set(t *testing.T) {
+	cache := NewDiscoveryCache(0)
+	cache.Set("https://sso.example.com", OIDCDiscoveryMetadata{TokenEndpoint: "https://sso.example.com/token"})
+
+	metadata, err := FetchOIDCDiscovery(context.Background(), "https://sso.example.com", FetchOIDCDiscoveryOptions{Cache: cache})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metadata.TokenEndpoint != "https://sso.example.com/token" {
+		t.Errorf("Expected the pre-fetched document, got %+v", metadata)
+	}
+}
+
+func TestDiscoveryCache_concurrentFetches(t *testing.T) {
+	gate := make(chan struct{})
+	server, requests := newDiscoveryServer(t, "", gate)
+	options := FetchOIDCDiscoveryOptions{Cache: NewDiscoveryCache(time.Minute)}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := FetchOIDCDiscovery(context.Background(), server.URL, options)
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(gate)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected exactly 1 discovery request, got %d", got)
+	}
+}
+
+func TestDiscoveryCache_errorsNotCached(t *testing.T) {
+	cache := NewDiscoveryCache(time.Minute)
+	for range 2 {
+		if _, err := FetchOIDCDiscovery(context.Background(), "invalid-url", FetchOIDCDiscoveryOptions{Cache: cache}); err == nil {
+			t.Fatal("Expected error for invalid issuer")
+		}
+	}
+	if len(cache.entries) != 0 {
+		t.Errorf("Expected no cached entries, got %d", len(cache.entries))
+	}
+}
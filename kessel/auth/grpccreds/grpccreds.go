@@ -0,0 +1,85 @@
+// Package grpccreds sends tokens from auth.OAuth2ClientCredentials as gRPC
+// per-RPC credentials. It is the single OAuth2 adapter used by the client
+// builders and by kesselgrpc.OAuth2CallCredentials.
+package grpccreds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"google.golang.org/grpc"
+)
+
+type Option func(*OAuth2)
+
+// AllowInsecure lets the credentials be sent over a plaintext connection,
+// e.g. to a local server started with the builder's Insecure(). Without it
+// gRPC refuses to attach them to an insecure transport.
+func AllowInsecure() Option {
+	return func(o *OAuth2) {
+		o.allowInsecure = true
+	}
+}
+
+// TokenFetchTimeout bounds fetching the token for one call, on top of the
+// call's own deadline and the credentials' auth.WithTokenFetchTimeout. Zero
+// (the default) adds no bound.
+func TokenFetchTimeout(timeout time.Duration) Option {
+	return func(o *OAuth2) {
+		o.fetchTimeout = timeout
+	}
+}
+
+// OAuth2 implements credentials.PerRPCCredentials. Tokens come from the
+// credentials' cache and honour the per-call overrides auth.WithToken and
+// auth.WithCredentials.
+type OAuth2 struct {
+	credentials   *auth.OAuth2ClientCredentials
+	allowInsecure bool
+	fetchTimeout  time.Duration
+}
+
+func NewOAuth2(credentials *auth.OAuth2ClientCredentials, options ...Option) *OAuth2 {
+	o := &OAuth2{credentials: credentials}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// DialOption returns a grpc.DialOption that sends tokens from credentials on
+// every call of a connection the SDK does not build, e.g. one created by a
+// framework such as Kratos:
+//
+//	conn, err := kratosgrpc.Dial(ctx, kratosgrpc.WithEndpoint(target),
+//	    kratosgrpc.WithOptions(grpccreds.DialOption(&creds)))
+func DialOption(credentials *auth.OAuth2ClientCredentials, options ...Option) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(NewOAuth2(credentials, options...))
+}
+
+// Credentials returns the client credentials tokens are fetched with.
+func (o *OAuth2) Credentials() *auth.OAuth2ClientCredentials {
+	return o.credentials
+}
+
+func (o *OAuth2) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	if o.fetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.fetchTimeout)
+		defer cancel()
+	}
+	token, err := auth.TokenForContext(ctx, o.credentials, auth.GetTokenOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"authorization": fmt.Sprintf("Bearer %s", token),
+	}, nil
+}
+
+func (o *OAuth2) RequireTransportSecurity() bool {
+	return !o.allowInsecure
+}
@@ -0,0 +1,131 @@
+package grpccreds
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestOAuth2_RequireTransportSecurity(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		expected bool
+	}{
+		{name: "secure", expected: true},
+		{name: "allow insecure", options: []Option{AllowInsecure()}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := auth.NewOAuth2ClientCredentials("client", "secret", "https://example.com/token")
+			if got := NewOAuth2(&creds, tt.options...).RequireTransportSecurity(); got != tt.expected {
+				t.Errorf("RequireTransportSecurity() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOAuth2_GetRequestMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "fetched", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+	creds := auth.NewOAuth2ClientCredentials("client", "secret", server.URL)
+
+	metadata, err := NewOAuth2(&creds).GetRequestMetadata(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metadata["authorization"] != "Bearer fetched" {
+		t.Errorf("Expected the fetched token, got %q", metadata["authorization"])
+	}
+}
+
+func TestOAuth2_tokenOverride(t *testing.T) {
+	creds := auth.NewOAuth2ClientCredentials("client", "secret", "invalid-url")
+
+	metadata, err := NewOAuth2(&creds).GetRequestMetadata(auth.WithToken(context.Background(), "impersonated"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metadata["authorization"] != "Bearer impersonated" {
+		t.Errorf("Expected the override token, got %q", metadata["authorization"])
+	}
+}
+
+func TestOAuth2_TokenFetchTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	creds := auth.NewOAuth2ClientCredentials("client", "secret", server.URL)
+
+	start := time.Now()
+	metadata, err := NewOAuth2(&creds, TokenFetchTimeout(50*time.Millisecond)).GetRequestMetadata(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if metadata != nil {
+		t.Errorf("Expected metadata to be nil on error, got %v", metadata)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the fetch to be bounded, took %v", elapsed)
+	}
+}
+
+func TestDialOption(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "fetched", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer tokenServer.Close()
+
+	received := make(chan []string, 1)
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(srv any, stream grpc.ServerStream) error {
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		received <- md.Get("authorization")
+		if err := stream.RecvMsg(&emptypb.Empty{}); err != nil {
+			return err
+		}
+		return stream.SendMsg(&emptypb.Empty{})
+	}))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	creds := auth.NewOAuth2ClientCredentials("client", "secret", tokenServer.URL)
+	conn, err := grpc.NewClient(listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		DialOption(&creds, AllowInsecure()))
+	if err != nil {
+		t.Fatalf("Failed to create the connection: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.Invoke(context.Background(), "/test.Service/Call", &emptypb.Empty{}, &emptypb.Empty{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if authorization := <-received; len(authorization) != 1 || authorization[0] != "Bearer fetched" {
+		t.Errorf("Expected the fetched token on the call, got %v", authorization)
+	}
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const defaultDialTimeout = 30 * time.Second
+
+// HTTPClientOptions configures the transport built by NewHTTPClient.
+type HTTPClientOptions struct {
+	// ProxyURL routes requests through this proxy. When empty, the
+	// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are honored.
+	ProxyURL string
+	// DialTimeout bounds establishing the TCP connection (default 30 seconds).
+	DialTimeout time.Duration
+	// Timeout bounds each request, including reading the response body. Zero
+	// means no timeout; prefer context deadlines per call.
+	Timeout time.Duration
+	// TLSConfig is used for TLS connections. It is cloned, so later changes do
+	// not affect the client.
+	TLSConfig *tls.Config
+	// DisableHTTP2 restricts the client to HTTP/1.1. HTTP/2 is otherwise
+	// attempted even with a custom TLSConfig.
+	DisableHTTP2 bool
+}
+
+// NewHTTPClient returns an http.Client configured from options, starting from
+// the settings of http.DefaultTransport. Build one client and share it between
+// the SDK calls that accept an HttpClient (OIDC discovery, token requests and
+// RBAC workspace lookups) so they use the same proxy, TLS and timeouts and
+// share connections.
+func NewHTTPClient(options HTTPClientOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if options.ProxyURL != "" {
+		proxy, err := url.Parse(options.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	} else {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	dialTimeout := options.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = defaultDialTimeout
+	}
+	transport.DialContext = (&net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+
+	if options.TLSConfig != nil {
+		transport.TLSClientConfig = options.TLSConfig.Clone()
+	}
+
+	if options.DisableHTTP2 {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	} else {
+		transport.ForceAttemptHTTP2 = true
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   options.Timeout,
+	}, nil
+}
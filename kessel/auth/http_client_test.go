@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       HTTPClientOptions
+		expectedProxy string
+		expectedHTTP2 bool
+		expectedError bool
+	}{
+		{
+			name:          "defaults",
+			expectedHTTP2: true,
+		},
+		{
+			name:          "explicit proxy",
+			options:       HTTPClientOptions{ProxyURL: "http://proxy.example.com:3128"},
+			expectedProxy: "http://proxy.example.com:3128",
+			expectedHTTP2: true,
+		},
+		{
+			name:    "http2 disabled",
+			options: HTTPClientOptions{DisableHTTP2: true},
+		},
+		{
+			name:          "invalid proxy",
+			options:       HTTPClientOptions{ProxyURL: "http://[::1"},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewHTTPClient(tt.options)
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			transport := client.Transport.(*http.Transport)
+			if transport.Proxy == nil {
+				t.Error("Expected a proxy function (explicit or from the environment)")
+			}
+			if transport.ForceAttemptHTTP2 != tt.expectedHTTP2 {
+				t.Errorf("Expected ForceAttemptHTTP2 %v, got %v", tt.expectedHTTP2, transport.ForceAttemptHTTP2)
+			}
+			if tt.expectedProxy != "" {
+				request, _ := http.NewRequest(http.MethodGet, "https://rbac.example.com", nil)
+				proxy, err := transport.Proxy(request)
+				if err != nil || proxy == nil || proxy.String() != tt.expectedProxy {
+					t.Errorf("Expected proxy %s, got %v (%v)", tt.expectedProxy, proxy, err)
+				}
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_tlsAndTimeout(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tlsConfig := server.Client().Transport.(*http.Transport).TLSClientConfig
+	client, err := NewHTTPClient(HTTPClientOptions{
+		TLSConfig:   tlsConfig,
+		Timeout:     5 * time.Second,
+		DialTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.Timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s, got %v", client.Timeout)
+	}
+	if client.Transport.(*http.Transport).TLSClientConfig == tlsConfig {
+		t.Error("Expected the TLS config to be cloned")
+	}
+
+	response, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", response.StatusCode)
+	}
+}
@@ -0,0 +1,116 @@
+package auth
+
+import "time"
+
+// TokenMetrics receives token lifecycle measurements from
+// OAuth2ClientCredentials. Implement it to publish to a metrics backend such
+// as Prometheus or OpenTelemetry. Methods are called after the credentials
+// release their refresh lock, alongside the OnTokenRefresh and OnTokenError
+// callbacks, so a slow exporter only delays the caller whose GetToken
+// refreshed the token.
+type TokenMetrics interface {
+	// TokenRefreshed is called after each successful refresh with how long the
+	// token endpoint call took and when the new token expires.
+	TokenRefreshed(duration time.Duration, expiresAt time.Time)
+	// TokenRefreshFailed is called after each failed refresh with how long the
+	// attempt took and the number of consecutive failed refreshes so far.
+	TokenRefreshFailed(duration time.Duration, failureStreak int, err error)
+}
+
+// WrapWithObservability attaches metrics to credentials and returns the same
+// credentials so they can be passed straight to any SDK consumer. Call it
+// before the credentials are shared; passing nil metrics detaches them.
+//
+// Pair it with ExpiresIn for an expiry countdown gauge, e.g. a callback gauge
+// that reports creds.ExpiresIn().Seconds().
+func WrapWithObservability(credentials *OAuth2ClientCredentials, metrics TokenMetrics) *OAuth2ClientCredentials {
+	credentials.tokenMutex.Lock()
+	defer credentials.tokenMutex.Unlock()
+
+	credentials.hooks.metrics = metrics
+	credentials.failureStreak = 0
+	return credentials
+}
+
+// OnTokenRefresh registers callback to be called after every successful
+// refresh with the new token, replacing any earlier callback. Like
+// TokenMetrics it runs after the refresh lock is released, so it may call
+// ExpiresIn or GetToken. The token carries the access token; do not log it.
+func (o *OAuth2ClientCredentials) OnTokenRefresh(callback func(RefreshTokenResponse)) {
+	o.tokenMutex.Lock()
+	defer o.tokenMutex.Unlock()
+
+	o.hooks.onRefresh = callback
+}
+
+// OnTokenError registers callback to be called with the error of every failed
+// refresh, replacing any earlier callback. Like OnTokenRefresh it runs after
+// the refresh lock is released.
+func (o *OAuth2ClientCredentials) OnTokenError(callback func(error)) {
+	o.tokenMutex.Lock()
+	defer o.tokenMutex.Unlock()
+
+	o.hooks.onError = callback
+}
+
+// ExpiresIn returns the time left before the cached token expires, or zero if
+// there is no cached token or it has already expired.
+func (o *OAuth2ClientCredentials) ExpiresIn() time.Duration {
+	o.tokenMutex.RLock()
+	defer o.tokenMutex.RUnlock()
+
+	if o.cachedToken.AccessToken == "" {
+		return 0
+	}
+	remaining := time.Until(o.cachedToken.ExpiresAt)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// refreshEvent describes one refresh of the cached token.
+type refreshEvent struct {
+	duration      time.Duration
+	token         RefreshTokenResponse
+	err           error
+	failureStreak int
+}
+
+// refreshHooks are the TokenMetrics and callbacks registered on credentials.
+// GetToken copies them under the refresh lock and notifies them after
+// releasing it.
+type refreshHooks struct {
+	metrics   TokenMetrics
+	onRefresh func(RefreshTokenResponse)
+	onError   func(error)
+}
+
+// recordRefresh updates the failure streak and returns the event to notify.
+// It must be called with tokenMutex held for writing.
+func (o *OAuth2ClientCredentials) recordRefresh(duration time.Duration, token RefreshTokenResponse, err error) refreshEvent {
+	if err != nil {
+		o.failureStreak++
+	} else {
+		o.failureStreak = 0
+	}
+	return refreshEvent{duration: duration, token: token, err: err, failureStreak: o.failureStreak}
+}
+
+func (h refreshHooks) notify(event refreshEvent) {
+	if event.err != nil {
+		if h.metrics != nil {
+			h.metrics.TokenRefreshFailed(event.duration, event.failureStreak, event.err)
+		}
+		if h.onError != nil {
+			h.onError(event.err)
+		}
+		return
+	}
+	if h.metrics != nil {
+		h.metrics.TokenRefreshed(event.duration, event.token.ExpiresAt)
+	}
+	if h.onRefresh != nil {
+		h.onRefresh(event.token)
+	}
+}
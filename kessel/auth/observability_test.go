@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	refreshed      int
+	lastExpiresAt  time.Time
+	failed         int
+	lastStreak     int
+	lastDurationOK bool
+}
+
+func (m *recordingMetrics) TokenRefreshed(duration time.Duration, expiresAt time.Time) {
+	m.refreshed++
+	m.lastExpiresAt = expiresAt
+	m.lastDurationOK = duration >= 0
+}
+
+func (m *recordingMetrics) TokenRefreshFailed(duration time.Duration, failureStreak int, err error) {
+	m.failed++
+	m.lastStreak = failureStreak
+	m.lastDurationOK = duration >= 0
+}
+
+func TestWrapWithObservability(t *testing.T) {
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "observed-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		}); err != nil {
+			t.Errorf("Failed to encode test response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	creds := NewOAuth2ClientCredentials("client", "secret", server.URL)
+	metrics := &recordingMetrics{}
+	wrapped := WrapWithObservability(&creds, metrics)
+	if wrapped != &creds {
+		t.Fatal("Expected WrapWithObservability to return the same credentials")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := wrapped.GetToken(context.Background(), GetTokenOptions{}); err == nil {
+			t.Fatal("Expected token fetch to fail")
+		}
+	}
+	if metrics.failed != 2 || metrics.lastStreak != 2 {
+		t.Errorf("Expected 2 failures with streak 2, got %d failures with streak %d", metrics.failed, metrics.lastStreak)
+	}
+
+	fail = false
+	if _, err := wrapped.GetToken(context.Background(), GetTokenOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metrics.refreshed != 1 {
+		t.Errorf("Expected 1 refresh, got %d", metrics.refreshed)
+	}
+	if time.Until(metrics.lastExpiresAt) < 59*time.Minute {
+		t.Errorf("Expected expiry about one hour out, got %v", metrics.lastExpiresAt)
+	}
+	if !metrics.lastDurationOK {
+		t.Error("Expected a non-negative refresh duration")
+	}
+	if creds.failureStreak != 0 {
+		t.Errorf("Expected failure streak to reset after success, got %d", creds.failureStreak)
+	}
+
+	if _, err := wrapped.GetToken(context.Background(), GetTokenOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metrics.refreshed != 1 {
+		t.Errorf("Expected cached token to not report a refresh, got %d refreshes", metrics.refreshed)
+	}
+}
+
+func TestOAuth2ClientCredentials_ExpiresIn(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   RefreshTokenResponse
+		minimum time.Duration
+		maximum time.Duration
+	}{
+		{
+			name:    "no cached token",
+			token:   RefreshTokenResponse{},
+			minimum: 0,
+			maximum: 0,
+		},
+		{
+			name:    "expired token",
+			token:   RefreshTokenResponse{AccessToken: "old", ExpiresAt: time.Now().Add(-time.Minute)},
+			minimum: 0,
+			maximum: 0,
+		},
+		{
+			name:    "valid token",
+			token:   RefreshTokenResponse{AccessToken: "current", ExpiresAt: time.Now().Add(time.Hour)},
+			minimum: 59 * time.Minute,
+			maximum: time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := NewOAuth2ClientCredentials("client", "secret", "https://example.com/token")
+			creds.cachedToken = tt.token
+
+			got := creds.ExpiresIn()
+			if got < tt.minimum || got > tt.maximum {
+				t.Errorf("ExpiresIn() = %v, expected between %v and %v", got, tt.minimum, tt.maximum)
+			}
+		})
+	}
+}
+
+func TestOnTokenRefreshAndError(t *testing.T) {
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "callback-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		}); err != nil {
+			t.Errorf("Failed to encode test response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	creds := NewOAuth2ClientCredentials("client", "secret", server.URL)
+	var errorCount int
+	var refreshed RefreshTokenResponse
+	var expiresIn time.Duration
+	creds.OnTokenError(func(err error) {
+		if err == nil {
+			t.Error("Expected a non-nil error")
+		}
+		errorCount++
+	})
+	creds.OnTokenRefresh(func(token RefreshTokenResponse) {
+		refreshed = token
+		// Calling back into the credentials must not deadlock.
+		expiresIn = creds.ExpiresIn()
+	})
+
+	if _, err := creds.GetToken(context.Background(), GetTokenOptions{}); err == nil {
+		t.Fatal("Expected token fetch to fail")
+	}
+	if errorCount != 1 {
+		t.Errorf("Expected 1 error callback, got %d", errorCount)
+	}
+
+	fail = false
+	if _, err := creds.GetToken(context.Background(), GetTokenOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if refreshed.AccessToken != "callback-token" {
+		t.Errorf("Expected refresh callback with the new token, got %q", refreshed.AccessToken)
+	}
+	if expiresIn < 59*time.Minute {
+		t.Errorf("Expected ExpiresIn about one hour from the callback, got %v", expiresIn)
+	}
+
+	// Cached tokens do not trigger the callback again.
+	refreshed = RefreshTokenResponse{}
+	if _, err := creds.GetToken(context.Background(), GetTokenOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if refreshed.AccessToken != "" {
+		t.Error("Expected no refresh callback for a cached token")
+	}
+}
+
+// expiryMetrics reads the credentials' expiry from inside TokenRefreshed.
+type expiryMetrics struct {
+	creds     *OAuth2ClientCredentials
+	expiresIn time.Duration
+}
+
+func (m *expiryMetrics) TokenRefreshed(duration time.Duration, expiresAt time.Time) {
+	m.expiresIn = m.creds.ExpiresIn()
+}
+
+func (m *expiryMetrics) TokenRefreshFailed(duration time.Duration, failureStreak int, err error) {}
+
+func TestWrapWithObservability_notifiesOutsideLock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "metrics-token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	creds := NewOAuth2ClientCredentials("client", "secret", server.URL)
+	metrics := &expiryMetrics{creds: &creds}
+	WrapWithObservability(&creds, metrics)
+	var callbackCalled bool
+	creds.OnTokenRefresh(func(RefreshTokenResponse) { callbackCalled = true })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := creds.GetToken(context.Background(), GetTokenOptions{})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetToken deadlocked: TokenMetrics was called with the refresh lock held")
+	}
+
+	if metrics.expiresIn < 59*time.Minute {
+		t.Errorf("Expected ExpiresIn about one hour from TokenRefreshed, got %v", metrics.expiresIn)
+	}
+	if !callbackCalled {
+		t.Error("Expected OnTokenRefresh to be called alongside TokenMetrics")
+	}
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type staticTokenAuth struct {
+	token string
+}
+
+// StaticTokenAuth returns an AuthRequest that sends token as the bearer token
+// on every request, for environments where a sidecar or the platform already
+// issues tokens. The token is never refreshed; use TokenFileAuth when it is
+// rotated.
+func StaticTokenAuth(token string) AuthRequest {
+	return staticTokenAuth{token: token}
+}
+
+func (s staticTokenAuth) ConfigureRequest(ctx context.Context, request *http.Request) error {
+	if s.token == "" {
+		return errors.New("static token is empty")
+	}
+
+	request.Header.Set("authorization", "Bearer "+s.token)
+	return nil
+}
+
+// TokenFile reads a pre-issued token (e.g. a JWT) that a sidecar writes to a
+// file. It is safe for concurrent use.
+type TokenFile struct {
+	path           string
+	reloadInterval time.Duration
+	mu             sync.Mutex
+	token          string
+	readAt         time.Time
+}
+
+// NewTokenFile reads the token at path. The file is re-read once
+// reloadInterval has passed since the last read, so rotated tokens are picked
+// up; a reloadInterval <= 0 re-reads it on every call.
+func NewTokenFile(path string, reloadInterval time.Duration) *TokenFile {
+	return &TokenFile{path: path, reloadInterval: reloadInterval}
+}
+
+// Token returns the current token.
+func (t *TokenFile) Token() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != "" && t.reloadInterval > 0 && time.Since(t.readAt) < t.reloadInterval {
+		return t.token, nil
+	}
+
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", t.path)
+	}
+	t.token, t.readAt = token, time.Now()
+	return token, nil
+}
+
+type tokenFileAuth struct {
+	file *TokenFile
+}
+
+// TokenFileAuth returns an AuthRequest that sends the token read from path as
+// the bearer token, re-reading the file as described in NewTokenFile.
+func TokenFileAuth(path string, reloadInterval time.Duration) AuthRequest {
+	return tokenFileAuth{file: NewTokenFile(path, reloadInterval)}
+}
+
+func (t tokenFileAuth) ConfigureRequest(ctx context.Context, request *http.Request) error {
+	token, err := t.file.Token()
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("authorization", "Bearer "+token)
+	return nil
+}
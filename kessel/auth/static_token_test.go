@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStaticTokenAuth(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "http://rbac", nil)
+	if err := StaticTokenAuth("static-token").ConfigureRequest(context.Background(), request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := request.Header.Get("authorization"); got != "Bearer static-token" {
+		t.Errorf("Expected static bearer token, got %q", got)
+	}
+
+	if err := StaticTokenAuth("").ConfigureRequest(context.Background(), request); err == nil {
+		t.Error("Expected an error for an empty token")
+	}
+}
+
+func TestTokenFile(t *testing.T) {
+	tests := []struct {
+		name           string
+		reloadInterval time.Duration
+		expected       string
+	}{
+		{name: "reloads every call", reloadInterval: 0, expected: "second"},
+		{name: "keeps token within interval", reloadInterval: time.Hour, expected: "first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "token")
+			writeTokenFile(t, path, "first", time.Now())
+			file := NewTokenFile(path, tt.reloadInterval)
+
+			if got, err := file.Token(); err != nil || got != "first" {
+				t.Fatalf("Expected trimmed token first, got %q, %v", got, err)
+			}
+			writeTokenFile(t, path, "second", time.Now())
+			got, err := file.Token()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTokenFile_errors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := NewTokenFile(filepath.Join(dir, "missing"), time.Minute).Token(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist, got %v", err)
+	}
+
+	empty := filepath.Join(dir, "empty")
+	writeTokenFile(t, empty, "  ", time.Now())
+	if _, err := NewTokenFile(empty, time.Minute).Token(); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("Expected empty token error, got %v", err)
+	}
+}
+
+func TestTokenFileAuth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeTokenFile(t, path, "file-token", time.Now())
+
+	request, _ := http.NewRequest(http.MethodGet, "http://rbac", nil)
+	if err := TokenFileAuth(path, time.Minute).ConfigureRequest(context.Background(), request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := request.Header.Get("authorization"); got != "Bearer file-token" {
+		t.Errorf("Expected file bearer token, got %q", got)
+	}
+}
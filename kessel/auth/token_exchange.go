@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+)
+
+const (
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	accessTokenType        = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// TokenExchangeCredentials exchanges an incoming user token (the subject
+// token) for a Kessel-scoped token with the RFC 8693 token exchange grant, so
+// a gateway can make calls as the calling user instead of as its service
+// account. Exchanged tokens are cached per subject token until they are within
+// the expiration window. Share one instance per process.
+type TokenExchangeCredentials struct {
+	clientId      string
+	clientSecret  string
+	tokenEndpoint string
+	parameters    tokenParameters
+	mu            sync.Mutex
+	cache         map[string]RefreshTokenResponse
+}
+
+type tokenExchangeRequest struct {
+	ClientID         string `schema:"client_id,omitempty"`
+	ClientSecret     string `schema:"client_secret,omitempty"`
+	GrantType        string `schema:"grant_type"`
+	SubjectToken     string `schema:"subject_token"`
+	SubjectTokenType string `schema:"subject_token_type"`
+}
+
+// NewTokenExchangeCredentials returns credentials that authenticate to the
+// token endpoint as the given client. WithScopes, WithAudience and
+// WithTokenParameter apply to every exchange; WithAudience is usually needed to
+// scope the token to Kessel.
+func NewTokenExchangeCredentials(clientId string, clientSecret string, tokenEndpoint string, options ...CredentialsOption) *TokenExchangeCredentials {
+	var parameters tokenParameters
+	for _, option := range options {
+		option(&parameters)
+	}
+	return &TokenExchangeCredentials{
+		clientId:      clientId,
+		clientSecret:  clientSecret,
+		tokenEndpoint: tokenEndpoint,
+		parameters:    parameters,
+		cache:         map[string]RefreshTokenResponse{},
+	}
+}
+
+// Exchange returns a token issued for subjectToken, from the cache when a
+// valid one is held. ForceRefresh skips the cache; Scopes, Audience and
+// ExtraParameters override the construction-time parameters for this call,
+// whose token is not cached.
+func (t *TokenExchangeCredentials) Exchange(ctx context.Context, subjectToken string, options GetTokenOptions) (RefreshTokenResponse, error) {
+	if subjectToken == "" {
+		return RefreshTokenResponse{}, kesselerrors.ErrSubjectTokenRequired
+	}
+	httpClient := options.HttpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if len(options.Scopes) > 0 || options.Audience != "" || len(options.ExtraParameters) > 0 {
+		return t.exchange(ctx, httpClient, subjectToken, t.parameters.override(options))
+	}
+
+	key := subjectTokenKey(subjectToken)
+	if !options.ForceRefresh {
+		t.mu.Lock()
+		token, ok := t.cache[key]
+		t.mu.Unlock()
+		if ok && tokenValid(token) {
+			return token, nil
+		}
+	}
+
+	token, err := t.exchange(ctx, httpClient, subjectToken, t.parameters)
+	if err != nil {
+		return RefreshTokenResponse{}, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for cachedKey, cached := range t.cache {
+		if !tokenValid(cached) {
+			delete(t.cache, cachedKey)
+		}
+	}
+	t.cache[key] = token
+	return token, nil
+}
+
+func (t *TokenExchangeCredentials) exchange(ctx context.Context, httpClient *http.Client, subjectToken string, parameters tokenParameters) (RefreshTokenResponse, error) {
+	request := tokenExchangeRequest{
+		ClientID:         t.clientId,
+		ClientSecret:     t.clientSecret,
+		GrantType:        tokenExchangeGrantType,
+		SubjectToken:     subjectToken,
+		SubjectTokenType: accessTokenType,
+	}
+	if parameters.assertion != nil {
+		request.ClientSecret = ""
+	}
+	ctx, cancel := parameters.fetchContext(ctx)
+	defer cancel()
+	parameters, err := parameters.withAssertion(ctx)
+	if err != nil {
+		return RefreshTokenResponse{}, err
+	}
+
+	token, err := parameters.callTokenEndpoint(ctx, request, t.tokenEndpoint, httpClient)
+	if err != nil {
+		return RefreshTokenResponse{}, err
+	}
+
+	expiresIn := token.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = defaultExpiresIn
+	}
+
+	return RefreshTokenResponse{
+		AccessToken: token.AccessToken,
+		ExpiresAt:   time.Now().Add(time.Duration(expiresIn) * time.Second),
+	}, nil
+}
+
+// subjectTokenKey hashes the subject token so the cache does not hold user
+// tokens as map keys.
+func subjectTokenKey(subjectToken string) string {
+	sum := sha256.Sum256([]byte(subjectToken))
+	return hex.EncodeToString(sum[:])
+}
+
+type subjectTokenKeyType struct{}
+
+// WithSubjectToken attaches the calling user's token to ctx for
+// TokenExchangeAuthRequest and kesselgrpc.TokenExchangeCallCredentials.
+func WithSubjectToken(ctx context.Context, subjectToken string) context.Context {
+	return context.WithValue(ctx, subjectTokenKeyType{}, subjectToken)
+}
+
+// SubjectTokenFromContext returns the token attached with WithSubjectToken.
+func SubjectTokenFromContext(ctx context.Context) (string, bool) {
+	subjectToken, ok := ctx.Value(subjectTokenKeyType{}).(string)
+	return subjectToken, ok && subjectToken != ""
+}
+
+type tokenExchangeAuth struct {
+	credentials *TokenExchangeCredentials
+	httpClient  *http.Client
+}
+
+// TokenExchangeAuthRequest returns an AuthRequest that exchanges the subject
+// token on the request context (see WithSubjectToken) and sends the result as
+// the bearer token. Requests whose context has no subject token fail with
+// errors.ErrSubjectTokenRequired.
+func TokenExchangeAuthRequest(credentials *TokenExchangeCredentials, options OAuth2AuthRequestOptions) AuthRequest {
+	return tokenExchangeAuth{credentials: credentials, httpClient: options.HttpClient}
+}
+
+func (t tokenExchangeAuth) ConfigureRequest(ctx context.Context, request *http.Request) error {
+	subjectToken, _ := SubjectTokenFromContext(ctx)
+	token, err := t.credentials.Exchange(ctx, subjectToken, GetTokenOptions{
+		HttpClient: t.httpClient,
+	})
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("authorization", "Bearer "+token.AccessToken)
+	return nil
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+)
+
+// newTokenExchangeServer issues "exchanged-<subject_token>" tokens and counts
+// requests.
+func newTokenExchangeServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse form: %v", err)
+		}
+		if got := r.PostForm.Get("grant_type"); got != tokenExchangeGrantType {
+			t.Errorf("Expected token exchange grant type, got %q", got)
+		}
+		if got := r.PostForm.Get("subject_token_type"); got != accessTokenType {
+			t.Errorf("Expected access token subject type, got %q", got)
+		}
+		if got := r.PostForm.Get("audience"); got != "kessel" {
+			t.Errorf("Expected audience kessel, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token": "exchanged-%s", "token_type": "Bearer", "expires_in": 3600}`, r.PostForm.Get("subject_token"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestTokenExchangeCredentials_Exchange(t *testing.T) {
+	server, requests := newTokenExchangeServer(t)
+	credentials := NewTokenExchangeCredentials("gateway", "secret", server.URL, WithAudience("kessel"))
+
+	tests := []struct {
+		name             string
+		subjectToken     string
+		options          GetTokenOptions
+		expectedToken    string
+		expectedRequests int32
+	}{
+		{name: "first exchange", subjectToken: "alice", expectedToken: "exchanged-alice", expectedRequests: 1},
+		{name: "cached", subjectToken: "alice", expectedToken: "exchanged-alice", expectedRequests: 1},
+		{name: "other subject", subjectToken: "bob", expectedToken: "exchanged-bob", expectedRequests: 2},
+		{name: "force refresh", subjectToken: "alice", options: GetTokenOptions{ForceRefresh: true}, expectedToken: "exchanged-alice", expectedRequests: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := credentials.Exchange(context.Background(), tt.subjectToken, tt.options)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if token.AccessToken != tt.expectedToken {
+				t.Errorf("Expected token %q, got %q", tt.expectedToken, token.AccessToken)
+			}
+			if got := requests.Load(); got != tt.expectedRequests {
+				t.Errorf("Expected %d token requests, got %d", tt.expectedRequests, got)
+			}
+		})
+	}
+}
+
+func TestTokenExchangeCredentials_requiresSubjectToken(t *testing.T) {
+	credentials := NewTokenExchangeCredentials("gateway", "secret", "https://example.com/token")
+
+	_, err := credentials.Exchange(context.Background(), "", GetTokenOptions{})
+	if !errors.Is(err, kesselerrors.ErrSubjectTokenRequired) {
+		t.Errorf("Expected ErrSubjectTokenRequired, got %v", err)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "https://example.com", nil)
+	err = TokenExchangeAuthRequest(credentials, OAuth2AuthRequestOptions{}).ConfigureRequest(context.Background(), request)
+	if !errors.Is(err, kesselerrors.ErrSubjectTokenRequired) {
+		t.Errorf("Expected ErrSubjectTokenRequired, got %v", err)
+	}
+}
+
+func TestTokenExchangeAuthRequest(t *testing.T) {
+	server, _ := newTokenExchangeServer(t)
+	credentials := NewTokenExchangeCredentials("gateway", "secret", server.URL, WithAudience("kessel"))
+
+	ctx := WithSubjectToken(context.Background(), "alice")
+	request := httptest.NewRequest(http.MethodGet, "https://example.com", nil)
+	if err := TokenExchangeAuthRequest(credentials, OAuth2AuthRequestOptions{}).ConfigureRequest(ctx, request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := request.Header.Get("authorization"); got != "Bearer exchanged-alice" {
+		t.Errorf("Expected exchanged bearer token, got %q", got)
+	}
+}
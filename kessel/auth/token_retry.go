@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zitadel/oidc/v3/pkg/client"
+	"golang.org/x/oauth2"
+)
+
+const (
+	defaultTokenRetryAttempts       = 3
+	defaultTokenRetryInitialBackoff = 200 * time.Millisecond
+	defaultTokenRetryMaxBackoff     = 5 * time.Second
+)
+
+// TokenRetryPolicy controls how token requests are retried when the token
+// endpoint answers 429 Too Many Requests or a 5xx status. Other statuses,
+// e.g. 400 or 401 for bad credentials, and transport errors are never
+// retried. A Retry-After header on the response sets the minimum wait; a
+// response asking for more than MaxBackoff is not retried. Zero fields take
+// their defaults.
+type TokenRetryPolicy struct {
+	// MaxAttempts is the total number of requests, including the first.
+	// Defaults to 3.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry; it doubles with each
+	// further retry. Defaults to 200ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries. Defaults to 5s.
+	MaxBackoff time.Duration
+}
+
+// WithTokenRetry retries token requests that fail with 429 or 5xx according
+// to policy, waiting with exponential backoff and jitter between attempts.
+// Retries share the bound set by WithTokenFetchTimeout and stop when the
+// request context ends. Without it, credentials use the default policy of 3
+// attempts; pass TokenRetryPolicy{MaxAttempts: 1} to make every token request
+// once.
+func WithTokenRetry(policy TokenRetryPolicy) CredentialsOption {
+	return func(p *tokenParameters) {
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = defaultTokenRetryAttempts
+		}
+		if policy.InitialBackoff <= 0 {
+			policy.InitialBackoff = defaultTokenRetryInitialBackoff
+		}
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = defaultTokenRetryMaxBackoff
+		}
+		p.retry = &policy
+	}
+}
+
+// TokenEndpointError is returned when the token endpoint answers with a
+// status other than 200 OK. It unwraps to the error reported by the OIDC
+// client, e.g. an *oidc.Error carrying the OAuth2 error code.
+type TokenEndpointError struct {
+	// StatusCode is the HTTP status of the last response.
+	StatusCode int
+	// Attempts is the number of requests made.
+	Attempts int
+	Err      error
+}
+
+func (e *TokenEndpointError) Error() string {
+	return fmt.Sprintf("token endpoint returned HTTP %d after %d attempt(s): %v", e.StatusCode, e.Attempts, e.Err)
+}
+
+func (e *TokenEndpointError) Unwrap() error {
+	return e.Err
+}
+
+// defaultTokenRetryPolicy is used by credentials created without
+// WithTokenRetry, so a single transient IdP error does not fail a token fetch.
+var defaultTokenRetryPolicy = TokenRetryPolicy{
+	MaxAttempts:    defaultTokenRetryAttempts,
+	InitialBackoff: defaultTokenRetryInitialBackoff,
+	MaxBackoff:     defaultTokenRetryMaxBackoff,
+}
+
+// retryableTokenStatus reports whether a token request that got status may
+// succeed if repeated.
+func retryableTokenStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// callTokenEndpoint sends request to the token endpoint, retrying according
+// to p.retry, or defaultTokenRetryPolicy when unset. The response status and
+// Retry-After header are read through a shallow copy of httpClient whose
+// transport records them, as discover does for headers.
+func (p tokenParameters) callTokenEndpoint(ctx context.Context, request any, tokenEndpoint string, httpClient *http.Client) (*oauth2.Token, error) {
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	policy := p.retry
+	if policy == nil {
+		policy = &defaultTokenRetryPolicy
+	}
+
+	for attempt := 1; ; attempt++ {
+		recorder := &statusRecorder{base: base}
+		recording := *httpClient
+		recording.Transport = recorder
+		caller := oauth2TokenEndpointCaller{tokenEndpoint: tokenEndpoint, httpClient: &recording}
+
+		token, err := client.CallTokenEndpointWithAuthFn(ctx, request, p.formAuthorization(), caller)
+		if err == nil {
+			return token, nil
+		}
+		if recorder.status == 0 || recorder.status == http.StatusOK {
+			return nil, err
+		}
+		err = &TokenEndpointError{StatusCode: recorder.status, Attempts: attempt, Err: err}
+		if attempt >= policy.MaxAttempts || !retryableTokenStatus(recorder.status) {
+			return nil, err
+		}
+		if recorder.retryAfter > policy.MaxBackoff {
+			return nil, err
+		}
+
+		timer := time.NewTimer(max(policy.backoff(attempt), recorder.retryAfter))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+	}
+}
+
+// backoff returns the wait before retry number attempt: the exponential
+// delay capped at MaxBackoff, with up to half of it replaced by jitter so
+// that clients rejected together do not retry together.
+func (p *TokenRetryPolicy) backoff(attempt int) time.Duration {
+	delay := min(p.InitialBackoff, p.MaxBackoff)
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay = min(delay*2, p.MaxBackoff)
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+type statusRecorder struct {
+	base       http.RoundTripper
+	status     int
+	retryAfter time.Duration
+}
+
+func (r *statusRecorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := r.base.RoundTrip(request)
+	if err == nil {
+		r.status = response.StatusCode
+		r.retryAfter = parseRetryAfter(response.Header.Get("Retry-After"), time.Now())
+	}
+	return response, err
+}
+
+// parseRetryAfter reads a Retry-After value in seconds or as an HTTP date,
+// returning 0 when it is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
@@ -0,0 +1,246 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+)
+
+// sequenceServer answers token requests with statuses in order, then with a
+// token once they are used up.
+func sequenceServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1))
+		w.Header().Set("Content-Type", "application/json")
+		if call <= len(statuses) {
+			w.WriteHeader(statuses[call-1])
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": "temporarily_unavailable"})
+			return
+		}
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "retried-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		}); err != nil {
+			t.Errorf("Failed to encode test response: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func fastRetry(attempts int) CredentialsOption {
+	return WithTokenRetry(TokenRetryPolicy{MaxAttempts: attempts, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+}
+
+func TestWithTokenRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []int
+		expectedCalls int32
+		expectedCode  int
+	}{
+		{name: "retries 503 until success", statuses: []int{503, 503}, expectedCalls: 3},
+		{name: "retries 429", statuses: []int{429}, expectedCalls: 2},
+		{name: "gives up after max attempts", statuses: []int{500, 502, 503}, expectedCalls: 3, expectedCode: 503},
+		{name: "never retries 400", statuses: []int{400}, expectedCalls: 1, expectedCode: 400},
+		{name: "never retries 401", statuses: []int{401}, expectedCalls: 1, expectedCode: 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := sequenceServer(t, tt.statuses...)
+			creds := NewOAuth2ClientCredentials("client", "secret", server.URL, fastRetry(3))
+
+			token, err := creds.GetToken(context.Background(), GetTokenOptions{})
+			if got := calls.Load(); got != tt.expectedCalls {
+				t.Errorf("Expected %d token requests, got %d", tt.expectedCalls, got)
+			}
+			if tt.expectedCode == 0 {
+				if err != nil {
+					t.Fatalf("GetToken() error = %v", err)
+				}
+				if token.AccessToken != "retried-token" {
+					t.Errorf("Expected access token retried-token, got %s", token.AccessToken)
+				}
+				return
+			}
+
+			var endpointErr *TokenEndpointError
+			if !errors.As(err, &endpointErr) {
+				t.Fatalf("Expected a TokenEndpointError, got %v", err)
+			}
+			if endpointErr.StatusCode != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, endpointErr.StatusCode)
+			}
+			if endpointErr.Attempts != int(tt.expectedCalls) {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedCalls, endpointErr.Attempts)
+			}
+		})
+	}
+}
+
+func TestTokenEndpointError_withoutRetry(t *testing.T) {
+	server, calls := sequenceServer(t, http.StatusServiceUnavailable)
+	creds := NewOAuth2ClientCredentials("client", "secret", server.URL, WithTokenRetry(TokenRetryPolicy{MaxAttempts: 1}))
+
+	_, err := creds.GetToken(context.Background(), GetTokenOptions{})
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single token request, got %d", calls.Load())
+	}
+	var endpointErr *TokenEndpointError
+	if !errors.As(err, &endpointErr) || endpointErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a 503 TokenEndpointError, got %v", err)
+	}
+	var oidcErr *oidc.Error
+	if !errors.As(err, &oidcErr) || oidcErr.ErrorType != "temporarily_unavailable" {
+		t.Errorf("Expected the OAuth2 error to be unwrappable, got %v", err)
+	}
+}
+
+func TestTokenRetry_defaultPolicy(t *testing.T) {
+	server, calls := sequenceServer(t, http.StatusServiceUnavailable)
+	creds := NewOAuth2ClientCredentials("client", "secret", server.URL)
+
+	token, err := creds.GetToken(context.Background(), GetTokenOptions{})
+	if err != nil {
+		t.Fatalf("Expected the transient 503 to be retried by default, got %v", err)
+	}
+	if token.AccessToken != "retried-token" || calls.Load() != 2 {
+		t.Errorf("Expected the retried token after 2 requests, got %q after %d", token.AccessToken, calls.Load())
+	}
+}
+
+// retryAfterServer answers the first token request with 429 and the given
+// Retry-After header, then with a token.
+func retryAfterServer(t *testing.T, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": "slow_down"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "retried-token", "token_type": "Bearer", "expires_in": 3600})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestWithTokenRetry_honoursRetryAfter(t *testing.T) {
+	server, calls := retryAfterServer(t, "1")
+	creds := NewOAuth2ClientCredentials("client", "secret", server.URL,
+		WithTokenRetry(TokenRetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Second}))
+
+	start := time.Now()
+	if _, err := creds.GetToken(context.Background(), GetTokenOptions{}); err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected the retry to wait the 1s Retry-After, waited %v", elapsed)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 token requests, got %d", calls.Load())
+	}
+}
+
+func TestWithTokenRetry_retryAfterBeyondMaxBackoff(t *testing.T) {
+	server, calls := retryAfterServer(t, "120")
+	creds := NewOAuth2ClientCredentials("client", "secret", server.URL, fastRetry(3))
+
+	_, err := creds.GetToken(context.Background(), GetTokenOptions{})
+
+	var endpointErr *TokenEndpointError
+	if !errors.As(err, &endpointErr) || endpointErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected the 429 to be returned, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected no retry when Retry-After exceeds MaxBackoff, got %d requests", calls.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{value: "", expected: 0},
+		{value: "3", expected: 3 * time.Second},
+		{value: "-1", expected: 0},
+		{value: now.Add(90 * time.Second).Format(http.TimeFormat), expected: 90 * time.Second},
+		{value: "soon", expected: 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestWithTokenRetry_stopsWhenContextEnds(t *testing.T) {
+	server, calls := sequenceServer(t, 503, 503, 503)
+	creds := NewOAuth2ClientCredentials("client", "secret", server.URL,
+		WithTokenRetry(TokenRetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour}))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := creds.GetToken(ctx, GetTokenOptions{})
+
+	var endpointErr *TokenEndpointError
+	if !errors.As(err, &endpointErr) || endpointErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected the last 503 to be reported, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single token request, got %d", calls.Load())
+	}
+}
+
+func TestTokenExchange_retry(t *testing.T) {
+	server, calls := sequenceServer(t, http.StatusBadGateway)
+	exchange := NewTokenExchangeCredentials("client", "secret", server.URL, fastRetry(2))
+
+	if _, err := exchange.Exchange(context.Background(), "subject-token", GetTokenOptions{}); err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 token requests, got %d", calls.Load())
+	}
+}
+
+func TestTokenRetryPolicy_backoff(t *testing.T) {
+	policy := TokenRetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 1, max: 100 * time.Millisecond},
+		{attempt: 2, max: 200 * time.Millisecond},
+		{attempt: 3, max: 400 * time.Millisecond},
+		{attempt: 10, max: time.Second},
+		{attempt: 100, max: time.Second},
+	}
+
+	for _, tt := range tests {
+		for range 20 {
+			delay := policy.backoff(tt.attempt)
+			if delay < tt.max/2 || delay > tt.max {
+				t.Fatalf("backoff(%d) = %v, expected between %v and %v", tt.attempt, delay, tt.max/2, tt.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+type TokenSourceOptions struct {
+	// Optionally specify an http.Client or use http.DefaultClient
+	HttpClient *http.Client
+}
+
+type credentialsTokenSource struct {
+	ctx         context.Context
+	credentials *OAuth2ClientCredentials
+	httpClient  *http.Client
+}
+
+// TokenSource exposes credentials as an oauth2.TokenSource so they can be used
+// with any oauth2-aware library (e.g. oauth2.NewClient). Tokens are served from
+// the credentials' cache. oauth2.TokenSource has no per-call context, so ctx is
+// used for every token fetch made through the returned source.
+func TokenSource(ctx context.Context, credentials *OAuth2ClientCredentials, options TokenSourceOptions) oauth2.TokenSource {
+	return credentialsTokenSource{ctx: ctx, credentials: credentials, httpClient: options.HttpClient}
+}
+
+func (s credentialsTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.credentials.GetToken(s.ctx, GetTokenOptions{
+		HttpClient: s.httpClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      token.ExpiresAt,
+	}, nil
+}
+
+type tokenSourceAuth struct {
+	source oauth2.TokenSource
+}
+
+// TokenSourceAuthRequest adapts an oauth2.TokenSource to AuthRequest so tokens
+// from any oauth2 flow can be used with the RBAC helpers. The source is wrapped
+// in oauth2.ReuseTokenSource, so it is only called when the current token has
+// expired. oauth2.TokenSource does not accept a context, so the request context
+// does not bound token fetches.
+func TokenSourceAuthRequest(source oauth2.TokenSource) AuthRequest {
+	return tokenSourceAuth{source: oauth2.ReuseTokenSource(nil, source)}
+}
+
+func (t tokenSourceAuth) ConfigureRequest(ctx context.Context, request *http.Request) error {
+	token, err := t.source.Token()
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("authorization", "Bearer "+token.AccessToken)
+	return nil
+}
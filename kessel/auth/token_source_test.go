@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestTokenSource_Token(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		expectedError bool
+	}{
+		{
+			name:          "returns bearer token with expiry",
+			statusCode:    http.StatusOK,
+			expectedError: false,
+		},
+		{
+			name:          "propagates token endpoint errors",
+			statusCode:    http.StatusUnauthorized,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.statusCode)
+				if tt.statusCode != http.StatusOK {
+					return
+				}
+				if err := json.NewEncoder(w).Encode(map[string]any{
+					"access_token": "source-token",
+					"token_type":   "Bearer",
+					"expires_in":   3600,
+				}); err != nil {
+					t.Errorf("Failed to encode test response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			creds := NewOAuth2ClientCredentials("client", "secret", server.URL)
+			source := TokenSource(context.Background(), &creds, TokenSourceOptions{})
+
+			token, err := source.Token()
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("Token() error = %v, expectedError %v", err, tt.expectedError)
+			}
+			if tt.expectedError {
+				return
+			}
+
+			if token.AccessToken != "source-token" {
+				t.Errorf("Expected access token source-token, got %s", token.AccessToken)
+			}
+			if token.TokenType != "Bearer" {
+				t.Errorf("Expected token type Bearer, got %s", token.TokenType)
+			}
+			if time.Until(token.Expiry) < 59*time.Minute {
+				t.Errorf("Expected expiry about one hour out, got %v", token.Expiry)
+			}
+		})
+	}
+}
+
+func TestTokenSource_usesCredentialsCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "cached-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		}); err != nil {
+			t.Errorf("Failed to encode test response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	creds := NewOAuth2ClientCredentials("client", "secret", server.URL)
+	if _, err := creds.GetToken(context.Background(), GetTokenOptions{}); err != nil {
+		t.Fatalf("Failed to prime token cache: %v", err)
+	}
+
+	source := TokenSource(context.Background(), &creds, TokenSourceOptions{})
+	if _, err := source.Token(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected 1 token endpoint call, got %d", calls)
+	}
+}
+
+type mockTokenSource struct {
+	token *oauth2.Token
+	err   error
+	calls int
+}
+
+func (m *mockTokenSource) Token() (*oauth2.Token, error) {
+	m.calls++
+	return m.token, m.err
+}
+
+func TestTokenSourceAuthRequest_ConfigureRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		source        *mockTokenSource
+		expectedError bool
+		expectedAuth  string
+	}{
+		{
+			name: "sets bearer header",
+			source: &mockTokenSource{
+				token: &oauth2.Token{AccessToken: "external-token", Expiry: time.Now().Add(time.Hour)},
+			},
+			expectedAuth: "Bearer external-token",
+		},
+		{
+			name:          "propagates source errors",
+			source:        &mockTokenSource{err: errors.New("source failed")},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authRequest := TokenSourceAuthRequest(tt.source)
+			req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+
+			err = authRequest.ConfigureRequest(context.Background(), req)
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("ConfigureRequest() error = %v, expectedError %v", err, tt.expectedError)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.expectedAuth {
+				t.Errorf("Expected authorization %q, got %q", tt.expectedAuth, got)
+			}
+		})
+	}
+}
+
+func TestTokenSourceAuthRequest_reusesValidToken(t *testing.T) {
+	source := &mockTokenSource{
+		token: &oauth2.Token{AccessToken: "external-token", Expiry: time.Now().Add(time.Hour)},
+	}
+	authRequest := TokenSourceAuthRequest(source)
+
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		if err := authRequest.ConfigureRequest(context.Background(), req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if source.calls != 1 {
+		t.Errorf("Expected source to be called once, got %d", source.calls)
+	}
+}
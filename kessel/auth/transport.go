@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"io"
+	"net/http"
+)
+
+type authenticatedTransport struct {
+	credentials *OAuth2ClientCredentials
+	base        http.RoundTripper
+}
+
+// NewAuthenticatedTransport returns an http.RoundTripper that sets a bearer
+// token from credentials on every request before passing it to base (or
+// http.DefaultTransport when base is nil). When the server answers 401 the
+// token is force-refreshed and the request retried once, provided its body
+// can be replayed (no body, or http.Request.GetBody is set).
+//
+//	httpClient := &http.Client{Transport: auth.NewAuthenticatedTransport(&creds, nil)}
+func NewAuthenticatedTransport(credentials *OAuth2ClientCredentials, base http.RoundTripper) http.RoundTripper {
+	return &authenticatedTransport{credentials: credentials, base: base}
+}
+
+func (t *authenticatedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	authenticated, err := t.authenticate(request, false)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := base.RoundTrip(authenticated)
+	if err != nil || response.StatusCode != http.StatusUnauthorized {
+		return response, err
+	}
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return response, nil
+	}
+
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+
+	retry, err := t.authenticate(request, true)
+	if err != nil {
+		return nil, err
+	}
+	if request.GetBody != nil {
+		retry.Body, err = request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return base.RoundTrip(retry)
+}
+
+// authenticate returns a copy of request carrying a bearer token, since a
+// RoundTripper must not modify the caller's request.
+func (t *authenticatedTransport) authenticate(request *http.Request, forceRefresh bool) (*http.Request, error) {
+	token, err := TokenForContext(request.Context(), t.credentials, GetTokenOptions{
+		ForceRefresh: forceRefresh,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authenticated := request.Clone(request.Context())
+	authenticated.Header.Set("authorization", "Bearer "+token)
+	return authenticated, nil
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func newCountingTokenServer(t *testing.T, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		}); err != nil {
+			t.Errorf("Failed to encode test response: %v", err)
+		}
+	}))
+}
+
+func TestAuthenticatedTransport_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name               string
+		rejectFirst        bool
+		body               string
+		replayableBody     bool
+		expectedStatus     int
+		expectedAPICalls   int32
+		expectedTokenCalls int32
+		expectedAuth       string
+	}{
+		{
+			name:               "sets bearer token",
+			expectedStatus:     http.StatusOK,
+			expectedAPICalls:   1,
+			expectedTokenCalls: 1,
+			expectedAuth:       "Bearer token-1",
+		},
+		{
+			name:               "refreshes and retries on 401",
+			rejectFirst:        true,
+			expectedStatus:     http.StatusOK,
+			expectedAPICalls:   2,
+			expectedTokenCalls: 2,
+			expectedAuth:       "Bearer token-2",
+		},
+		{
+			name:               "replays body on retry",
+			rejectFirst:        true,
+			body:               `{"name":"ws"}`,
+			replayableBody:     true,
+			expectedStatus:     http.StatusOK,
+			expectedAPICalls:   2,
+			expectedTokenCalls: 2,
+			expectedAuth:       "Bearer token-2",
+		},
+		{
+			name:               "does not retry non-replayable body",
+			rejectFirst:        true,
+			body:               `{"name":"ws"}`,
+			replayableBody:     false,
+			expectedStatus:     http.StatusUnauthorized,
+			expectedAPICalls:   1,
+			expectedTokenCalls: 1,
+			expectedAuth:       "Bearer token-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tokenCalls int32
+			tokenServer := newCountingTokenServer(t, &tokenCalls)
+			defer tokenServer.Close()
+
+			var apiCalls int32
+			var lastAuth string
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&apiCalls, 1)
+				lastAuth = r.Header.Get("Authorization")
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != tt.body {
+					t.Errorf("Expected body %q, got %q", tt.body, string(body))
+				}
+				if tt.rejectFirst && n == 1 {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer apiServer.Close()
+
+			creds := NewOAuth2ClientCredentials("client", "secret", tokenServer.URL)
+			client := &http.Client{Transport: NewAuthenticatedTransport(&creds, nil)}
+
+			var body io.Reader
+			if tt.body != "" {
+				if tt.replayableBody {
+					body = strings.NewReader(tt.body)
+				} else {
+					body = io.NopCloser(strings.NewReader(tt.body))
+				}
+			}
+			req, err := http.NewRequest(http.MethodPost, apiServer.URL, body)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if apiCalls != tt.expectedAPICalls {
+				t.Errorf("Expected %d API calls, got %d", tt.expectedAPICalls, apiCalls)
+			}
+			if tokenCalls != tt.expectedTokenCalls {
+				t.Errorf("Expected %d token calls, got %d", tt.expectedTokenCalls, tokenCalls)
+			}
+			if lastAuth != tt.expectedAuth {
+				t.Errorf("Expected authorization %q, got %q", tt.expectedAuth, lastAuth)
+			}
+			if req.Header.Get("Authorization") != "" {
+				t.Error("Expected caller's request to be left unmodified")
+			}
+		})
+	}
+}
+
+func TestAuthenticatedTransport_tokenError(t *testing.T) {
+	creds := NewOAuth2ClientCredentials("client", "secret", "invalid-url")
+	client := &http.Client{Transport: NewAuthenticatedTransport(&creds, nil)}
+
+	_, err := client.Get("http://127.0.0.1:0")
+	if err == nil {
+		t.Error("Expected token error to be returned")
+	}
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultServiceAccountTokenPath is where Kubernetes mounts the pod's service
+// account token.
+const DefaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+const jwtBearerAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// ServiceAccountToken reads a projected Kubernetes service account token from
+// a file. The kubelet rotates projected tokens by replacing the file, so the
+// file is checked on every call and re-read when it has changed. It is safe
+// for concurrent use.
+type ServiceAccountToken struct {
+	path    string
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewServiceAccountToken reads the token at path, or at
+// DefaultServiceAccountTokenPath when path is empty. Mount a projected token
+// with the audience your identity provider expects rather than relying on the
+// default token.
+func NewServiceAccountToken(path string) *ServiceAccountToken {
+	if path == "" {
+		path = DefaultServiceAccountTokenPath
+	}
+	return &ServiceAccountToken{path: path}
+}
+
+// Token returns the current token.
+func (s *ServiceAccountToken) Token() (string, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.token, nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("service account token file %s is empty", s.path)
+	}
+	s.token, s.modTime, s.size = token, info.ModTime(), info.Size()
+	return token, nil
+}
+
+type serviceAccountAuth struct {
+	token *ServiceAccountToken
+}
+
+// ServiceAccountAuthRequest returns an AuthRequest that sends the service
+// account token itself as the bearer token, for deployments where Kessel
+// trusts the cluster's token issuer directly. To trade it for a token from
+// your identity provider instead, use NewWorkloadIdentityCredentials.
+func ServiceAccountAuthRequest(token *ServiceAccountToken) AuthRequest {
+	return serviceAccountAuth{token: token}
+}
+
+func (s serviceAccountAuth) ConfigureRequest(ctx context.Context, request *http.Request) error {
+	token, err := s.token.Token()
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("authorization", "Bearer "+token)
+	return nil
+}
+
+// WithClientAssertion authenticates to the token endpoint with a JWT client
+// assertion (RFC 7523) instead of the client secret. assertion is called for
+// every token request, so it can return a freshly rotated token.
+func WithClientAssertion(assertion func(ctx context.Context) (string, error)) CredentialsOption {
+	return func(p *tokenParameters) {
+		p.assertion = assertion
+	}
+}
+
+// NewWorkloadIdentityCredentials returns client credentials that authenticate
+// with the service account token as a client assertion (workload identity
+// federation), so in-cluster services need no client secret. The identity
+// provider must trust the cluster's token issuer for clientId. The result
+// works everywhere OAuth2ClientCredentials does.
+func NewWorkloadIdentityCredentials(clientId string, tokenEndpoint string, token *ServiceAccountToken, options ...CredentialsOption) OAuth2ClientCredentials {
+	assertion := WithClientAssertion(func(ctx context.Context) (string, error) {
+		return token.Token()
+	})
+	return NewOAuth2ClientCredentials(clientId, "", tokenEndpoint, append(options, assertion)...)
+}
+
+// withAssertion returns p with the client assertion added to the extra
+// parameters when one is configured.
+func (p tokenParameters) withAssertion(ctx context.Context) (tokenParameters, error) {
+	if p.assertion == nil {
+		return p, nil
+	}
+	assertion, err := p.assertion(ctx)
+	if err != nil {
+		return p, err
+	}
+	extra := url.Values{}
+	maps.Copy(extra, p.extra)
+	extra.Set("client_assertion_type", jwtBearerAssertionType)
+	extra.Set("client_assertion", assertion)
+	p.extra = extra
+	return p, nil
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTokenFile(t *testing.T, path string, token string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Failed to set token file time: %v", err)
+	}
+}
+
+func TestServiceAccountToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	start := time.Now().Add(-time.Hour)
+	writeTokenFile(t, path, "first", start)
+
+	token := NewServiceAccountToken(path)
+	got, err := token.Token()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != "first" {
+		t.Errorf("Expected trimmed token first, got %q", got)
+	}
+
+	writeTokenFile(t, path, "second", start.Add(time.Minute))
+	got, err = token.Token()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != "second" {
+		t.Errorf("Expected rotated token second, got %q", got)
+	}
+}
+
+func TestServiceAccountToken_errors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := NewServiceAccountToken(filepath.Join(dir, "missing")).Token(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist, got %v", err)
+	}
+
+	empty := filepath.Join(dir, "empty")
+	writeTokenFile(t, empty, "  ", time.Now())
+	if _, err := NewServiceAccountToken(empty).Token(); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("Expected empty token error, got %v", err)
+	}
+}
+
+func TestNewServiceAccountToken_defaultPath(t *testing.T) {
+	if got := NewServiceAccountToken("").path; got != DefaultServiceAccountTokenPath {
+		t.Errorf("Expected default path, got %s", got)
+	}
+}
+
+func TestServiceAccountAuthRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeTokenFile(t, path, "sa-token", time.Now())
+
+	request, _ := http.NewRequest(http.MethodGet, "http://rbac", nil)
+	if err := ServiceAccountAuthRequest(NewServiceAccountToken(path)).ConfigureRequest(context.Background(), request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := request.Header.Get("authorization"); got != "Bearer sa-token" {
+		t.Errorf("Expected service account bearer token, got %q", got)
+	}
+}
+
+func TestNewWorkloadIdentityCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeTokenFile(t, path, "sa-token", time.Now())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse form: %v", err)
+		}
+		if got := r.PostForm.Get("grant_type"); got != "client_credentials" {
+			t.Errorf("Expected client_credentials grant, got %q", got)
+		}
+		if got := r.PostForm.Get("client_assertion_type"); got != jwtBearerAssertionType {
+			t.Errorf("Expected jwt-bearer assertion type, got %q", got)
+		}
+		if got := r.PostForm.Get("client_assertion"); got != "sa-token" {
+			t.Errorf("Expected service account token as assertion, got %q", got)
+		}
+		if r.PostForm.Has("client_secret") {
+			t.Error("Expected no client_secret")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "kessel-token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	credentials := NewWorkloadIdentityCredentials("svc", server.URL, NewServiceAccountToken(path))
+	token, err := credentials.GetToken(context.Background(), GetTokenOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if token.AccessToken != "kessel-token" {
+		t.Errorf("Expected kessel-token, got %q", token.AccessToken)
+	}
+
+	sanitized, err := credentials.Sanitized()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(sanitized), `"client_assertion":true`) {
+		t.Errorf("Expected client_assertion in sanitized config, got %s", sanitized)
+	}
+}
+
+func TestWithClientAssertion_error(t *testing.T) {
+	assertionErr := errors.New("no assertion")
+	credentials := NewOAuth2ClientCredentials("svc", "secret", "http://unused", WithClientAssertion(func(ctx context.Context) (string, error) {
+		return "", assertionErr
+	}))
+
+	if _, err := credentials.GetToken(context.Background(), GetTokenOptions{}); !errors.Is(err, assertionErr) {
+		t.Errorf("Expected assertion error, got %v", err)
+	}
+}
@@ -0,0 +1,337 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultConsecutiveFailures = 5
+	defaultOpenTimeout         = 30 * time.Second
+	defaultHalfOpenProbes      = 1
+	defaultWindow              = 60 * time.Second
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed lets all calls through and counts failures.
+	StateClosed State = iota
+	// StateOpen rejects all calls with errors.ErrCircuitOpen.
+	StateOpen
+	// StateHalfOpen lets a limited number of probe calls through to decide
+	// whether to close or re-open the circuit.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker is a circuit breaker that is safe for concurrent use. Share a single
+// Breaker between all clients that talk to the same server.
+type Breaker struct {
+	consecutiveFailures int
+	failureRate         float64
+	minimumRequests     int
+	window              time.Duration
+	openTimeout         time.Duration
+	halfOpenProbes      int
+	isFailure           func(error) bool
+	now                 func() time.Time
+
+	mu             sync.Mutex
+	state          State
+	openedAt       time.Time
+	failureStreak  int
+	windowStart    time.Time
+	windowRequests int
+	windowFailures int
+	probesInFlight int
+	probeSuccesses int
+	// probeGeneration counts half-open periods so that probes started in an
+	// earlier period cannot affect the current one.
+	probeGeneration uint64
+}
+
+// Option configures a Breaker.
+type Option func(*Breaker)
+
+// WithConsecutiveFailures opens the circuit after n consecutive failures.
+// Zero disables the consecutive failure trigger.
+func WithConsecutiveFailures(n int) Option {
+	return func(b *Breaker) {
+		b.consecutiveFailures = n
+	}
+}
+
+// WithFailureRate opens the circuit when the ratio of failed calls within the
+// window reaches threshold (0 to 1), once at least minimumRequests calls have
+// been made in that window.
+func WithFailureRate(threshold float64, minimumRequests int, window time.Duration) Option {
+	return func(b *Breaker) {
+		b.failureRate = threshold
+		b.minimumRequests = minimumRequests
+		b.window = window
+	}
+}
+
+// WithOpenTimeout sets how long the circuit stays open before probing.
+func WithOpenTimeout(d time.Duration) Option {
+	return func(b *Breaker) {
+		b.openTimeout = d
+	}
+}
+
+// WithHalfOpenProbes sets how many probe calls are allowed while half-open.
+// The circuit closes once that many probes succeed.
+func WithHalfOpenProbes(n int) Option {
+	return func(b *Breaker) {
+		b.halfOpenProbes = n
+	}
+}
+
+// WithFailureClassifier overrides which errors count as failures.
+func WithFailureClassifier(isFailure func(error) bool) Option {
+	return func(b *Breaker) {
+		b.isFailure = isFailure
+	}
+}
+
+// New creates a Breaker. By default it opens after 5 consecutive failures,
+// stays open for 30 seconds, and closes after one successful probe.
+func New(options ...Option) *Breaker {
+	b := &Breaker{
+		consecutiveFailures: defaultConsecutiveFailures,
+		window:              defaultWindow,
+		openTimeout:         defaultOpenTimeout,
+		halfOpenProbes:      defaultHalfOpenProbes,
+		isFailure:           IsFailure,
+		now:                 time.Now,
+	}
+
+	for _, option := range options {
+		option(b)
+	}
+
+	if b.halfOpenProbes < 1 {
+		b.halfOpenProbes = 1
+	}
+
+	return b
+}
+
+// IsFailure is the default failure classifier. gRPC errors count as failures
+// when their code indicates the server is unhealthy; client-side cancellation
+// and request errors (e.g. InvalidArgument, NotFound) do not. Non-gRPC errors
+// count as failures unless they are context.Canceled.
+func IsFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted,
+			codes.Internal, codes.Unknown, codes.Aborted, codes.DataLoss:
+			return true
+		default:
+			return false
+		}
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+// State returns the current state of the circuit.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(b.now())
+	return b.state
+}
+
+// Allow reports whether a call may proceed. When it may, the returned function
+// must be called exactly once with the outcome of the call. When the circuit is
+// open, errors.ErrCircuitOpen is returned. A nil Breaker allows every call.
+func (b *Breaker) Allow() (func(error), error) {
+	if b == nil {
+		return func(error) {}, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(b.now())
+	switch b.state {
+	case StateOpen:
+		return nil, kesselerrors.ErrCircuitOpen
+	case StateHalfOpen:
+		if b.probesInFlight >= b.halfOpenProbes {
+			return nil, kesselerrors.ErrCircuitOpen
+		}
+		b.probesInFlight++
+		return b.doneFunc(true, b.probeGeneration), nil
+	default:
+		return b.doneFunc(false, 0), nil
+	}
+}
+
+func (b *Breaker) doneFunc(probe bool, generation uint64) func(error) {
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			b.record(probe, generation, b.isFailure(err))
+		})
+	}
+}
+
+func (b *Breaker) record(probe bool, generation uint64, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if probe {
+		if generation != b.probeGeneration {
+			return
+		}
+		b.probesInFlight--
+		if b.state != StateHalfOpen {
+			return
+		}
+		if failed {
+			b.open(now)
+			return
+		}
+		b.probeSuccesses++
+		if b.probeSuccesses >= b.halfOpenProbes {
+			b.close(now)
+		}
+		return
+	}
+
+	if b.state != StateClosed {
+		return
+	}
+
+	if b.window > 0 && now.Sub(b.windowStart) >= b.window {
+		b.windowStart = now
+		b.windowRequests = 0
+		b.windowFailures = 0
+	}
+	b.windowRequests++
+
+	if !failed {
+		b.failureStreak = 0
+		return
+	}
+
+	b.failureStreak++
+	b.windowFailures++
+
+	if b.consecutiveFailures > 0 && b.failureStreak >= b.consecutiveFailures {
+		b.open(now)
+		return
+	}
+	if b.failureRate > 0 && b.windowRequests >= b.minimumRequests &&
+		float64(b.windowFailures)/float64(b.windowRequests) >= b.failureRate {
+		b.open(now)
+	}
+}
+
+func (b *Breaker) refresh(now time.Time) {
+	if b.state == StateOpen && now.Sub(b.openedAt) >= b.openTimeout {
+		b.state = StateHalfOpen
+		b.probeGeneration++
+		b.probesInFlight = 0
+		b.probeSuccesses = 0
+	}
+}
+
+func (b *Breaker) open(now time.Time) {
+	b.state = StateOpen
+	b.openedAt = now
+}
+
+func (b *Breaker) close(now time.Time) {
+	b.state = StateClosed
+	b.failureStreak = 0
+	b.windowStart = now
+	b.windowRequests = 0
+	b.windowFailures = 0
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that fails fast with
+// errors.ErrCircuitOpen while the circuit is open and records the outcome of
+// every call.
+func UnaryClientInterceptor(b *Breaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		done, err := b.Allow()
+		if err != nil {
+			return err
+		}
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		done(err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a gRPC interceptor that fails fast with
+// errors.ErrCircuitOpen while the circuit is open. The outcome of a stream is
+// recorded from the result of its first RecvMsg (io.EOF counts as success), so
+// a server that accepts streams but fails them does not close the circuit. A
+// stream whose context ends before the first RecvMsg returns is recorded with
+// the context's error; errors received later on the stream are not recorded.
+func StreamClientInterceptor(b *Breaker) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		done, err := b.Allow()
+		if err != nil {
+			return nil, err
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			done(err)
+			return nil, err
+		}
+		stop := context.AfterFunc(ctx, func() {
+			done(status.FromContextError(ctx.Err()).Err())
+		})
+		return &recordedStream{ClientStream: stream, record: func(err error) {
+			stop()
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			done(err)
+		}}, nil
+	}
+}
+
+// recordedStream reports the result of its first RecvMsg to the breaker.
+type recordedStream struct {
+	grpc.ClientStream
+	once   sync.Once
+	record func(error)
+}
+
+func (s *recordedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	s.once.Do(func() { s.record(err) })
+	return err
+}
@@ -0,0 +1,307 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestBreaker(now *time.Time, options ...Option) *Breaker {
+	b := New(options...)
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func call(t *testing.T, b *Breaker, err error) {
+	t.Helper()
+	done, allowErr := b.Allow()
+	if allowErr != nil {
+		t.Fatalf("Expected call to be allowed, got %v", allowErr)
+	}
+	done(err)
+}
+
+func TestBreaker_opensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newTestBreaker(&now, WithConsecutiveFailures(3))
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	call(t, b, unavailable)
+	call(t, b, unavailable)
+	call(t, b, nil)
+	call(t, b, unavailable)
+	call(t, b, unavailable)
+	if b.State() != StateClosed {
+		t.Fatalf("Expected closed after a success reset the streak, got %v", b.State())
+	}
+
+	call(t, b, unavailable)
+	if b.State() != StateOpen {
+		t.Fatalf("Expected open after 3 consecutive failures, got %v", b.State())
+	}
+
+	_, err := b.Allow()
+	if !errors.Is(err, kesselerrors.ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestBreaker_opensOnFailureRate(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newTestBreaker(&now,
+		WithConsecutiveFailures(0),
+		WithFailureRate(0.5, 4, time.Minute),
+	)
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	call(t, b, unavailable)
+	call(t, b, nil)
+	call(t, b, unavailable)
+	if b.State() != StateClosed {
+		t.Fatalf("Expected closed below minimum requests, got %v", b.State())
+	}
+
+	call(t, b, nil)
+	call(t, b, unavailable)
+	if b.State() != StateOpen {
+		t.Errorf("Expected open at 60%% failure rate, got %v", b.State())
+	}
+}
+
+func TestBreaker_halfOpenProbing(t *testing.T) {
+	tests := []struct {
+		name          string
+		probeErr      error
+		expectedState State
+	}{
+		{
+			name:          "successful probe closes the circuit",
+			probeErr:      nil,
+			expectedState: StateClosed,
+		},
+		{
+			name:          "failed probe re-opens the circuit",
+			probeErr:      status.Error(codes.Unavailable, "still down"),
+			expectedState: StateOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(0, 0)
+			b := newTestBreaker(&now, WithConsecutiveFailures(1), WithOpenTimeout(time.Second))
+
+			call(t, b, status.Error(codes.Unavailable, "down"))
+			if b.State() != StateOpen {
+				t.Fatalf("Expected open, got %v", b.State())
+			}
+
+			now = now.Add(time.Second)
+			if b.State() != StateHalfOpen {
+				t.Fatalf("Expected half-open after timeout, got %v", b.State())
+			}
+
+			done, err := b.Allow()
+			if err != nil {
+				t.Fatalf("Expected probe to be allowed, got %v", err)
+			}
+			if _, err := b.Allow(); !errors.Is(err, kesselerrors.ErrCircuitOpen) {
+				t.Errorf("Expected second concurrent probe to be rejected, got %v", err)
+			}
+
+			done(tt.probeErr)
+			if b.State() != tt.expectedState {
+				t.Errorf("Expected state %v, got %v", tt.expectedState, b.State())
+			}
+		})
+	}
+}
+
+func TestBreaker_ignoresStaleProbes(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newTestBreaker(&now, WithConsecutiveFailures(1), WithOpenTimeout(time.Second), WithHalfOpenProbes(2))
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	call(t, b, unavailable)
+	now = now.Add(time.Second)
+	staleDone, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Expected probe to be allowed, got %v", err)
+	}
+	call(t, b, unavailable)
+	if b.State() != StateOpen {
+		t.Fatalf("Expected failed probe to re-open the circuit, got %v", b.State())
+	}
+
+	now = now.Add(time.Second)
+	if _, err := b.Allow(); err != nil {
+		t.Fatalf("Expected probe to be allowed, got %v", err)
+	}
+	staleDone(nil)
+
+	if _, err := b.Allow(); err != nil {
+		t.Fatalf("Expected second probe to be allowed, got %v", err)
+	}
+	if _, err := b.Allow(); !errors.Is(err, kesselerrors.ErrCircuitOpen) {
+		t.Errorf("Expected a stale probe not to free a probe slot, got %v", err)
+	}
+	if b.State() != StateHalfOpen {
+		t.Errorf("Expected a stale probe success not to count, got %v", b.State())
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil error", err: nil, expected: false},
+		{name: "unavailable", err: status.Error(codes.Unavailable, ""), expected: true},
+		{name: "deadline exceeded", err: status.Error(codes.DeadlineExceeded, ""), expected: true},
+		{name: "invalid argument", err: status.Error(codes.InvalidArgument, ""), expected: false},
+		{name: "permission denied", err: status.Error(codes.PermissionDenied, ""), expected: false},
+		{name: "cancelled by caller", err: context.Canceled, expected: false},
+		{name: "transport error", err: fmt.Errorf("connection refused"), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsFailure(tt.err); got != tt.expected {
+				t.Errorf("IsFailure(%v) = %v, expected %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBreaker_nilAllowsCalls(t *testing.T) {
+	var b *Breaker
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Expected nil breaker to allow calls, got %v", err)
+	}
+	done(errors.New("ignored"))
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	b := New(WithConsecutiveFailures(1))
+	interceptor := UnaryClientInterceptor(b)
+
+	calls := 0
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return status.Error(codes.Unavailable, "down")
+	}
+
+	err := interceptor(context.Background(), "/test/Method", nil, nil, nil, invoker)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable from invoker, got %v", err)
+	}
+
+	err = interceptor(context.Background(), "/test/Method", nil, nil, nil, invoker)
+	if !errors.Is(err, kesselerrors.ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected invoker to be called once, got %d", calls)
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	b := New(WithConsecutiveFailures(1))
+	interceptor := StreamClientInterceptor(b)
+
+	calls := 0
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		calls++
+		return nil, status.Error(codes.Unavailable, "down")
+	}
+
+	_, _ = interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/test/Stream", streamer)
+	_, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/test/Stream", streamer)
+	if !errors.Is(err, kesselerrors.ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected streamer to be called once, got %d", calls)
+	}
+}
+
+// fakeStream answers RecvMsg with err.
+type fakeStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s *fakeStream) RecvMsg(m any) error {
+	return s.err
+}
+
+func TestStreamClientInterceptor_probeRecordedOnFirstRecv(t *testing.T) {
+	tests := []struct {
+		name          string
+		recvErr       error
+		expectedState State
+	}{
+		{name: "stream fails on first message", recvErr: status.Error(codes.Unavailable, "down"), expectedState: StateOpen},
+		{name: "stream ends cleanly", recvErr: io.EOF, expectedState: StateClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(0, 0)
+			b := newTestBreaker(&now, WithConsecutiveFailures(1), WithOpenTimeout(time.Second))
+			call(t, b, status.Error(codes.Unavailable, "down"))
+			now = now.Add(time.Second)
+
+			interceptor := StreamClientInterceptor(b)
+			streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return &fakeStream{err: tt.recvErr}, nil
+			}
+			stream, err := interceptor(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, "/test/Stream", streamer)
+			if err != nil {
+				t.Fatalf("Expected the probe stream to open, got %v", err)
+			}
+			if b.State() != StateHalfOpen {
+				t.Fatalf("Expected the circuit to stay half-open until the first message, got %v", b.State())
+			}
+
+			_ = stream.RecvMsg(nil)
+			_ = stream.RecvMsg(nil)
+			if b.State() != tt.expectedState {
+				t.Errorf("Expected state %v, got %v", tt.expectedState, b.State())
+			}
+		})
+	}
+}
+
+func TestStreamClientInterceptor_contextEndsBeforeRecv(t *testing.T) {
+	b := New(WithConsecutiveFailures(1))
+	interceptor := StreamClientInterceptor(b)
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &fakeStream{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := interceptor(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, "/test/Stream", streamer); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	<-ctx.Done()
+
+	deadline := time.Now().Add(time.Second)
+	for b.State() != StateOpen && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if b.State() != StateOpen {
+		t.Errorf("Expected the stream's deadline to be recorded as a failure, got %v", b.State())
+	}
+}
@@ -0,0 +1,127 @@
+// Package concurrent fans Kessel calls out over a bounded pool of workers,
+// for servers that lack the bulk endpoints.
+package concurrent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type checkManyOptions struct {
+	itemTimeout time.Duration
+	consistency *v1beta2.Consistency
+}
+
+type CheckManyOption func(*checkManyOptions)
+
+// WithItemTimeout bounds each Check call, so one slow check fails on its own
+// instead of holding up its worker until ctx ends.
+func WithItemTimeout(timeout time.Duration) CheckManyOption {
+	return func(o *checkManyOptions) {
+		o.itemTimeout = timeout
+	}
+}
+
+// WithConsistency sets the consistency of every Check call, like the
+// consistency of a CheckBulkRequest.
+func WithConsistency(consistency *v1beta2.Consistency) CheckManyOption {
+	return func(o *checkManyOptions) {
+		o.consistency = consistency
+	}
+}
+
+// Result is the outcome of the check of one item.
+type Result struct {
+	Allowed          v1beta2.Allowed
+	ConsistencyToken *v1beta2.ConsistencyToken
+	Err              error
+}
+
+// IsAllowed reports whether the check succeeded and was allowed.
+func (r Result) IsAllowed() bool {
+	return r.Err == nil && r.Allowed == v1beta2.Allowed_ALLOWED_TRUE
+}
+
+// CheckMany sends one Check per item using workers concurrent workers, for
+// servers that predate CheckBulk; the items are those of a CheckBulkRequest,
+// so callers can switch to CheckBulk once it is available. The results are
+// in the order of items. Failed checks are also returned as a
+// *errors.Multi whose items carry the index of the item. If ctx ends, the
+// checks that have not started yet fail with the context error.
+func CheckMany(
+	ctx context.Context,
+	client v1beta2.KesselInventoryServiceClient,
+	items []*v1beta2.CheckBulkRequestItem,
+	workers int,
+	options ...CheckManyOption,
+) ([]Result, error) {
+	o := checkManyOptions{}
+	for _, option := range options {
+		option(&o)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]Result, len(items))
+	check := func(i int) {
+		callCtx := ctx
+		if o.itemTimeout > 0 {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithTimeout(ctx, o.itemTimeout)
+			defer cancel()
+		}
+		response, err := client.Check(callCtx, &v1beta2.CheckRequest{
+			Object:      items[i].GetObject(),
+			Relation:    items[i].GetRelation(),
+			Subject:     items[i].GetSubject(),
+			Consistency: o.consistency,
+		})
+		if err != nil {
+			results[i] = Result{Err: err}
+			return
+		}
+		results[i] = Result{Allowed: response.GetAllowed(), ConsistencyToken: response.GetConsistencyToken()}
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				check(i)
+			}
+		}()
+	}
+
+	for i := range items {
+		if err := ctx.Err(); err != nil {
+			results[i] = Result{Err: err}
+			continue
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			results[i] = Result{Err: ctx.Err()}
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	errs := &kesselerrors.Multi{}
+	for i, result := range results {
+		errs.Append(i, itemKey(items[i]), result.Err)
+	}
+	return results, errs.ErrorOrNil()
+}
+
+func itemKey(item *v1beta2.CheckBulkRequestItem) string {
+	return fmt.Sprintf("%s/%s#%s", item.GetObject().GetResourceType(), item.GetObject().GetResourceId(), item.GetRelation())
+}
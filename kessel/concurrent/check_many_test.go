@@ -0,0 +1,146 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// mockCheckClient allows objects whose id starts with "allow", fails "fail",
+// blocks "slow" until its context ends and tracks concurrency.
+type mockCheckClient struct {
+	v1beta2.KesselInventoryServiceClient
+	mu          sync.Mutex
+	requests    []*v1beta2.CheckRequest
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (m *mockCheckClient) Check(ctx context.Context, in *v1beta2.CheckRequest, opts ...grpc.CallOption) (*v1beta2.CheckResponse, error) {
+	m.mu.Lock()
+	m.requests = append(m.requests, in)
+	m.mu.Unlock()
+
+	n := m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+	for {
+		current := m.maxInFlight.Load()
+		if n <= current || m.maxInFlight.CompareAndSwap(current, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+
+	switch id := in.GetObject().GetResourceId(); {
+	case id == "fail":
+		return nil, status.Error(codes.Internal, "boom")
+	case id == "slow":
+		<-ctx.Done()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case len(id) >= 5 && id[:5] == "allow":
+		return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE, ConsistencyToken: &v1beta2.ConsistencyToken{Token: id}}, nil
+	default:
+		return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_FALSE}, nil
+	}
+}
+
+func item(id string) *v1beta2.CheckBulkRequestItem {
+	return &v1beta2.CheckBulkRequestItem{
+		Object:   &v1beta2.ResourceReference{ResourceType: "host", ResourceId: id, Reporter: &v1beta2.ReporterReference{Type: "hbi"}},
+		Relation: "view",
+		Subject:  &v1beta2.SubjectReference{Resource: &v1beta2.ResourceReference{ResourceType: "principal", ResourceId: "redhat/alice", Reporter: &v1beta2.ReporterReference{Type: "rbac"}}},
+	}
+}
+
+func TestCheckMany(t *testing.T) {
+	client := &mockCheckClient{}
+	items := []*v1beta2.CheckBulkRequestItem{item("allow-1"), item("deny"), item("allow-2"), item("fail"), item("allow-3")}
+
+	results, err := CheckMany(context.Background(), client, items, 2)
+
+	require.Len(t, results, 5)
+	assert.True(t, results[0].IsAllowed())
+	assert.Equal(t, "allow-1", results[0].ConsistencyToken.GetToken())
+	assert.False(t, results[1].IsAllowed())
+	assert.NoError(t, results[1].Err)
+	assert.True(t, results[2].IsAllowed())
+	assert.Equal(t, codes.Internal, status.Code(results[3].Err))
+	assert.True(t, results[4].IsAllowed())
+
+	var multi *kesselerrors.Multi
+	require.ErrorAs(t, err, &multi)
+	require.Equal(t, 1, multi.Len())
+	assert.Equal(t, 3, multi.Errors[0].Index)
+	assert.Equal(t, "host/fail#view", multi.Errors[0].Key)
+
+	assert.Len(t, client.requests, 5)
+	assert.LessOrEqual(t, client.maxInFlight.Load(), int32(2))
+}
+
+func TestCheckMany_workers(t *testing.T) {
+	tests := []struct {
+		name                string
+		workers             int
+		expectedMaxInFlight int32
+	}{
+		{name: "zero workers run one at a time", workers: 0, expectedMaxInFlight: 1},
+		{name: "one worker", workers: 1, expectedMaxInFlight: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockCheckClient{}
+			_, err := CheckMany(context.Background(), client, []*v1beta2.CheckBulkRequestItem{item("allow-1"), item("allow-2"), item("allow-3")}, tt.workers)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMaxInFlight, client.maxInFlight.Load())
+		})
+	}
+}
+
+func TestCheckMany_options(t *testing.T) {
+	client := &mockCheckClient{}
+	consistency := &v1beta2.Consistency{Requirement: &v1beta2.Consistency_MinimizeLatency{MinimizeLatency: true}}
+
+	results, err := CheckMany(context.Background(), client, []*v1beta2.CheckBulkRequestItem{item("slow"), item("allow-1")}, 2,
+		WithItemTimeout(20*time.Millisecond), WithConsistency(consistency))
+
+	require.Error(t, err)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(results[0].Err))
+	assert.True(t, results[1].IsAllowed())
+	for _, request := range client.requests {
+		assert.Same(t, consistency, request.GetConsistency())
+	}
+}
+
+func TestCheckMany_canceled(t *testing.T) {
+	client := &mockCheckClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := CheckMany(ctx, client, []*v1beta2.CheckBulkRequestItem{item("allow-1"), item("allow-2")}, 1)
+
+	require.Error(t, err)
+	assert.Empty(t, client.requests)
+	for _, result := range results {
+		assert.True(t, errors.Is(result.Err, context.Canceled))
+	}
+}
+
+func TestCheckMany_empty(t *testing.T) {
+	results, err := CheckMany(context.Background(), &mockCheckClient{}, nil, 4)
+
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
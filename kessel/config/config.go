@@ -2,6 +2,7 @@ package config
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"time"
 )
 
@@ -82,3 +83,41 @@ func NewCompatibilityConfig(options ...CompatibilityClientOption) *Compatibility
 
 	return config
 }
+
+type sanitizedTLSConfig struct {
+	ServerName         string `json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+	ClientCertificates int    `json:"client_certificates"`
+	CustomRootCAs      bool   `json:"custom_root_cas"`
+}
+
+type sanitizedConfig struct {
+	Url                   string              `json:"endpoint"`
+	Insecure              bool                `json:"insecure"`
+	TLS                   *sanitizedTLSConfig `json:"tls,omitempty"`
+	Timeout               string              `json:"timeout"`
+	MaxReceiveMessageSize int                 `json:"max_receive_message_size"`
+	MaxSendMessageSize    int                 `json:"max_send_message_size"`
+}
+
+// Sanitized returns the configuration as JSON suitable for startup logs and
+// support bundles. The TLS configuration is summarised rather than included,
+// so certificates and private keys are never written out.
+func (c *CompatibilityConfig) Sanitized() ([]byte, error) {
+	sanitized := sanitizedConfig{
+		Url:                   c.Url,
+		Insecure:              c.Insecure,
+		Timeout:               c.Timeout.String(),
+		MaxReceiveMessageSize: c.MaxReceiveMessageSize,
+		MaxSendMessageSize:    c.MaxSendMessageSize,
+	}
+	if c.TLSConfig != nil {
+		sanitized.TLS = &sanitizedTLSConfig{
+			ServerName:         c.TLSConfig.ServerName,
+			InsecureSkipVerify: c.TLSConfig.InsecureSkipVerify,
+			ClientCertificates: len(c.TLSConfig.Certificates),
+			CustomRootCAs:      c.TLSConfig.RootCAs != nil,
+		}
+	}
+	return json.Marshal(sanitized)
+}
@@ -279,3 +279,37 @@ func TestConfigurationFlow(t *testing.T) {
 		t.Errorf("Expected MaxSendMessageSize to be 4MB, got %d", config.MaxSendMessageSize)
 	}
 }
+
+func TestCompatibilityConfig_Sanitized(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *CompatibilityConfig
+		expected string
+	}{
+		{
+			name:     "defaults",
+			config:   NewCompatibilityConfig(WithGRPCEndpoint("localhost:9000"), WithTimeout(5*time.Second)),
+			expected: `{"endpoint":"localhost:9000","insecure":false,"timeout":"5s","max_receive_message_size":4194304,"max_send_message_size":4194304}`,
+		},
+		{
+			name: "tls summary",
+			config: NewCompatibilityConfig(WithGRPCTLSConfig(&tls.Config{
+				ServerName:   "kessel.example.com",
+				Certificates: []tls.Certificate{{PrivateKey: "key"}},
+			})),
+			expected: `{"endpoint":"","insecure":false,"tls":{"server_name":"kessel.example.com","insecure_skip_verify":false,"client_certificates":1,"custom_root_cas":false},"timeout":"0s","max_receive_message_size":4194304,"max_send_message_size":4194304}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.config.Sanitized()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, data)
+			}
+		})
+	}
+}
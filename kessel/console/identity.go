@@ -0,0 +1,148 @@
+package console
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+)
+
+// IdentityHeader is the console.redhat.com identity header. Its value is the
+// base64-encoded JSON {"identity": {...}}.
+const IdentityHeader = "x-rh-identity"
+
+// Identity is the identity carried by the x-rh-identity header. Set User for
+// the "User" type and ServiceAccount for the "ServiceAccount" type.
+type Identity struct {
+	AccountNumber  string                  `json:"account_number,omitempty"`
+	OrgId          string                  `json:"org_id"`
+	Type           string                  `json:"type"`
+	AuthType       string                  `json:"auth_type,omitempty"`
+	Internal       *InternalIdentity       `json:"internal,omitempty"`
+	User           *UserIdentity           `json:"user,omitempty"`
+	ServiceAccount *ServiceAccountIdentity `json:"service_account,omitempty"`
+}
+
+// InternalIdentity is the "internal" object of an identity.
+type InternalIdentity struct {
+	OrgId string `json:"org_id"`
+}
+
+// UserIdentity is the "user" object of a User identity.
+type UserIdentity struct {
+	UserId     string `json:"user_id"`
+	Username   string `json:"username,omitempty"`
+	Email      string `json:"email,omitempty"`
+	FirstName  string `json:"first_name,omitempty"`
+	LastName   string `json:"last_name,omitempty"`
+	IsActive   bool   `json:"is_active"`
+	IsOrgAdmin bool   `json:"is_org_admin"`
+	IsInternal bool   `json:"is_internal"`
+	Locale     string `json:"locale,omitempty"`
+}
+
+// ServiceAccountIdentity is the "service_account" object of a ServiceAccount
+// identity.
+type ServiceAccountIdentity struct {
+	ClientId string `json:"client_id,omitempty"`
+	UserId   string `json:"user_id"`
+	Username string `json:"username,omitempty"`
+}
+
+type identityEnvelope struct {
+	Identity Identity `json:"identity"`
+}
+
+// NewUserIdentity returns an active User identity of orgId.
+func NewUserIdentity(orgId string, userId string, username string) Identity {
+	return Identity{
+		OrgId:    orgId,
+		Type:     "User",
+		AuthType: "jwt-auth",
+		Internal: &InternalIdentity{OrgId: orgId},
+		User:     &UserIdentity{UserId: userId, Username: username, IsActive: true},
+	}
+}
+
+// NewServiceAccountIdentity returns a ServiceAccount identity of orgId.
+func NewServiceAccountIdentity(orgId string, clientId string, userId string, username string) Identity {
+	return Identity{
+		OrgId:          orgId,
+		Type:           "ServiceAccount",
+		AuthType:       "jwt-auth",
+		Internal:       &InternalIdentity{OrgId: orgId},
+		ServiceAccount: &ServiceAccountIdentity{ClientId: clientId, UserId: userId, Username: username},
+	}
+}
+
+// Header returns the value of the x-rh-identity header for i.
+func (i Identity) Header() (string, error) {
+	data, err := json.Marshal(identityEnvelope{Identity: i})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode identity header: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// ParseIdentityHeader decodes the value of an x-rh-identity header.
+func ParseIdentityHeader(header string) (Identity, error) {
+	decoded, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		return Identity{}, fmt.Errorf("failed to decode identity header: %w", err)
+	}
+	var envelope identityEnvelope
+	if err := json.Unmarshal(decoded, &envelope); err != nil {
+		return Identity{}, fmt.Errorf("failed to decode identity header: %w", err)
+	}
+	return envelope.Identity, nil
+}
+
+// SetIdentityHeader sets the x-rh-identity header of request.
+func SetIdentityHeader(request *http.Request, identity Identity) error {
+	header, err := identity.Header()
+	if err != nil {
+		return err
+	}
+	request.Header.Set(IdentityHeader, header)
+	return nil
+}
+
+// AppendIdentityToOutgoingContext returns ctx with the x-rh-identity header
+// added to the outgoing gRPC metadata, for Inventory calls made with it.
+func AppendIdentityToOutgoingContext(ctx context.Context, identity Identity) (context.Context, error) {
+	header, err := identity.Header()
+	if err != nil {
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx, IdentityHeader, header), nil
+}
+
+type identityAuth struct {
+	identity Identity
+	next     auth.AuthRequest
+}
+
+// IdentityAuthRequest returns an auth.AuthRequest that sets the x-rh-identity
+// header, after next (typically auth.OAuth2AuthRequest) when it is not nil,
+// for RBAC calls routed through console.redhat.com:
+//
+//	options := v2.FetchWorkspaceOptions{
+//	    Auth: console.IdentityAuthRequest(identity, auth.OAuth2AuthRequest(&credentials, auth.OAuth2AuthRequestOptions{})),
+//	}
+func IdentityAuthRequest(identity Identity, next auth.AuthRequest) auth.AuthRequest {
+	return identityAuth{identity: identity, next: next}
+}
+
+func (a identityAuth) ConfigureRequest(ctx context.Context, request *http.Request) error {
+	if a.next != nil {
+		if err := a.next.ConfigureRequest(ctx, request); err != nil {
+			return err
+		}
+	}
+	return SetIdentityHeader(request, a.identity)
+}
@@ -0,0 +1,118 @@
+package console
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestIdentity_Header(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity Identity
+	}{
+		{name: "user", identity: NewUserIdentity("12345", "7393748", "jdoe")},
+		{name: "service account", identity: NewServiceAccountIdentity("456", "client-1", "sa-001", "service-account-sa-001")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, err := tt.identity.Header()
+			require.NoError(t, err)
+
+			parsed, err := ParseIdentityHeader(header)
+			require.NoError(t, err)
+			assert.Equal(t, tt.identity, parsed)
+
+			// The header is accepted by the principal helpers.
+			subject, err := PrincipalFromRHIdentityHeader(header)
+			require.NoError(t, err)
+			userId := "7393748"
+			if tt.identity.ServiceAccount != nil {
+				userId = "sa-001"
+			}
+			assert.Equal(t, "redhat/"+userId, subject.GetResource().GetResourceId())
+		})
+	}
+}
+
+func TestIdentity_HeaderFormat(t *testing.T) {
+	header, err := NewUserIdentity("12345", "42", "jdoe").Header()
+	require.NoError(t, err)
+
+	decoded, err := base64.StdEncoding.DecodeString(header)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"identity": {
+		"org_id": "12345",
+		"type": "User",
+		"auth_type": "jwt-auth",
+		"internal": {"org_id": "12345"},
+		"user": {"user_id": "42", "username": "jdoe", "is_active": true, "is_org_admin": false, "is_internal": false}
+	}}`, string(decoded))
+}
+
+func TestParseIdentityHeader_invalid(t *testing.T) {
+	_, err := ParseIdentityHeader("not-valid-base64!@#$")
+
+	assert.ErrorContains(t, err, "failed to decode identity header")
+}
+
+func TestSetIdentityHeader(t *testing.T) {
+	request, err := http.NewRequest(http.MethodGet, "http://rbac:8000", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, SetIdentityHeader(request, NewUserIdentity("12345", "42", "jdoe")))
+
+	parsed, err := ParseIdentityHeader(request.Header.Get(IdentityHeader))
+	require.NoError(t, err)
+	assert.Equal(t, "42", parsed.User.UserId)
+}
+
+func TestAppendIdentityToOutgoingContext(t *testing.T) {
+	ctx, err := AppendIdentityToOutgoingContext(context.Background(), NewUserIdentity("12345", "42", "jdoe"))
+	require.NoError(t, err)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	require.Len(t, md.Get(IdentityHeader), 1)
+	parsed, err := ParseIdentityHeader(md.Get(IdentityHeader)[0])
+	require.NoError(t, err)
+	assert.Equal(t, "12345", parsed.OrgId)
+}
+
+type bearerAuth struct {
+	err error
+}
+
+func (b bearerAuth) ConfigureRequest(ctx context.Context, request *http.Request) error {
+	if b.err != nil {
+		return b.err
+	}
+	request.Header.Set("authorization", "Bearer token")
+	return nil
+}
+
+func TestIdentityAuthRequest(t *testing.T) {
+	identity := NewServiceAccountIdentity("456", "client-1", "sa-001", "sa")
+
+	request, err := http.NewRequest(http.MethodGet, "http://rbac:8000", nil)
+	require.NoError(t, err)
+	require.NoError(t, IdentityAuthRequest(identity, bearerAuth{}).ConfigureRequest(context.Background(), request))
+	assert.Equal(t, "Bearer token", request.Header.Get("authorization"))
+	assert.NotEmpty(t, request.Header.Get(IdentityHeader))
+
+	request, err = http.NewRequest(http.MethodGet, "http://rbac:8000", nil)
+	require.NoError(t, err)
+	require.NoError(t, IdentityAuthRequest(identity, nil).ConfigureRequest(context.Background(), request))
+	assert.NotEmpty(t, request.Header.Get(IdentityHeader))
+
+	tokenErr := errors.New("token failed")
+	err = IdentityAuthRequest(identity, bearerAuth{err: tokenErr}).ConfigureRequest(context.Background(), request)
+	assert.ErrorIs(t, err, tokenErr)
+}
@@ -0,0 +1,314 @@
+package debuglog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Redacted replaces the value of every redacted field in logged payloads.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedFields are the payload fields redacted by every Logger.
+var DefaultRedactedFields = []string{
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"client_secret",
+	"password",
+	"token",
+	"authorization",
+}
+
+// Logger logs gRPC calls and HTTP requests made by the SDK at debug level.
+// Logging and payload capture can be switched on and off at runtime and the
+// Logger is safe for concurrent use. A nil Logger logs nothing.
+type Logger struct {
+	logger   *slog.Logger
+	redacted map[string]bool
+	enabled  atomic.Bool
+	payloads atomic.Bool
+}
+
+// Option configures a Logger.
+type Option func(*Logger)
+
+// WithPayloads sets whether request and response payloads are logged.
+func WithPayloads(enabled bool) Option {
+	return func(l *Logger) {
+		l.payloads.Store(enabled)
+	}
+}
+
+// WithRedactedFields adds payload field names, matched case-insensitively at
+// any depth, whose values are replaced with Redacted.
+func WithRedactedFields(fields ...string) Option {
+	return func(l *Logger) {
+		for _, field := range fields {
+			l.redacted[strings.ToLower(field)] = true
+		}
+	}
+}
+
+// New creates an enabled Logger writing to logger (slog.Default() when nil).
+// Payloads are not logged unless WithPayloads(true) is given.
+func New(logger *slog.Logger, options ...Option) *Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	l := &Logger{logger: logger, redacted: map[string]bool{}}
+	for _, field := range DefaultRedactedFields {
+		l.redacted[field] = true
+	}
+	l.enabled.Store(true)
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+// SetEnabled switches logging on or off.
+func (l *Logger) SetEnabled(enabled bool) {
+	l.enabled.Store(enabled)
+}
+
+// SetPayloads switches payload logging on or off.
+func (l *Logger) SetPayloads(enabled bool) {
+	l.payloads.Store(enabled)
+}
+
+func (l *Logger) active() bool {
+	return l != nil && l.enabled.Load()
+}
+
+func (l *Logger) logPayloads() bool {
+	return l.payloads.Load()
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that logs the method,
+// duration and status code of every unary call, plus the redacted request and
+// response messages when payload logging is on.
+func UnaryClientInterceptor(l *Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !l.active() {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		attrs := []slog.Attr{
+			slog.String("method", method),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("code", status.Code(err).String()),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		if l.logPayloads() {
+			attrs = append(attrs, slog.String("request", l.message(req)))
+			if err == nil {
+				attrs = append(attrs, slog.String("response", l.message(reply)))
+			}
+		}
+		l.logger.LogAttrs(ctx, slog.LevelDebug, "kessel grpc call", attrs...)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a gRPC interceptor that logs the method,
+// duration and status code of opening every stream. Streamed messages are not
+// logged.
+func StreamClientInterceptor(l *Logger) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if !l.active() {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+
+		attrs := []slog.Attr{
+			slog.String("method", method),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("code", status.Code(err).String()),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		l.logger.LogAttrs(ctx, slog.LevelDebug, "kessel grpc stream", attrs...)
+		return stream, err
+	}
+}
+
+type transport struct {
+	logger *Logger
+	base   http.RoundTripper
+}
+
+// NewTransport returns an http.RoundTripper that logs the method, URL,
+// duration and status of every request passed to base (or
+// http.DefaultTransport when base is nil). When payload logging is on, JSON
+// and form-encoded bodies are logged with secrets redacted; other bodies are
+// logged by size only. Headers are never logged.
+//
+//	httpClient := &http.Client{Transport: debuglog.NewTransport(logger, nil)}
+func NewTransport(logger *Logger, base http.RoundTripper) http.RoundTripper {
+	return &transport{logger: logger, base: base}
+}
+
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	l := t.logger
+	if !l.active() {
+		return base.RoundTrip(request)
+	}
+
+	payloads := l.logPayloads()
+	var requestBody string
+	if payloads && request.GetBody != nil {
+		if body, err := request.GetBody(); err == nil {
+			data, readErr := io.ReadAll(body)
+			_ = body.Close()
+			if readErr == nil {
+				requestBody = l.body(data, request.Header.Get("Content-Type"))
+			}
+		}
+	}
+
+	start := time.Now()
+	response, err := base.RoundTrip(request)
+
+	attrs := []slog.Attr{
+		slog.String("method", request.Method),
+		slog.String("url", l.url(request.URL)),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", response.StatusCode))
+	}
+	if payloads {
+		if requestBody != "" {
+			attrs = append(attrs, slog.String("request", requestBody))
+		}
+		if err == nil && response.Body != nil {
+			data, readErr := io.ReadAll(response.Body)
+			_ = response.Body.Close()
+			if readErr == nil {
+				response.Body = io.NopCloser(bytes.NewReader(data))
+				attrs = append(attrs, slog.String("response", l.body(data, response.Header.Get("Content-Type"))))
+			} else {
+				// Replay what was read, then the error, so the caller does not
+				// mistake a truncated body for a complete one.
+				response.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), errorReader{readErr}))
+			}
+		}
+	}
+	l.logger.LogAttrs(request.Context(), slog.LevelDebug, "kessel http request", attrs...)
+	return response, err
+}
+
+// errorReader fails every read with err.
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func (l *Logger) message(message any) string {
+	m, ok := message.(proto.Message)
+	if !ok {
+		return ""
+	}
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return l.redactJSON(data)
+}
+
+func (l *Logger) body(data []byte, contentType string) string {
+	if len(data) == 0 {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			break
+		}
+		return l.redactValues(values).Encode()
+	case json.Valid(data):
+		return l.redactJSON(data)
+	}
+	return "<" + http.DetectContentType(data) + ", " + strconv.Itoa(len(data)) + " bytes>"
+}
+
+func (l *Logger) url(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	redacted := *u
+	redacted.RawQuery = l.redactValues(u.Query()).Encode()
+	return redacted.String()
+}
+
+func (l *Logger) redactValues(values url.Values) url.Values {
+	for key := range values {
+		if l.redacted[strings.ToLower(key)] {
+			values[key] = []string{Redacted}
+		}
+	}
+	return values
+}
+
+func (l *Logger) redactJSON(data []byte) string {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return ""
+	}
+	redacted, err := json.Marshal(l.redact(value))
+	if err != nil {
+		return ""
+	}
+	return string(redacted)
+}
+
+func (l *Logger) redact(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if l.redacted[strings.ToLower(key)] {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = l.redact(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = l.redact(item)
+		}
+	}
+	return value
+}
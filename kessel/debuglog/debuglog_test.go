@@ -0,0 +1,214 @@
+package debuglog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newTestLogger(options ...Option) (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return New(slog.New(handler), options...), &buf
+}
+
+func lastEntry(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry %q: %v", buf.String(), err)
+	}
+	return entry
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name          string
+		payloads      bool
+		invokeErr     error
+		expectedCode  string
+		expectPayload bool
+	}{
+		{name: "logs success without payloads", expectedCode: "OK"},
+		{name: "logs status code on error", invokeErr: status.Error(codes.PermissionDenied, "denied"), expectedCode: "PermissionDenied"},
+		{name: "logs redacted payloads", payloads: true, expectedCode: "OK", expectPayload: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(WithPayloads(tt.payloads))
+			req, _ := structpb.NewStruct(map[string]any{"resource": "host", "client_secret": "s3cret"})
+			reply, _ := structpb.NewStruct(map[string]any{"allowed": true})
+
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				return tt.invokeErr
+			}
+			err := UnaryClientInterceptor(logger)(context.Background(), "/svc/Check", req, reply, nil, invoker)
+			if !errors.Is(err, tt.invokeErr) {
+				t.Fatalf("Expected error %v, got %v", tt.invokeErr, err)
+			}
+
+			entry := lastEntry(t, buf)
+			if entry["method"] != "/svc/Check" {
+				t.Errorf("Expected method to be logged, got %v", entry["method"])
+			}
+			if entry["code"] != tt.expectedCode {
+				t.Errorf("Expected code %s, got %v", tt.expectedCode, entry["code"])
+			}
+			request, _ := entry["request"].(string)
+			if tt.expectPayload {
+				if strings.Contains(request, "s3cret") || !strings.Contains(request, Redacted) {
+					t.Errorf("Expected client_secret to be redacted, got %s", request)
+				}
+				if !strings.Contains(request, "host") {
+					t.Errorf("Expected other fields to be kept, got %s", request)
+				}
+			} else if request != "" {
+				t.Errorf("Expected no payload, got %s", request)
+			}
+		})
+	}
+}
+
+func TestLogger_toggle(t *testing.T) {
+	logger, buf := newTestLogger()
+	logger.SetEnabled(false)
+
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	_ = UnaryClientInterceptor(logger)(context.Background(), "/svc/Check", nil, nil, nil, invoker)
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing logged while disabled, got %s", buf.String())
+	}
+
+	logger.SetEnabled(true)
+	_ = UnaryClientInterceptor(logger)(context.Background(), "/svc/Check", nil, nil, nil, invoker)
+	if buf.Len() == 0 {
+		t.Error("Expected a log entry once enabled")
+	}
+
+	var nilLogger *Logger
+	if err := UnaryClientInterceptor(nilLogger)(context.Background(), "/svc/Check", nil, nil, nil, invoker); err != nil {
+		t.Errorf("Expected nil logger to pass calls through, got %v", err)
+	}
+}
+
+func TestTransport_redactsFormAndJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"tok-123","expires_in":300}`))
+	}))
+	defer server.Close()
+
+	logger, buf := newTestLogger(WithPayloads(true))
+	client := &http.Client{Transport: NewTransport(logger, nil)}
+
+	form := url.Values{"grant_type": {"client_credentials"}, "client_secret": {"s3cret"}}
+	request, _ := http.NewRequest(http.MethodPost, server.URL+"/token", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	if !strings.Contains(string(body), "tok-123") {
+		t.Errorf("Expected the caller to still receive the response body, got %s", body)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "s3cret") || strings.Contains(output, "tok-123") {
+		t.Errorf("Expected secrets to be redacted, got %s", output)
+	}
+	entry := lastEntry(t, buf)
+	if entry["status"] != float64(http.StatusOK) {
+		t.Errorf("Expected status 200, got %v", entry["status"])
+	}
+	if !strings.Contains(entry["request"].(string), "client_credentials") {
+		t.Errorf("Expected non-secret form fields to be kept, got %v", entry["request"])
+	}
+}
+
+func TestTransport_redactsQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	logger, buf := newTestLogger()
+	client := &http.Client{Transport: NewTransport(logger, nil)}
+
+	response, err := client.Get(server.URL + "/api?type=default&token=abc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	response.Body.Close()
+
+	output := buf.String()
+	if strings.Contains(output, "abc") {
+		t.Errorf("Expected token query parameter to be redacted, got %s", output)
+	}
+	if !strings.Contains(output, "type=default") {
+		t.Errorf("Expected other query parameters to be kept, got %s", output)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func TestTransport_bodyReadErrors(t *testing.T) {
+	readErr := errors.New("connection reset")
+	base := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(io.MultiReader(strings.NewReader(`{"partial":`), errorReader{readErr})),
+		}, nil
+	})
+	logger, buf := newTestLogger(WithPayloads(true))
+	client := &http.Client{Transport: NewTransport(logger, base)}
+
+	request, _ := http.NewRequest(http.MethodPost, "http://kessel.test/api", strings.NewReader(`{"a":1}`))
+	request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(io.MultiReader(strings.NewReader(`{"a":`), errorReader{readErr})), nil
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if !errors.Is(err, readErr) {
+		t.Errorf("Expected the read error to reach the caller, got %v", err)
+	}
+	if string(body) != `{"partial":` {
+		t.Errorf("Expected the data read before the error, got %q", body)
+	}
+
+	entry := lastEntry(t, buf)
+	if _, ok := entry["request"]; ok {
+		t.Errorf("Expected no request body to be logged, got %v", entry["request"])
+	}
+	if _, ok := entry["response"]; ok {
+		t.Errorf("Expected no response body to be logged, got %v", entry["response"])
+	}
+}
@@ -0,0 +1,283 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxDetailBytes bounds how much of an HTTP error body is read for its detail
+// message.
+const maxDetailBytes = 4096
+
+// Category classifies a failed Kessel or RBAC call independently of whether it
+// was made over gRPC or HTTP.
+type Category int
+
+const (
+	// CategoryUnknown covers errors that fit no other category, such as
+	// cancellation or deadline expiry.
+	CategoryUnknown Category = iota
+	// CategoryPermissionDenied means the caller is unauthenticated or lacks
+	// permission.
+	CategoryPermissionDenied
+	// CategoryNotFound means the requested resource does not exist.
+	CategoryNotFound
+	// CategoryInvalidArgument means the request was rejected as malformed.
+	CategoryInvalidArgument
+	// CategoryRateLimited means the server asked the caller to slow down.
+	CategoryRateLimited
+	// CategoryServerError means the server failed or was unavailable; the call
+	// may succeed if retried.
+	CategoryServerError
+)
+
+func (c Category) String() string {
+	switch c {
+	case CategoryPermissionDenied:
+		return "permission denied"
+	case CategoryNotFound:
+		return "not found"
+	case CategoryInvalidArgument:
+		return "invalid argument"
+	case CategoryRateLimited:
+		return "rate limited"
+	case CategoryServerError:
+		return "server error"
+	default:
+		return "unknown"
+	}
+}
+
+// Sentinels matched by errors.Is for an *APIError of the corresponding
+// category.
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrNotFound         = errors.New("not found")
+	ErrInvalidArgument  = errors.New("invalid argument")
+	ErrRateLimited      = errors.New("rate limited")
+	ErrServerError      = errors.New("server error")
+)
+
+// APIError is a categorised error returned by the server. Exactly one of Code
+// (gRPC) and HTTPStatus (REST) describes where it came from.
+type APIError struct {
+	Category Category
+	// Code is the gRPC status code; codes.OK for HTTP errors.
+	Code codes.Code
+	// HTTPStatus is the HTTP status code; 0 for gRPC errors.
+	HTTPStatus int
+	// Status is the HTTP status line, e.g. "404 Not Found".
+	Status string
+	// Message is the detail message sent by the server, if any.
+	Message string
+	// Url is the request URL of HTTP errors.
+	Url string
+	// Details are the entries of an RBAC {"errors": [...]} body, if any.
+	Details []HTTPErrorDetail
+	// FieldViolations are the fields named by a gRPC google.rpc.BadRequest
+	// detail, if any.
+	FieldViolations FieldViolations
+
+	grpcStatus *status.Status
+}
+
+// HTTPErrorDetail is one entry of the error list in an RBAC error body.
+type HTTPErrorDetail struct {
+	Detail string
+	Source string
+	// Status is the HTTP status the entry reports, e.g. "403".
+	Status string
+}
+
+func (e *APIError) Error() string {
+	if e.HTTPStatus != 0 {
+		if e.Message == "" {
+			return "http status " + e.Status
+		}
+		return "http status " + e.Status + ": " + e.Message
+	}
+	return e.grpcStatus.Err().Error()
+}
+
+// Is reports whether target is the sentinel for the error's category.
+func (e *APIError) Is(target error) bool {
+	switch e.Category {
+	case CategoryPermissionDenied:
+		return target == ErrPermissionDenied
+	case CategoryNotFound:
+		return target == ErrNotFound
+	case CategoryInvalidArgument:
+		return target == ErrInvalidArgument
+	case CategoryRateLimited:
+		return target == ErrRateLimited
+	case CategoryServerError:
+		return target == ErrServerError
+	default:
+		return false
+	}
+}
+
+// GRPCStatus returns the original status, including any details, for errors
+// that came from gRPC, and nil for HTTP errors.
+func (e *APIError) GRPCStatus() *status.Status {
+	return e.grpcStatus
+}
+
+// FromGRPCStatus converts a gRPC status error into an *APIError, keeping its
+// code, message and details. google.rpc.BadRequest details are also parsed
+// into FieldViolations. Errors that carry no gRPC status (including nil)
+// are returned unchanged.
+func FromGRPCStatus(err error) error {
+	st, ok := status.FromError(err)
+	if err == nil || !ok {
+		return err
+	}
+	return &APIError{
+		Category:        categoryFromCode(st.Code()),
+		Code:            st.Code(),
+		Message:         st.Message(),
+		FieldViolations: fieldViolationsFromStatus(st),
+		grpcStatus:      st,
+	}
+}
+
+// FromHTTPResponse converts a non-2xx response into an *APIError, reading the
+// detail message from the body (RBAC's {"errors": [{"detail": ...}]} or
+// {"detail": ...}, falling back to the raw text). 429 responses, and 503
+// responses with a Retry-After header, are wrapped in a *RateLimitedError. It
+// returns nil for 2xx responses. The caller still closes the body.
+func FromHTTPResponse(response *http.Response) error {
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	apiErr := &APIError{
+		Category:   categoryFromHTTPStatus(response.StatusCode),
+		HTTPStatus: response.StatusCode,
+		Status:     response.Status,
+	}
+	if response.Request != nil && response.Request.URL != nil {
+		apiErr.Url = response.Request.URL.String()
+	}
+	if response.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, maxDetailBytes))
+		apiErr.Message = detailMessage(body)
+		apiErr.Details = errorDetails(body)
+	}
+	return withHTTPRateLimit(apiErr, response.Header)
+}
+
+// IsPermissionDenied reports whether err is an *APIError of
+// CategoryPermissionDenied.
+func IsPermissionDenied(err error) bool {
+	return errors.Is(err, ErrPermissionDenied)
+}
+
+// IsNotFound reports whether err is an *APIError of CategoryNotFound.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsInvalidArgument reports whether err is an *APIError of
+// CategoryInvalidArgument.
+func IsInvalidArgument(err error) bool {
+	return errors.Is(err, ErrInvalidArgument)
+}
+
+// IsRateLimited reports whether err is an *APIError of CategoryRateLimited or
+// a *RateLimitedError.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsServerError reports whether err is an *APIError of CategoryServerError.
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServerError)
+}
+
+func categoryFromCode(code codes.Code) Category {
+	switch code {
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return CategoryPermissionDenied
+	case codes.NotFound:
+		return CategoryNotFound
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return CategoryInvalidArgument
+	case codes.ResourceExhausted:
+		return CategoryRateLimited
+	case codes.Internal, codes.Unavailable, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		return CategoryServerError
+	default:
+		return CategoryUnknown
+	}
+}
+
+func categoryFromHTTPStatus(statusCode int) Category {
+	switch {
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return CategoryPermissionDenied
+	case statusCode == http.StatusNotFound:
+		return CategoryNotFound
+	case statusCode == http.StatusBadRequest, statusCode == http.StatusUnprocessableEntity:
+		return CategoryInvalidArgument
+	case statusCode == http.StatusTooManyRequests:
+		return CategoryRateLimited
+	case statusCode >= 500:
+		return CategoryServerError
+	default:
+		return CategoryUnknown
+	}
+}
+
+func detailMessage(body []byte) string {
+	var payload struct {
+		Detail string `json:"detail"`
+		Errors []struct {
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		var details []string
+		for _, e := range payload.Errors {
+			if e.Detail != "" {
+				details = append(details, e.Detail)
+			}
+		}
+		if len(details) > 0 {
+			return strings.Join(details, "; ")
+		}
+		if payload.Detail != "" {
+			return payload.Detail
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// errorDetails parses the RBAC error list in body. RBAC sends the status of
+// each entry as a string, but numbers are accepted too.
+func errorDetails(body []byte) []HTTPErrorDetail {
+	var payload struct {
+		Errors []struct {
+			Detail string          `json:"detail"`
+			Source string          `json:"source"`
+			Status json.RawMessage `json:"status"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+	var details []HTTPErrorDetail
+	for _, e := range payload.Errors {
+		details = append(details, HTTPErrorDetail{
+			Detail: e.Detail,
+			Source: e.Source,
+			Status: strings.Trim(string(e.Status), `"`),
+		})
+	}
+	return details
+}
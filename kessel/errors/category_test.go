@@ -0,0 +1,183 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFromGRPCStatus(t *testing.T) {
+	tests := []struct {
+		name             string
+		err              error
+		expectedCategory Category
+		expectedSentinel error
+	}{
+		{name: "permission denied", err: status.Error(codes.PermissionDenied, "denied"), expectedCategory: CategoryPermissionDenied, expectedSentinel: ErrPermissionDenied},
+		{name: "unauthenticated", err: status.Error(codes.Unauthenticated, "no token"), expectedCategory: CategoryPermissionDenied, expectedSentinel: ErrPermissionDenied},
+		{name: "not found", err: status.Error(codes.NotFound, "missing"), expectedCategory: CategoryNotFound, expectedSentinel: ErrNotFound},
+		{name: "invalid argument", err: status.Error(codes.InvalidArgument, "bad"), expectedCategory: CategoryInvalidArgument, expectedSentinel: ErrInvalidArgument},
+		{name: "resource exhausted", err: status.Error(codes.ResourceExhausted, "slow down"), expectedCategory: CategoryRateLimited, expectedSentinel: ErrRateLimited},
+		{name: "unavailable", err: status.Error(codes.Unavailable, "down"), expectedCategory: CategoryServerError, expectedSentinel: ErrServerError},
+		{name: "canceled", err: status.Error(codes.Canceled, "canceled"), expectedCategory: CategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromGRPCStatus(tt.err)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected *APIError, got %v", err)
+			}
+			if apiErr.Category != tt.expectedCategory {
+				t.Errorf("Expected category %v, got %v", tt.expectedCategory, apiErr.Category)
+			}
+			if apiErr.Code != status.Code(tt.err) {
+				t.Errorf("Expected code %v, got %v", status.Code(tt.err), apiErr.Code)
+			}
+			if tt.expectedSentinel != nil && !errors.Is(err, tt.expectedSentinel) {
+				t.Errorf("Expected errors.Is to match %v", tt.expectedSentinel)
+			}
+			if err.Error() != tt.err.Error() {
+				t.Errorf("Error() = %q, expected %q", err.Error(), tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestFromGRPCStatus_preservesDetails(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "bad request").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "relation", Description: "required"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build status: %v", err)
+	}
+
+	converted := FromGRPCStatus(st.Err())
+	if details := status.Convert(converted).Details(); len(details) != 1 {
+		t.Errorf("Expected status details to be preserved, got %v", details)
+	}
+}
+
+func TestFromGRPCStatus_nonStatusErrors(t *testing.T) {
+	if FromGRPCStatus(nil) != nil {
+		t.Error("Expected nil for a nil error")
+	}
+	if err := FromGRPCStatus(io.EOF); err != io.EOF {
+		t.Errorf("Expected non-status errors to be returned unchanged, got %v", err)
+	}
+}
+
+func TestFromHTTPResponse(t *testing.T) {
+	tests := []struct {
+		name             string
+		statusCode       int
+		body             string
+		expectedCategory Category
+		expectedMessage  string
+		expectedNil      bool
+	}{
+		{name: "ok", statusCode: http.StatusOK, expectedNil: true},
+		{name: "rbac error list", statusCode: http.StatusForbidden, body: `{"errors":[{"detail":"no access","status":"403"}]}`, expectedCategory: CategoryPermissionDenied, expectedMessage: "no access"},
+		{name: "detail field", statusCode: http.StatusNotFound, body: `{"detail":"Not found."}`, expectedCategory: CategoryNotFound, expectedMessage: "Not found."},
+		{name: "plain text", statusCode: http.StatusBadRequest, body: "bad org id\n", expectedCategory: CategoryInvalidArgument, expectedMessage: "bad org id"},
+		{name: "too many requests", statusCode: http.StatusTooManyRequests, expectedCategory: CategoryRateLimited},
+		{name: "server error", statusCode: http.StatusBadGateway, expectedCategory: CategoryServerError},
+		{name: "conflict", statusCode: http.StatusConflict, expectedCategory: CategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &http.Response{
+				StatusCode: tt.statusCode,
+				Status:     http.StatusText(tt.statusCode),
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			err := FromHTTPResponse(response)
+			if tt.expectedNil {
+				if err != nil {
+					t.Errorf("Expected nil, got %v", err)
+				}
+				return
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected *APIError, got %v", err)
+			}
+			if apiErr.Category != tt.expectedCategory {
+				t.Errorf("Expected category %v, got %v", tt.expectedCategory, apiErr.Category)
+			}
+			if apiErr.HTTPStatus != tt.statusCode {
+				t.Errorf("Expected status %d, got %d", tt.statusCode, apiErr.HTTPStatus)
+			}
+			if apiErr.Message != tt.expectedMessage {
+				t.Errorf("Expected message %q, got %q", tt.expectedMessage, apiErr.Message)
+			}
+			if apiErr.GRPCStatus() != nil {
+				t.Error("Expected no gRPC status for an HTTP error")
+			}
+		})
+	}
+}
+
+func TestFromHTTPResponse_requestDetails(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "http://rbac/api/rbac/v2/workspaces/?type=root", nil)
+	response := &http.Response{
+		StatusCode: http.StatusForbidden,
+		Status:     "403 Forbidden",
+		Request:    request,
+		Body:       io.NopCloser(strings.NewReader(`{"errors":[{"detail":"no access","source":"workspace","status":"403"},{"detail":"also","status":403}]}`)),
+	}
+
+	var apiErr *APIError
+	if !errors.As(FromHTTPResponse(response), &apiErr) {
+		t.Fatal("Expected *APIError")
+	}
+	if apiErr.Url != "http://rbac/api/rbac/v2/workspaces/?type=root" {
+		t.Errorf("Expected request URL, got %q", apiErr.Url)
+	}
+	expected := []HTTPErrorDetail{{Detail: "no access", Source: "workspace", Status: "403"}, {Detail: "also", Status: "403"}}
+	if len(apiErr.Details) != len(expected) {
+		t.Fatalf("Expected %d details, got %v", len(expected), apiErr.Details)
+	}
+	for i, detail := range expected {
+		if apiErr.Details[i] != detail {
+			t.Errorf("Detail %d: expected %+v, got %+v", i, detail, apiErr.Details[i])
+		}
+	}
+}
+
+func TestCategoryCheckers(t *testing.T) {
+	checkers := map[string]struct {
+		check func(error) bool
+		code  codes.Code
+	}{
+		"IsPermissionDenied": {IsPermissionDenied, codes.PermissionDenied},
+		"IsNotFound":         {IsNotFound, codes.NotFound},
+		"IsInvalidArgument":  {IsInvalidArgument, codes.InvalidArgument},
+		"IsRateLimited":      {IsRateLimited, codes.ResourceExhausted},
+		"IsServerError":      {IsServerError, codes.Unavailable},
+	}
+
+	for name, checker := range checkers {
+		t.Run(name, func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", FromGRPCStatus(status.Error(checker.code, "failed")))
+			if !checker.check(err) {
+				t.Errorf("Expected %s to match %v", name, err)
+			}
+			if checker.check(FromGRPCStatus(status.Error(codes.Canceled, "canceled"))) {
+				t.Errorf("Expected %s not to match a canceled call", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCircuitOpen is returned without contacting the server when a circuit
+// breaker has opened after repeated failures.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// ErrReadOnlyClient is returned without contacting the server when a client
+// built in read-only mode is asked to make a call that mutates inventory.
+var ErrReadOnlyClient = errors.New("client is read-only")
+
+// ErrAuthenticationRequired is returned without contacting the server when an
+// unauthenticated client calls a method outside its allowlist.
+var ErrAuthenticationRequired = errors.New("method requires authentication")
+
+// ErrSubjectTokenRequired is returned when token exchange credentials are used
+// with a context that carries no subject token (see auth.WithSubjectToken).
+var ErrSubjectTokenRequired = errors.New("subject token is required for token exchange")
+
+// ErrMessageTooLarge matches a *MessageSizeError with errors.Is.
+var ErrMessageTooLarge = errors.New("message too large")
+
+// MessageSizeError is returned without contacting the server when a request
+// is larger than the client's maximum send message size. It converts to the
+// ResourceExhausted gRPC status the transport would have returned.
+type MessageSizeError struct {
+	Method string
+	// Size is the serialized size of the message in bytes.
+	Size  int
+	Limit int
+}
+
+func (e *MessageSizeError) Error() string {
+	return fmt.Sprintf("%s: %s request is %d bytes, over the %d byte limit", ErrMessageTooLarge, e.Method, e.Size, e.Limit)
+}
+
+func (e *MessageSizeError) Is(target error) bool {
+	return target == ErrMessageTooLarge
+}
+
+// GRPCStatus lets status.FromError and status.Code treat the error as
+// ResourceExhausted.
+func (e *MessageSizeError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.Error())
+}
+
+// RequestIdError annotates an error with the request ID
+// (x-rh-insights-request-id) sent with the failed call, so it can be found in
+// server logs.
+type RequestIdError struct {
+	RequestId string
+	Err       error
+}
+
+func (e *RequestIdError) Error() string {
+	return fmt.Sprintf("%v (request id %s)", e.Err, e.RequestId)
+}
+
+func (e *RequestIdError) Unwrap() error {
+	return e.Err
+}
+
+// RequestId returns the request ID attached to err by a *RequestIdError, or "".
+func RequestId(err error) string {
+	var requestIdErr *RequestIdError
+	if errors.As(err, &requestIdErr) {
+		return requestIdErr.RequestId
+	}
+	return ""
+}
+
+// Violation is a single failed validation rule.
+type Violation struct {
+	// Path of the offending field, e.g. "items[0].object.resource_id".
+	Field string
+	// Rule that failed, e.g. "string.min_len".
+	Rule    string
+	Message string
+}
+
+// ValidationError is returned when a request fails client-side validation.
+// It converts to an InvalidArgument gRPC status with a google.rpc.BadRequest
+// detail, matching what the server would have returned.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		parts[i] = violation.Field + ": " + violation.Message
+	}
+	return "invalid request: " + strings.Join(parts, "; ")
+}
+
+// GRPCStatus lets status.FromError and status.Code treat the error as
+// InvalidArgument.
+func (e *ValidationError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range e.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: violation.Field, Description: violation.Message})
+	}
+	if withDetails, err := st.WithDetails(badRequest); err == nil {
+		return withDetails
+	}
+	return st
+}
+
+// ItemError is a single failure within a Multi. Index is the position of the
+// failed item in the caller's input, or -1 when the failure is not tied to an
+// input position. Key identifies the item (e.g. a resource ID or option name).
+type ItemError struct {
+	Index int
+	Key   string
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	switch {
+	case e.Key != "" && e.Index >= 0:
+		return fmt.Sprintf("item %d (%s): %v", e.Index, e.Key, e.Err)
+	case e.Key != "":
+		return fmt.Sprintf("%s: %v", e.Key, e.Err)
+	case e.Index >= 0:
+		return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+	default:
+		return e.Err.Error()
+	}
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// Multi aggregates several independent failures, such as all invalid builder
+// options or every failed item of a batch operation. errors.Is and errors.As
+// match against each of the aggregated errors.
+type Multi struct {
+	Errors []*ItemError
+}
+
+// Append records err for the item at index identified by key. A nil err is
+// ignored.
+func (m *Multi) Append(index int, key string, err error) {
+	if err == nil {
+		return
+	}
+	m.Errors = append(m.Errors, &ItemError{Index: index, Key: key, Err: err})
+}
+
+// Len returns the number of aggregated errors.
+func (m *Multi) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.Errors)
+}
+
+// ErrorOrNil returns m if it holds at least one error and nil otherwise, so a
+// Multi can be returned as an error without producing a non-nil empty value.
+func (m *Multi) ErrorOrNil() error {
+	if m.Len() == 0 {
+		return nil
+	}
+	return m
+}
+
+func (m *Multi) Error() string {
+	if len(m.Errors) == 1 {
+		return m.Errors[0].Error()
+	}
+	messages := make([]string, len(m.Errors))
+	for i, err := range m.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(m.Errors), strings.Join(messages, "; "))
+}
+
+func (m *Multi) Unwrap() []error {
+	errs := make([]error, len(m.Errors))
+	for i, err := range m.Errors {
+		errs[i] = err
+	}
+	return errs
+}
@@ -0,0 +1,124 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestItemError_Error(t *testing.T) {
+	cause := errors.New("boom")
+	tests := []struct {
+		name     string
+		err      *ItemError
+		expected string
+	}{
+		{name: "index and key", err: &ItemError{Index: 2, Key: "host-1", Err: cause}, expected: "item 2 (host-1): boom"},
+		{name: "key only", err: &ItemError{Index: -1, Key: "WithRateLimit", Err: cause}, expected: "WithRateLimit: boom"},
+		{name: "index only", err: &ItemError{Index: 0, Err: cause}, expected: "item 0: boom"},
+		{name: "no metadata", err: &ItemError{Index: -1, Err: cause}, expected: "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("Error() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMulti(t *testing.T) {
+	var m Multi
+	if m.ErrorOrNil() != nil {
+		t.Error("Expected empty Multi to produce a nil error")
+	}
+
+	m.Append(0, "a", nil)
+	if m.Len() != 0 {
+		t.Errorf("Expected nil errors to be ignored, got %d", m.Len())
+	}
+
+	m.Append(0, "a", io.EOF)
+	m.Append(3, "b", ErrCircuitOpen)
+
+	err := m.ErrorOrNil()
+	if err == nil {
+		t.Fatal("Expected non-nil error")
+	}
+	if !errors.Is(err, io.EOF) || !errors.Is(err, ErrCircuitOpen) {
+		t.Error("Expected errors.Is to match every aggregated error")
+	}
+
+	var item *ItemError
+	if !errors.As(err, &item) || item.Key != "a" {
+		t.Errorf("Expected errors.As to find the first item error, got %v", item)
+	}
+
+	expected := "2 errors occurred: item 0 (a): EOF; item 3 (b): circuit breaker is open"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, expected %q", err.Error(), expected)
+	}
+}
+
+func TestMulti_nilLen(t *testing.T) {
+	var m *Multi
+	if m.Len() != 0 || m.ErrorOrNil() != nil {
+		t.Error("Expected nil Multi to be empty")
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{Violations: []Violation{
+		{Field: "object.resource_id", Rule: "string.min_len", Message: "value length must be at least 1 characters"},
+		{Field: "relation", Rule: "string.min_len", Message: "value length must be at least 1 characters"},
+	}}
+
+	expected := "invalid request: object.resource_id: value length must be at least 1 characters; relation: value length must be at least 1 characters"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, expected %q", err.Error(), expected)
+	}
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("Expected code InvalidArgument, got %v", code)
+	}
+}
+
+func TestMessageSizeError(t *testing.T) {
+	err := fmt.Errorf("reporting: %w", &MessageSizeError{Method: "/kessel.inventory.v1beta2.KesselInventoryService/ReportResource", Size: 5000, Limit: 4096})
+
+	expected := "reporting: message too large: /kessel.inventory.v1beta2.KesselInventoryService/ReportResource request is 5000 bytes, over the 4096 byte limit"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, expected %q", err.Error(), expected)
+	}
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Error("Expected errors.Is to match ErrMessageTooLarge")
+	}
+	var sizeErr *MessageSizeError
+	if !errors.As(err, &sizeErr) || sizeErr.Size != 5000 || sizeErr.Limit != 4096 {
+		t.Errorf("Expected errors.As to expose the size and limit, got %v", sizeErr)
+	}
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Errorf("Expected code ResourceExhausted, got %v", code)
+	}
+}
+
+func TestRequestIdError(t *testing.T) {
+	err := fmt.Errorf("fetching workspace: %w", &RequestIdError{RequestId: "req-1", Err: io.EOF})
+
+	if got := RequestId(err); got != "req-1" {
+		t.Errorf("Expected request ID req-1, got %q", got)
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Error("Expected errors.Is to match the wrapped error")
+	}
+	if expected := "fetching workspace: EOF (request id req-1)"; err.Error() != expected {
+		t.Errorf("Error() = %q, expected %q", err.Error(), expected)
+	}
+	if got := RequestId(io.EOF); got != "" {
+		t.Errorf("Expected no request ID, got %q", got)
+	}
+}
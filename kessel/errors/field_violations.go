@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// FieldViolation is one field the server rejected, as reported in a
+// google.rpc.BadRequest status detail.
+type FieldViolation struct {
+	// Path of the offending field, e.g. "resource.reporter.type".
+	Field string
+	// Description is the server's explanation, suitable for showing to users.
+	Description string
+}
+
+// FieldViolations lists the fields rejected by an InvalidArgument call.
+type FieldViolations []FieldViolation
+
+// String joins the violations as "field: description" pairs.
+func (v FieldViolations) String() string {
+	parts := make([]string, len(v))
+	for i, violation := range v {
+		parts[i] = violation.Field + ": " + violation.Description
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FieldViolationsOf returns the field violations carried by err: the
+// google.rpc.BadRequest details of its gRPC status (including through an
+// *APIError or a wrapping error), or the violations of a client-side
+// *ValidationError. It returns nil when err names no fields.
+func FieldViolationsOf(err error) FieldViolations {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	return fieldViolationsFromStatus(st)
+}
+
+func fieldViolationsFromStatus(st *status.Status) FieldViolations {
+	var violations FieldViolations
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range badRequest.GetFieldViolations() {
+			violations = append(violations, FieldViolation{Field: violation.GetField(), Description: violation.GetDescription()})
+		}
+	}
+	return violations
+}
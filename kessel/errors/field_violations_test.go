@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func badRequestError(t *testing.T, violations ...*errdetails.BadRequest_FieldViolation) error {
+	t.Helper()
+	st, err := status.New(codes.InvalidArgument, "validation error").WithDetails(
+		&errdetails.ErrorInfo{Reason: "VALIDATION"},
+		&errdetails.BadRequest{FieldViolations: violations},
+	)
+	if err != nil {
+		t.Fatalf("Failed to build status: %v", err)
+	}
+	return st.Err()
+}
+
+func TestFieldViolationsOf(t *testing.T) {
+	serverErr := badRequestError(t,
+		&errdetails.BadRequest_FieldViolation{Field: "resource.reporter.type", Description: "value is required"},
+		&errdetails.BadRequest_FieldViolation{Field: "relation", Description: "value length must be at least 1 characters"},
+	)
+	expected := FieldViolations{
+		{Field: "resource.reporter.type", Description: "value is required"},
+		{Field: "relation", Description: "value length must be at least 1 characters"},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "status error", err: serverErr},
+		{name: "wrapped status error", err: fmt.Errorf("checking: %w", serverErr)},
+		{name: "APIError", err: FromGRPCStatus(serverErr)},
+		{name: "client-side ValidationError", err: &ValidationError{Violations: []Violation{
+			{Field: "resource.reporter.type", Rule: "required", Message: "value is required"},
+			{Field: "relation", Rule: "string.min_len", Message: "value length must be at least 1 characters"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FieldViolationsOf(tt.err); !reflect.DeepEqual(got, expected) {
+				t.Errorf("FieldViolationsOf() = %v, expected %v", got, expected)
+			}
+		})
+	}
+}
+
+func TestFieldViolationsOf_none(t *testing.T) {
+	for _, err := range []error{nil, io.EOF, status.Error(codes.InvalidArgument, "bad request"), status.Error(codes.NotFound, "missing")} {
+		if got := FieldViolationsOf(err); got != nil {
+			t.Errorf("FieldViolationsOf(%v) = %v, expected nil", err, got)
+		}
+	}
+}
+
+func TestFromGRPCStatus_fieldViolations(t *testing.T) {
+	err := FromGRPCStatus(badRequestError(t, &errdetails.BadRequest_FieldViolation{Field: "relation", Description: "required"}))
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+	if got := apiErr.FieldViolations.String(); got != "relation: required" {
+		t.Errorf("FieldViolations.String() = %q, expected %q", got, "relation: required")
+	}
+}
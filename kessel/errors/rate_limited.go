@@ -0,0 +1,148 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// pushbackMetadata is the trailer gRPC's own retry policy reads the server's
+// requested delay from, in milliseconds.
+const pushbackMetadata = "grpc-retry-pushback-ms"
+
+// RateLimitedError is returned when the server throttled a call. It carries
+// how long the server asked the caller to wait and the quota it reported, and
+// wraps the *APIError or gRPC status error it was built from, so
+// IsRateLimited, errors.As and status.Code keep working.
+type RateLimitedError struct {
+	// RetryAfter is the delay the server asked for, or 0 when it sent none.
+	RetryAfter time.Duration
+	// Limit and Remaining are the quota reported in RateLimit-Limit and
+	// RateLimit-Remaining (or X-RateLimit-*), or -1 when not sent.
+	Limit     int
+	Remaining int
+	Err       error
+}
+
+func (e *RateLimitedError) Error() string {
+	if e.RetryAfter <= 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v (retry after %s)", e.Err, e.RetryAfter)
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrRateLimited, also for 503 and Unavailable responses that
+// asked the caller to back off.
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RetryAfter returns the delay the server asked for when it throttled the
+// call that returned err, and whether it asked for one.
+func RetryAfter(err error) (time.Duration, bool) {
+	var rateLimitedErr *RateLimitedError
+	if errors.As(err, &rateLimitedErr) && rateLimitedErr.RetryAfter > 0 {
+		return rateLimitedErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// FromGRPCTrailer turns a ResourceExhausted error, or an Unavailable error
+// that names a delay, into a *RateLimitedError. The delay is read from the
+// status's RetryInfo detail, then from the "retry-after" (seconds) and
+// "grpc-retry-pushback-ms" trailers; the quota from "ratelimit-limit" and
+// "ratelimit-remaining". Other errors, including nil, are returned unchanged.
+func FromGRPCTrailer(err error, trailer metadata.MD) error {
+	st, ok := status.FromError(err)
+	if err == nil || !ok {
+		return err
+	}
+	header := func(key string) string {
+		if values := trailer.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	rateLimitedErr := rateLimitFromHeaders(err, header, time.Now())
+	for _, detail := range st.Details() {
+		if retryInfo, ok := detail.(*errdetails.RetryInfo); ok && retryInfo.GetRetryDelay() != nil {
+			rateLimitedErr.RetryAfter = retryInfo.GetRetryDelay().AsDuration()
+		}
+	}
+	if rateLimitedErr.RetryAfter == 0 {
+		if millis, err := strconv.Atoi(header(pushbackMetadata)); err == nil && millis > 0 {
+			rateLimitedErr.RetryAfter = time.Duration(millis) * time.Millisecond
+		}
+	}
+
+	switch {
+	case st.Code() == codes.ResourceExhausted:
+	case st.Code() == codes.Unavailable && rateLimitedErr.RetryAfter > 0:
+	default:
+		return err
+	}
+	return rateLimitedErr
+}
+
+// withHTTPRateLimit wraps 429 responses, and 503 responses that name a delay,
+// in a *RateLimitedError.
+func withHTTPRateLimit(apiErr *APIError, header http.Header) error {
+	rateLimitedErr := rateLimitFromHeaders(apiErr, header.Get, time.Now())
+	switch {
+	case apiErr.HTTPStatus == http.StatusTooManyRequests:
+	case apiErr.HTTPStatus == http.StatusServiceUnavailable && rateLimitedErr.RetryAfter > 0:
+	default:
+		return apiErr
+	}
+	return rateLimitedErr
+}
+
+// rateLimitFromHeaders reads Retry-After (seconds or an HTTP date), falling
+// back to RateLimit-Reset (seconds) and X-RateLimit-Reset (seconds, or a Unix
+// time), and the RateLimit-Limit/-Remaining quota. Header names are looked up
+// in lower case for gRPC metadata; http.Header.Get canonicalises them.
+func rateLimitFromHeaders(cause error, header func(string) string, now time.Time) *RateLimitedError {
+	rateLimitedErr := &RateLimitedError{
+		Limit:     headerInt(header, "ratelimit-limit", "x-ratelimit-limit"),
+		Remaining: headerInt(header, "ratelimit-remaining", "x-ratelimit-remaining"),
+		Err:       cause,
+	}
+	if retryAfter := strings.TrimSpace(header("retry-after")); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			rateLimitedErr.RetryAfter = time.Duration(max(seconds, 0)) * time.Second
+		} else if date, err := http.ParseTime(retryAfter); err == nil {
+			rateLimitedErr.RetryAfter = max(date.Sub(now), 0)
+		}
+		return rateLimitedErr
+	}
+	if reset := headerInt(header, "ratelimit-reset", "x-ratelimit-reset"); reset > 0 {
+		// Some servers send the reset as a Unix time rather than a delay.
+		if unix := time.Unix(int64(reset), 0); unix.After(now.AddDate(-1, 0, 0)) {
+			rateLimitedErr.RetryAfter = max(unix.Sub(now), 0)
+		} else {
+			rateLimitedErr.RetryAfter = time.Duration(reset) * time.Second
+		}
+	}
+	return rateLimitedErr
+}
+
+func headerInt(header func(string) string, keys ...string) int {
+	for _, key := range keys {
+		if value, err := strconv.Atoi(strings.TrimSpace(header(key))); err == nil {
+			return value
+		}
+	}
+	return -1
+}
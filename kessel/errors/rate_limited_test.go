@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestFromHTTPResponse_rateLimited(t *testing.T) {
+	tests := []struct {
+		name               string
+		statusCode         int
+		header             http.Header
+		expectedRateLimit  bool
+		expectedRetryAfter time.Duration
+		expectedLimit      int
+		expectedRemaining  int
+	}{
+		{name: "retry after seconds", statusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"30"}}, expectedRateLimit: true, expectedRetryAfter: 30 * time.Second, expectedLimit: -1, expectedRemaining: -1},
+		{name: "ratelimit headers", statusCode: http.StatusTooManyRequests, header: http.Header{"Ratelimit-Limit": {"100"}, "Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"12"}}, expectedRateLimit: true, expectedRetryAfter: 12 * time.Second, expectedLimit: 100, expectedRemaining: 0},
+		{name: "x-ratelimit headers", statusCode: http.StatusTooManyRequests, header: http.Header{"X-Ratelimit-Limit": {"50"}, "X-Ratelimit-Remaining": {"3"}}, expectedRateLimit: true, expectedLimit: 50, expectedRemaining: 3},
+		{name: "no headers", statusCode: http.StatusTooManyRequests, expectedRateLimit: true, expectedLimit: -1, expectedRemaining: -1},
+		{name: "unavailable with retry after", statusCode: http.StatusServiceUnavailable, header: http.Header{"Retry-After": {"5"}}, expectedRateLimit: true, expectedRetryAfter: 5 * time.Second, expectedLimit: -1, expectedRemaining: -1},
+		{name: "unavailable without retry after", statusCode: http.StatusServiceUnavailable},
+		{name: "forbidden", statusCode: http.StatusForbidden, header: http.Header{"Retry-After": {"5"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &http.Response{
+				StatusCode: tt.statusCode,
+				Status:     http.StatusText(tt.statusCode),
+				Header:     tt.header,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}
+
+			err := FromHTTPResponse(response)
+			var rateLimitedErr *RateLimitedError
+			if !errors.As(err, &rateLimitedErr) {
+				if tt.expectedRateLimit {
+					t.Fatalf("Expected *RateLimitedError, got %v", err)
+				}
+				return
+			}
+			if !tt.expectedRateLimit {
+				t.Fatalf("Expected no *RateLimitedError, got %v", err)
+			}
+			if rateLimitedErr.RetryAfter != tt.expectedRetryAfter {
+				t.Errorf("Expected RetryAfter %s, got %s", tt.expectedRetryAfter, rateLimitedErr.RetryAfter)
+			}
+			if rateLimitedErr.Limit != tt.expectedLimit || rateLimitedErr.Remaining != tt.expectedRemaining {
+				t.Errorf("Expected quota %d/%d, got %d/%d", tt.expectedRemaining, tt.expectedLimit, rateLimitedErr.Remaining, rateLimitedErr.Limit)
+			}
+			if !IsRateLimited(err) {
+				t.Error("Expected IsRateLimited to match")
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.HTTPStatus != tt.statusCode {
+				t.Errorf("Expected the *APIError to be wrapped, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRateLimitFromHeaders_retryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected time.Duration
+	}{
+		{name: "http date", header: http.Header{"Retry-After": {"Fri, 02 Jan 2026 15:05:30 GMT"}}, expected: 90 * time.Second},
+		{name: "past http date", header: http.Header{"Retry-After": {"Fri, 02 Jan 2026 15:00:00 GMT"}}},
+		{name: "negative seconds", header: http.Header{"Retry-After": {"-3"}}},
+		{name: "invalid", header: http.Header{"Retry-After": {"soon"}}},
+		{name: "unix reset", header: http.Header{"X-Ratelimit-Reset": {"1767366300"}}, expected: 60 * time.Second},
+		{name: "retry after wins over reset", header: http.Header{"Retry-After": {"2"}, "Ratelimit-Reset": {"10"}}, expected: 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rateLimitFromHeaders(ErrRateLimited, tt.header.Get, now).RetryAfter
+			if got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFromGRPCTrailer(t *testing.T) {
+	withRetryInfo, err := status.New(codes.ResourceExhausted, "slow down").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name               string
+		err                error
+		trailer            metadata.MD
+		expectedRateLimit  bool
+		expectedRetryAfter time.Duration
+	}{
+		{name: "retry info", err: withRetryInfo.Err(), expectedRateLimit: true, expectedRetryAfter: 1500 * time.Millisecond},
+		{name: "retry-after trailer", err: status.Error(codes.ResourceExhausted, "slow down"), trailer: metadata.Pairs("retry-after", "4"), expectedRateLimit: true, expectedRetryAfter: 4 * time.Second},
+		{name: "pushback trailer", err: status.Error(codes.ResourceExhausted, "slow down"), trailer: metadata.Pairs("grpc-retry-pushback-ms", "250"), expectedRateLimit: true, expectedRetryAfter: 250 * time.Millisecond},
+		{name: "no delay", err: status.Error(codes.ResourceExhausted, "slow down"), expectedRateLimit: true},
+		{name: "unavailable with delay", err: status.Error(codes.Unavailable, "draining"), trailer: metadata.Pairs("retry-after", "1"), expectedRateLimit: true, expectedRetryAfter: time.Second},
+		{name: "unavailable without delay", err: status.Error(codes.Unavailable, "down")},
+		{name: "other code", err: status.Error(codes.NotFound, "missing"), trailer: metadata.Pairs("retry-after", "1")},
+		{name: "non-status error", err: errors.New("boom")},
+		{name: "nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromGRPCTrailer(tt.err, tt.trailer)
+			retryAfter, ok := RetryAfter(err)
+			if retryAfter != tt.expectedRetryAfter || ok != (tt.expectedRetryAfter > 0) {
+				t.Errorf("Expected RetryAfter %s, got %s (%v)", tt.expectedRetryAfter, retryAfter, ok)
+			}
+			if IsRateLimited(err) != tt.expectedRateLimit {
+				t.Errorf("Expected IsRateLimited %v, got %v", tt.expectedRateLimit, IsRateLimited(err))
+			}
+			if !tt.expectedRateLimit && err != tt.err {
+				t.Errorf("Expected the error unchanged, got %v", err)
+			}
+			if status.Code(err) != status.Code(tt.err) {
+				t.Errorf("Expected code %v, got %v", status.Code(tt.err), status.Code(err))
+			}
+		})
+	}
+}
+
+func TestRateLimitedError_Error(t *testing.T) {
+	err := &RateLimitedError{RetryAfter: 3 * time.Second, Err: status.Error(codes.ResourceExhausted, "slow down")}
+	expected := "rpc error: code = ResourceExhausted desc = slow down (retry after 3s)"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// CallCredentialsFunc adapts a function to credentials.PerRPCCredentials. It
+// does not require transport security, so it suits non-secret metadata such
+// as an org-id header.
+type CallCredentialsFunc func(ctx context.Context, uri ...string) (map[string]string, error)
+
+func (f CallCredentialsFunc) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return f(ctx, uri...)
+}
+
+func (f CallCredentialsFunc) RequireTransportSecurity() bool {
+	return false
+}
+
+type compositeCredentials struct {
+	sources []credentials.PerRPCCredentials
+}
+
+// CompositeCredentials combines several per-RPC credential sources into one,
+// e.g. an OAuth2 bearer token, an org-id header provider and a request signer.
+// Metadata is collected from each source in order; when two sources set the
+// same key the later one wins. The first error is returned as is. Transport
+// security is required if any source requires it. Nil sources are ignored.
+//
+//	creds := kesselgrpc.CompositeCredentials(
+//	    grpccreds.NewOAuth2(&oauthCreds),
+//	    kesselgrpc.CallCredentialsFunc(orgIdHeader),
+//	)
+//	client, conn, err := v1beta2.NewClientBuilder(target).Authenticated(creds, nil).Build()
+func CompositeCredentials(sources ...credentials.PerRPCCredentials) credentials.PerRPCCredentials {
+	composite := compositeCredentials{}
+	for _, source := range sources {
+		if source != nil {
+			composite.sources = append(composite.sources, source)
+		}
+	}
+	return composite
+}
+
+func (c compositeCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	merged := map[string]string{}
+	for _, source := range c.sources {
+		metadata, err := source.GetRequestMetadata(ctx, uri...)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range metadata {
+			merged[key] = value
+		}
+	}
+	return merged, nil
+}
+
+func (c compositeCredentials) RequireTransportSecurity() bool {
+	for _, source := range c.sources {
+		if source.RequireTransportSecurity() {
+			return true
+		}
+	}
+	return false
+}
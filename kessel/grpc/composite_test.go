@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"google.golang.org/grpc/credentials"
+)
+
+func staticCredentials(metadata map[string]string) CallCredentialsFunc {
+	return func(ctx context.Context, uri ...string) (map[string]string, error) {
+		return metadata, nil
+	}
+}
+
+func TestCompositeCredentials_GetRequestMetadata(t *testing.T) {
+	tests := []struct {
+		name          string
+		sources       []credentials.PerRPCCredentials
+		expected      map[string]string
+		expectedError bool
+	}{
+		{
+			name: "merges metadata from all sources",
+			sources: []credentials.PerRPCCredentials{
+				staticCredentials(map[string]string{"authorization": "Bearer token"}),
+				staticCredentials(map[string]string{"x-rh-rbac-org-id": "12345"}),
+			},
+			expected: map[string]string{"authorization": "Bearer token", "x-rh-rbac-org-id": "12345"},
+		},
+		{
+			name: "later sources win on conflicts",
+			sources: []credentials.PerRPCCredentials{
+				staticCredentials(map[string]string{"x-signature": "first"}),
+				staticCredentials(map[string]string{"x-signature": "second"}),
+			},
+			expected: map[string]string{"x-signature": "second"},
+		},
+		{
+			name: "returns the first error",
+			sources: []credentials.PerRPCCredentials{
+				staticCredentials(map[string]string{"authorization": "Bearer token"}),
+				CallCredentialsFunc(func(ctx context.Context, uri ...string) (map[string]string, error) {
+					return nil, errors.New("signer unavailable")
+				}),
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := CompositeCredentials(tt.sources...)
+
+			metadata, err := creds.GetRequestMetadata(context.Background(), "https://example.com")
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				if metadata != nil {
+					t.Errorf("Expected metadata to be nil on error, got %v", metadata)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(metadata) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, metadata)
+			}
+			for key, value := range tt.expected {
+				if metadata[key] != value {
+					t.Errorf("Expected %s to be %q, got %q", key, value, metadata[key])
+				}
+			}
+		})
+	}
+}
+
+func TestCompositeCredentials_RequireTransportSecurity(t *testing.T) {
+	authCreds := auth.NewOAuth2ClientCredentials("client", "secret", "https://example.com/token")
+
+	if CompositeCredentials(staticCredentials(nil), nil).RequireTransportSecurity() {
+		t.Error("Expected no transport security requirement without secure sources")
+	}
+	if !CompositeCredentials(staticCredentials(nil), OAuth2CallCredentials(&authCreds)).RequireTransportSecurity() {
+		t.Error("Expected transport security to be required when any source requires it")
+	}
+}
@@ -2,8 +2,12 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
 	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth/grpccreds"
 )
 import "google.golang.org/grpc/credentials"
 
@@ -11,12 +15,37 @@ type callCredentials struct {
 	credentials *auth.OAuth2ClientCredentials
 }
 
+// OAuth2CallCredentials sends tokens from auth as the bearer token and always
+// requires transport security.
+//
+// Deprecated: Use grpccreds.NewOAuth2, which this delegates to.
 func OAuth2CallCredentials(auth *auth.OAuth2ClientCredentials) credentials.PerRPCCredentials {
 	return callCredentials{credentials: auth}
 }
 
 func (o callCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
-	token, err := o.credentials.GetToken(ctx, auth.GetTokenOptions{})
+	return grpccreds.NewOAuth2(o.credentials).GetRequestMetadata(ctx, uri...)
+}
+
+func (o callCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+type tokenExchangeCallCredentials struct {
+	credentials *auth.TokenExchangeCredentials
+}
+
+// TokenExchangeCallCredentials exchanges the subject token attached to each
+// call's context with auth.WithSubjectToken and sends the result as the bearer
+// token, so calls run as the calling user. Calls without a subject token fail
+// with errors.ErrSubjectTokenRequired.
+func TokenExchangeCallCredentials(credentials *auth.TokenExchangeCredentials) credentials.PerRPCCredentials {
+	return tokenExchangeCallCredentials{credentials: credentials}
+}
+
+func (t tokenExchangeCallCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	subjectToken, _ := auth.SubjectTokenFromContext(ctx)
+	token, err := t.credentials.Exchange(ctx, subjectToken, auth.GetTokenOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -26,6 +55,84 @@ func (o callCredentials) GetRequestMetadata(ctx context.Context, uri ...string)
 	}, nil
 }
 
-func (o callCredentials) RequireTransportSecurity() bool {
+func (t tokenExchangeCallCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+type serviceAccountCallCredentials struct {
+	token *auth.ServiceAccountToken
+}
+
+// ServiceAccountCallCredentials sends the Kubernetes service account token
+// itself as the bearer token, re-reading it when it is rotated. Use
+// OAuth2CallCredentials with auth.NewWorkloadIdentityCredentials to trade it
+// for an identity provider token instead.
+func ServiceAccountCallCredentials(token *auth.ServiceAccountToken) credentials.PerRPCCredentials {
+	return serviceAccountCallCredentials{token: token}
+}
+
+func (s serviceAccountCallCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := s.token.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"authorization": fmt.Sprintf("Bearer %s", token),
+	}, nil
+}
+
+func (s serviceAccountCallCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+type staticTokenCallCredentials struct {
+	token string
+}
+
+// StaticTokenCallCredentials sends token as the bearer token on every call,
+// for environments where a sidecar already issues tokens. It is the gRPC
+// counterpart of auth.StaticTokenAuth.
+func StaticTokenCallCredentials(token string) credentials.PerRPCCredentials {
+	return staticTokenCallCredentials{token: token}
+}
+
+func (s staticTokenCallCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	if s.token == "" {
+		return nil, errors.New("static token is empty")
+	}
+
+	return map[string]string{
+		"authorization": fmt.Sprintf("Bearer %s", s.token),
+	}, nil
+}
+
+func (s staticTokenCallCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+type tokenFileCallCredentials struct {
+	file *auth.TokenFile
+}
+
+// TokenFileCallCredentials sends the token read from path as the bearer
+// token, re-reading the file as described in auth.NewTokenFile. It is the
+// gRPC counterpart of auth.TokenFileAuth.
+func TokenFileCallCredentials(path string, reloadInterval time.Duration) credentials.PerRPCCredentials {
+	return tokenFileCallCredentials{file: auth.NewTokenFile(path, reloadInterval)}
+}
+
+func (t tokenFileCallCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := t.file.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"authorization": fmt.Sprintf("Bearer %s", token),
+	}, nil
+}
+
+func (t tokenFileCallCredentials) RequireTransportSecurity() bool {
 	return true
 }
@@ -2,9 +2,14 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
 )
 
 func TestOAuth2CallCredentials(t *testing.T) {
@@ -155,3 +160,99 @@ func TestCallCredentials_TypeAssertion(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenExchangeCallCredentials_requiresSubjectToken(t *testing.T) {
+	credentials := TokenExchangeCallCredentials(auth.NewTokenExchangeCredentials("gateway", "secret", "https://example.com/token"))
+
+	if !credentials.RequireTransportSecurity() {
+		t.Error("Expected RequireTransportSecurity to return true")
+	}
+
+	metadata, err := credentials.GetRequestMetadata(context.Background())
+	if !errors.Is(err, kesselerrors.ErrSubjectTokenRequired) {
+		t.Errorf("Expected ErrSubjectTokenRequired, got %v", err)
+	}
+	if metadata != nil {
+		t.Errorf("Expected metadata to be nil on error, got %v", metadata)
+	}
+}
+
+func TestCallCredentials_GetRequestMetadata_tokenOverride(t *testing.T) {
+	authCreds := auth.NewOAuth2ClientCredentials("client", "secret", "invalid-url")
+	credentials := OAuth2CallCredentials(&authCreds)
+
+	metadata, err := credentials.GetRequestMetadata(auth.WithToken(context.Background(), "impersonated"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metadata["authorization"] != "Bearer impersonated" {
+		t.Errorf("Expected the override token, got %q", metadata["authorization"])
+	}
+}
+
+func TestServiceAccountCallCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("sa-token"), 0o600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+	credentials := ServiceAccountCallCredentials(auth.NewServiceAccountToken(path))
+
+	if !credentials.RequireTransportSecurity() {
+		t.Error("Expected RequireTransportSecurity to return true")
+	}
+	metadata, err := credentials.GetRequestMetadata(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metadata["authorization"] != "Bearer sa-token" {
+		t.Errorf("Expected service account bearer token, got %q", metadata["authorization"])
+	}
+
+	missing := ServiceAccountCallCredentials(auth.NewServiceAccountToken(filepath.Join(t.TempDir(), "missing")))
+	if _, err := missing.GetRequestMetadata(context.Background()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist, got %v", err)
+	}
+}
+
+func TestStaticTokenCallCredentials(t *testing.T) {
+	credentials := StaticTokenCallCredentials("static-token")
+
+	if !credentials.RequireTransportSecurity() {
+		t.Error("Expected RequireTransportSecurity to return true")
+	}
+	metadata, err := credentials.GetRequestMetadata(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metadata["authorization"] != "Bearer static-token" {
+		t.Errorf("Expected static bearer token, got %q", metadata["authorization"])
+	}
+
+	if _, err := StaticTokenCallCredentials("").GetRequestMetadata(context.Background()); err == nil {
+		t.Error("Expected an error for an empty token")
+	}
+}
+
+func TestTokenFileCallCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+	credentials := TokenFileCallCredentials(path, time.Minute)
+
+	if !credentials.RequireTransportSecurity() {
+		t.Error("Expected RequireTransportSecurity to return true")
+	}
+	metadata, err := credentials.GetRequestMetadata(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metadata["authorization"] != "Bearer file-token" {
+		t.Errorf("Expected file bearer token, got %q", metadata["authorization"])
+	}
+
+	missing := TokenFileCallCredentials(filepath.Join(t.TempDir(), "missing"), time.Minute)
+	if _, err := missing.GetRequestMetadata(context.Background()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist, got %v", err)
+	}
+}
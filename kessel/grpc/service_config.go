@@ -0,0 +1,166 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// RetryPolicy is the retryPolicy stanza of a gRPC service config. gRPC retries
+// a call that fails with one of RetryableStatusCodes, waiting a random time up
+// to InitialBackoff*BackoffMultiplier^(n-1), capped at MaxBackoff, before
+// attempt n+1.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. It
+	// must be at least 2; gRPC caps it at 5.
+	MaxAttempts          int
+	InitialBackoff       time.Duration
+	MaxBackoff           time.Duration
+	BackoffMultiplier    float64
+	RetryableStatusCodes []codes.Code
+}
+
+// DefaultRetryPolicy retries calls that fail with Unavailable up to three
+// times. Only apply it to methods that are safe to repeat, such as Check or
+// the idempotent ReportResource.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:          4,
+	InitialBackoff:       100 * time.Millisecond,
+	MaxBackoff:           2 * time.Second,
+	BackoffMultiplier:    2,
+	RetryableStatusCodes: []codes.Code{codes.Unavailable},
+}
+
+// MethodConfig applies a timeout and retry policy to calls of Methods.
+type MethodConfig struct {
+	// Methods are full method names, e.g.
+	// v1beta2.KesselInventoryService_Check_FullMethodName. A service name
+	// followed by "/" (e.g. "/kessel.inventory.v1beta2.KesselInventoryService/")
+	// matches every method of the service.
+	Methods []string
+	// Timeout is the default deadline of each call, or zero for none.
+	Timeout     time.Duration
+	RetryPolicy *RetryPolicy
+}
+
+// ServiceConfig is a typed subset of the gRPC service config, for use with
+// the client builder's WithServiceConfig.
+type ServiceConfig struct {
+	MethodConfigs []MethodConfig
+}
+
+// RetryServiceConfig returns the JSON of a service config applying policy to
+// methods.
+func RetryServiceConfig(policy RetryPolicy, methods ...string) (string, error) {
+	return ServiceConfig{MethodConfigs: []MethodConfig{{Methods: methods, RetryPolicy: &policy}}}.JSON()
+}
+
+// JSON validates c and returns it in the service config JSON format.
+func (c ServiceConfig) JSON() (string, error) {
+	var methodConfigs []map[string]any
+	for i, methodConfig := range c.MethodConfigs {
+		stanza, err := methodConfig.stanza()
+		if err != nil {
+			return "", fmt.Errorf("method config %d: %w", i, err)
+		}
+		methodConfigs = append(methodConfigs, stanza)
+	}
+	data, err := json.Marshal(map[string]any{"methodConfig": methodConfigs})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (m MethodConfig) stanza() (map[string]any, error) {
+	if len(m.Methods) == 0 {
+		return nil, fmt.Errorf("no methods")
+	}
+	var names []map[string]string
+	for _, method := range m.Methods {
+		service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+		if !ok || service == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid method name %q", method)
+		}
+		entry := map[string]string{"service": service}
+		if name != "" {
+			entry["method"] = name
+		}
+		names = append(names, entry)
+	}
+
+	stanza := map[string]any{"name": names}
+	if m.Timeout < 0 {
+		return nil, fmt.Errorf("negative timeout %s", m.Timeout)
+	}
+	if m.Timeout > 0 {
+		stanza["timeout"] = durationJSON(m.Timeout)
+	}
+	if m.RetryPolicy != nil {
+		policy, err := m.RetryPolicy.stanza()
+		if err != nil {
+			return nil, err
+		}
+		stanza["retryPolicy"] = policy
+	}
+	return stanza, nil
+}
+
+func (p RetryPolicy) stanza() (map[string]any, error) {
+	switch {
+	case p.MaxAttempts < 2:
+		return nil, fmt.Errorf("retry policy MaxAttempts must be at least 2, got %d", p.MaxAttempts)
+	case p.InitialBackoff <= 0 || p.MaxBackoff <= 0:
+		return nil, fmt.Errorf("retry policy backoffs must be positive")
+	case p.BackoffMultiplier <= 0:
+		return nil, fmt.Errorf("retry policy BackoffMultiplier must be positive, got %v", p.BackoffMultiplier)
+	case len(p.RetryableStatusCodes) == 0:
+		return nil, fmt.Errorf("retry policy needs at least one retryable status code")
+	}
+	var statusCodes []string
+	for _, code := range p.RetryableStatusCodes {
+		name, ok := codeNames[code]
+		if !ok || code == codes.OK {
+			return nil, fmt.Errorf("retry policy cannot retry status code %s", code)
+		}
+		statusCodes = append(statusCodes, name)
+	}
+	return map[string]any{
+		"maxAttempts":          p.MaxAttempts,
+		"initialBackoff":       durationJSON(p.InitialBackoff),
+		"maxBackoff":           durationJSON(p.MaxBackoff),
+		"backoffMultiplier":    p.BackoffMultiplier,
+		"retryableStatusCodes": statusCodes,
+	}, nil
+}
+
+// durationJSON formats d as the protobuf JSON duration gRPC expects, e.g. "0.1s".
+func durationJSON(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// codeNames are the status code names used in service configs, which differ
+// from codes.Code.String().
+var codeNames = map[codes.Code]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const checkMethod = "/kessel.inventory.v1beta2.KesselInventoryService/Check"
+
+func TestRetryServiceConfig(t *testing.T) {
+	config, err := RetryServiceConfig(DefaultRetryPolicy, checkMethod, "/kessel.inventory.v1beta2.KesselTupleService/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"methodConfig":[{"name":[{"method":"Check","service":"kessel.inventory.v1beta2.KesselInventoryService"},{"service":"kessel.inventory.v1beta2.KesselTupleService"}],` +
+		`"retryPolicy":{"backoffMultiplier":2,"initialBackoff":"0.1s","maxAttempts":4,"maxBackoff":"2s","retryableStatusCodes":["UNAVAILABLE"]}}]}`
+	if config != expected {
+		t.Errorf("Unexpected service config:\n got %s\nwant %s", config, expected)
+	}
+
+	// gRPC rejects invalid service configs when the client is created.
+	conn, err := grpcgo.NewClient("passthrough:///kessel", grpcgo.WithTransportCredentials(insecure.NewCredentials()), grpcgo.WithDefaultServiceConfig(config))
+	if err != nil {
+		t.Fatalf("gRPC rejected service config: %v", err)
+	}
+	_ = conn.Close()
+}
+
+func TestServiceConfig_JSON_timeout(t *testing.T) {
+	config, err := ServiceConfig{MethodConfigs: []MethodConfig{{Methods: []string{checkMethod}, Timeout: 1500 * time.Millisecond}}}.JSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(config, `"timeout":"1.5s"`) {
+		t.Errorf("Expected timeout in %s", config)
+	}
+}
+
+func TestServiceConfig_JSON_invalid(t *testing.T) {
+	policy := func(modify func(*RetryPolicy)) *RetryPolicy {
+		p := DefaultRetryPolicy
+		modify(&p)
+		return &p
+	}
+
+	tests := []struct {
+		name   string
+		config MethodConfig
+	}{
+		{"no methods", MethodConfig{Timeout: time.Second}},
+		{"bad method name", MethodConfig{Methods: []string{"Check"}}},
+		{"negative timeout", MethodConfig{Methods: []string{checkMethod}, Timeout: -time.Second}},
+		{"one attempt", MethodConfig{Methods: []string{checkMethod}, RetryPolicy: policy(func(p *RetryPolicy) { p.MaxAttempts = 1 })}},
+		{"zero backoff", MethodConfig{Methods: []string{checkMethod}, RetryPolicy: policy(func(p *RetryPolicy) { p.InitialBackoff = 0 })}},
+		{"zero multiplier", MethodConfig{Methods: []string{checkMethod}, RetryPolicy: policy(func(p *RetryPolicy) { p.BackoffMultiplier = 0 })}},
+		{"no codes", MethodConfig{Methods: []string{checkMethod}, RetryPolicy: policy(func(p *RetryPolicy) { p.RetryableStatusCodes = nil })}},
+		{"retry OK", MethodConfig{Methods: []string{checkMethod}, RetryPolicy: policy(func(p *RetryPolicy) { p.RetryableStatusCodes = []codes.Code{codes.OK} })}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (ServiceConfig{MethodConfigs: []MethodConfig{tt.config}}).JSON(); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// SRVScheme is the target scheme served by NewSRVResolver.
+const SRVScheme = "srv"
+
+// defaultSRVRefresh is how often the SRV records are looked up again when
+// nothing asks for it sooner.
+const defaultSRVRefresh = 30 * time.Second
+
+type srvResolverBuilder struct {
+	lookup  func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	refresh time.Duration
+}
+
+// NewSRVResolver returns a resolver for targets of the form
+// "srv:///_grpc._tcp.kessel.example.com", which dials the hosts and ports of
+// the name's DNS SRV records. The records are looked up again every 30
+// seconds and whenever a connection fails. Pass it to the client builder's
+// WithResolver; records are used in the order net.LookupSRV returns them, so
+// add WithRoundRobin to spread calls across every record.
+func NewSRVResolver() resolver.Builder {
+	return &srvResolverBuilder{lookup: net.DefaultResolver.LookupSRV, refresh: defaultSRVRefresh}
+}
+
+func (b *srvResolverBuilder) Scheme() string {
+	return SRVScheme
+}
+
+func (b *srvResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	name := target.Endpoint()
+	if name == "" {
+		return nil, fmt.Errorf("srv target %q has no record name", target.String())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &srvResolver{
+		builder:    b,
+		name:       name,
+		cc:         cc,
+		cancel:     cancel,
+		resolveNow: make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	go r.watch(ctx)
+	return r, nil
+}
+
+type srvResolver struct {
+	builder    *srvResolverBuilder
+	name       string
+	cc         resolver.ClientConn
+	cancel     context.CancelFunc
+	resolveNow chan struct{}
+	done       chan struct{}
+}
+
+func (r *srvResolver) watch(ctx context.Context) {
+	defer close(r.done)
+	ticker := time.NewTicker(r.builder.refresh)
+	defer ticker.Stop()
+	for {
+		r.resolve(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.resolveNow:
+		}
+	}
+}
+
+func (r *srvResolver) resolve(ctx context.Context) {
+	_, records, err := r.builder.lookup(ctx, "", "", r.name)
+	if ctx.Err() != nil {
+		return
+	}
+	if err == nil && len(records) == 0 {
+		err = fmt.Errorf("no SRV records for %s", r.name)
+	}
+	if err != nil {
+		r.cc.ReportError(fmt.Errorf("looking up SRV records for %s: %w", r.name, err))
+		return
+	}
+
+	state := resolver.State{}
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: net.JoinHostPort(host, strconv.Itoa(int(record.Port)))})
+	}
+	_ = r.cc.UpdateState(state)
+}
+
+func (r *srvResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolveNow <- struct{}{}:
+	default:
+	}
+}
+
+func (r *srvResolver) Close() {
+	r.cancel()
+	<-r.done
+}
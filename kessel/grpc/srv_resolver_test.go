@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
+)
+
+func startHealthServer(t *testing.T) *net.TCPAddr {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := grpcgo.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().(*net.TCPAddr)
+}
+
+func dialSRV(t *testing.T, builder *srvResolverBuilder, target string) healthpb.HealthClient {
+	t.Helper()
+	conn, err := grpcgo.NewClient(target, grpcgo.WithTransportCredentials(insecure.NewCredentials()), grpcgo.WithResolvers(builder))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestSRVResolver(t *testing.T) {
+	addr := startHealthServer(t)
+	var lookedUp atomic.Value
+	builder := &srvResolverBuilder{
+		lookup: func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+			lookedUp.Store(name)
+			return "", []*net.SRV{{Target: "127.0.0.1.", Port: uint16(addr.Port)}}, nil
+		},
+		refresh: time.Hour,
+	}
+
+	client := dialSRV(t, builder, "srv:///_grpc._tcp.kessel.example.com")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name := lookedUp.Load(); name != "_grpc._tcp.kessel.example.com" {
+		t.Errorf("Expected the record name to be looked up, got %v", name)
+	}
+}
+
+func TestSRVResolver_lookupFailure(t *testing.T) {
+	builder := &srvResolverBuilder{
+		lookup: func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+			return "", nil, errors.New("no such host")
+		},
+		refresh: time.Hour,
+	}
+
+	client := dialSRV(t, builder, "srv:///_grpc._tcp.missing")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Unavailable || !strings.Contains(err.Error(), "no such host") {
+		t.Errorf("Expected Unavailable with the lookup error, got %v", err)
+	}
+}
+
+func TestSRVResolver_refreshes(t *testing.T) {
+	addr := startHealthServer(t)
+	var lookups atomic.Int32
+	builder := &srvResolverBuilder{
+		lookup: func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+			lookups.Add(1)
+			return "", []*net.SRV{{Target: "127.0.0.1", Port: uint16(addr.Port)}}, nil
+		},
+		refresh: 10 * time.Millisecond,
+	}
+
+	client := dialSRV(t, builder, "srv:///kessel")
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for lookups.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := lookups.Load(); got < 3 {
+		t.Errorf("Expected periodic lookups, got %d", got)
+	}
+}
+
+func TestNewSRVResolver(t *testing.T) {
+	builder := NewSRVResolver()
+	if builder.Scheme() != SRVScheme {
+		t.Errorf("Expected scheme %q, got %q", SRVScheme, builder.Scheme())
+	}
+
+	_, err := builder.Build(resolver.Target{URL: url.URL{Scheme: SRVScheme, Path: "/"}}, nil, resolver.BuildOptions{})
+	if err == nil || !strings.Contains(err.Error(), "no record name") {
+		t.Errorf("Expected an error for a target without a name, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package callmetadata
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIdKey is the header and metadata key that carries the correlation ID
+// of a call across Red Hat services.
+const RequestIdKey = "x-rh-insights-request-id"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying md merged with any call metadata
+// already on ctx.
+func NewContext(ctx context.Context, md metadata.MD) context.Context {
+	return context.WithValue(ctx, contextKey{}, metadata.Join(FromContext(ctx), md))
+}
+
+// FromContext returns the call metadata on ctx, or nil.
+func FromContext(ctx context.Context) metadata.MD {
+	md, _ := ctx.Value(contextKey{}).(metadata.MD)
+	return md
+}
+
+// WithRequestId returns a copy of ctx whose call metadata carries id as the
+// request ID, replacing any earlier one.
+func WithRequestId(ctx context.Context, id string) context.Context {
+	md := FromContext(ctx).Copy()
+	md.Set(RequestIdKey, id)
+	return context.WithValue(ctx, contextKey{}, md)
+}
+
+// RequestId returns the request ID in the call metadata on ctx, or "".
+func RequestId(ctx context.Context) string {
+	if ids := FromContext(ctx).Get(RequestIdKey); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+// EnsureRequestId returns ctx and its request ID, first attaching a new random
+// ID if ctx has none.
+func EnsureRequestId(ctx context.Context) (context.Context, string) {
+	if id := RequestId(ctx); id != "" {
+		return ctx, id
+	}
+	id := uuid.NewString()
+	return WithRequestId(ctx, id), id
+}
+
+// SetHeaders adds the call metadata on ctx to header.
+func SetHeaders(ctx context.Context, header http.Header) {
+	for key, values := range FromContext(ctx) {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+}
+
+func outgoingContext(ctx context.Context, static metadata.MD) context.Context {
+	md := metadata.Join(static, FromContext(ctx))
+	if len(md) == 0 {
+		return ctx
+	}
+	kv := make([]string, 0, md.Len()*2)
+	for key, values := range md {
+		for _, value := range values {
+			kv = append(kv, key, value)
+		}
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that appends static and
+// per-call metadata to the outgoing metadata of every unary call.
+func UnaryClientInterceptor(static metadata.MD) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx, static), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a gRPC interceptor that appends static and
+// per-call metadata to the outgoing metadata of every stream.
+func StreamClientInterceptor(static metadata.MD) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx, static), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,151 @@
+package callmetadata
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNewContext_merges(t *testing.T) {
+	ctx := NewContext(context.Background(), metadata.Pairs("x-org-id", "org1"))
+	ctx = NewContext(ctx, metadata.Pairs("x-request-id", "req1"))
+
+	md := FromContext(ctx)
+	if got := md.Get("x-org-id"); len(got) != 1 || got[0] != "org1" {
+		t.Errorf("Expected x-org-id org1, got %v", got)
+	}
+	if got := md.Get("x-request-id"); len(got) != 1 || got[0] != "req1" {
+		t.Errorf("Expected x-request-id req1, got %v", got)
+	}
+}
+
+func TestFromContext_empty(t *testing.T) {
+	if md := FromContext(context.Background()); md != nil {
+		t.Errorf("Expected nil metadata, got %v", md)
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	ctx := NewContext(context.Background(), metadata.Pairs("x-request-id", "req1"))
+	header := http.Header{}
+
+	SetHeaders(ctx, header)
+
+	if got := header.Get("X-Request-Id"); got != "req1" {
+		t.Errorf("Expected X-Request-Id req1, got %q", got)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name     string
+		static   metadata.MD
+		perCall  metadata.MD
+		expected map[string][]string
+	}{
+		{
+			name:     "static only",
+			static:   metadata.Pairs("x-team", "platform"),
+			expected: map[string][]string{"x-team": {"platform"}},
+		},
+		{
+			name:     "per-call only",
+			perCall:  metadata.Pairs("x-org-id", "org1"),
+			expected: map[string][]string{"x-org-id": {"org1"}},
+		},
+		{
+			name:     "static and per-call combined",
+			static:   metadata.Pairs("x-team", "platform"),
+			perCall:  metadata.Pairs("x-team", "override", "x-org-id", "org1"),
+			expected: map[string][]string{"x-team": {"platform", "override"}, "x-org-id": {"org1"}},
+		},
+		{
+			name:     "nothing configured",
+			expected: map[string][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.perCall != nil {
+				ctx = NewContext(ctx, tt.perCall)
+			}
+
+			var outgoing metadata.MD
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				outgoing, _ = metadata.FromOutgoingContext(ctx)
+				return nil
+			}
+
+			if err := UnaryClientInterceptor(tt.static)(ctx, "/test/Method", nil, nil, nil, invoker); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for key, values := range tt.expected {
+				got := outgoing.Get(key)
+				if len(got) != len(values) {
+					t.Errorf("Expected %s=%v, got %v", key, values, got)
+					continue
+				}
+				for i := range values {
+					if got[i] != values[i] {
+						t.Errorf("Expected %s=%v, got %v", key, values, got)
+					}
+				}
+			}
+			if len(outgoing) != len(tt.expected) {
+				t.Errorf("Expected %d keys, got %v", len(tt.expected), outgoing)
+			}
+		})
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	ctx := NewContext(context.Background(), metadata.Pairs("x-org-id", "org1"))
+
+	var outgoing metadata.MD
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil, nil
+	}
+
+	if _, err := StreamClientInterceptor(metadata.Pairs("x-team", "platform"))(ctx, &grpc.StreamDesc{}, nil, "/test/Stream", streamer); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if outgoing.Get("x-org-id")[0] != "org1" || outgoing.Get("x-team")[0] != "platform" {
+		t.Errorf("Expected static and per-call metadata, got %v", outgoing)
+	}
+}
+
+func TestWithRequestId_replaces(t *testing.T) {
+	ctx := NewContext(context.Background(), metadata.Pairs("x-org-id", "org1"))
+	ctx = WithRequestId(ctx, "req1")
+	ctx = WithRequestId(ctx, "req2")
+
+	if got := FromContext(ctx).Get(RequestIdKey); len(got) != 1 || got[0] != "req2" {
+		t.Errorf("Expected only request ID req2, got %v", got)
+	}
+	if got := RequestId(ctx); got != "req2" {
+		t.Errorf("Expected req2, got %q", got)
+	}
+	if got := FromContext(ctx).Get("x-org-id"); len(got) != 1 {
+		t.Errorf("Expected other metadata to be kept, got %v", got)
+	}
+}
+
+func TestEnsureRequestId(t *testing.T) {
+	ctx, generated := EnsureRequestId(context.Background())
+	if generated == "" || RequestId(ctx) != generated {
+		t.Fatalf("Expected a generated request ID on the context, got %q", generated)
+	}
+
+	_, kept := EnsureRequestId(ctx)
+	if kept != generated {
+		t.Errorf("Expected the existing request ID %q to be kept, got %q", generated, kept)
+	}
+}
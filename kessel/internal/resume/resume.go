@@ -0,0 +1,56 @@
+// Package resume decides when a server stream that broke mid-way may be
+// reopened from its last continuation token.
+package resume
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	initialDelay = 100 * time.Millisecond
+	maxDelay     = 5 * time.Second
+)
+
+// Interrupted reports whether err means the stream was cut off rather than
+// rejected: the server became unavailable (e.g. during a rolling restart) or
+// reset the stream.
+func Interrupted(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch st.Code() {
+	case codes.Unavailable:
+		return true
+	case codes.Internal:
+		return strings.Contains(st.Message(), "RST_STREAM")
+	}
+	return false
+}
+
+// Retry reports whether a stream that failed with err should be reopened,
+// having waited with exponential backoff first. attempts counts the resumes
+// since the last received message; Retry increments it and callers reset it
+// to zero on progress. It returns false once attempts reaches maxAttempts, for
+// errors that are not Interrupted and when ctx ends while waiting.
+func Retry(ctx context.Context, err error, attempts *int, maxAttempts int) bool {
+	if *attempts >= maxAttempts || !Interrupted(err) {
+		return false
+	}
+	delay := min(initialDelay<<*attempts, maxDelay)
+	*attempts++
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
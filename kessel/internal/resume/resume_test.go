@@ -0,0 +1,60 @@
+package resume
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInterrupted(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "unavailable", err: status.Error(codes.Unavailable, "connection closed"), expected: true},
+		{name: "wrapped unavailable", err: fmt.Errorf("receiving: %w", status.Error(codes.Unavailable, "gone")), expected: true},
+		{name: "stream reset", err: status.Error(codes.Internal, "stream terminated by RST_STREAM with error code: INTERNAL_ERROR"), expected: true},
+		{name: "other internal error", err: status.Error(codes.Internal, "panic"), expected: false},
+		{name: "permission denied", err: status.Error(codes.PermissionDenied, "no"), expected: false},
+		{name: "plain error", err: errors.New("boom"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Interrupted(tt.err); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "down")
+	attempts := 0
+
+	if !Retry(context.Background(), unavailable, &attempts, 1) {
+		t.Fatal("Expected the first attempt to be allowed")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected attempts to be incremented, got %d", attempts)
+	}
+	if Retry(context.Background(), unavailable, &attempts, 1) {
+		t.Error("Expected no attempts beyond the maximum")
+	}
+
+	attempts = 0
+	if Retry(context.Background(), status.Error(codes.NotFound, "gone"), &attempts, 3) {
+		t.Error("Expected errors that are not interruptions not to be retried")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if Retry(ctx, unavailable, &attempts, 3) {
+		t.Error("Expected a canceled context to stop retrying")
+	}
+}
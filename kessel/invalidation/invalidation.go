@@ -0,0 +1,81 @@
+// Package invalidation feeds Kessel and RBAC change events, such as those
+// published on the Inventory Kafka topic, into the SDK's caches so cached
+// answers are dropped as soon as the underlying data changes, without
+// resorting to short TTLs.
+//
+// Decode messages from the consumer's client into Events and hand them to
+// the caches:
+//
+//	handle := invalidation.Handler(decodeInventoryEvent, invalidation.All(decisionCache, workspaceCache))
+//	for message := range consumer.Messages() {
+//		if err := handle(message.Value); err != nil {
+//			logger.Warn("undecodable change event", "error", err)
+//		}
+//	}
+//
+// inventory.DecisionCache and v2.WorkspaceCache implement Invalidator.
+package invalidation
+
+import (
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// Event describes a change that may make cached answers stale. Set whatever
+// the source message identifies; unset fields are not matched.
+type Event struct {
+	// Resource that was reported, updated or deleted, e.g. a host or a
+	// workspace. For reported resources the ID is the local resource ID.
+	Resource *v1beta2.ResourceReference
+	// Subject whose access changed, e.g. a principal added to a group.
+	Subject *v1beta2.ResourceReference
+	// OrgId of the tenant the change belongs to, e.g. for a role binding
+	// change that may affect any of its resources.
+	OrgId string
+}
+
+// Invalidator drops the cached entries an Event may have made stale. It must
+// be safe for concurrent use.
+type Invalidator interface {
+	Invalidate(event Event)
+}
+
+// Func adapts a function to an Invalidator, e.g. for an application cache.
+type Func func(event Event)
+
+func (f Func) Invalidate(event Event) {
+	f(event)
+}
+
+// All returns an Invalidator that passes every event to each of invalidators
+// in order. Nil invalidators are skipped.
+func All(invalidators ...Invalidator) Invalidator {
+	return Func(func(event Event) {
+		for _, invalidator := range invalidators {
+			if invalidator != nil {
+				invalidator.Invalidate(event)
+			}
+		}
+	})
+}
+
+// Decoder turns one message, such as the value of a Kafka record, into the
+// events it describes. It returns no events for messages that do not affect
+// authorization.
+type Decoder func(message []byte) ([]Event, error)
+
+// Handler returns a function for a Kafka (or similar) consumer loop that
+// decodes each message and passes its events to invalidator. Decoding errors
+// are returned, so the consumer can log or dead-letter the message; nothing is
+// invalidated for it.
+func Handler(decode Decoder, invalidator Invalidator) func(message []byte) error {
+	return func(message []byte) error {
+		events, err := decode(message)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			invalidator.Invalidate(event)
+		}
+		return nil
+	}
+}
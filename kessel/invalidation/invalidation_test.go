@@ -0,0 +1,67 @@
+package invalidation
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type recorder struct {
+	events []Event
+}
+
+func (r *recorder) Invalidate(event Event) {
+	r.events = append(r.events, event)
+}
+
+func decodeOrgIds(message []byte) ([]Event, error) {
+	var orgIds []string
+	if err := json.Unmarshal(message, &orgIds); err != nil {
+		return nil, err
+	}
+	var events []Event
+	for _, orgId := range orgIds {
+		events = append(events, Event{OrgId: orgId})
+	}
+	return events, nil
+}
+
+func TestHandler(t *testing.T) {
+	first, second := &recorder{}, &recorder{}
+	handle := Handler(decodeOrgIds, All(first, nil, second))
+
+	if err := handle([]byte(`["org1","org2"]`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, r := range []*recorder{first, second} {
+		if len(r.events) != 2 || r.events[0].OrgId != "org1" || r.events[1].OrgId != "org2" {
+			t.Errorf("Expected events for org1 and org2, got %v", r.events)
+		}
+	}
+}
+
+func TestHandler_decodeError(t *testing.T) {
+	invalidated := false
+	handle := Handler(decodeOrgIds, Func(func(Event) { invalidated = true }))
+
+	var syntaxErr *json.SyntaxError
+	if err := handle([]byte("not json")); !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected the decoding error, got %v", err)
+	}
+	if invalidated {
+		t.Error("Expected nothing to be invalidated")
+	}
+}
+
+func TestFunc(t *testing.T) {
+	var received Event
+	var invalidator Invalidator = Func(func(event Event) { received = event })
+
+	resource := &v1beta2.ResourceReference{ResourceType: "host", ResourceId: "h1"}
+	invalidator.Invalidate(Event{Resource: resource})
+	if received.Resource != resource {
+		t.Errorf("Expected the event to be passed on, got %v", received)
+	}
+}
@@ -0,0 +1,207 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	inventoryPackagePrefix = "kessel.inventory."
+	inventoryService       = "kessel.inventory.v1beta2.KesselInventoryService"
+	checkBulkRequestName   = "kessel.inventory.v1beta2.CheckBulkRequest"
+)
+
+// ServerCapabilities describes what an Inventory API server supports.
+type ServerCapabilities struct {
+	// Fully qualified names of the gRPC services the server exposes, sorted.
+	Services []string
+	// Inventory API versions the server serves, e.g. "v1beta2", sorted.
+	ApiVersions []string
+	// Whether the v1beta2 service serves the streaming list methods.
+	StreamedListObjects  bool
+	StreamedListSubjects bool
+	// Maximum number of items the server accepts in one CheckBulkRequest.
+	CheckBulkMaxItems int
+	// Whether the capabilities were read from the server. False when the
+	// server does not expose gRPC reflection and they describe the API this
+	// SDK was built against instead.
+	Discovered bool
+}
+
+// DefaultCapabilities describes the API this SDK was built against.
+func DefaultCapabilities() *ServerCapabilities {
+	return &ServerCapabilities{
+		Services:             []string{inventoryService},
+		ApiVersions:          []string{"v1beta2"},
+		StreamedListObjects:  true,
+		StreamedListSubjects: true,
+		CheckBulkMaxItems:    CheckBulkMaxItems(),
+	}
+}
+
+// FetchCapabilities asks the server behind conn what it supports, using gRPC
+// server reflection: the services it lists, the methods and validation rules
+// of the descriptors it serves. The Inventory API has no dedicated metadata
+// endpoint, so servers without reflection fail with codes.Unimplemented.
+func FetchCapabilities(ctx context.Context, conn grpc.ClientConnInterface) (*ServerCapabilities, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := reflectionCall(stream, &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities := &ServerCapabilities{Discovered: true, CheckBulkMaxItems: CheckBulkMaxItems()}
+	for _, service := range response.GetListServicesResponse().GetService() {
+		name := service.GetName()
+		capabilities.Services = append(capabilities.Services, name)
+		if version, ok := strings.CutPrefix(name, inventoryPackagePrefix); ok {
+			version, _, _ = strings.Cut(version, ".")
+			if !slices.Contains(capabilities.ApiVersions, version) {
+				capabilities.ApiVersions = append(capabilities.ApiVersions, version)
+			}
+		}
+	}
+	slices.Sort(capabilities.Services)
+	slices.Sort(capabilities.ApiVersions)
+
+	if !slices.Contains(capabilities.Services, inventoryService) {
+		return capabilities, nil
+	}
+
+	// The server sends each file once per stream, along with the files it
+	// imports, so descriptors accumulate across both requests.
+	var files []*descriptorpb.FileDescriptorProto
+	for _, symbol := range []string{inventoryService, checkBulkRequestName} {
+		if findMessage(files, checkBulkRequestName) != nil {
+			break
+		}
+		response, err := reflectionCall(stream, &reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range response.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			file := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(raw, file); err != nil {
+				return nil, fmt.Errorf("parsing descriptor of %s: %w", symbol, err)
+			}
+			files = append(files, file)
+		}
+	}
+
+	for _, method := range findService(files, inventoryService).GetMethod() {
+		switch method.GetName() {
+		case "StreamedListObjects":
+			capabilities.StreamedListObjects = method.GetServerStreaming()
+		case "StreamedListSubjects":
+			capabilities.StreamedListSubjects = method.GetServerStreaming()
+		}
+	}
+	for _, field := range findMessage(files, checkBulkRequestName).GetField() {
+		if field.GetName() != "items" {
+			continue
+		}
+		rules, _ := proto.GetExtension(field.GetOptions(), validate.E_Field).(*validate.FieldRules)
+		if maxItems := rules.GetRepeated().GetMaxItems(); maxItems > 0 {
+			capabilities.CheckBulkMaxItems = int(maxItems)
+		}
+	}
+	return capabilities, nil
+}
+
+func reflectionCall(stream reflectionpb.ServerReflection_ServerReflectionInfoClient, request *reflectionpb.ServerReflectionRequest) (*reflectionpb.ServerReflectionResponse, error) {
+	if err := stream.Send(request); err != nil {
+		return nil, err
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if failure := response.GetErrorResponse(); failure != nil {
+		return nil, status.Error(codes.Code(failure.GetErrorCode()), failure.GetErrorMessage())
+	}
+	return response, nil
+}
+
+func findService(files []*descriptorpb.FileDescriptorProto, fullName string) *descriptorpb.ServiceDescriptorProto {
+	for _, file := range files {
+		for _, service := range file.GetService() {
+			if file.GetPackage()+"."+service.GetName() == fullName {
+				return service
+			}
+		}
+	}
+	return nil
+}
+
+func findMessage(files []*descriptorpb.FileDescriptorProto, fullName string) *descriptorpb.DescriptorProto {
+	for _, file := range files {
+		for _, message := range file.GetMessageType() {
+			if file.GetPackage()+"."+message.GetName() == fullName {
+				return message
+			}
+		}
+	}
+	return nil
+}
+
+// CapabilitiesCache fetches a server's capabilities once and shares them, so
+// helpers can consult them on every call. It is safe for concurrent use.
+type CapabilitiesCache struct {
+	conn         grpc.ClientConnInterface
+	mu           sync.Mutex
+	capabilities *ServerCapabilities
+}
+
+func NewCapabilitiesCache(conn grpc.ClientConnInterface) *CapabilitiesCache {
+	return &CapabilitiesCache{conn: conn}
+}
+
+// Capabilities returns the cached capabilities, fetching them on first use.
+// If the server does not expose reflection, DefaultCapabilities are cached
+// instead. Other errors are returned without caching, so the next call asks
+// again. The result is shared and must not be modified.
+func (c *CapabilitiesCache) Capabilities(ctx context.Context) (*ServerCapabilities, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capabilities != nil {
+		return c.capabilities, nil
+	}
+
+	capabilities, err := FetchCapabilities(ctx, c.conn)
+	if status.Code(err) == codes.Unimplemented {
+		capabilities, err = DefaultCapabilities(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.capabilities = capabilities
+	return capabilities, nil
+}
+
+// CheckBulkChunkOptions returns chunk options that follow the server's
+// CheckBulk limit, for use with CheckBulkChunked.
+func (c *ServerCapabilities) CheckBulkChunkOptions(concurrency int) CheckBulkChunkOptions {
+	return CheckBulkChunkOptions{MaxItems: c.CheckBulkMaxItems, Concurrency: concurrency}
+}
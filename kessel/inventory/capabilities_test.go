@@ -0,0 +1,107 @@
+package inventory
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type capabilitiesServer struct {
+	v1beta2.UnimplementedKesselInventoryServiceServer
+}
+
+func newCapabilitiesConn(t *testing.T, withReflection bool) *grpc.ClientConn {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	v1beta2.RegisterKesselInventoryServiceServer(server, &capabilitiesServer{})
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	if withReflection {
+		reflection.Register(server)
+	}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestFetchCapabilities(t *testing.T) {
+	conn := newCapabilitiesConn(t, true)
+
+	capabilities, err := FetchCapabilities(context.Background(), conn)
+	require.NoError(t, err)
+
+	assert.True(t, capabilities.Discovered)
+	assert.Contains(t, capabilities.Services, "kessel.inventory.v1beta2.KesselInventoryService")
+	assert.Contains(t, capabilities.Services, "grpc.health.v1.Health")
+	assert.Equal(t, []string{"v1beta2"}, capabilities.ApiVersions)
+	assert.True(t, capabilities.StreamedListObjects)
+	assert.True(t, capabilities.StreamedListSubjects)
+	assert.Equal(t, CheckBulkMaxItems(), capabilities.CheckBulkMaxItems)
+}
+
+func TestFetchCapabilities_withoutReflection(t *testing.T) {
+	conn := newCapabilitiesConn(t, false)
+
+	_, err := FetchCapabilities(context.Background(), conn)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestCapabilitiesCache_fallsBackToDefaults(t *testing.T) {
+	conn := newCapabilitiesConn(t, false)
+	cache := NewCapabilitiesCache(conn)
+
+	capabilities, err := cache.Capabilities(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCapabilities(), capabilities)
+	assert.False(t, capabilities.Discovered)
+}
+
+func TestCapabilitiesCache_fetchesOnce(t *testing.T) {
+	conn := newCapabilitiesConn(t, true)
+	cache := NewCapabilitiesCache(conn)
+
+	first, err := cache.Capabilities(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	second, err := cache.Capabilities(context.Background())
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+}
+
+func TestCapabilitiesCache_doesNotCacheErrors(t *testing.T) {
+	conn := newCapabilitiesConn(t, true)
+	cache := NewCapabilitiesCache(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := cache.Capabilities(ctx)
+	require.Error(t, err)
+
+	capabilities, err := cache.Capabilities(context.Background())
+	require.NoError(t, err)
+	assert.True(t, capabilities.Discovered)
+}
+
+func TestServerCapabilities_CheckBulkChunkOptions(t *testing.T) {
+	capabilities := &ServerCapabilities{CheckBulkMaxItems: 250}
+
+	assert.Equal(t, CheckBulkChunkOptions{MaxItems: 250, Concurrency: 4}, capabilities.CheckBulkChunkOptions(4))
+}
@@ -0,0 +1,229 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/callmetadata"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"github.com/project-kessel/kessel-sdk-go/kessel/ratelimit"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultBatchWindow  = 2 * time.Millisecond
+	defaultMaxBatchSize = 100
+	defaultBatchTimeout = 10 * time.Second
+)
+
+type CheckBatcherOptions struct {
+	// How long to collect checks before sending them. Defaults to 2ms.
+	Window time.Duration
+	// Send immediately once this many checks are queued. Defaults to 100.
+	MaxBatchSize int
+	// Optionally set the consistency of every CheckBulk request.
+	Consistency *v1beta2.Consistency
+	// How long each CheckBulk request may take. Defaults to 10s.
+	Timeout time.Duration
+}
+
+// CheckBatcher collects Check calls made within a short window and sends them
+// as a single CheckBulk request. It is safe for concurrent use and is intended
+// for request handlers that authorize many objects independently.
+//
+// Checks are only batched with checks made on behalf of the same caller:
+// contexts with different outgoing metadata, call metadata (including the
+// request ID), auth.WithToken or auth.WithCredentials overrides, subject
+// token or ratelimit tenant go into separate batches. Each CheckBulk request
+// runs with the context of the first check in its batch, detached from its
+// cancellation so one caller giving up does not fail the others, and bounded
+// by options.Timeout. Each caller's own context only bounds how long it
+// waits.
+type CheckBatcher struct {
+	client       v1beta2.KesselInventoryServiceClient
+	window       time.Duration
+	maxBatchSize int
+	consistency  *v1beta2.Consistency
+	timeout      time.Duration
+
+	mu      sync.Mutex
+	pending map[batchKey]*checkBatch
+}
+
+// batchKey identifies the caller a check is made for, as far as it changes
+// how the CheckBulk request is sent and authorized.
+type batchKey struct {
+	metadata     string
+	token        string
+	credentials  *auth.OAuth2ClientCredentials
+	subjectToken string
+	tenant       string
+}
+
+type checkBatch struct {
+	key     batchKey
+	ctx     context.Context
+	items   []*v1beta2.CheckBulkRequestItem
+	futures []*CheckFuture
+	timer   *time.Timer
+}
+
+// CheckFuture is the pending result of a batched check.
+type CheckFuture struct {
+	done    chan struct{}
+	allowed bool
+	err     error
+}
+
+// NewCheckBatcher creates a CheckBatcher that sends batches through client.
+func NewCheckBatcher(client v1beta2.KesselInventoryServiceClient, options CheckBatcherOptions) *CheckBatcher {
+	window := options.Window
+	if window <= 0 {
+		window = defaultBatchWindow
+	}
+	maxBatchSize := options.MaxBatchSize
+	if maxBatchSize <= 0 {
+		maxBatchSize = defaultMaxBatchSize
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = defaultBatchTimeout
+	}
+
+	return &CheckBatcher{
+		client:       client,
+		window:       window,
+		maxBatchSize: maxBatchSize,
+		consistency:  options.Consistency,
+		timeout:      timeout,
+		pending:      map[batchKey]*checkBatch{},
+	}
+}
+
+func newBatchKey(ctx context.Context) batchKey {
+	outgoing, _ := metadata.FromOutgoingContext(ctx)
+	token, credentials := auth.OverridesFromContext(ctx)
+	subjectToken, _ := auth.SubjectTokenFromContext(ctx)
+	return batchKey{
+		metadata:     encodeMetadata(metadata.Join(outgoing, callmetadata.FromContext(ctx))),
+		token:        token,
+		credentials:  credentials,
+		subjectToken: subjectToken,
+		tenant:       ratelimit.TenantFromContext(ctx),
+	}
+}
+
+// encodeMetadata returns md as a string that is equal for equal metadata.
+func encodeMetadata(md metadata.MD) string {
+	keys := slices.Sorted(func(yield func(string) bool) {
+		for key := range md {
+			if !yield(key) {
+				return
+			}
+		}
+	})
+	var encoded strings.Builder
+	for _, key := range keys {
+		encoded.WriteString(strconv.Quote(key))
+		for _, value := range md[key] {
+			encoded.WriteString("," + strconv.Quote(value))
+		}
+		encoded.WriteString(";")
+	}
+	return encoded.String()
+}
+
+// Check queues item and returns a future for its result. It does not wait for
+// the CheckBulk call, even when item fills the batch.
+func (b *CheckBatcher) Check(ctx context.Context, item *v1beta2.CheckBulkRequestItem) *CheckFuture {
+	future := &CheckFuture{done: make(chan struct{})}
+
+	key := newBatchKey(ctx)
+	b.mu.Lock()
+	batch := b.pending[key]
+	if batch == nil {
+		batch = &checkBatch{key: key, ctx: context.WithoutCancel(ctx)}
+		batch.timer = time.AfterFunc(b.window, func() { b.flushBatch(batch) })
+		b.pending[key] = batch
+	}
+	batch.items = append(batch.items, item)
+	batch.futures = append(batch.futures, future)
+	full := len(batch.items) >= b.maxBatchSize
+	b.mu.Unlock()
+
+	if full {
+		go b.flushBatch(batch)
+	}
+	return future
+}
+
+// CheckAllowed queues item and waits for its result.
+func (b *CheckBatcher) CheckAllowed(ctx context.Context, item *v1beta2.CheckBulkRequestItem) (bool, error) {
+	return b.Check(ctx, item).Wait(ctx)
+}
+
+// Flush sends the pending batches immediately, if any.
+func (b *CheckBatcher) Flush() {
+	b.mu.Lock()
+	batches := make([]*checkBatch, 0, len(b.pending))
+	for _, batch := range b.pending {
+		batches = append(batches, batch)
+	}
+	b.mu.Unlock()
+
+	for _, batch := range batches {
+		b.flushBatch(batch)
+	}
+}
+
+func (b *CheckBatcher) flushBatch(batch *checkBatch) {
+	b.mu.Lock()
+	if b.pending[batch.key] != batch {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, batch.key)
+	batch.timer.Stop()
+	b.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(batch.ctx, b.timeout)
+	defer cancel()
+	response, err := b.client.CheckBulk(ctx, &v1beta2.CheckBulkRequest{
+		Items:       batch.items,
+		Consistency: b.consistency,
+	})
+	if err == nil && len(response.GetPairs()) != len(batch.items) {
+		err = fmt.Errorf("unexpected number of CheckBulk results: got %d, expected %d", len(response.GetPairs()), len(batch.items))
+	}
+
+	for i, future := range batch.futures {
+		if err != nil {
+			future.err = err
+		} else {
+			pair := response.GetPairs()[i]
+			if pairErr := pair.GetError(); pairErr != nil {
+				future.err = status.ErrorProto(pairErr)
+			} else {
+				future.allowed = pair.GetItem().GetAllowed() == v1beta2.Allowed_ALLOWED_TRUE
+			}
+		}
+		close(future.done)
+	}
+}
+
+// Wait blocks until the result is available or ctx is done.
+func (f *CheckFuture) Wait(ctx context.Context) (bool, error) {
+	select {
+	case <-f.done:
+		return f.allowed, f.err
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
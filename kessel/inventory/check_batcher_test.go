@@ -0,0 +1,244 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type mockCheckBulkClient struct {
+	v1beta2.KesselInventoryServiceClient
+	mu       sync.Mutex
+	requests []*v1beta2.CheckBulkRequest
+	tokens   []string
+	err      error
+	dropLast bool
+	hang     bool
+	// release, when set, holds every CheckBulk call until it is closed.
+	release chan struct{}
+}
+
+func (m *mockCheckBulkClient) CheckBulk(ctx context.Context, in *v1beta2.CheckBulkRequest, opts ...grpc.CallOption) (*v1beta2.CheckBulkResponse, error) {
+	token, _ := auth.OverridesFromContext(ctx)
+	m.mu.Lock()
+	m.requests = append(m.requests, in)
+	m.tokens = append(m.tokens, token)
+	m.mu.Unlock()
+	if m.release != nil {
+		<-m.release
+	}
+	if m.hang {
+		<-ctx.Done()
+		return nil, grpcstatus.FromContextError(ctx.Err()).Err()
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var pairs []*v1beta2.CheckBulkResponsePair
+	for _, item := range in.Items {
+		pair := &v1beta2.CheckBulkResponsePair{Request: item}
+		switch item.Object.ResourceId {
+		case "error":
+			pair.Response = &v1beta2.CheckBulkResponsePair_Error{Error: &status.Status{Code: int32(codes.NotFound), Message: "missing"}}
+		case "allowed":
+			pair.Response = &v1beta2.CheckBulkResponsePair_Item{Item: &v1beta2.CheckBulkResponseItem{Allowed: v1beta2.Allowed_ALLOWED_TRUE}}
+		default:
+			pair.Response = &v1beta2.CheckBulkResponsePair_Item{Item: &v1beta2.CheckBulkResponseItem{Allowed: v1beta2.Allowed_ALLOWED_FALSE}}
+		}
+		pairs = append(pairs, pair)
+	}
+	if m.dropLast {
+		pairs = pairs[:len(pairs)-1]
+	}
+	return &v1beta2.CheckBulkResponse{Pairs: pairs}, nil
+}
+
+func checkItem(id string) *v1beta2.CheckBulkRequestItem {
+	return &v1beta2.CheckBulkRequestItem{
+		Object:   hostRef(id),
+		Relation: "view",
+		Subject:  &v1beta2.SubjectReference{Resource: &v1beta2.ResourceReference{ResourceType: "principal", ResourceId: "redhat/alice"}},
+	}
+}
+
+func TestCheckBatcher_combinesConcurrentChecks(t *testing.T) {
+	client := &mockCheckBulkClient{}
+	batcher := NewCheckBatcher(client, CheckBatcherOptions{Window: 50 * time.Millisecond})
+
+	ids := []string{"allowed", "denied", "error", "allowed"}
+	futures := make([]*CheckFuture, len(ids))
+	for i, id := range ids {
+		futures[i] = batcher.Check(context.Background(), checkItem(id))
+	}
+
+	allowed, err := futures[0].Wait(context.Background())
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = futures[1].Wait(context.Background())
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	_, err = futures[2].Wait(context.Background())
+	assert.Equal(t, codes.NotFound, grpcstatus.Code(err))
+
+	allowed, err = futures[3].Wait(context.Background())
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	require.Len(t, client.requests, 1)
+	assert.Len(t, client.requests[0].Items, 4)
+}
+
+func TestCheckBatcher_flushesAtMaxBatchSize(t *testing.T) {
+	client := &mockCheckBulkClient{}
+	batcher := NewCheckBatcher(client, CheckBatcherOptions{Window: time.Hour, MaxBatchSize: 2})
+
+	first := batcher.Check(context.Background(), checkItem("allowed"))
+	second := batcher.Check(context.Background(), checkItem("allowed"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := first.Wait(ctx)
+	require.NoError(t, err)
+	_, err = second.Wait(ctx)
+	require.NoError(t, err)
+
+	third := batcher.Check(context.Background(), checkItem("allowed"))
+	batcher.Flush()
+	_, err = third.Wait(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, client.requests, 2)
+	assert.Len(t, client.requests[0].Items, 2)
+	assert.Len(t, client.requests[1].Items, 1)
+}
+
+func TestCheckBatcher_fullBatchDoesNotBlockCheck(t *testing.T) {
+	client := &mockCheckBulkClient{release: make(chan struct{})}
+	batcher := NewCheckBatcher(client, CheckBatcherOptions{Window: time.Hour, MaxBatchSize: 1})
+
+	returned := make(chan *CheckFuture)
+	go func() { returned <- batcher.Check(context.Background(), checkItem("allowed")) }()
+
+	var future *CheckFuture
+	select {
+	case future = <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("Check blocked on the CheckBulk call of the batch it filled")
+	}
+	close(client.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	allowed, err := future.Wait(ctx)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestCheckBatcher_errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		client *mockCheckBulkClient
+	}{
+		{
+			name:   "request error fails every check",
+			client: &mockCheckBulkClient{err: grpcstatus.Error(codes.Unavailable, "down")},
+		},
+		{
+			name:   "mismatched result count fails every check",
+			client: &mockCheckBulkClient{dropLast: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batcher := NewCheckBatcher(tt.client, CheckBatcherOptions{})
+
+			var wg sync.WaitGroup
+			errs := make([]error, 3)
+			for i := range errs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, errs[i] = batcher.CheckAllowed(context.Background(), checkItem(fmt.Sprintf("host-%d", i)))
+				}()
+			}
+			wg.Wait()
+
+			for _, err := range errs {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckBatcher_separatesCallers(t *testing.T) {
+	client := &mockCheckBulkClient{}
+	batcher := NewCheckBatcher(client, CheckBatcherOptions{Window: time.Hour})
+
+	alice := auth.WithToken(context.Background(), "alice")
+	contexts := []context.Context{
+		alice,
+		auth.WithToken(context.Background(), "bob"),
+		alice,
+		WithRequestId(alice, "request-2"),
+		metadata.AppendToOutgoingContext(alice, "x-team", "inventory"),
+	}
+	futures := make([]*CheckFuture, len(contexts))
+	for i, ctx := range contexts {
+		futures[i] = batcher.Check(ctx, checkItem("allowed"))
+	}
+	batcher.Flush()
+	for _, future := range futures {
+		_, err := future.Wait(context.Background())
+		require.NoError(t, err)
+	}
+
+	require.Len(t, client.requests, 4)
+	var sizes []int
+	for _, request := range client.requests {
+		sizes = append(sizes, len(request.Items))
+	}
+	assert.ElementsMatch(t, []int{2, 1, 1, 1}, sizes)
+	assert.ElementsMatch(t, []string{"alice", "bob", "alice", "alice"}, client.tokens)
+}
+
+func TestCheckBatcher_timeout(t *testing.T) {
+	client := &mockCheckBulkClient{hang: true}
+	batcher := NewCheckBatcher(client, CheckBatcherOptions{Timeout: 10 * time.Millisecond})
+
+	_, err := batcher.CheckAllowed(context.Background(), checkItem("allowed"))
+	assert.Equal(t, codes.DeadlineExceeded, grpcstatus.Code(err))
+}
+
+func TestCheckFuture_WaitContextCancelled(t *testing.T) {
+	client := &mockCheckBulkClient{}
+	batcher := NewCheckBatcher(client, CheckBatcherOptions{Window: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	future := batcher.Check(ctx, checkItem("allowed"))
+	cancel()
+
+	_, err := future.Wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	batcher.Flush()
+	allowed, err := future.Wait(context.Background())
+	require.NoError(t, err)
+	assert.True(t, allowed, "cancelling one caller must not fail the batch")
+}
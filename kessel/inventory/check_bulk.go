@@ -0,0 +1,131 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/protobuf/proto"
+)
+
+// fallbackCheckBulkMaxItems is used if the API stops declaring a limit.
+const fallbackCheckBulkMaxItems = 1000
+
+// CheckBulkChunkOptions configures CheckBulkChunked.
+type CheckBulkChunkOptions struct {
+	// Maximum number of items per CheckBulk request. Defaults to
+	// CheckBulkMaxItems().
+	MaxItems int
+	// Maximum number of requests in flight. Values below 1 are treated as 1.
+	Concurrency int
+}
+
+// CheckBulkMaxItems returns the maximum number of items the server accepts
+// in one CheckBulkRequest, as declared by the API's validation rules.
+func CheckBulkMaxItems() int {
+	field := (&v1beta2.CheckBulkRequest{}).ProtoReflect().Descriptor().Fields().ByName("items")
+	rules, _ := proto.GetExtension(field.Options(), validate.E_Field).(*validate.FieldRules)
+	if maxItems := rules.GetRepeated().GetMaxItems(); maxItems > 0 {
+		return int(maxItems)
+	}
+	return fallbackCheckBulkMaxItems
+}
+
+// CheckBulkChunked sends request as CheckBulk requests of at most
+// options.MaxItems items each, options.Concurrency at a time, and returns a
+// single response with the pairs in the order of request.Items. Every chunk
+// uses request.Consistency. The response's consistency token is the freshest
+// of the chunks' tokens, or the first chunk's when they cannot be ordered.
+// If a chunk fails, the chunks not yet sent are skipped, those in flight are
+// canceled and the first error is returned.
+func CheckBulkChunked(
+	ctx context.Context,
+	client v1beta2.KesselInventoryServiceClient,
+	request *v1beta2.CheckBulkRequest,
+	options CheckBulkChunkOptions,
+) (*v1beta2.CheckBulkResponse, error) {
+	maxItems := options.MaxItems
+	if maxItems <= 0 {
+		maxItems = CheckBulkMaxItems()
+	}
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	items := request.GetItems()
+	if len(items) <= maxItems {
+		return client.CheckBulk(ctx, request)
+	}
+
+	var chunks [][]*v1beta2.CheckBulkRequestItem
+	for start := 0; start < len(items); start += maxItems {
+		chunks = append(chunks, items[start:min(start+maxItems, len(items))])
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var once sync.Once
+	var failure error
+	fail := func(err error) {
+		once.Do(func() {
+			failure = err
+			cancel()
+		})
+	}
+
+	responses := make([]*v1beta2.CheckBulkResponse, len(chunks))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(chunks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					continue
+				}
+				response, err := client.CheckBulk(ctx, &v1beta2.CheckBulkRequest{
+					Items:       chunks[i],
+					Consistency: request.GetConsistency(),
+				})
+				if err == nil && len(response.GetPairs()) != len(chunks[i]) {
+					err = fmt.Errorf("unexpected number of CheckBulk results: got %d, expected %d", len(response.GetPairs()), len(chunks[i]))
+				}
+				if err != nil {
+					fail(err)
+					continue
+				}
+				responses[i] = response
+			}
+		}()
+	}
+	for i := range chunks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if failure != nil {
+		return nil, failure
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &v1beta2.CheckBulkResponse{}
+	tokens := make([]*v1beta2.ConsistencyToken, len(responses))
+	for i, response := range responses {
+		result.Pairs = append(result.Pairs, response.GetPairs()...)
+		tokens[i] = response.GetConsistencyToken()
+	}
+	token, err := MaxConsistencyToken(tokens...)
+	if err != nil {
+		token = tokens[0]
+	}
+	result.ConsistencyToken = token
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// mockChunkClient echoes each chunk back as pairs and returns a ZedToken
+// whose revision is the chunk's first item id. Chunks starting with "fail"
+// fail.
+type mockChunkClient struct {
+	v1beta2.KesselInventoryServiceClient
+	mu       sync.Mutex
+	requests []*v1beta2.CheckBulkRequest
+}
+
+func (m *mockChunkClient) CheckBulk(ctx context.Context, in *v1beta2.CheckBulkRequest, opts ...grpc.CallOption) (*v1beta2.CheckBulkResponse, error) {
+	m.mu.Lock()
+	m.requests = append(m.requests, in)
+	m.mu.Unlock()
+
+	first := in.GetItems()[0].GetObject().GetResourceId()
+	if first == "fail" {
+		return nil, grpcstatus.Error(codes.Unavailable, "down")
+	}
+	response := &v1beta2.CheckBulkResponse{ConsistencyToken: zedToken(first)}
+	for _, item := range in.GetItems() {
+		response.Pairs = append(response.Pairs, &v1beta2.CheckBulkResponsePair{Request: item})
+	}
+	return response, nil
+}
+
+func numberedItems(n int) []*v1beta2.CheckBulkRequestItem {
+	items := make([]*v1beta2.CheckBulkRequestItem, n)
+	for i := range items {
+		items[i] = checkItem(fmt.Sprint(i + 1))
+	}
+	return items
+}
+
+func TestCheckBulkMaxItems(t *testing.T) {
+	assert.Equal(t, 1000, CheckBulkMaxItems())
+}
+
+func TestCheckBulkChunked(t *testing.T) {
+	consistency := &v1beta2.Consistency{Requirement: &v1beta2.Consistency_MinimizeLatency{MinimizeLatency: true}}
+
+	tests := []struct {
+		name             string
+		items            int
+		options          CheckBulkChunkOptions
+		expectedRequests int
+	}{
+		{name: "fits in one request", items: 5, options: CheckBulkChunkOptions{MaxItems: 10}, expectedRequests: 1},
+		{name: "sequential chunks", items: 25, options: CheckBulkChunkOptions{MaxItems: 10}, expectedRequests: 3},
+		{name: "concurrent chunks", items: 100, options: CheckBulkChunkOptions{MaxItems: 7, Concurrency: 4}, expectedRequests: 15},
+		{name: "default limit", items: 1500, expectedRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockChunkClient{}
+			items := numberedItems(tt.items)
+
+			response, err := CheckBulkChunked(context.Background(), client, &v1beta2.CheckBulkRequest{Items: items, Consistency: consistency}, tt.options)
+			require.NoError(t, err)
+			require.Len(t, response.GetPairs(), tt.items)
+			for i, pair := range response.GetPairs() {
+				assert.Same(t, items[i], pair.GetRequest(), "pair %d out of order", i)
+			}
+			assert.Len(t, client.requests, tt.expectedRequests)
+			for _, request := range client.requests {
+				assert.Equal(t, consistency, request.GetConsistency())
+			}
+
+			// The freshest token is the last chunk's, whose first id is highest.
+			chunkSize := tt.options.MaxItems
+			if chunkSize == 0 {
+				chunkSize = CheckBulkMaxItems()
+			}
+			lastChunk := items[(tt.items-1)/chunkSize*chunkSize]
+			assert.Equal(t, zedToken(lastChunk.GetObject().GetResourceId()).GetToken(), response.GetConsistencyToken().GetToken())
+		})
+	}
+}
+
+func TestCheckBulkChunked_error(t *testing.T) {
+	items := numberedItems(30)
+	items[10] = checkItem("fail")
+
+	_, err := CheckBulkChunked(context.Background(), &mockChunkClient{}, &v1beta2.CheckBulkRequest{Items: items}, CheckBulkChunkOptions{MaxItems: 10})
+	assert.Equal(t, codes.Unavailable, grpcstatus.Code(err))
+}
+
+func TestCheckBulkChunked_canceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := &mockChunkClient{}
+
+	_, err := CheckBulkChunked(ctx, client, &v1beta2.CheckBulkRequest{Items: numberedItems(30)}, CheckBulkChunkOptions{MaxItems: 10})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, client.requests)
+}
@@ -0,0 +1,67 @@
+package inventory
+
+import (
+	"context"
+	"sync"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// ConsistencyTracker holds the most recent consistency token seen by a
+// service, so reads that follow a write-protection check can ask for data at
+// least as fresh as that check. The zero value is ready to use and safe for
+// concurrent use.
+type ConsistencyTracker struct {
+	mu    sync.RWMutex
+	token *v1beta2.ConsistencyToken
+}
+
+// Observe records token, keeping the fresher of it and the current token when
+// MaxConsistencyToken can order them and token otherwise. Nil and empty tokens
+// are ignored so a response without a token does not clear an earlier one.
+func (t *ConsistencyTracker) Observe(token *v1beta2.ConsistencyToken) {
+	if token.GetToken() == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if max, err := MaxConsistencyToken(t.token, token); err == nil {
+		token = max
+	}
+	t.token = token
+}
+
+// Token returns the latest observed token, or nil if none was observed.
+func (t *ConsistencyTracker) Token() *v1beta2.ConsistencyToken {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.token
+}
+
+// Consistency returns AtLeastAsFresh(t.Token()), ready to set on a Check or
+// ListObjects request.
+func (t *ConsistencyTracker) Consistency() *v1beta2.Consistency {
+	return AtLeastAsFresh(t.Token())
+}
+
+// CheckForUpdateWithToken calls CheckForUpdate and returns whether the subject
+// is allowed along with the response's consistency token. Only ALLOWED_TRUE
+// counts as allowed. When tracker is non-nil the token is also recorded on
+// it; pass nil to skip tracking.
+func CheckForUpdateWithToken(
+	ctx context.Context,
+	client v1beta2.KesselInventoryServiceClient,
+	request *v1beta2.CheckForUpdateRequest,
+	tracker *ConsistencyTracker,
+) (bool, *v1beta2.ConsistencyToken, error) {
+	response, err := client.CheckForUpdate(ctx, request)
+	if err != nil {
+		return false, nil, err
+	}
+
+	token := response.GetConsistencyToken()
+	if tracker != nil {
+		tracker.Observe(token)
+	}
+	return response.GetAllowed() == v1beta2.Allowed_ALLOWED_TRUE, token, nil
+}
@@ -0,0 +1,97 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type mockCheckForUpdateClient struct {
+	v1beta2.KesselInventoryServiceClient
+	response *v1beta2.CheckForUpdateResponse
+	err      error
+}
+
+func (m *mockCheckForUpdateClient) CheckForUpdate(ctx context.Context, in *v1beta2.CheckForUpdateRequest, opts ...grpc.CallOption) (*v1beta2.CheckForUpdateResponse, error) {
+	return m.response, m.err
+}
+
+func TestCheckForUpdateWithToken(t *testing.T) {
+	token := &v1beta2.ConsistencyToken{Token: "token-1"}
+
+	tests := []struct {
+		name            string
+		client          *mockCheckForUpdateClient
+		tracker         *ConsistencyTracker
+		expectedAllowed bool
+		expectedToken   *v1beta2.ConsistencyToken
+		expectedError   bool
+	}{
+		{
+			name:            "allowed with tracker",
+			client:          &mockCheckForUpdateClient{response: &v1beta2.CheckForUpdateResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE, ConsistencyToken: token}},
+			tracker:         &ConsistencyTracker{},
+			expectedAllowed: true,
+			expectedToken:   token,
+		},
+		{
+			name:          "denied without tracker",
+			client:        &mockCheckForUpdateClient{response: &v1beta2.CheckForUpdateResponse{Allowed: v1beta2.Allowed_ALLOWED_FALSE, ConsistencyToken: token}},
+			expectedToken: token,
+		},
+		{
+			name:          "error",
+			client:        &mockCheckForUpdateClient{err: status.Error(codes.Unavailable, "down")},
+			tracker:       &ConsistencyTracker{},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, got, err := CheckForUpdateWithToken(context.Background(), tt.client, &v1beta2.CheckForUpdateRequest{}, tt.tracker)
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, tt.tracker.Token())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAllowed, allowed)
+			assert.Equal(t, tt.expectedToken, got)
+			if tt.tracker != nil {
+				assert.Equal(t, tt.expectedToken, tt.tracker.Token())
+			}
+		})
+	}
+}
+
+func TestConsistencyTracker(t *testing.T) {
+	var tracker ConsistencyTracker
+	assert.Nil(t, tracker.Token())
+	assert.Nil(t, tracker.Consistency())
+
+	first := &v1beta2.ConsistencyToken{Token: "token-1"}
+	tracker.Observe(first)
+	tracker.Observe(nil)
+	tracker.Observe(&v1beta2.ConsistencyToken{})
+	assert.Equal(t, first, tracker.Token())
+	assert.Equal(t, first, tracker.Consistency().GetAtLeastAsFresh())
+
+	second := &v1beta2.ConsistencyToken{Token: "token-2"}
+	tracker.Observe(second)
+	assert.Equal(t, second, tracker.Token())
+}
+
+func TestConsistencyTracker_keepsFreshest(t *testing.T) {
+	var tracker ConsistencyTracker
+	newer := zedToken("200")
+	tracker.Observe(newer)
+	tracker.Observe(zedToken("100"))
+	assert.Equal(t, newer, tracker.Token())
+}
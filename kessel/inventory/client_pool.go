@@ -0,0 +1,310 @@
+package inventory
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defaultMaxPooledClients = 64
+	// evictionGrace is how long an evicted client stays open for callers that
+	// fetched it just before it was evicted.
+	evictionGrace = 5 * time.Second
+)
+
+// TenantConfig identifies the endpoint and credentials a tenant's client
+// uses. Tenants with equal configurations share one client. An empty ClientId
+// builds an unauthenticated client.
+type TenantConfig struct {
+	Endpoint      string
+	ClientId      string
+	ClientSecret  string
+	TokenEndpoint string
+}
+
+type ClientPoolOptions struct {
+	// Maximum number of pooled clients. The least recently used client is
+	// evicted when a new one would exceed it, and closed once its calls have
+	// finished. Defaults to 64.
+	MaxClients int
+	// Transport credentials shared by every client, or nil for the default
+	// TLS configuration. Ignored when Insecure is set.
+	ChannelCredentials credentials.TransportCredentials
+	// Build plaintext clients without per-call credentials. Dev only. Implies
+	// the builder's AllowInsecureAuth, so any endpoint is accepted.
+	Insecure bool
+	// Optionally add feature options (rate limits, logging, ...) to each
+	// client's builder.
+	Configure func(*v1beta2.ClientBuilder) *v1beta2.ClientBuilder
+}
+
+// ClientPool lazily builds one inventory client per TenantConfig, for
+// multi-tenant services that talk to several endpoints or use per-tenant
+// credentials. Clients share the pool's transport credentials, and tenants
+// with the same client credentials share one token cache, which is dropped
+// with the last pooled client using it. The pool is safe for concurrent use.
+type ClientPool struct {
+	options       ClientPoolOptions
+	evictionGrace time.Duration
+	mu            sync.Mutex
+	clients       map[TenantConfig]*list.Element
+	lru           *list.List
+	credentials   map[TenantConfig]*pooledCredentials
+}
+
+// pooledCredentials is shared by the pooled clients of tenants with the same
+// client credentials, and dropped when the last of them leaves the pool.
+type pooledCredentials struct {
+	creds   *auth.OAuth2ClientCredentials
+	clients int
+}
+
+// pooledClient counts the calls in flight on its connection, so that an
+// evicted client is only closed once they have finished. The stub is built on
+// the pooledClient rather than the connection.
+type pooledClient struct {
+	config TenantConfig
+	client v1beta2.KesselInventoryServiceClient
+	conn   *grpc.ClientConn
+
+	mu       sync.Mutex
+	inFlight int
+	draining bool
+	closed   bool
+}
+
+func NewClientPool(options ClientPoolOptions) *ClientPool {
+	if options.MaxClients < 1 {
+		options.MaxClients = defaultMaxPooledClients
+	}
+	return &ClientPool{
+		options:       options,
+		evictionGrace: evictionGrace,
+		clients:       map[TenantConfig]*list.Element{},
+		lru:           list.New(),
+		credentials:   map[TenantConfig]*pooledCredentials{},
+	}
+}
+
+// Get returns the client for config, building it on first use. An evicted
+// client is closed a few seconds after its eviction, once no call is in
+// flight on it, so calls already started are not interrupted. Do not keep a
+// client beyond the calls it was fetched for; call Get again instead.
+func (p *ClientPool) Get(config TenantConfig) (v1beta2.KesselInventoryServiceClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pooled, err := p.get(config)
+	if err != nil {
+		return nil, err
+	}
+	return pooled.client, nil
+}
+
+// get returns the pooled client for config, building it and evicting the
+// least recently used client as needed. It must be called with p.mu held.
+func (p *ClientPool) get(config TenantConfig) (*pooledClient, error) {
+	if element, ok := p.clients[config]; ok {
+		p.lru.MoveToFront(element)
+		return element.Value.(*pooledClient), nil
+	}
+
+	creds := p.acquireCredentials(config)
+	builder := v1beta2.NewClientBuilder(config.Endpoint)
+	switch {
+	case p.options.Insecure:
+		builder.Insecure().AllowInsecureAuth()
+	case creds != nil:
+		builder.OAuth2ClientAuthenticated(creds, p.options.ChannelCredentials)
+	default:
+		builder.Unauthenticated(p.options.ChannelCredentials)
+	}
+	if p.options.Configure != nil {
+		builder = p.options.Configure(builder)
+	}
+
+	_, conn, err := builder.Build()
+	if err != nil {
+		p.releaseCredentials(config)
+		return nil, err
+	}
+
+	pooled := &pooledClient{config: config, conn: conn}
+	pooled.client = v1beta2.NewKesselInventoryServiceClient(pooled)
+	p.clients[config] = p.lru.PushFront(pooled)
+	for p.lru.Len() > p.options.MaxClients {
+		oldest := p.lru.Back()
+		evicted := p.lru.Remove(oldest).(*pooledClient)
+		delete(p.clients, evicted.config)
+		p.releaseCredentials(evicted.config)
+		time.AfterFunc(p.evictionGrace, evicted.drain)
+	}
+	return pooled, nil
+}
+
+// AuthRequest returns an auth.AuthRequest sharing the token cache of config's
+// inventory client, for RBAC calls made on the tenant's behalf. The client is
+// built if it is not pooled yet, so the credentials stay in the pool as long
+// as it does. It returns nil for a config without credentials, which
+// FetchWorkspaceOptions treats as no authentication.
+func (p *ClientPool) AuthRequest(config TenantConfig, options auth.OAuth2AuthRequestOptions) auth.AuthRequest {
+	if config.ClientId == "" {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.get(config); err != nil {
+		creds := auth.NewOAuth2ClientCredentials(config.ClientId, config.ClientSecret, config.TokenEndpoint)
+		return auth.OAuth2AuthRequest(&creds, options)
+	}
+	return auth.OAuth2AuthRequest(p.credentials[credentialsKey(config)].creds, options)
+}
+
+// Len returns the number of open clients.
+func (p *ClientPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lru.Len()
+}
+
+// Close closes every client in the pool, including calls in flight on them.
+// Evicted clients still close once idle. The pool may be used again
+// afterwards.
+func (p *ClientPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for element := p.lru.Front(); element != nil; element = element.Next() {
+		errs = append(errs, element.Value.(*pooledClient).close())
+	}
+	p.clients = map[TenantConfig]*list.Element{}
+	p.lru.Init()
+	clear(p.credentials)
+	return errors.Join(errs...)
+}
+
+// credentialsKey keys shared credentials without the endpoint, so tenants on
+// different endpoints reuse one token.
+func credentialsKey(config TenantConfig) TenantConfig {
+	return TenantConfig{ClientId: config.ClientId, ClientSecret: config.ClientSecret, TokenEndpoint: config.TokenEndpoint}
+}
+
+// acquireCredentials returns the shared credentials for config's client
+// credentials, or nil when it has none, and counts one more pooled client
+// using them. It must be called with p.mu held.
+func (p *ClientPool) acquireCredentials(config TenantConfig) *auth.OAuth2ClientCredentials {
+	if config.ClientId == "" {
+		return nil
+	}
+	key := credentialsKey(config)
+	shared, ok := p.credentials[key]
+	if !ok {
+		creds := auth.NewOAuth2ClientCredentials(config.ClientId, config.ClientSecret, config.TokenEndpoint)
+		shared = &pooledCredentials{creds: &creds}
+		p.credentials[key] = shared
+	}
+	shared.clients++
+	return shared.creds
+}
+
+// releaseCredentials undoes acquireCredentials for a client leaving the pool,
+// dropping the credentials once no pooled client uses them. It must be called
+// with p.mu held.
+func (p *ClientPool) releaseCredentials(config TenantConfig) {
+	if config.ClientId == "" {
+		return
+	}
+	key := credentialsKey(config)
+	if shared, ok := p.credentials[key]; ok {
+		shared.clients--
+		if shared.clients <= 0 {
+			delete(p.credentials, key)
+		}
+	}
+}
+
+func (c *pooledClient) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	c.begin()
+	defer c.end()
+	return c.conn.Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream counts the stream as in flight until it ends with an error
+// (io.EOF included) or its context is done.
+func (c *pooledClient) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	c.begin()
+	stream, err := c.conn.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		c.end()
+		return nil, err
+	}
+	end := sync.OnceFunc(c.end)
+	stop := context.AfterFunc(ctx, end)
+	return &pooledStream{ClientStream: stream, done: func() {
+		stop()
+		end()
+	}}, nil
+}
+
+func (c *pooledClient) begin() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inFlight++
+}
+
+func (c *pooledClient) end() {
+	c.mu.Lock()
+	c.inFlight--
+	idle := c.draining && c.inFlight == 0
+	c.mu.Unlock()
+	if idle {
+		_ = c.close()
+	}
+}
+
+// drain closes the client now if it is idle, or otherwise when its last call
+// in flight ends.
+func (c *pooledClient) drain() {
+	c.mu.Lock()
+	c.draining = true
+	idle := c.inFlight == 0
+	c.mu.Unlock()
+	if idle {
+		_ = c.close()
+	}
+}
+
+func (c *pooledClient) close() error {
+	c.mu.Lock()
+	closed := c.closed
+	c.closed = true
+	c.mu.Unlock()
+	if closed {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+type pooledStream struct {
+	grpc.ClientStream
+	done func()
+}
+
+func (s *pooledStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.done()
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+package inventory
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+func TestClientPool_Get(t *testing.T) {
+	pool := NewClientPool(ClientPoolOptions{Insecure: true})
+	defer pool.Close()
+
+	first, err := pool.Get(TenantConfig{Endpoint: "localhost:9000"})
+	require.NoError(t, err)
+	again, err := pool.Get(TenantConfig{Endpoint: "localhost:9000"})
+	require.NoError(t, err)
+	other, err := pool.Get(TenantConfig{Endpoint: "localhost:9001"})
+	require.NoError(t, err)
+
+	assert.Same(t, first, again)
+	assert.NotSame(t, first, other)
+	assert.Equal(t, 2, pool.Len())
+}
+
+func TestClientPool_evictsLeastRecentlyUsed(t *testing.T) {
+	pool := NewClientPool(ClientPoolOptions{MaxClients: 2, Insecure: true})
+	defer pool.Close()
+
+	a := TenantConfig{Endpoint: "a:9000"}
+	b := TenantConfig{Endpoint: "b:9000"}
+	c := TenantConfig{Endpoint: "c:9000"}
+
+	clientA, err := pool.Get(a)
+	require.NoError(t, err)
+	_, err = pool.Get(b)
+	require.NoError(t, err)
+	_, err = pool.Get(a)
+	require.NoError(t, err)
+	_, err = pool.Get(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, pool.Len())
+	assert.Contains(t, pool.clients, a)
+	assert.NotContains(t, pool.clients, b)
+
+	again, err := pool.Get(a)
+	require.NoError(t, err)
+	assert.Same(t, clientA, again)
+}
+
+func TestClientPool_sharesCredentials(t *testing.T) {
+	pool := NewClientPool(ClientPoolOptions{})
+	defer pool.Close()
+
+	east := TenantConfig{Endpoint: "east:9000", ClientId: "svc", ClientSecret: "secret", TokenEndpoint: "https://sso.example.com/token"}
+	west := east
+	west.Endpoint = "west:9000"
+
+	_, err := pool.Get(east)
+	require.NoError(t, err)
+	_, err = pool.Get(west)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, pool.Len())
+	assert.Len(t, pool.credentials, 1)
+	assert.NotNil(t, pool.AuthRequest(east, auth.OAuth2AuthRequestOptions{}))
+	assert.Nil(t, pool.AuthRequest(TenantConfig{Endpoint: "east:9000"}, auth.OAuth2AuthRequestOptions{}))
+}
+
+func TestClientPool_releasesCredentials(t *testing.T) {
+	pool := NewClientPool(ClientPoolOptions{MaxClients: 2})
+	defer pool.Close()
+
+	tenant := func(id string) TenantConfig {
+		return TenantConfig{Endpoint: "kessel:9000", ClientId: id, ClientSecret: "secret", TokenEndpoint: "https://sso.example.com/token"}
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		_, err := pool.Get(tenant(id))
+		require.NoError(t, err)
+	}
+	assert.Len(t, pool.credentials, 2, "the evicted tenant's credentials should be dropped")
+	assert.NotContains(t, pool.credentials, credentialsKey(tenant("a")))
+
+	assert.NotNil(t, pool.AuthRequest(tenant("d"), auth.OAuth2AuthRequestOptions{}))
+	assert.Len(t, pool.credentials, 2, "AuthRequest should pool the client holding its credentials")
+
+	require.NoError(t, pool.Close())
+	assert.Empty(t, pool.credentials)
+}
+
+func TestClientPool_configureAndErrors(t *testing.T) {
+	configured := 0
+	pool := NewClientPool(ClientPoolOptions{
+		Insecure: true,
+		Configure: func(builder *v1beta2.ClientBuilder) *v1beta2.ClientBuilder {
+			configured++
+			return builder.WithReadOnly()
+		},
+	})
+	defer pool.Close()
+
+	_, err := pool.Get(TenantConfig{Endpoint: "localhost:9000"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, configured)
+
+	_, err = pool.Get(TenantConfig{})
+	assert.Error(t, err)
+	assert.Equal(t, 1, pool.Len())
+
+	require.NoError(t, pool.Close())
+	assert.Zero(t, pool.Len())
+}
+
+// startBlockingServer fails every call with NotFound once release is closed,
+// and sends a value on started when each call arrives.
+func startBlockingServer(t *testing.T, started chan<- struct{}, release <-chan struct{}) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(srv any, stream grpc.ServerStream) error {
+		started <- struct{}{}
+		<-release
+		return status.Error(codes.NotFound, "released")
+	}))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestClientPool_evictionWaitsForCallsInFlight(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	addr := startBlockingServer(t, started, release)
+	pool := NewClientPool(ClientPoolOptions{MaxClients: 1, Insecure: true})
+	pool.evictionGrace = time.Millisecond
+	defer pool.Close()
+
+	tenant := TenantConfig{Endpoint: addr}
+	client, err := pool.Get(tenant)
+	require.NoError(t, err)
+	evicted := pool.clients[tenant].Value.(*pooledClient)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.Check(context.Background(), &v1beta2.CheckRequest{})
+		errs <- err
+	}()
+	<-started
+
+	_, err = pool.Get(TenantConfig{Endpoint: "localhost:9001"})
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	assert.NotEqual(t, connectivity.Shutdown, evicted.conn.GetState(), "the call in flight keeps the client open")
+
+	close(release)
+	assert.Equal(t, codes.NotFound, status.Code(<-errs))
+	require.Eventually(t, func() bool { return evicted.conn.GetState() == connectivity.Shutdown }, time.Second, time.Millisecond)
+}
+
+func TestClientPool_evictedIdleClientCloses(t *testing.T) {
+	pool := NewClientPool(ClientPoolOptions{MaxClients: 1, Insecure: true})
+	pool.evictionGrace = time.Millisecond
+	defer pool.Close()
+
+	tenant := TenantConfig{Endpoint: "localhost:9000"}
+	_, err := pool.Get(tenant)
+	require.NoError(t, err)
+	evicted := pool.clients[tenant].Value.(*pooledClient)
+
+	_, err = pool.Get(TenantConfig{Endpoint: "localhost:9001"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return evicted.conn.GetState() == connectivity.Shutdown }, time.Second, time.Millisecond)
+}
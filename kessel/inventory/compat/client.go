@@ -0,0 +1,46 @@
+package compat
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// Client makes Inventory calls with the compat types over a v1beta2 client.
+type Client struct {
+	client v1beta2.KesselInventoryServiceClient
+}
+
+// NewClient returns a Client that sends its calls through client, e.g. one
+// built with v1beta2.NewClientBuilder.
+func NewClient(client v1beta2.KesselInventoryServiceClient) *Client {
+	return &Client{client: client}
+}
+
+// Check reports whether request.Subject has request.Relation on
+// request.Object.
+func (c *Client) Check(ctx context.Context, request CheckRequest, opts ...grpc.CallOption) (CheckResponse, error) {
+	response, err := c.client.Check(ctx, request.ToV1beta2(), opts...)
+	if err != nil {
+		return CheckResponse{}, err
+	}
+	return CheckResponseFromV1beta2(response), nil
+}
+
+// ReportResource reports the state of a resource.
+func (c *Client) ReportResource(ctx context.Context, request ReportResourceRequest, opts ...grpc.CallOption) error {
+	message, err := request.ToV1beta2()
+	if err != nil {
+		return err
+	}
+	_, err = c.client.ReportResource(ctx, message, opts...)
+	return err
+}
+
+// DeleteResource deletes a resource.
+func (c *Client) DeleteResource(ctx context.Context, request DeleteResourceRequest, opts ...grpc.CallOption) error {
+	_, err := c.client.DeleteResource(ctx, request.ToV1beta2(), opts...)
+	return err
+}
@@ -0,0 +1,288 @@
+// Package compat holds transport-agnostic request and response types for the
+// Kessel Inventory API. They map onto the v1beta2 protobuf messages today and
+// will map onto the GA API when it is released, so code written against
+// compat keeps compiling when the protobuf package is promoted. Each type
+// converts both ways: ToV1beta2 builds the message and <Type>FromV1beta2 reads
+// one back.
+//
+// The types cover the core resource and check calls. Use the v1beta2 package
+// directly for the rest of the API.
+package compat
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// ReporterReference identifies the service that reports a resource.
+type ReporterReference struct {
+	Type       string
+	InstanceId string
+}
+
+// ResourceReference identifies a resource.
+type ResourceReference struct {
+	Type string
+	Id   string
+	// Reporter is optional.
+	Reporter *ReporterReference
+}
+
+// SubjectReference is the subject of a check: a resource, or the subjects
+// with Relation on it when Relation is set.
+type SubjectReference struct {
+	Resource ResourceReference
+	Relation string
+}
+
+// ConsistencyMode selects the snapshot a read is evaluated against.
+type ConsistencyMode int
+
+const (
+	// ConsistencyDefault leaves the choice to the server.
+	ConsistencyDefault ConsistencyMode = iota
+	ConsistencyMinimizeLatency
+	// ConsistencyAtLeastAsFresh requires Consistency.Token.
+	ConsistencyAtLeastAsFresh
+	ConsistencyAtLeastAsAcknowledged
+)
+
+// Consistency is the consistency requirement of a read.
+type Consistency struct {
+	Mode ConsistencyMode
+	// Token is the consistency token for ConsistencyAtLeastAsFresh.
+	Token string
+}
+
+// CheckRequest asks whether Subject has Relation on Object.
+type CheckRequest struct {
+	Object      ResourceReference
+	Relation    string
+	Subject     SubjectReference
+	Consistency Consistency
+}
+
+// CheckResponse is the outcome of a check.
+type CheckResponse struct {
+	Allowed bool
+	// ConsistencyToken can be used for later reads that must be at least as
+	// fresh as this check.
+	ConsistencyToken string
+}
+
+// ReportResourceRequest reports the state of a resource.
+type ReportResourceRequest struct {
+	Type               string
+	ReporterType       string
+	ReporterInstanceId string
+	LocalResourceId    string
+	ApiHref            string
+	ConsoleHref        string
+	ReporterVersion    string
+	TransactionId      string
+	// Common and Reporter are the representations, which must be
+	// convertible with structpb.NewStruct.
+	Common   map[string]any
+	Reporter map[string]any
+	// Immediate waits until the write is visible to subsequent reads.
+	Immediate bool
+}
+
+// DeleteResourceRequest deletes a resource.
+type DeleteResourceRequest struct {
+	Reference ResourceReference
+}
+
+// ToV1beta2 returns r as a v1beta2 message.
+func (r ReporterReference) ToV1beta2() *v1beta2.ReporterReference {
+	reporter := &v1beta2.ReporterReference{Type: r.Type}
+	if r.InstanceId != "" {
+		reporter.InstanceId = &r.InstanceId
+	}
+	return reporter
+}
+
+// ReporterReferenceFromV1beta2 converts a v1beta2 reporter reference.
+func ReporterReferenceFromV1beta2(m *v1beta2.ReporterReference) ReporterReference {
+	return ReporterReference{Type: m.GetType(), InstanceId: m.GetInstanceId()}
+}
+
+// ToV1beta2 returns r as a v1beta2 message.
+func (r ResourceReference) ToV1beta2() *v1beta2.ResourceReference {
+	reference := &v1beta2.ResourceReference{ResourceType: r.Type, ResourceId: r.Id}
+	if r.Reporter != nil {
+		reference.Reporter = r.Reporter.ToV1beta2()
+	}
+	return reference
+}
+
+// ResourceReferenceFromV1beta2 converts a v1beta2 resource reference.
+func ResourceReferenceFromV1beta2(m *v1beta2.ResourceReference) ResourceReference {
+	reference := ResourceReference{Type: m.GetResourceType(), Id: m.GetResourceId()}
+	if m.GetReporter() != nil {
+		reporter := ReporterReferenceFromV1beta2(m.GetReporter())
+		reference.Reporter = &reporter
+	}
+	return reference
+}
+
+// ToV1beta2 returns s as a v1beta2 message.
+func (s SubjectReference) ToV1beta2() *v1beta2.SubjectReference {
+	subject := &v1beta2.SubjectReference{Resource: s.Resource.ToV1beta2()}
+	if s.Relation != "" {
+		subject.Relation = &s.Relation
+	}
+	return subject
+}
+
+// SubjectReferenceFromV1beta2 converts a v1beta2 subject reference.
+func SubjectReferenceFromV1beta2(m *v1beta2.SubjectReference) SubjectReference {
+	return SubjectReference{Resource: ResourceReferenceFromV1beta2(m.GetResource()), Relation: m.GetRelation()}
+}
+
+// ToV1beta2 returns c as a v1beta2 message, or nil for ConsistencyDefault.
+func (c Consistency) ToV1beta2() *v1beta2.Consistency {
+	switch c.Mode {
+	case ConsistencyMinimizeLatency:
+		return &v1beta2.Consistency{Requirement: &v1beta2.Consistency_MinimizeLatency{MinimizeLatency: true}}
+	case ConsistencyAtLeastAsFresh:
+		return &v1beta2.Consistency{Requirement: &v1beta2.Consistency_AtLeastAsFresh{AtLeastAsFresh: &v1beta2.ConsistencyToken{Token: c.Token}}}
+	case ConsistencyAtLeastAsAcknowledged:
+		return &v1beta2.Consistency{Requirement: &v1beta2.Consistency_AtLeastAsAcknowledged{AtLeastAsAcknowledged: true}}
+	default:
+		return nil
+	}
+}
+
+// ConsistencyFromV1beta2 converts a v1beta2 consistency; nil converts to
+// ConsistencyDefault.
+func ConsistencyFromV1beta2(m *v1beta2.Consistency) Consistency {
+	switch {
+	case m.GetMinimizeLatency():
+		return Consistency{Mode: ConsistencyMinimizeLatency}
+	case m.GetAtLeastAsFresh() != nil:
+		return Consistency{Mode: ConsistencyAtLeastAsFresh, Token: m.GetAtLeastAsFresh().GetToken()}
+	case m.GetAtLeastAsAcknowledged():
+		return Consistency{Mode: ConsistencyAtLeastAsAcknowledged}
+	default:
+		return Consistency{}
+	}
+}
+
+// ToV1beta2 returns r as a v1beta2 message.
+func (r CheckRequest) ToV1beta2() *v1beta2.CheckRequest {
+	return &v1beta2.CheckRequest{
+		Object:      r.Object.ToV1beta2(),
+		Relation:    r.Relation,
+		Subject:     r.Subject.ToV1beta2(),
+		Consistency: r.Consistency.ToV1beta2(),
+	}
+}
+
+// CheckRequestFromV1beta2 converts a v1beta2 check request.
+func CheckRequestFromV1beta2(m *v1beta2.CheckRequest) CheckRequest {
+	return CheckRequest{
+		Object:      ResourceReferenceFromV1beta2(m.GetObject()),
+		Relation:    m.GetRelation(),
+		Subject:     SubjectReferenceFromV1beta2(m.GetSubject()),
+		Consistency: ConsistencyFromV1beta2(m.GetConsistency()),
+	}
+}
+
+// ToV1beta2 returns r as a v1beta2 message.
+func (r CheckResponse) ToV1beta2() *v1beta2.CheckResponse {
+	response := &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_FALSE}
+	if r.Allowed {
+		response.Allowed = v1beta2.Allowed_ALLOWED_TRUE
+	}
+	if r.ConsistencyToken != "" {
+		response.ConsistencyToken = &v1beta2.ConsistencyToken{Token: r.ConsistencyToken}
+	}
+	return response
+}
+
+// CheckResponseFromV1beta2 converts a v1beta2 check response. Only
+// ALLOWED_TRUE counts as allowed.
+func CheckResponseFromV1beta2(m *v1beta2.CheckResponse) CheckResponse {
+	return CheckResponse{
+		Allowed:          m.GetAllowed() == v1beta2.Allowed_ALLOWED_TRUE,
+		ConsistencyToken: m.GetConsistencyToken().GetToken(),
+	}
+}
+
+// ToV1beta2 returns r as a v1beta2 message. It fails if a representation
+// cannot be converted to a structpb.Struct.
+func (r ReportResourceRequest) ToV1beta2() (*v1beta2.ReportResourceRequest, error) {
+	metadata := &v1beta2.RepresentationMetadata{LocalResourceId: r.LocalResourceId, ApiHref: r.ApiHref}
+	if r.ConsoleHref != "" {
+		metadata.ConsoleHref = &r.ConsoleHref
+	}
+	if r.ReporterVersion != "" {
+		metadata.ReporterVersion = &r.ReporterVersion
+	}
+	if r.TransactionId != "" {
+		metadata.IdempotencyKey = &v1beta2.RepresentationMetadata_TransactionId{TransactionId: r.TransactionId}
+	}
+	representations := &v1beta2.ResourceRepresentations{Metadata: metadata}
+	if r.Common != nil {
+		common, err := structpb.NewStruct(r.Common)
+		if err != nil {
+			return nil, fmt.Errorf("invalid common representation: %w", err)
+		}
+		representations.Common = common
+	}
+	if r.Reporter != nil {
+		reporter, err := structpb.NewStruct(r.Reporter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reporter representation: %w", err)
+		}
+		representations.Reporter = reporter
+	}
+
+	request := &v1beta2.ReportResourceRequest{
+		Type:               r.Type,
+		ReporterType:       r.ReporterType,
+		ReporterInstanceId: r.ReporterInstanceId,
+		Representations:    representations,
+	}
+	if r.Immediate {
+		request.WriteVisibility = v1beta2.WriteVisibility_IMMEDIATE
+	}
+	return request, nil
+}
+
+// ReportResourceRequestFromV1beta2 converts a v1beta2 report request.
+func ReportResourceRequestFromV1beta2(m *v1beta2.ReportResourceRequest) ReportResourceRequest {
+	metadata := m.GetRepresentations().GetMetadata()
+	request := ReportResourceRequest{
+		Type:               m.GetType(),
+		ReporterType:       m.GetReporterType(),
+		ReporterInstanceId: m.GetReporterInstanceId(),
+		LocalResourceId:    metadata.GetLocalResourceId(),
+		ApiHref:            metadata.GetApiHref(),
+		ConsoleHref:        metadata.GetConsoleHref(),
+		ReporterVersion:    metadata.GetReporterVersion(),
+		TransactionId:      metadata.GetTransactionId(),
+		Immediate:          m.GetWriteVisibility() == v1beta2.WriteVisibility_IMMEDIATE,
+	}
+	if common := m.GetRepresentations().GetCommon(); common != nil {
+		request.Common = common.AsMap()
+	}
+	if reporter := m.GetRepresentations().GetReporter(); reporter != nil {
+		request.Reporter = reporter.AsMap()
+	}
+	return request
+}
+
+// ToV1beta2 returns r as a v1beta2 message.
+func (r DeleteResourceRequest) ToV1beta2() *v1beta2.DeleteResourceRequest {
+	return &v1beta2.DeleteResourceRequest{Reference: r.Reference.ToV1beta2()}
+}
+
+// DeleteResourceRequestFromV1beta2 converts a v1beta2 delete request.
+func DeleteResourceRequestFromV1beta2(m *v1beta2.DeleteResourceRequest) DeleteResourceRequest {
+	return DeleteResourceRequest{Reference: ResourceReferenceFromV1beta2(m.GetReference())}
+}
@@ -0,0 +1,145 @@
+package compat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"github.com/project-kessel/kessel-sdk-go/kessel/testutil"
+)
+
+func TestCheckRequest_roundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		consistency Consistency
+	}{
+		{name: "default consistency"},
+		{name: "minimize latency", consistency: Consistency{Mode: ConsistencyMinimizeLatency}},
+		{name: "at least as fresh", consistency: Consistency{Mode: ConsistencyAtLeastAsFresh, Token: "token-1"}},
+		{name: "at least as acknowledged", consistency: Consistency{Mode: ConsistencyAtLeastAsAcknowledged}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := CheckRequest{
+				Object:      ResourceReference{Type: "host", Id: "host-1", Reporter: &ReporterReference{Type: "hbi"}},
+				Relation:    "view",
+				Subject:     SubjectReference{Resource: ResourceReference{Type: "principal", Id: "redhat/alice", Reporter: &ReporterReference{Type: "rbac"}}},
+				Consistency: tt.consistency,
+			}
+
+			assert.Equal(t, request, CheckRequestFromV1beta2(request.ToV1beta2()))
+		})
+	}
+}
+
+func TestCheckRequest_ToV1beta2(t *testing.T) {
+	request := CheckRequest{
+		Object:   ResourceReference{Type: "workspace", Id: "ws-1", Reporter: &ReporterReference{Type: "rbac"}},
+		Relation: "view",
+		Subject:  SubjectReference{Resource: ResourceReference{Type: "principal", Id: "redhat/alice", Reporter: &ReporterReference{Type: "rbac"}}},
+	}
+
+	testutil.AssertProtoEqual(t, testutil.CheckRequest(testutil.Workspace("ws-1"), "view", testutil.Principal("alice")), request.ToV1beta2())
+}
+
+func TestSubjectReference_relation(t *testing.T) {
+	subject := SubjectReference{Resource: ResourceReference{Type: "group", Id: "g-1"}, Relation: "member"}
+
+	message := subject.ToV1beta2()
+
+	assert.Equal(t, "member", message.GetRelation())
+	assert.Nil(t, message.GetResource().Reporter)
+	assert.Equal(t, subject, SubjectReferenceFromV1beta2(message))
+}
+
+func TestCheckResponse_roundTrip(t *testing.T) {
+	for _, response := range []CheckResponse{{Allowed: true, ConsistencyToken: "token-1"}, {Allowed: false}} {
+		assert.Equal(t, response, CheckResponseFromV1beta2(response.ToV1beta2()))
+	}
+	assert.False(t, CheckResponseFromV1beta2(&v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_UNSPECIFIED}).Allowed)
+}
+
+func TestReportResourceRequest_roundTrip(t *testing.T) {
+	request := ReportResourceRequest{
+		Type:               "host",
+		ReporterType:       "hbi",
+		ReporterInstanceId: "hbi-1",
+		LocalResourceId:    "host-1",
+		ApiHref:            "https://example.com/api/host-1",
+		ConsoleHref:        "https://example.com/host-1",
+		ReporterVersion:    "1.0",
+		TransactionId:      "tx-1",
+		Common:             map[string]any{"workspace_id": "ws-1"},
+		Reporter:           map[string]any{"satellite_id": "sat-1", "tags": []any{"a", "b"}},
+		Immediate:          true,
+	}
+
+	message, err := request.ToV1beta2()
+	require.NoError(t, err)
+
+	assert.Equal(t, v1beta2.WriteVisibility_IMMEDIATE, message.GetWriteVisibility())
+	assert.Equal(t, "tx-1", message.GetRepresentations().GetMetadata().GetTransactionId())
+	assert.Equal(t, request, ReportResourceRequestFromV1beta2(message))
+}
+
+func TestReportResourceRequest_invalidRepresentation(t *testing.T) {
+	_, err := ReportResourceRequest{Common: map[string]any{"bad": struct{}{}}}.ToV1beta2()
+
+	assert.ErrorContains(t, err, "invalid common representation")
+}
+
+func TestDeleteResourceRequest_roundTrip(t *testing.T) {
+	request := DeleteResourceRequest{Reference: ResourceReference{Type: "host", Id: "host-1", Reporter: &ReporterReference{Type: "hbi", InstanceId: "hbi-1"}}}
+
+	message := request.ToV1beta2()
+
+	assert.Equal(t, "hbi-1", message.GetReference().GetReporter().GetInstanceId())
+	assert.Equal(t, request, DeleteResourceRequestFromV1beta2(message))
+}
+
+type mockInventoryClient struct {
+	v1beta2.KesselInventoryServiceClient
+	check  *v1beta2.CheckRequest
+	report *v1beta2.ReportResourceRequest
+	delete *v1beta2.DeleteResourceRequest
+}
+
+func (m *mockInventoryClient) Check(ctx context.Context, in *v1beta2.CheckRequest, opts ...grpc.CallOption) (*v1beta2.CheckResponse, error) {
+	m.check = in
+	return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE, ConsistencyToken: &v1beta2.ConsistencyToken{Token: "token-1"}}, nil
+}
+
+func (m *mockInventoryClient) ReportResource(ctx context.Context, in *v1beta2.ReportResourceRequest, opts ...grpc.CallOption) (*v1beta2.ReportResourceResponse, error) {
+	m.report = in
+	return &v1beta2.ReportResourceResponse{}, nil
+}
+
+func (m *mockInventoryClient) DeleteResource(ctx context.Context, in *v1beta2.DeleteResourceRequest, opts ...grpc.CallOption) (*v1beta2.DeleteResourceResponse, error) {
+	m.delete = in
+	return &v1beta2.DeleteResourceResponse{}, nil
+}
+
+func TestClient(t *testing.T) {
+	mock := &mockInventoryClient{}
+	client := NewClient(mock)
+	ctx := context.Background()
+
+	response, err := client.Check(ctx, CheckRequest{Object: ResourceReference{Type: "host", Id: "host-1"}, Relation: "view"})
+	require.NoError(t, err)
+	assert.Equal(t, CheckResponse{Allowed: true, ConsistencyToken: "token-1"}, response)
+	assert.Equal(t, "host-1", mock.check.GetObject().GetResourceId())
+
+	require.NoError(t, client.ReportResource(ctx, ReportResourceRequest{Type: "host", LocalResourceId: "host-1", Common: map[string]any{"workspace_id": "ws-1"}}))
+	testutil.AssertProtoEqual(t, &structpb.Struct{Fields: map[string]*structpb.Value{"workspace_id": structpb.NewStringValue("ws-1")}}, mock.report.GetRepresentations().GetCommon())
+
+	require.NoError(t, client.DeleteResource(ctx, DeleteResourceRequest{Reference: ResourceReference{Type: "host", Id: "host-1"}}))
+	assert.Equal(t, "host-1", mock.delete.GetReference().GetResourceId())
+
+	assert.Error(t, client.ReportResource(ctx, ReportResourceRequest{Reporter: map[string]any{"bad": struct{}{}}}))
+}
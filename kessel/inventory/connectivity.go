@@ -0,0 +1,57 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// WaitForReady connects conn if it is idle and blocks until it is READY, so
+// services can gate their readiness on the Kessel connection instead of
+// failing their first requests. It keeps waiting through TRANSIENT_FAILURE,
+// since gRPC keeps reconnecting, and fails only when ctx ends or conn is
+// closed. The connection stage of SelfTest uses it too.
+func WaitForReady(ctx context.Context, conn *grpc.ClientConn) error {
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Shutdown:
+			return fmt.Errorf("connection to %s is closed", conn.Target())
+		case connectivity.Idle:
+			conn.Connect()
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return errors.Join(fmt.Errorf("connection to %s is %s", conn.Target(), state), ctx.Err())
+		}
+	}
+}
+
+// WatchConnectivityState sends the state of conn on the returned channel,
+// starting with the current state and then on every change. The channel is
+// closed once ctx ends or after the SHUTDOWN state is sent. Receive promptly:
+// changes that happen while a send is pending are observed as the latest
+// state only.
+func WatchConnectivityState(ctx context.Context, conn *grpc.ClientConn) <-chan connectivity.State {
+	states := make(chan connectivity.State, 1)
+	go func() {
+		defer close(states)
+		for {
+			state := conn.GetState()
+			select {
+			case states <- state:
+			case <-ctx.Done():
+				return
+			}
+			if state == connectivity.Shutdown || !conn.WaitForStateChange(ctx, state) {
+				return
+			}
+		}
+	}()
+	return states
+}
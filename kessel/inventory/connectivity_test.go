@@ -0,0 +1,90 @@
+package inventory
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestWaitForReady(t *testing.T) {
+	conn := newReadyConn(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, WaitForReady(ctx, conn))
+	assert.Equal(t, connectivity.Ready, conn.GetState())
+}
+
+func TestWaitForReady_waitsForServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Start the server only after the first connection attempts fail.
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return
+		}
+		server := grpc.NewServer()
+		t.Cleanup(server.Stop)
+		_ = server.Serve(listener)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, WaitForReady(ctx, conn))
+}
+
+func TestWaitForReady_deadline(t *testing.T) {
+	conn, err := grpc.NewClient("127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = WaitForReady(ctx, conn)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "connection to 127.0.0.1:1")
+}
+
+func TestWaitForReady_closed(t *testing.T) {
+	conn, err := grpc.NewClient("127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	assert.ErrorContains(t, WaitForReady(context.Background(), conn), "is closed")
+}
+
+func TestWatchConnectivityState(t *testing.T) {
+	conn := newReadyConn(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	states := WatchConnectivityState(ctx, conn)
+	assert.Equal(t, connectivity.Idle, <-states)
+	conn.Connect()
+
+	var last connectivity.State
+	for state := range states {
+		last = state
+		if state == connectivity.Ready {
+			require.NoError(t, conn.Close())
+		}
+	}
+	assert.Equal(t, connectivity.Shutdown, last)
+}
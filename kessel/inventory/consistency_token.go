@@ -0,0 +1,173 @@
+package inventory
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ConsistencyTokenHeader is the HTTP header (or message header) suggested for
+// carrying an encoded consistency token between services.
+const ConsistencyTokenHeader = "X-Kessel-Consistency-Token"
+
+const consistencyTokenVersion = "v1"
+
+// EncodeConsistencyToken turns a consistency token into an opaque, versioned,
+// URL- and header-safe string so a writing service can hand it to a reading
+// service through Kafka messages or HTTP headers. A nil or empty token encodes
+// to "".
+func EncodeConsistencyToken(token *v1beta2.ConsistencyToken) string {
+	if token.GetToken() == "" {
+		return ""
+	}
+	return consistencyTokenVersion + "." + base64.RawURLEncoding.EncodeToString([]byte(token.GetToken()))
+}
+
+// DecodeConsistencyToken reverses EncodeConsistencyToken. "" decodes to a nil
+// token, which callers can pass on as "no consistency requirement". Strings
+// from an unknown encoding version are rejected.
+func DecodeConsistencyToken(encoded string) (*v1beta2.ConsistencyToken, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	version, payload, ok := strings.Cut(encoded, ".")
+	if !ok {
+		return nil, fmt.Errorf("invalid consistency token: missing version")
+	}
+	if version != consistencyTokenVersion {
+		return nil, fmt.Errorf("unsupported consistency token version %q", version)
+	}
+
+	token, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid consistency token: %w", err)
+	}
+	if len(token) == 0 {
+		return nil, fmt.Errorf("invalid consistency token: empty payload")
+	}
+	return &v1beta2.ConsistencyToken{Token: string(token)}, nil
+}
+
+// SetConsistencyTokenHeader encodes token into ConsistencyTokenHeader. Nothing
+// is set for a nil or empty token.
+func SetConsistencyTokenHeader(header http.Header, token *v1beta2.ConsistencyToken) {
+	if encoded := EncodeConsistencyToken(token); encoded != "" {
+		header.Set(ConsistencyTokenHeader, encoded)
+	}
+}
+
+// ConsistencyTokenFromHeader decodes ConsistencyTokenHeader, returning a nil
+// token when the header is absent.
+func ConsistencyTokenFromHeader(header http.Header) (*v1beta2.ConsistencyToken, error) {
+	return DecodeConsistencyToken(header.Get(ConsistencyTokenHeader))
+}
+
+// AtLeastAsFresh returns a Consistency requiring reads to be at least as fresh
+// as token, or nil (the server default) when token is nil.
+func AtLeastAsFresh(token *v1beta2.ConsistencyToken) *v1beta2.Consistency {
+	if token == nil {
+		return nil
+	}
+	return &v1beta2.Consistency{Requirement: &v1beta2.Consistency_AtLeastAsFresh{AtLeastAsFresh: token}}
+}
+
+// ErrIncomparableTokens is returned by MaxConsistencyToken when the tokens'
+// revisions cannot be ordered.
+var ErrIncomparableTokens = errors.New("consistency tokens are not comparable")
+
+// MaxConsistencyToken returns the freshest of tokens, so a service that made
+// several writes can keep a single token for read-your-writes (for example
+// across restarts, stored with EncodeConsistencyToken). Nil and empty tokens
+// are ignored and nil is returned when no token is left.
+//
+// Kessel tokens are SpiceDB ZedTokens. Only ZedTokens whose revisions are
+// decimal (CockroachDB, Spanner, MySQL and in-memory datastores) can be
+// ordered; for other tokens MaxConsistencyToken returns ErrIncomparableTokens
+// unless they are all equal.
+func MaxConsistencyToken(tokens ...*v1beta2.ConsistencyToken) (*v1beta2.ConsistencyToken, error) {
+	var max *v1beta2.ConsistencyToken
+	var maxRevision *big.Rat
+	for _, token := range tokens {
+		if token.GetToken() == "" {
+			continue
+		}
+		if max == nil {
+			max = token
+			maxRevision = zedTokenRevision(token.GetToken())
+			continue
+		}
+		if token.GetToken() == max.GetToken() {
+			continue
+		}
+
+		revision := zedTokenRevision(token.GetToken())
+		if revision == nil || maxRevision == nil {
+			return nil, ErrIncomparableTokens
+		}
+		if revision.Cmp(maxRevision) > 0 {
+			max, maxRevision = token, revision
+		}
+	}
+	return max, nil
+}
+
+// zedTokenRevision decodes a ZedToken (base64 of a DecodedZedToken message
+// whose field 3 holds a message with the revision in field 1) and returns its
+// revision as a number, or nil if the token is not a ZedToken with a decimal
+// revision.
+func zedTokenRevision(token string) *big.Rat {
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil
+	}
+	v1 := protowireField(decoded, 3)
+	if v1 == nil {
+		return nil
+	}
+	revision := protowireField(v1, 1)
+	if len(revision) == 0 || strings.ContainsAny(string(revision), "eE/+-") {
+		return nil
+	}
+	value, ok := new(big.Rat).SetString(string(revision))
+	if !ok {
+		return nil
+	}
+	return value
+}
+
+// protowireField returns the bytes of the last length-delimited field number
+// in message, or nil if it is absent or message is malformed.
+func protowireField(message []byte, number protowire.Number) []byte {
+	var field []byte
+	for len(message) > 0 {
+		num, typ, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return nil
+		}
+		message = message[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, message)
+			if n < 0 {
+				return nil
+			}
+			message = message[n:]
+			continue
+		}
+		value, n := protowire.ConsumeBytes(message)
+		if n < 0 {
+			return nil
+		}
+		message = message[n:]
+		if num == number {
+			field = value
+		}
+	}
+	return field
+}
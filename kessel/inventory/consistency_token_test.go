@@ -0,0 +1,116 @@
+package inventory
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+func TestEncodeConsistencyToken_roundTrip(t *testing.T) {
+	token := &v1beta2.ConsistencyToken{Token: "GhUKEzE3NTY/+wMjk3MDAwMDAwMDA="}
+
+	encoded := EncodeConsistencyToken(token)
+	assert.Regexp(t, `^v1\.[A-Za-z0-9_-]+$`, encoded)
+
+	decoded, err := DecodeConsistencyToken(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, token.Token, decoded.Token)
+}
+
+func TestEncodeConsistencyToken_empty(t *testing.T) {
+	assert.Equal(t, "", EncodeConsistencyToken(nil))
+	assert.Equal(t, "", EncodeConsistencyToken(&v1beta2.ConsistencyToken{}))
+
+	decoded, err := DecodeConsistencyToken("")
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+}
+
+func TestDecodeConsistencyToken_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{name: "missing version", encoded: "abc"},
+		{name: "unknown version", encoded: "v2.YWJj"},
+		{name: "bad payload", encoded: "v1.***"},
+		{name: "empty payload", encoded: "v1."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeConsistencyToken(tt.encoded)
+			assert.Error(t, err)
+			assert.Nil(t, decoded)
+		})
+	}
+}
+
+func TestConsistencyTokenHeader(t *testing.T) {
+	header := http.Header{}
+	SetConsistencyTokenHeader(header, nil)
+	assert.Empty(t, header)
+
+	token := &v1beta2.ConsistencyToken{Token: "token-1"}
+	SetConsistencyTokenHeader(header, token)
+
+	decoded, err := ConsistencyTokenFromHeader(header)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", decoded.Token)
+}
+
+func TestAtLeastAsFresh(t *testing.T) {
+	assert.Nil(t, AtLeastAsFresh(nil))
+
+	token := &v1beta2.ConsistencyToken{Token: "token-1"}
+	assert.Equal(t, token, AtLeastAsFresh(token).GetAtLeastAsFresh())
+}
+
+// zedToken builds a ZedToken for revision the way SpiceDB encodes it.
+func zedToken(revision string) *v1beta2.ConsistencyToken {
+	v1 := protowire.AppendTag(nil, 1, protowire.BytesType)
+	v1 = protowire.AppendString(v1, revision)
+	decoded := protowire.AppendTag(nil, 3, protowire.BytesType)
+	decoded = protowire.AppendBytes(decoded, v1)
+	return &v1beta2.ConsistencyToken{Token: base64.StdEncoding.EncodeToString(decoded)}
+}
+
+func TestMaxConsistencyToken(t *testing.T) {
+	older := zedToken("1621538189028928000")
+	newer := zedToken("1621538189028928000.0000000001")
+	newest := zedToken("1621538200000000000")
+	opaque := &v1beta2.ConsistencyToken{Token: "opaque"}
+
+	tests := []struct {
+		name          string
+		tokens        []*v1beta2.ConsistencyToken
+		expected      *v1beta2.ConsistencyToken
+		expectedError error
+	}{
+		{name: "no tokens"},
+		{name: "nil and empty ignored", tokens: []*v1beta2.ConsistencyToken{nil, {}, older}, expected: older},
+		{name: "decimal revisions", tokens: []*v1beta2.ConsistencyToken{newer, newest, older}, expected: newest},
+		{name: "hybrid logical clock", tokens: []*v1beta2.ConsistencyToken{older, newer}, expected: newer},
+		{name: "equal opaque tokens", tokens: []*v1beta2.ConsistencyToken{opaque, {Token: "opaque"}}, expected: opaque},
+		{name: "opaque tokens", tokens: []*v1beta2.ConsistencyToken{older, opaque}, expectedError: ErrIncomparableTokens},
+		{name: "snapshot revision", tokens: []*v1beta2.ConsistencyToken{zedToken("5:8:6"), zedToken("9:9:")}, expectedError: ErrIncomparableTokens},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := MaxConsistencyToken(tt.tokens...)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, token)
+		})
+	}
+}
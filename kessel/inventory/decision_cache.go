@@ -0,0 +1,386 @@
+package inventory
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/invalidation"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultDecisionTTL        = 5 * time.Second
+	defaultMaxCachedDecisions = 10000
+)
+
+type DecisionCacheOptions struct {
+	// How long an allowed decision is reused. Defaults to 5s.
+	TTL time.Duration
+	// How long a denied decision is reused. Defaults to TTL; set it to a
+	// negative value to never cache denials.
+	NegativeTTL time.Duration
+	// Maximum number of cached decisions. When full, the least recently used
+	// decision is dropped. Defaults to 10000.
+	MaxEntries int
+}
+
+// DecisionCache is a v1beta2.KesselInventoryServiceClient that reuses recent
+// Check and CheckBulk decisions for the same (subject, relation, object),
+// for hot permission checks such as those of an API gateway. All other
+// methods go straight to the wrapped client. It is safe for concurrent use;
+// share one instance for the application's lifetime.
+//
+// Requests with AtLeastAsFresh or AtLeastAsAcknowledged consistency always
+// reach the server, and their answers replace the cached ones. Successful
+// ReportResource and DeleteResource calls made through the cache drop the
+// decisions about that resource, whichever reporter the Check named, and
+// answers to checks that were in flight at the time are not cached. Answers served from the cache carry no
+// consistency token, and only ALLOWED_TRUE and ALLOWED_FALSE are cached.
+type DecisionCache struct {
+	v1beta2.KesselInventoryServiceClient
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxEntries  int
+	now         func() time.Time
+
+	mu      sync.Mutex
+	entries map[decisionKey]*list.Element
+	lru     *list.List
+	// pending tracks the objects of checks in flight. Invalidating an object
+	// bumps its generation, so answers from before the invalidation are not
+	// cached.
+	pending map[objectKey]*pendingObject
+}
+
+// objectKey identifies a resource regardless of the reporter that names it.
+type objectKey struct {
+	resourceType string
+	resourceId   string
+}
+
+type pendingObject struct {
+	calls      int
+	generation uint64
+}
+
+type resourceKey struct {
+	reporterType string
+	resourceType string
+	resourceId   string
+}
+
+type decisionKey struct {
+	object          resourceKey
+	relation        string
+	subject         resourceKey
+	subjectRelation string
+}
+
+type decisionEntry struct {
+	key       decisionKey
+	allowed   v1beta2.Allowed
+	expiresAt time.Time
+}
+
+// NewDecisionCache creates a DecisionCache in front of client.
+func NewDecisionCache(client v1beta2.KesselInventoryServiceClient, options DecisionCacheOptions) *DecisionCache {
+	ttl := options.TTL
+	if ttl <= 0 {
+		ttl = defaultDecisionTTL
+	}
+	negativeTTL := options.NegativeTTL
+	if negativeTTL == 0 {
+		negativeTTL = ttl
+	}
+	maxEntries := options.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxCachedDecisions
+	}
+
+	return &DecisionCache{
+		KesselInventoryServiceClient: client,
+		ttl:                          ttl,
+		negativeTTL:                  negativeTTL,
+		maxEntries:                   maxEntries,
+		now:                          time.Now,
+		entries:                      map[decisionKey]*list.Element{},
+		lru:                          list.New(),
+		pending:                      map[objectKey]*pendingObject{},
+	}
+}
+
+func (c *DecisionCache) Check(ctx context.Context, in *v1beta2.CheckRequest, opts ...grpc.CallOption) (*v1beta2.CheckResponse, error) {
+	key := newDecisionKey(in.GetObject(), in.GetRelation(), in.GetSubject())
+	if !requiresFreshness(in.GetConsistency()) {
+		if allowed, ok := c.get(key); ok {
+			return &v1beta2.CheckResponse{Allowed: allowed}, nil
+		}
+	}
+
+	generation := c.begin(key.object.objectKey())
+	defer c.end(key.object.objectKey())
+
+	response, err := c.KesselInventoryServiceClient.Check(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.set(key, response.GetAllowed(), generation)
+	return response, nil
+}
+
+// CheckBulk answers the items it has cached decisions for and sends the rest
+// in one CheckBulk request. The response's consistency token is that of the
+// request sent, or nil when every item was cached.
+func (c *DecisionCache) CheckBulk(ctx context.Context, in *v1beta2.CheckBulkRequest, opts ...grpc.CallOption) (*v1beta2.CheckBulkResponse, error) {
+	items := in.GetItems()
+	pairs := make([]*v1beta2.CheckBulkResponsePair, len(items))
+	keys := make([]decisionKey, len(items))
+	var missing []int
+	fresh := requiresFreshness(in.GetConsistency())
+	for i, item := range items {
+		keys[i] = newDecisionKey(item.GetObject(), item.GetRelation(), item.GetSubject())
+		if !fresh {
+			if allowed, ok := c.get(keys[i]); ok {
+				pairs[i] = &v1beta2.CheckBulkResponsePair{
+					Request:  item,
+					Response: &v1beta2.CheckBulkResponsePair_Item{Item: &v1beta2.CheckBulkResponseItem{Allowed: allowed}},
+				}
+				continue
+			}
+		}
+		missing = append(missing, i)
+	}
+	if len(missing) == 0 {
+		return &v1beta2.CheckBulkResponse{Pairs: pairs}, nil
+	}
+
+	generations := make([]uint64, len(items))
+	for _, i := range missing {
+		object := keys[i].object.objectKey()
+		generations[i] = c.begin(object)
+		defer c.end(object)
+	}
+
+	request := in
+	if len(missing) < len(items) {
+		request = &v1beta2.CheckBulkRequest{Consistency: in.GetConsistency()}
+		for _, i := range missing {
+			request.Items = append(request.Items, items[i])
+		}
+	}
+	response, err := c.KesselInventoryServiceClient.CheckBulk(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) == len(items) {
+		for i, pair := range response.GetPairs() {
+			if i < len(keys) && pair.GetError() == nil {
+				c.set(keys[i], pair.GetItem().GetAllowed(), generations[i])
+			}
+		}
+		return response, nil
+	}
+
+	for j, pair := range response.GetPairs() {
+		if j >= len(missing) {
+			break
+		}
+		i := missing[j]
+		pairs[i] = pair
+		if pair.GetError() == nil {
+			c.set(keys[i], pair.GetItem().GetAllowed(), generations[i])
+		}
+	}
+	return &v1beta2.CheckBulkResponse{Pairs: pairs, ConsistencyToken: response.GetConsistencyToken()}, nil
+}
+
+func (c *DecisionCache) ReportResource(ctx context.Context, in *v1beta2.ReportResourceRequest, opts ...grpc.CallOption) (*v1beta2.ReportResourceResponse, error) {
+	response, err := c.KesselInventoryServiceClient.ReportResource(ctx, in, opts...)
+	if err == nil {
+		c.invalidate(objectKey{
+			resourceType: in.GetType(),
+			resourceId:   in.GetRepresentations().GetMetadata().GetLocalResourceId(),
+		})
+	}
+	return response, err
+}
+
+func (c *DecisionCache) DeleteResource(ctx context.Context, in *v1beta2.DeleteResourceRequest, opts ...grpc.CallOption) (*v1beta2.DeleteResourceResponse, error) {
+	response, err := c.KesselInventoryServiceClient.DeleteResource(ctx, in, opts...)
+	if err == nil {
+		c.invalidate(newResourceKey(in.GetReference()).objectKey())
+	}
+	return response, err
+}
+
+// Invalidate drops the decisions about event.Resource, whichever reporter
+// named it, and those whose subject is event.Subject. An event naming neither, such as a role change in
+// event.OrgId, drops every decision, since decisions are not kept per org.
+// It makes DecisionCache an invalidation.Invalidator.
+func (c *DecisionCache) Invalidate(event invalidation.Event) {
+	if event.Resource == nil && event.Subject == nil {
+		c.Purge()
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var object objectKey
+	if event.Resource != nil {
+		object = newResourceKey(event.Resource).objectKey()
+	}
+	for key, element := range c.entries {
+		if (event.Resource != nil && key.object.objectKey() == object) ||
+			(event.Subject != nil && key.subject == newResourceKey(event.Subject)) {
+			c.remove(element)
+		}
+	}
+	for pendingKey, pending := range c.pending {
+		if event.Subject != nil || pendingKey == object {
+			pending.generation++
+		}
+	}
+}
+
+// Purge drops every cached decision, e.g. after a bulk role change.
+func (c *DecisionCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.lru.Init()
+	for _, pending := range c.pending {
+		pending.generation++
+	}
+}
+
+func (c *DecisionCache) get(key decisionKey) (v1beta2.Allowed, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return v1beta2.Allowed_ALLOWED_UNSPECIFIED, false
+	}
+	entry := element.Value.(*decisionEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(element)
+		return v1beta2.Allowed_ALLOWED_UNSPECIFIED, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.allowed, true
+}
+
+// begin registers a check in flight for object and returns the generation
+// its answer must be cached under.
+func (c *DecisionCache) begin(object objectKey) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending, ok := c.pending[object]
+	if !ok {
+		pending = &pendingObject{}
+		c.pending[object] = pending
+	}
+	pending.calls++
+	return pending.generation
+}
+
+func (c *DecisionCache) end(object objectKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if pending := c.pending[object]; pending != nil {
+		pending.calls--
+		if pending.calls == 0 {
+			delete(c.pending, object)
+		}
+	}
+}
+
+// set caches a decision unless its object was invalidated since the check
+// that produced it began.
+func (c *DecisionCache) set(key decisionKey, allowed v1beta2.Allowed, generation uint64) {
+	ttl := c.ttl
+	switch allowed {
+	case v1beta2.Allowed_ALLOWED_TRUE:
+	case v1beta2.Allowed_ALLOWED_FALSE:
+		ttl = c.negativeTTL
+	default:
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if pending := c.pending[key.object.objectKey()]; pending != nil && pending.generation != generation {
+		return
+	}
+	element, ok := c.entries[key]
+	if ttl < 0 {
+		if ok {
+			c.remove(element)
+		}
+		return
+	}
+	entry := &decisionEntry{key: key, allowed: allowed, expiresAt: c.now().Add(ttl)}
+	if ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove drops a cached decision. It must be called with c.mu held.
+func (c *DecisionCache) remove(element *list.Element) {
+	delete(c.entries, c.lru.Remove(element).(*decisionEntry).key)
+}
+
+func (c *DecisionCache) invalidate(object objectKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if key.object.objectKey() == object {
+			c.remove(element)
+		}
+	}
+	if pending := c.pending[object]; pending != nil {
+		pending.generation++
+	}
+}
+
+func newResourceKey(reference *v1beta2.ResourceReference) resourceKey {
+	return resourceKey{
+		reporterType: reference.GetReporter().GetType(),
+		resourceType: reference.GetResourceType(),
+		resourceId:   reference.GetResourceId(),
+	}
+}
+
+func (k resourceKey) objectKey() objectKey {
+	return objectKey{resourceType: k.resourceType, resourceId: k.resourceId}
+}
+
+func newDecisionKey(object *v1beta2.ResourceReference, relation string, subject *v1beta2.SubjectReference) decisionKey {
+	return decisionKey{
+		object:          newResourceKey(object),
+		relation:        relation,
+		subject:         newResourceKey(subject.GetResource()),
+		subjectRelation: subject.GetRelation(),
+	}
+}
+
+// requiresFreshness reports whether consistency asks for an answer newer
+// than a cached one may be.
+func requiresFreshness(consistency *v1beta2.Consistency) bool {
+	return consistency.GetAtLeastAsFresh() != nil || consistency.GetAtLeastAsAcknowledged()
+}
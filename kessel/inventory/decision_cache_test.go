@@ -0,0 +1,339 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/invalidation"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"github.com/project-kessel/kessel-sdk-go/kessel/testutil"
+)
+
+// mockDecisionClient allows checks on workspaces listed in allowed and
+// records the requests it receives.
+type mockDecisionClient struct {
+	v1beta2.KesselInventoryServiceClient
+	allowed      map[string]bool
+	err          error
+	checks       []*v1beta2.CheckRequest
+	bulkRequests []*v1beta2.CheckBulkRequest
+	deletes      int
+	reports      int
+	// onCheck, when set, runs while a Check is in flight.
+	onCheck func()
+}
+
+func (m *mockDecisionClient) decide(object *v1beta2.ResourceReference) v1beta2.Allowed {
+	if m.allowed[object.GetResourceId()] {
+		return v1beta2.Allowed_ALLOWED_TRUE
+	}
+	return v1beta2.Allowed_ALLOWED_FALSE
+}
+
+func (m *mockDecisionClient) Check(ctx context.Context, in *v1beta2.CheckRequest, opts ...grpc.CallOption) (*v1beta2.CheckResponse, error) {
+	m.checks = append(m.checks, in)
+	if m.onCheck != nil {
+		m.onCheck()
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &v1beta2.CheckResponse{Allowed: m.decide(in.GetObject()), ConsistencyToken: &v1beta2.ConsistencyToken{Token: "server"}}, nil
+}
+
+func (m *mockDecisionClient) CheckBulk(ctx context.Context, in *v1beta2.CheckBulkRequest, opts ...grpc.CallOption) (*v1beta2.CheckBulkResponse, error) {
+	m.bulkRequests = append(m.bulkRequests, in)
+	if m.err != nil {
+		return nil, m.err
+	}
+	response := &v1beta2.CheckBulkResponse{ConsistencyToken: &v1beta2.ConsistencyToken{Token: "server"}}
+	for _, item := range in.GetItems() {
+		pair := &v1beta2.CheckBulkResponsePair{Request: item}
+		if item.GetObject().GetResourceId() == "broken" {
+			pair.Response = &v1beta2.CheckBulkResponsePair_Error{Error: grpcstatus.New(codes.Internal, "broken").Proto()}
+		} else {
+			pair.Response = &v1beta2.CheckBulkResponsePair_Item{Item: &v1beta2.CheckBulkResponseItem{Allowed: m.decide(item.GetObject())}}
+		}
+		response.Pairs = append(response.Pairs, pair)
+	}
+	return response, nil
+}
+
+func (m *mockDecisionClient) DeleteResource(ctx context.Context, in *v1beta2.DeleteResourceRequest, opts ...grpc.CallOption) (*v1beta2.DeleteResourceResponse, error) {
+	m.deletes++
+	return &v1beta2.DeleteResourceResponse{}, nil
+}
+
+func (m *mockDecisionClient) ReportResource(ctx context.Context, in *v1beta2.ReportResourceRequest, opts ...grpc.CallOption) (*v1beta2.ReportResourceResponse, error) {
+	m.reports++
+	return &v1beta2.ReportResourceResponse{}, nil
+}
+
+func workspaceCheck(workspaceId string) *v1beta2.CheckRequest {
+	return testutil.CheckRequest(testutil.Workspace(workspaceId), "view", testutil.Principal("alice"))
+}
+
+func TestDecisionCache_Check(t *testing.T) {
+	fresh := &v1beta2.Consistency{Requirement: &v1beta2.Consistency_AtLeastAsFresh{AtLeastAsFresh: &v1beta2.ConsistencyToken{Token: "t"}}}
+	acknowledged := &v1beta2.Consistency{Requirement: &v1beta2.Consistency_AtLeastAsAcknowledged{AtLeastAsAcknowledged: true}}
+
+	tests := []struct {
+		name          string
+		options       DecisionCacheOptions
+		consistency   *v1beta2.Consistency
+		workspaceId   string
+		expectedCalls int
+	}{
+		{name: "allowed decision is reused", workspaceId: "ws-1", expectedCalls: 1},
+		{name: "denied decision is reused", workspaceId: "ws-2", expectedCalls: 1},
+		{name: "negative caching disabled", options: DecisionCacheOptions{NegativeTTL: -1}, workspaceId: "ws-2", expectedCalls: 2},
+		{name: "at least as fresh bypasses the cache", consistency: fresh, workspaceId: "ws-1", expectedCalls: 2},
+		{name: "at least as acknowledged bypasses the cache", consistency: acknowledged, workspaceId: "ws-1", expectedCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockDecisionClient{allowed: map[string]bool{"ws-1": true}}
+			cache := NewDecisionCache(client, tt.options)
+
+			for range 2 {
+				request := workspaceCheck(tt.workspaceId)
+				request.Consistency = tt.consistency
+				response, err := cache.Check(context.Background(), request)
+				require.NoError(t, err)
+				assert.Equal(t, client.decide(request.GetObject()), response.GetAllowed())
+			}
+			assert.Len(t, client.checks, tt.expectedCalls)
+		})
+	}
+}
+
+func TestDecisionCache_Check_expires(t *testing.T) {
+	client := &mockDecisionClient{allowed: map[string]bool{"ws-1": true}}
+	cache := NewDecisionCache(client, DecisionCacheOptions{TTL: time.Minute, NegativeTTL: time.Second})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.Check(context.Background(), workspaceCheck("ws-1"))
+	require.NoError(t, err)
+	_, err = cache.Check(context.Background(), workspaceCheck("ws-2"))
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Second)
+	_, err = cache.Check(context.Background(), workspaceCheck("ws-1"))
+	require.NoError(t, err)
+	_, err = cache.Check(context.Background(), workspaceCheck("ws-2"))
+	require.NoError(t, err)
+
+	assert.Len(t, client.checks, 3, "only the denial should have expired")
+}
+
+func TestDecisionCache_Check_keysBySubjectAndRelation(t *testing.T) {
+	client := &mockDecisionClient{allowed: map[string]bool{"ws-1": true}}
+	cache := NewDecisionCache(client, DecisionCacheOptions{})
+
+	requests := []*v1beta2.CheckRequest{
+		workspaceCheck("ws-1"),
+		testutil.CheckRequest(testutil.Workspace("ws-1"), "edit", testutil.Principal("alice")),
+		testutil.CheckRequest(testutil.Workspace("ws-1"), "view", testutil.Principal("bob")),
+	}
+	for _, request := range requests {
+		_, err := cache.Check(context.Background(), request)
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, client.checks, 3)
+}
+
+func TestDecisionCache_Check_doesNotCacheErrors(t *testing.T) {
+	client := &mockDecisionClient{err: grpcstatus.Error(codes.Unavailable, "down")}
+	cache := NewDecisionCache(client, DecisionCacheOptions{})
+
+	for range 2 {
+		_, err := cache.Check(context.Background(), workspaceCheck("ws-1"))
+		testutil.AssertCode(t, err, codes.Unavailable)
+	}
+	assert.Len(t, client.checks, 2)
+}
+
+func TestDecisionCache_CheckBulk(t *testing.T) {
+	client := &mockDecisionClient{allowed: map[string]bool{"workspace-0": true}}
+	cache := NewDecisionCache(client, DecisionCacheOptions{})
+
+	_, err := cache.Check(context.Background(), testutil.CheckRequest(testutil.Workspace("workspace-1"), "view", testutil.Principal("user-1")))
+	require.NoError(t, err)
+
+	request := testutil.CheckBulkRequest(3, "view")
+	response, err := cache.CheckBulk(context.Background(), request)
+	require.NoError(t, err)
+
+	require.Len(t, client.bulkRequests, 1)
+	assert.Len(t, client.bulkRequests[0].GetItems(), 2, "the cached item should not be sent")
+	require.Len(t, response.GetPairs(), 3)
+	for i, pair := range response.GetPairs() {
+		testutil.AssertProtoEqual(t, request.GetItems()[i], pair.GetRequest())
+	}
+	assert.Equal(t, v1beta2.Allowed_ALLOWED_TRUE, response.GetPairs()[0].GetItem().GetAllowed())
+	assert.Equal(t, v1beta2.Allowed_ALLOWED_FALSE, response.GetPairs()[1].GetItem().GetAllowed())
+	assert.Equal(t, "server", response.GetConsistencyToken().GetToken())
+
+	response, err = cache.CheckBulk(context.Background(), request)
+	require.NoError(t, err)
+	assert.Len(t, client.bulkRequests, 1, "every item should now be cached")
+	assert.Nil(t, response.GetConsistencyToken())
+	assert.Len(t, response.GetPairs(), 3)
+}
+
+func TestDecisionCache_CheckBulk_bypassesForFreshConsistency(t *testing.T) {
+	client := &mockDecisionClient{}
+	cache := NewDecisionCache(client, DecisionCacheOptions{})
+	request := testutil.CheckBulkRequest(2, "view")
+
+	_, err := cache.CheckBulk(context.Background(), request)
+	require.NoError(t, err)
+	request.Consistency = &v1beta2.Consistency{Requirement: &v1beta2.Consistency_AtLeastAsAcknowledged{AtLeastAsAcknowledged: true}}
+	_, err = cache.CheckBulk(context.Background(), request)
+	require.NoError(t, err)
+
+	require.Len(t, client.bulkRequests, 2)
+	assert.Same(t, request, client.bulkRequests[1])
+}
+
+func TestDecisionCache_CheckBulk_doesNotCachePairErrors(t *testing.T) {
+	client := &mockDecisionClient{}
+	cache := NewDecisionCache(client, DecisionCacheOptions{})
+	request := &v1beta2.CheckBulkRequest{Items: []*v1beta2.CheckBulkRequestItem{
+		{Object: testutil.Workspace("broken"), Relation: "view", Subject: testutil.Principal("alice")},
+	}}
+
+	for range 2 {
+		response, err := cache.CheckBulk(context.Background(), request)
+		require.NoError(t, err)
+		assert.NotNil(t, response.GetPairs()[0].GetError())
+	}
+	assert.Len(t, client.bulkRequests, 2)
+}
+
+func TestDecisionCache_DeleteResourceInvalidates(t *testing.T) {
+	client := &mockDecisionClient{allowed: map[string]bool{"ws-1": true, "ws-2": true}}
+	cache := NewDecisionCache(client, DecisionCacheOptions{})
+
+	for _, id := range []string{"ws-1", "ws-2"} {
+		_, err := cache.Check(context.Background(), workspaceCheck(id))
+		require.NoError(t, err)
+	}
+	_, err := cache.DeleteResource(context.Background(), &v1beta2.DeleteResourceRequest{Reference: testutil.Workspace("ws-1")})
+	require.NoError(t, err)
+	for _, id := range []string{"ws-1", "ws-2"} {
+		_, err := cache.Check(context.Background(), workspaceCheck(id))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, client.deletes)
+	assert.Len(t, client.checks, 3, "only the deleted workspace should be checked again")
+}
+
+func TestDecisionCache_ReportResourceInvalidatesChecksWithoutReporter(t *testing.T) {
+	client := &mockDecisionClient{allowed: map[string]bool{"host-1": true}}
+	cache := NewDecisionCache(client, DecisionCacheOptions{})
+	check := testutil.CheckRequest(&v1beta2.ResourceReference{ResourceType: "host", ResourceId: "host-1"}, "view", testutil.Principal("alice"))
+
+	_, err := cache.Check(context.Background(), check)
+	require.NoError(t, err)
+	_, err = cache.ReportResource(context.Background(), &v1beta2.ReportResourceRequest{
+		Type:         "host",
+		ReporterType: "hbi",
+		Representations: &v1beta2.ResourceRepresentations{
+			Metadata: &v1beta2.RepresentationMetadata{LocalResourceId: "host-1"},
+		},
+	})
+	require.NoError(t, err)
+	_, err = cache.Check(context.Background(), check)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, client.reports)
+	assert.Len(t, client.checks, 2, "the report should drop the decision cached without a reporter")
+}
+
+func TestDecisionCache_dropsAnswersFromBeforeInvalidation(t *testing.T) {
+	client := &mockDecisionClient{allowed: map[string]bool{"ws-1": true}}
+	cache := NewDecisionCache(client, DecisionCacheOptions{})
+	client.onCheck = func() {
+		_, err := cache.DeleteResource(context.Background(), &v1beta2.DeleteResourceRequest{Reference: testutil.Workspace("ws-1")})
+		require.NoError(t, err)
+	}
+
+	_, err := cache.Check(context.Background(), workspaceCheck("ws-1"))
+	require.NoError(t, err)
+	client.onCheck = nil
+	_, err = cache.Check(context.Background(), workspaceCheck("ws-1"))
+	require.NoError(t, err)
+
+	assert.Len(t, client.checks, 2, "the answer of a check in flight during the delete should not be cached")
+	assert.Empty(t, cache.pending)
+	_, err = cache.Check(context.Background(), workspaceCheck("ws-1"))
+	require.NoError(t, err)
+	assert.Len(t, client.checks, 2)
+}
+
+func TestDecisionCache_Invalidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		event         invalidation.Event
+		expectedCalls int
+	}{
+		{name: "resource", event: invalidation.Event{Resource: testutil.Workspace("ws-1")}, expectedCalls: 3},
+		{name: "subject", event: invalidation.Event{Subject: testutil.Principal("alice").GetResource()}, expectedCalls: 4},
+		{name: "other subject", event: invalidation.Event{Subject: testutil.Principal("bob").GetResource()}, expectedCalls: 2},
+		{name: "org only", event: invalidation.Event{OrgId: "12345"}, expectedCalls: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockDecisionClient{allowed: map[string]bool{"ws-1": true}}
+			cache := NewDecisionCache(client, DecisionCacheOptions{})
+
+			for _, id := range []string{"ws-1", "ws-2"} {
+				_, err := cache.Check(context.Background(), workspaceCheck(id))
+				require.NoError(t, err)
+			}
+			cache.Invalidate(tt.event)
+			for _, id := range []string{"ws-1", "ws-2"} {
+				_, err := cache.Check(context.Background(), workspaceCheck(id))
+				require.NoError(t, err)
+			}
+
+			assert.Len(t, client.checks, tt.expectedCalls)
+		})
+	}
+}
+
+func TestDecisionCache_MaxEntries(t *testing.T) {
+	client := &mockDecisionClient{}
+	cache := NewDecisionCache(client, DecisionCacheOptions{MaxEntries: 2})
+
+	for _, id := range []string{"ws-1", "ws-2", "ws-1", "ws-3"} {
+		_, err := cache.Check(context.Background(), workspaceCheck(id))
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, cache.entries, 2)
+	assert.Len(t, client.checks, 3, "the repeated ws-1 check is served from the cache")
+	_, err := cache.Check(context.Background(), workspaceCheck("ws-1"))
+	require.NoError(t, err)
+	assert.Len(t, client.checks, 3, "ws-1 was used more recently than ws-2, so ws-2 is evicted")
+	_, err = cache.Check(context.Background(), workspaceCheck("ws-2"))
+	require.NoError(t, err)
+	assert.Len(t, client.checks, 4)
+
+	cache.Purge()
+	assert.Empty(t, cache.entries)
+	assert.Zero(t, cache.lru.Len())
+}
@@ -0,0 +1,63 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// DeleteRequestFor builds a DeleteResourceRequest for ref.
+func DeleteRequestFor(ref *v1beta2.ResourceReference) *v1beta2.DeleteResourceRequest {
+	return &v1beta2.DeleteResourceRequest{
+		Reference: ref,
+	}
+}
+
+// BulkDelete deletes every resource in refs, running at most concurrency
+// deletes at a time (values below 1 are treated as 1). All deletes are
+// attempted even if some fail; failures are returned as a *errors.Multi whose
+// items carry the index of the reference in refs. If ctx ends, the deletes
+// that have not started yet fail with the context error.
+func BulkDelete(ctx context.Context, client v1beta2.KesselInventoryServiceClient, refs []*v1beta2.ResourceReference, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	errs := make([]error, len(refs))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, ref := range refs {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			_, errs[i] = client.DeleteResource(ctx, DeleteRequestFor(ref))
+		}()
+	}
+	wg.Wait()
+
+	result := &kesselerrors.Multi{}
+	for i, err := range errs {
+		result.Append(i, referenceKey(refs[i]), err)
+	}
+	return result.ErrorOrNil()
+}
+
+func referenceKey(ref *v1beta2.ResourceReference) string {
+	return fmt.Sprintf("%s/%s", ref.GetResourceType(), ref.GetResourceId())
+}
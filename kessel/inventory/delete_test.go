@@ -0,0 +1,161 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type mockDeleteClient struct {
+	v1beta2.KesselInventoryServiceClient
+	mu          sync.Mutex
+	deleted     []string
+	failIDs     map[string]bool
+	inFlight    int32
+	maxInFlight int32
+	block       chan struct{}
+}
+
+func (m *mockDeleteClient) DeleteResource(ctx context.Context, in *v1beta2.DeleteResourceRequest, opts ...grpc.CallOption) (*v1beta2.DeleteResourceResponse, error) {
+	current := atomic.AddInt32(&m.inFlight, 1)
+	defer atomic.AddInt32(&m.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&m.maxInFlight)
+		if current <= max || atomic.CompareAndSwapInt32(&m.maxInFlight, max, current) {
+			break
+		}
+	}
+	if m.block != nil {
+		<-m.block
+	}
+
+	id := in.GetReference().GetResourceId()
+	if m.failIDs[id] {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+
+	m.mu.Lock()
+	m.deleted = append(m.deleted, id)
+	m.mu.Unlock()
+	return &v1beta2.DeleteResourceResponse{}, nil
+}
+
+func hostRef(id string) *v1beta2.ResourceReference {
+	return &v1beta2.ResourceReference{
+		ResourceType: "host",
+		ResourceId:   id,
+		Reporter:     &v1beta2.ReporterReference{Type: "hbi"},
+	}
+}
+
+func TestDeleteRequestFor(t *testing.T) {
+	ref := hostRef("host-1")
+	req := DeleteRequestFor(ref)
+
+	assert.Same(t, ref, req.Reference)
+}
+
+func TestBulkDelete(t *testing.T) {
+	tests := []struct {
+		name            string
+		ids             []string
+		failIDs         map[string]bool
+		concurrency     int
+		expectedDeleted int
+		expectedFailed  []int
+	}{
+		{
+			name:            "deletes all references",
+			ids:             []string{"a", "b", "c"},
+			concurrency:     2,
+			expectedDeleted: 3,
+		},
+		{
+			name:            "aggregates per-item errors in input order",
+			ids:             []string{"a", "b", "c", "d"},
+			failIDs:         map[string]bool{"b": true, "d": true},
+			concurrency:     4,
+			expectedDeleted: 2,
+			expectedFailed:  []int{1, 3},
+		},
+		{
+			name:            "non-positive concurrency runs sequentially",
+			ids:             []string{"a", "b"},
+			concurrency:     0,
+			expectedDeleted: 2,
+		},
+		{
+			name:            "empty input",
+			ids:             nil,
+			concurrency:     2,
+			expectedDeleted: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockDeleteClient{failIDs: tt.failIDs}
+			refs := make([]*v1beta2.ResourceReference, len(tt.ids))
+			for i, id := range tt.ids {
+				refs[i] = hostRef(id)
+			}
+
+			err := BulkDelete(context.Background(), client, refs, tt.concurrency)
+			assert.Len(t, client.deleted, tt.expectedDeleted)
+
+			if len(tt.expectedFailed) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var multi *kesselerrors.Multi
+			require.True(t, errors.As(err, &multi))
+			require.Equal(t, len(tt.expectedFailed), multi.Len())
+			for i, index := range tt.expectedFailed {
+				assert.Equal(t, index, multi.Errors[i].Index)
+				assert.Equal(t, "host/"+tt.ids[index], multi.Errors[i].Key)
+				assert.Equal(t, codes.NotFound, status.Code(multi.Errors[i].Err))
+			}
+		})
+	}
+}
+
+func TestBulkDelete_boundsConcurrency(t *testing.T) {
+	client := &mockDeleteClient{block: make(chan struct{})}
+	refs := make([]*v1beta2.ResourceReference, 10)
+	for i := range refs {
+		refs[i] = hostRef("host")
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- BulkDelete(context.Background(), client, refs, 3)
+	}()
+	for i := 0; i < len(refs); i++ {
+		client.block <- struct{}{}
+	}
+
+	require.NoError(t, <-done)
+	assert.LessOrEqual(t, atomic.LoadInt32(&client.maxInFlight), int32(3))
+}
+
+func TestBulkDelete_cancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := &mockDeleteClient{}
+	err := BulkDelete(ctx, client, []*v1beta2.ResourceReference{hostRef("a"), hostRef("b")}, 1)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,188 @@
+package inventory
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// Diagnostic stage names, run in the order tls, token, connection,
+// reflection, check.
+const (
+	StageTLS        = "tls"
+	StageReflection = "reflection"
+)
+
+type DiagnoseOptions struct {
+	// Connect over plaintext without credentials. The TLS stage is skipped.
+	Insecure bool
+	// TLS configuration for the TLS stage and the connection, or nil for the
+	// system CA pool.
+	TLSConfig *tls.Config
+	// Credentials to fetch a fresh token with and to authenticate the
+	// sample check. The token stage is skipped when nil.
+	Credentials *auth.OAuth2ClientCredentials
+	// Optionally specify an http.Client or use http.DefaultClient
+	HttpClient *http.Client
+	// Check to send once the service is found. The check stage is skipped
+	// when nil.
+	SampleCheck *v1beta2.CheckRequest
+}
+
+// TLSDetails describes the TLS session negotiated with the endpoint.
+type TLSDetails struct {
+	// e.g. "TLS 1.3"
+	Version     string
+	CipherSuite string
+	// Protocol negotiated with ALPN. gRPC requires "h2".
+	NegotiatedProtocol string
+	// Subject and expiry of the server's leaf certificate.
+	PeerSubject  string
+	PeerNotAfter time.Time
+}
+
+// DiagnosticReport is the outcome of Diagnose. TLS is set whenever the
+// handshake completed, so a wrong ALPN protocol can be inspected; Capabilities
+// and CheckLatency only when their stage succeeded.
+type DiagnosticReport struct {
+	Endpoint     string
+	TLS          *TLSDetails
+	Capabilities *ServerCapabilities
+	CheckLatency time.Duration
+	// One StageResult per stage, in the order they ran.
+	Stages []StageResult
+}
+
+// Ok reports whether no stage failed.
+func (r DiagnosticReport) Ok() bool {
+	return r.Err() == nil
+}
+
+// Err returns a *kesselerrors.Multi with one entry per failed stage, keyed by
+// stage name, or nil when no stage failed.
+func (r DiagnosticReport) Err() error {
+	return SelfTestReport{Stages: r.Stages}.Err()
+}
+
+// Diagnose troubleshoots an Inventory endpoint (host:port, optionally with a
+// "dns:///" scheme) from scratch, for tooling such as support scripts: it
+// performs a TLS handshake and reports the negotiated version, cipher and
+// ALPN protocol, fetches a fresh token, connects, confirms through gRPC
+// server reflection that KesselInventoryService is served, and times
+// SampleCheck. Unlike SelfTest it dials the endpoint itself, so it works
+// before an application has a client. Later stages are skipped when the TLS
+// or connection stage fails; every other stage is reported even when an
+// earlier one failed.
+func Diagnose(ctx context.Context, endpoint string, options DiagnoseOptions) DiagnosticReport {
+	report := DiagnosticReport{Endpoint: endpoint}
+	tlsConfig := options.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	handshake := runStage(StageTLS, options.Insecure, func() (string, error) {
+		details, err := tlsHandshake(ctx, endpoint, tlsConfig)
+		if err != nil {
+			return "", err
+		}
+		report.TLS = details
+		if details.NegotiatedProtocol != "h2" {
+			return "", fmt.Errorf("server negotiated ALPN protocol %q instead of h2, which gRPC requires", details.NegotiatedProtocol)
+		}
+		return fmt.Sprintf("%s, %s, ALPN %s", details.Version, details.CipherSuite, details.NegotiatedProtocol), nil
+	})
+	report.Stages = append(report.Stages, handshake)
+
+	report.Stages = append(report.Stages, runStage(StageToken, options.Credentials == nil, func() (string, error) {
+		token, err := options.Credentials.GetToken(ctx, auth.GetTokenOptions{ForceRefresh: true, HttpClient: options.HttpClient})
+		return "expires at " + token.ExpiresAt.Format(time.RFC3339), err
+	}))
+
+	var client v1beta2.KesselInventoryServiceClient
+	var conn *grpc.ClientConn
+	connection := runStage(StageConnection, handshake.Err != nil, func() (string, error) {
+		builder := v1beta2.NewClientBuilder(endpoint)
+		switch {
+		case options.Insecure:
+			builder.Insecure().AllowInsecureAuth()
+		case options.Credentials != nil:
+			builder.OAuth2ClientAuthenticated(options.Credentials, credentials.NewTLS(tlsConfig))
+		default:
+			builder.Unauthenticated(credentials.NewTLS(tlsConfig)).AllowInsecureAuth()
+		}
+		var err error
+		client, conn, err = builder.Build()
+		if err != nil {
+			return "", err
+		}
+		return conn.Target(), WaitForReady(ctx, conn)
+	})
+	if conn != nil {
+		defer func() { _ = conn.Close() }()
+	}
+	report.Stages = append(report.Stages, connection)
+
+	connected := handshake.Err == nil && connection.Err == nil
+	report.Stages = append(report.Stages, runStage(StageReflection, !connected, func() (string, error) {
+		capabilities, err := FetchCapabilities(ctx, conn)
+		if status.Code(err) == codes.Unimplemented {
+			return "", fmt.Errorf("server does not expose gRPC reflection: %w", err)
+		}
+		if err != nil {
+			return "", err
+		}
+		report.Capabilities = capabilities
+		if !slices.Contains(capabilities.Services, inventoryService) {
+			return "", fmt.Errorf("%s is not served; found %s", inventoryService, strings.Join(capabilities.Services, ", "))
+		}
+		return "API versions " + strings.Join(capabilities.ApiVersions, ", "), nil
+	}))
+
+	check := runStage(StageCheck, !connected || options.SampleCheck == nil, func() (string, error) {
+		response, err := client.Check(ctx, options.SampleCheck)
+		return response.GetAllowed().String(), err
+	})
+	if check.Err == nil {
+		report.CheckLatency = check.Duration
+	}
+	report.Stages = append(report.Stages, check)
+
+	return report
+}
+
+func tlsHandshake(ctx context.Context, endpoint string, config *tls.Config) (*TLSDetails, error) {
+	if _, address, ok := strings.Cut(endpoint, ":///"); ok {
+		endpoint = address
+	}
+	config = config.Clone()
+	config.NextProtos = []string{"h2"}
+	dialer := &tls.Dialer{Config: config}
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	details := &TLSDetails{
+		Version:            tls.VersionName(state.Version),
+		CipherSuite:        tls.CipherSuiteName(state.CipherSuite),
+		NegotiatedProtocol: state.NegotiatedProtocol,
+	}
+	if len(state.PeerCertificates) > 0 {
+		details.PeerSubject = state.PeerCertificates[0].Subject.String()
+		details.PeerNotAfter = state.PeerCertificates[0].NotAfter
+	}
+	return details, nil
+}
@@ -0,0 +1,145 @@
+package inventory
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type diagnoseServer struct {
+	v1beta2.UnimplementedKesselInventoryServiceServer
+}
+
+func (s *diagnoseServer) Check(ctx context.Context, in *v1beta2.CheckRequest) (*v1beta2.CheckResponse, error) {
+	return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}, nil
+}
+
+// selfSignedCertificate returns a certificate for 127.0.0.1 and a pool that
+// trusts it.
+func selfSignedCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kessel-test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+func newDiagnoseServer(t *testing.T, withReflection bool, options ...grpc.ServerOption) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(options...)
+	v1beta2.RegisterKesselInventoryServiceServer(server, &diagnoseServer{})
+	if withReflection {
+		reflection.Register(server)
+	}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func diagnosticStage(report DiagnosticReport, name string) StageResult {
+	return stageByName(SelfTestReport{Stages: report.Stages}, name)
+}
+
+func sampleCheck() *v1beta2.CheckRequest {
+	return &v1beta2.CheckRequest{
+		Object:   &v1beta2.ResourceReference{ResourceType: "workspace", ResourceId: "ws-1", Reporter: &v1beta2.ReporterReference{Type: "rbac"}},
+		Relation: "view",
+		Subject:  &v1beta2.SubjectReference{Resource: &v1beta2.ResourceReference{ResourceType: "principal", ResourceId: "alice", Reporter: &v1beta2.ReporterReference{Type: "rbac"}}},
+	}
+}
+
+func TestDiagnose_allStagesPass(t *testing.T) {
+	certificate, pool := selfSignedCertificate(t)
+	endpoint := newDiagnoseServer(t, true, grpc.Creds(credentials.NewServerTLSFromCert(&certificate)))
+	oidc := newOIDCServer(t)
+	creds := auth.NewOAuth2ClientCredentials("client", "secret", oidc.URL+"/token")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report := Diagnose(ctx, endpoint, DiagnoseOptions{
+		TLSConfig:   &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		Credentials: &creds,
+		SampleCheck: sampleCheck(),
+	})
+
+	require.NoError(t, report.Err())
+	assert.True(t, report.Ok())
+	assert.Equal(t, []string{StageTLS, StageToken, StageConnection, StageReflection, StageCheck}, stageNames(report.Stages))
+	require.NotNil(t, report.TLS)
+	assert.Equal(t, "TLS 1.3", report.TLS.Version)
+	assert.Equal(t, "h2", report.TLS.NegotiatedProtocol)
+	assert.Equal(t, "CN=kessel-test", report.TLS.PeerSubject)
+	require.NotNil(t, report.Capabilities)
+	assert.Contains(t, report.Capabilities.Services, inventoryService)
+	assert.Positive(t, report.CheckLatency)
+	assert.Equal(t, "ALLOWED_TRUE", diagnosticStage(report, StageCheck).Detail)
+}
+
+func TestDiagnose_insecureWithoutReflection(t *testing.T) {
+	endpoint := newDiagnoseServer(t, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report := Diagnose(ctx, endpoint, DiagnoseOptions{Insecure: true, SampleCheck: sampleCheck()})
+
+	assert.False(t, report.Ok())
+	assert.True(t, diagnosticStage(report, StageTLS).Skipped)
+	assert.True(t, diagnosticStage(report, StageToken).Skipped)
+	assert.ErrorContains(t, diagnosticStage(report, StageReflection).Err, "does not expose gRPC reflection")
+	assert.NoError(t, diagnosticStage(report, StageCheck).Err)
+	assert.Nil(t, report.Capabilities)
+}
+
+func TestDiagnose_untrustedCertificateSkipsLaterStages(t *testing.T) {
+	certificate, _ := selfSignedCertificate(t)
+	endpoint := newDiagnoseServer(t, true, grpc.Creds(credentials.NewServerTLSFromCert(&certificate)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report := Diagnose(ctx, endpoint, DiagnoseOptions{SampleCheck: sampleCheck()})
+
+	assert.Error(t, diagnosticStage(report, StageTLS).Err)
+	assert.Nil(t, report.TLS)
+	for _, stage := range []string{StageConnection, StageReflection, StageCheck} {
+		assert.True(t, diagnosticStage(report, stage).Skipped, stage)
+	}
+}
+
+func stageNames(stages []StageResult) []string {
+	names := make([]string, len(stages))
+	for i, stage := range stages {
+		names[i] = stage.Stage
+	}
+	return names
+}
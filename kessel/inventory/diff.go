@@ -0,0 +1,75 @@
+package inventory
+
+import (
+	"sort"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Diff returns the paths of the fields that differ between a and b, sorted.
+// Nested fields are joined with "." and list elements are addressed by index,
+// e.g. "labels.env" or "tags[2]". A field added, removed or changed is
+// reported at the deepest path where a and b disagree; a change of type (such
+// as a struct replaced by a string) is reported at the field itself. A nil
+// Struct is treated as empty.
+func Diff(a, b *structpb.Struct) []string {
+	var paths []string
+	diffFields(&paths, "", a.GetFields(), b.GetFields())
+	sort.Strings(paths)
+	return paths
+}
+
+func diffFields(paths *[]string, prefix string, a, b map[string]*structpb.Value) {
+	for key, av := range a {
+		path := joinPath(prefix, key)
+		bv, ok := b[key]
+		if !ok {
+			*paths = append(*paths, path)
+			continue
+		}
+		diffValue(paths, path, av, bv)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			*paths = append(*paths, joinPath(prefix, key))
+		}
+	}
+}
+
+func diffValue(paths *[]string, path string, a, b *structpb.Value) {
+	switch av := a.GetKind().(type) {
+	case *structpb.Value_StructValue:
+		if bv, ok := b.GetKind().(*structpb.Value_StructValue); ok {
+			diffFields(paths, path, av.StructValue.GetFields(), bv.StructValue.GetFields())
+			return
+		}
+	case *structpb.Value_ListValue:
+		if bv, ok := b.GetKind().(*structpb.Value_ListValue); ok {
+			diffList(paths, path, av.ListValue.GetValues(), bv.ListValue.GetValues())
+			return
+		}
+	}
+	if !proto.Equal(a, b) {
+		*paths = append(*paths, path)
+	}
+}
+
+func diffList(paths *[]string, path string, a, b []*structpb.Value) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		elementPath := path + "[" + strconv.Itoa(i) + "]"
+		if i >= len(a) || i >= len(b) {
+			*paths = append(*paths, elementPath)
+			continue
+		}
+		diffValue(paths, elementPath, a[i], b[i])
+	}
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,73 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func mustStruct(t *testing.T, fields map[string]any) *structpb.Struct {
+	t.Helper()
+	s, err := structpb.NewStruct(fields)
+	require.NoError(t, err)
+	return s
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        map[string]any
+		b        map[string]any
+		expected []string
+	}{
+		{
+			name:     "equal",
+			a:        map[string]any{"name": "host", "labels": map[string]any{"env": "prod"}},
+			b:        map[string]any{"name": "host", "labels": map[string]any{"env": "prod"}},
+			expected: nil,
+		},
+		{
+			name:     "changed, added and removed fields",
+			a:        map[string]any{"name": "host", "old": true},
+			b:        map[string]any{"name": "renamed", "new": 1},
+			expected: []string{"name", "new", "old"},
+		},
+		{
+			name:     "nested fields",
+			a:        map[string]any{"labels": map[string]any{"env": "prod", "team": "a"}},
+			b:        map[string]any{"labels": map[string]any{"env": "stage", "team": "a"}},
+			expected: []string{"labels.env"},
+		},
+		{
+			name:     "list elements",
+			a:        map[string]any{"tags": []any{"a", "b"}},
+			b:        map[string]any{"tags": []any{"a", "c", "d"}},
+			expected: []string{"tags[1]", "tags[2]"},
+		},
+		{
+			name:     "structs inside lists",
+			a:        map[string]any{"nics": []any{map[string]any{"ip": "10.0.0.1"}}},
+			b:        map[string]any{"nics": []any{map[string]any{"ip": "10.0.0.2"}}},
+			expected: []string{"nics[0].ip"},
+		},
+		{
+			name:     "type change",
+			a:        map[string]any{"labels": map[string]any{"env": "prod"}},
+			b:        map[string]any{"labels": "none"},
+			expected: []string{"labels"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Diff(mustStruct(t, tt.a), mustStruct(t, tt.b)))
+		})
+	}
+}
+
+func TestDiff_nilStructs(t *testing.T) {
+	assert.Nil(t, Diff(nil, nil))
+	assert.Equal(t, []string{"name"}, Diff(nil, mustStruct(t, map[string]any{"name": "host"})))
+}
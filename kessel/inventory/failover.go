@@ -0,0 +1,226 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/circuitbreaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+const defaultHealthInterval = 10 * time.Second
+
+// FailoverEndpoint names the endpoint a Failover sends calls to.
+type FailoverEndpoint int
+
+const (
+	FailoverPrimary FailoverEndpoint = iota
+	FailoverSecondary
+)
+
+func (e FailoverEndpoint) String() string {
+	if e == FailoverSecondary {
+		return "secondary"
+	}
+	return "primary"
+}
+
+// FailoverEvent reports that a Failover moved traffic to another endpoint.
+type FailoverEvent struct {
+	// Active is the endpoint now receiving calls.
+	Active FailoverEndpoint
+	// Reason is why traffic left the primary: errors.ErrCircuitOpen or the
+	// error of the failed health check. It is nil on fail-back.
+	Reason error
+}
+
+type FailoverOptions struct {
+	// Breaker that records the outcome of calls to the primary; calls go to
+	// the secondary while it is open, and its half-open probes go to the
+	// primary to fail back. Defaults to circuitbreaker.New(). Do not also
+	// install it on the primary's builder, which would count every call
+	// twice.
+	Breaker *circuitbreaker.Breaker
+	// Optionally check the primary every HealthInterval, e.g. with the gRPC
+	// health service. While it fails, calls go to the secondary. Without it,
+	// the primary is unhealthy while its connection is in TRANSIENT_FAILURE or
+	// SHUTDOWN.
+	HealthCheck func(ctx context.Context, primary grpc.ClientConnInterface) error
+	// How often HealthCheck runs, and how long one check may take. Defaults
+	// to 10s.
+	HealthInterval time.Duration
+	// Optionally called, outside any lock, whenever traffic moves between the
+	// endpoints.
+	OnFailover func(FailoverEvent)
+}
+
+// Failover is a grpc.ClientConnInterface that sends calls to a primary
+// endpoint and fails over to a secondary one (e.g. in another region) while
+// the primary's circuit is open or its health check fails. It fails back
+// automatically once the circuit closes and the primary is healthy again.
+// Create stubs on it like on a connection:
+//
+//	failover := inventory.NewFailover(primaryConn, secondaryConn, inventory.FailoverOptions{})
+//	defer failover.Close()
+//	client := v1beta2.NewKesselInventoryServiceClient(failover)
+//
+// Calls are not retried on the other endpoint; a call that fails on the
+// primary returns its error and only moves later calls. Failover does not
+// close the connections.
+type Failover struct {
+	primary        grpc.ClientConnInterface
+	secondary      grpc.ClientConnInterface
+	breaker        *circuitbreaker.Breaker
+	healthCheck    func(context.Context, grpc.ClientConnInterface) error
+	healthInterval time.Duration
+	onFailover     func(FailoverEvent)
+
+	mu        sync.Mutex
+	active    FailoverEndpoint
+	healthErr error
+	stop      context.CancelFunc
+	stopped   chan struct{}
+}
+
+// NewFailover creates a Failover between primary and secondary. When
+// options.HealthCheck is set it starts checking the primary in the
+// background; call Close to stop.
+func NewFailover(primary, secondary grpc.ClientConnInterface, options FailoverOptions) *Failover {
+	breaker := options.Breaker
+	if breaker == nil {
+		breaker = circuitbreaker.New()
+	}
+	healthInterval := options.HealthInterval
+	if healthInterval <= 0 {
+		healthInterval = defaultHealthInterval
+	}
+
+	f := &Failover{
+		primary:        primary,
+		secondary:      secondary,
+		breaker:        breaker,
+		healthCheck:    options.HealthCheck,
+		healthInterval: healthInterval,
+		onFailover:     options.OnFailover,
+		stopped:        make(chan struct{}),
+	}
+	if f.healthCheck == nil {
+		close(f.stopped)
+		return f
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	f.stop = cancel
+	go f.checkHealth(ctx)
+	return f
+}
+
+// Active returns the endpoint that currently receives calls.
+func (f *Failover) Active() FailoverEndpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+// Close stops the health checks. It does not close the connections.
+func (f *Failover) Close() error {
+	if f.stop != nil {
+		f.stop()
+	}
+	<-f.stopped
+	return nil
+}
+
+func (f *Failover) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	conn, done := f.pick()
+	err := conn.Invoke(ctx, method, args, reply, opts...)
+	done(err)
+	return err
+}
+
+// NewStream opens the stream on the active endpoint. Only the outcome of
+// opening it is recorded on the breaker.
+func (f *Failover) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	conn, done := f.pick()
+	stream, err := conn.NewStream(ctx, desc, method, opts...)
+	done(err)
+	return stream, err
+}
+
+// pick returns the connection for the next call and the function to record
+// its outcome with.
+func (f *Failover) pick() (grpc.ClientConnInterface, func(error)) {
+	if err := f.primaryHealth(); err != nil {
+		f.transition(FailoverSecondary, err)
+		return f.secondary, func(error) {}
+	}
+	done, err := f.breaker.Allow()
+	if err != nil {
+		f.transition(FailoverSecondary, err)
+		return f.secondary, func(error) {}
+	}
+	return f.primary, func(err error) {
+		done(err)
+		if f.breaker.State() == circuitbreaker.StateClosed {
+			f.transition(FailoverPrimary, nil)
+		}
+	}
+}
+
+// primaryHealth returns the error of the last health check or, without one,
+// an error while the primary connection is failing.
+func (f *Failover) primaryHealth() error {
+	if f.healthCheck != nil {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.healthErr
+	}
+	if conn, ok := f.primary.(interface{ GetState() connectivity.State }); ok {
+		if state := conn.GetState(); state == connectivity.TransientFailure || state == connectivity.Shutdown {
+			return fmt.Errorf("primary connection is %s", state)
+		}
+	}
+	return nil
+}
+
+func (f *Failover) checkHealth(ctx context.Context) {
+	defer close(f.stopped)
+	ticker := time.NewTicker(f.healthInterval)
+	defer ticker.Stop()
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, f.healthInterval)
+		err := f.healthCheck(checkCtx, f.primary)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		f.mu.Lock()
+		f.healthErr = err
+		f.mu.Unlock()
+		if err != nil {
+			f.transition(FailoverSecondary, err)
+		} else if f.breaker.State() == circuitbreaker.StateClosed {
+			f.transition(FailoverPrimary, nil)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Failover) transition(active FailoverEndpoint, reason error) {
+	f.mu.Lock()
+	changed := f.active != active
+	f.active = active
+	f.mu.Unlock()
+
+	if changed && f.onFailover != nil {
+		f.onFailover(FailoverEvent{Active: active, Reason: reason})
+	}
+}
@@ -0,0 +1,134 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/circuitbreaker"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeConn counts the calls made on it and fails them with err.
+type fakeConn struct {
+	mu    sync.Mutex
+	err   error
+	calls int
+}
+
+func (c *fakeConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return c.err
+}
+
+func (c *fakeConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, c.Invoke(ctx, method, nil, nil, opts...)
+}
+
+func (c *fakeConn) setErr(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+func (c *fakeConn) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []FailoverEvent
+}
+
+func (r *eventRecorder) record(event FailoverEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) actives() []FailoverEndpoint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var actives []FailoverEndpoint
+	for _, event := range r.events {
+		actives = append(actives, event.Active)
+	}
+	return actives
+}
+
+func TestFailover_circuitOpenAndFailBack(t *testing.T) {
+	primary, secondary := &fakeConn{}, &fakeConn{}
+	events := &eventRecorder{}
+	breaker := circuitbreaker.New(circuitbreaker.WithConsecutiveFailures(2), circuitbreaker.WithOpenTimeout(50*time.Millisecond))
+	failover := NewFailover(primary, secondary, FailoverOptions{Breaker: breaker, OnFailover: events.record})
+	defer failover.Close()
+
+	require.NoError(t, failover.Invoke(context.Background(), "/test", nil, nil))
+	assert.Equal(t, FailoverPrimary, failover.Active())
+
+	primary.setErr(status.Error(codes.Unavailable, "region down"))
+	for range 2 {
+		assert.Error(t, failover.Invoke(context.Background(), "/test", nil, nil))
+	}
+	require.NoError(t, failover.Invoke(context.Background(), "/test", nil, nil))
+	assert.Equal(t, 3, primary.callCount())
+	assert.Equal(t, 1, secondary.callCount())
+	assert.Equal(t, FailoverSecondary, failover.Active())
+	require.Len(t, events.events, 1)
+	assert.ErrorIs(t, events.events[0].Reason, kesselerrors.ErrCircuitOpen)
+
+	primary.setErr(nil)
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, failover.Invoke(context.Background(), "/test", nil, nil))
+	assert.Equal(t, 4, primary.callCount(), "the half-open probe goes to the primary")
+	assert.Equal(t, FailoverPrimary, failover.Active())
+	assert.Equal(t, []FailoverEndpoint{FailoverSecondary, FailoverPrimary}, events.actives())
+	assert.NoError(t, events.events[1].Reason)
+}
+
+func TestFailover_healthCheck(t *testing.T) {
+	primary, secondary := &fakeConn{}, &fakeConn{}
+	events := &eventRecorder{}
+	var mu sync.Mutex
+	healthErr := errors.New("primary is draining")
+	failover := NewFailover(primary, secondary, FailoverOptions{
+		HealthCheck: func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return healthErr
+		},
+		HealthInterval: 10 * time.Millisecond,
+		OnFailover:     events.record,
+	})
+	defer failover.Close()
+
+	require.Eventually(t, func() bool { return failover.Active() == FailoverSecondary }, time.Second, 5*time.Millisecond)
+	require.NoError(t, failover.Invoke(context.Background(), "/test", nil, nil))
+	assert.Equal(t, 0, primary.callCount())
+	assert.Equal(t, 1, secondary.callCount())
+
+	mu.Lock()
+	healthErr = nil
+	mu.Unlock()
+	require.Eventually(t, func() bool { return failover.Active() == FailoverPrimary }, time.Second, 5*time.Millisecond)
+	_, err := failover.NewStream(context.Background(), &grpc.StreamDesc{}, "/test")
+	require.NoError(t, err)
+	assert.Equal(t, 1, primary.callCount())
+	assert.Equal(t, []FailoverEndpoint{FailoverSecondary, FailoverPrimary}, events.actives())
+}
+
+func TestFailover_closeWithoutHealthCheck(t *testing.T) {
+	failover := NewFailover(&fakeConn{}, &fakeConn{}, FailoverOptions{})
+	assert.NoError(t, failover.Close())
+}
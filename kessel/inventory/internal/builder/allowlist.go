@@ -0,0 +1,36 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+)
+
+type methodAllowlist map[string]bool
+
+func (a methodAllowlist) check(method string) error {
+	if a[method] {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is not in the unauthenticated allowlist", kesselerrors.ErrAuthenticationRequired, method)
+}
+
+func (a methodAllowlist) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := a.check(method); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func (a methodAllowlist) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := a.check(method); err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
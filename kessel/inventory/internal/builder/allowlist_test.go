@@ -0,0 +1,73 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+)
+
+const checkMethod = "/kessel.inventory.v1beta2.KesselInventoryService/Check"
+
+func TestMethodAllowlist(t *testing.T) {
+	allowlist := methodAllowlist{checkMethod: true}
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, nil
+	}
+
+	if err := allowlist.unaryInterceptor()(context.Background(), checkMethod, nil, nil, nil, invoker); err != nil {
+		t.Errorf("Expected allowlisted method to go through, got %v", err)
+	}
+
+	err := allowlist.unaryInterceptor()(context.Background(), reportResourceMethod, nil, nil, nil, invoker)
+	if !errors.Is(err, kesselerrors.ErrAuthenticationRequired) {
+		t.Errorf("Expected ErrAuthenticationRequired, got %v", err)
+	}
+
+	_, err = allowlist.streamInterceptor()(context.Background(), nil, nil, "/kessel.inventory.v1beta2.KesselInventoryService/StreamedListObjects", streamer)
+	if !errors.Is(err, kesselerrors.ErrAuthenticationRequired) {
+		t.Errorf("Expected ErrAuthenticationRequired for streams, got %v", err)
+	}
+}
+
+func TestWithUnauthenticatedMethods(t *testing.T) {
+	tests := []struct {
+		name          string
+		builder       *ClientBuilder[*testClient]
+		expectedUnary int
+	}{
+		{
+			name:          "insecure",
+			builder:       NewClientBuilder("localhost:9000", newTestClient).WithUnauthenticatedMethods(checkMethod).Insecure(),
+			expectedUnary: 2,
+		},
+		{
+			name:          "unauthenticated",
+			builder:       NewClientBuilder("localhost:9000", newTestClient).WithUnauthenticatedMethods(checkMethod).Unauthenticated(nil),
+			expectedUnary: 2,
+		},
+		{
+			name: "authenticated",
+			builder: func() *ClientBuilder[*testClient] {
+				creds := auth.NewOAuth2ClientCredentials("client", "secret", "https://example.com/token")
+				return NewClientBuilder("localhost:9000", newTestClient).WithUnauthenticatedMethods(checkMethod).OAuth2ClientAuthenticated(&creds, nil)
+			}(),
+			expectedUnary: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unary, _ := tt.builder.interceptors()
+			if len(unary) != tt.expectedUnary {
+				t.Errorf("Expected %d unary interceptors, got %d", tt.expectedUnary, len(unary))
+			}
+		})
+	}
+}
@@ -1,23 +1,85 @@
 package builder
 
 import (
-	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"regexp"
+	"time"
 
 	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth/grpccreds"
+	"github.com/project-kessel/kessel-sdk-go/kessel/circuitbreaker"
+	"github.com/project-kessel/kessel-sdk-go/kessel/debuglog"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/callmetadata"
+	"github.com/project-kessel/kessel-sdk-go/kessel/ratelimit"
+	"github.com/project-kessel/kessel-sdk-go/kessel/validation"
+	"github.com/project-kessel/kessel-sdk-go/kessel/version"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the "gzip" compressor for WithCompression
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/stats"
 )
 
+// roundRobinServiceConfig selects the round_robin load-balancing policy, which
+// keeps a subchannel to every resolved address and rotates calls across them.
+const roundRobinServiceConfig = `{"loadBalancingConfig": [{"round_robin": {}}]}`
+
+// costCenterMetadata is the metadata key Kessel operators use to attribute
+// load to the consuming team.
+const costCenterMetadata = "x-kessel-cost-center"
+
+// costCenterPattern is the agreed cost-center tag format: a lowercase DNS
+// label, optionally prefixed by an organisation label and "/".
+var costCenterPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?/)?[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
 // ClientBuilder is a generic builder that constructs a typed gRPC client stub and its connection.
 // C is the client interface type (e.g., v1beta2.KesselInventoryServiceClient).
 type ClientBuilder[C any] struct {
 	target             string
+	targets            []string
+	roundRobin         bool
+	serviceConfig      map[string]json.RawMessage
+	resolvers          []resolver.Builder
+	readOnly           bool
+	requestIds         bool
+	clientValidation   bool
+	schemaRegistry     *validation.SchemaRegistry
+	unauthenticated    methodAllowlist
 	channelCredentials credentials.TransportCredentials
 	perRPCCredentials  credentials.PerRPCCredentials
 	insecure           bool
+	allowInsecureAuth  bool
+	authority          string
+	tlsServerName      string
+	logger             *slog.Logger
+	rateLimiter        *ratelimit.Limiter
+	tenantLimiter      *ratelimit.KeyedLimiter
+	rateLimitRetry     *rateLimitRetry
+	circuitBreaker     *circuitbreaker.Breaker
+	staticMetadata     metadata.MD
+	debugLogger        *debuglog.Logger
+	idempotency        *idempotencyCache
+	hedgingDelay       time.Duration
+	compressor         string
+	maxSendSize        int
+	maxStreams         int
+	maxConns           int
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+	statsHandlers      []stats.Handler
+	grpcWeb            *http.Client
+	optionErrors       kesselerrors.Multi
 	newStub            func(grpc.ClientConnInterface) C
 }
 
@@ -41,7 +103,11 @@ func (b *ClientBuilder[C]) setChannelCredentialsOrDefault(channelCredentials cre
 func (b *ClientBuilder[C]) OAuth2ClientAuthenticated(oAuth2ClientCredentials *auth.OAuth2ClientCredentials, channelCredentials credentials.TransportCredentials) *ClientBuilder[C] {
 	b.setChannelCredentialsOrDefault(channelCredentials)
 	if oAuth2ClientCredentials != nil {
-		b.perRPCCredentials = &oauth2PerRPCCreds{creds: oAuth2ClientCredentials, insecure: b.insecure}
+		var options []grpccreds.Option
+		if b.insecure {
+			options = append(options, grpccreds.AllowInsecure())
+		}
+		b.perRPCCredentials = grpccreds.NewOAuth2(oAuth2ClientCredentials, options...)
 	}
 	return b
 }
@@ -65,43 +131,599 @@ func (b *ClientBuilder[C]) Insecure() *ClientBuilder[C] {
 	return b
 }
 
-func (b *ClientBuilder[C]) Build() (C, *grpc.ClientConn, error) {
-	var zero C
-	if b.target == "" {
-		return zero, nil, fmt.Errorf("target URI is required")
+// AllowInsecureAuth permits Insecure and Unauthenticated clients to call
+// targets that are not local. Without it, Build and Validate reject such
+// clients for every target other than a unix socket, localhost or a loopback
+// address, so a production endpoint is never called without credentials by
+// mistake. Clients without credentials log a warning at Build either way.
+func (b *ClientBuilder[C]) AllowInsecureAuth() *ClientBuilder[C] {
+	b.allowInsecureAuth = true
+	return b
+}
+
+// WithLogger sets the logger for the builder's warnings, e.g. when a client
+// is built without authentication. Defaults to slog.Default().
+func (b *ClientBuilder[C]) WithLogger(logger *slog.Logger) *ClientBuilder[C] {
+	b.logger = logger
+	return b
+}
+
+// WithAuthority sends host as the :authority of every call instead of the
+// dial target, e.g. when dialing an IP address or a TCP load balancer in front
+// of a virtual-hosted Kessel. Unless WithTLSServerName is set, the server's
+// certificate is also verified against host.
+func (b *ClientBuilder[C]) WithAuthority(host string) *ClientBuilder[C] {
+	if host == "" {
+		b.optionErrors.Append(-1, "WithAuthority", fmt.Errorf("authority must not be empty"))
+		return b
+	}
+	b.authority = host
+	return b
+}
+
+// WithTLSServerName verifies the server's certificate against name (and sends
+// it as SNI) instead of the dial target's host or the authority, so a
+// tls.Config does not have to be built for an SNI mismatch. A ServerName set
+// in the channel credentials' own tls.Config takes precedence.
+func (b *ClientBuilder[C]) WithTLSServerName(name string) *ClientBuilder[C] {
+	if name == "" {
+		b.optionErrors.Append(-1, "WithTLSServerName", fmt.Errorf("server name must not be empty"))
+		return b
+	}
+	b.tlsServerName = name
+	return b
+}
+
+// WithTargets spreads calls round-robin across the target passed to
+// NewClientBuilder (if any) and these additional targets, so load reaches
+// several Kessel replicas without an external proxy. Targets are host:port
+// addresses. With TLS, every replica must present a certificate valid for the
+// host of the first target.
+func (b *ClientBuilder[C]) WithTargets(targets []string) *ClientBuilder[C] {
+	b.targets = append(b.targets, targets...)
+	return b
+}
+
+// WithRoundRobin uses the round_robin load-balancing policy, spreading calls
+// across every address the target resolves to (e.g. "dns:///kessel:9000")
+// instead of pinning them to the first one.
+func (b *ClientBuilder[C]) WithRoundRobin() *ClientBuilder[C] {
+	b.roundRobin = true
+	return b
+}
+
+// WithServiceConfig sets the default gRPC service config, a JSON document
+// with e.g. per-method timeouts and retry policies (see
+// kesselgrpc.RetryServiceConfig), so it can be tuned through configuration
+// rather than code. A service config published by the name resolver takes
+// precedence. With WithRoundRobin or WithTargets, round_robin is added unless
+// config chooses a load-balancing policy itself.
+func (b *ClientBuilder[C]) WithServiceConfig(config string) *ClientBuilder[C] {
+	var parsed map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(config), &parsed); err != nil {
+		b.optionErrors.Append(-1, "WithServiceConfig", fmt.Errorf("invalid service config: %w", err))
+		return b
+	}
+	b.serviceConfig = parsed
+	return b
+}
+
+// WithResolver makes the name resolvers available to this client only, so a
+// target such as "consul://kessel-inventory" or "srv:///_grpc._tcp.kessel"
+// (see kesselgrpc.NewSRVResolver) is resolved by service discovery instead of
+// DNS. A resolver is used when its Scheme matches the target's; targets with a
+// registered scheme such as "passthrough:///" or "unix:" need no resolver.
+// With TLS, the resolved addresses must present a certificate valid for the
+// target's host, or the channel credentials must set ServerName. It cannot be
+// combined with WithTargets.
+func (b *ClientBuilder[C]) WithResolver(builders ...resolver.Builder) *ClientBuilder[C] {
+	for _, builder := range builders {
+		if builder == nil {
+			b.optionErrors.Append(-1, "WithResolver", fmt.Errorf("resolver builder must not be nil"))
+			return b
+		}
+	}
+	b.resolvers = append(b.resolvers, builders...)
+	return b
+}
+
+// WithUnauthenticatedMethods restricts clients built without per-RPC
+// credentials (Insecure or Unauthenticated) to the given full method names,
+// e.g. v1beta2.KesselInventoryService_Check_FullMethodName. Any other call
+// fails locally with errors.ErrAuthenticationRequired. It has no effect once
+// an authenticated mode is chosen, so one builder chain can serve both
+// configurations.
+func (b *ClientBuilder[C]) WithUnauthenticatedMethods(methods ...string) *ClientBuilder[C] {
+	if b.unauthenticated == nil {
+		b.unauthenticated = methodAllowlist{}
+	}
+	for _, method := range methods {
+		b.unauthenticated[method] = true
+	}
+	return b
+}
+
+// WithReadOnly makes calls that mutate inventory (ReportResource,
+// DeleteResource and the tuple writes) fail fast with
+// errors.ErrReadOnlyClient while checks and reads still go through. Use it for
+// disaster-recovery standbys that must not write.
+func (b *ClientBuilder[C]) WithReadOnly() *ClientBuilder[C] {
+	b.readOnly = true
+	return b
+}
+
+// WithRequestIds sends an x-rh-insights-request-id with every call, generating
+// a random one unless the context already carries one (see
+// inventory.WithRequestId), and annotates every error with it as an
+// *errors.RequestIdError. Request IDs set on the context are sent even without
+// this option.
+func (b *ClientBuilder[C]) WithRequestIds() *ClientBuilder[C] {
+	b.requestIds = true
+	return b
+}
+
+// WithClientValidation checks every request against the buf.validate rules in
+// its protobuf definition before it is sent. Invalid requests fail with an
+// *errors.ValidationError (gRPC code InvalidArgument) without reaching the
+// server. See the validation package for which rules are evaluated locally.
+func (b *ClientBuilder[C]) WithClientValidation() *ClientBuilder[C] {
+	b.clientValidation = true
+	return b
+}
+
+// WithSchemaValidation checks the representations of every ReportResource
+// request against the JSON schemas in registry before it is sent. Invalid
+// representations fail with an *errors.ValidationError (gRPC code
+// InvalidArgument) listing each offending field, without reaching the server.
+// Schemas registered after Build are used by later calls.
+func (b *ClientBuilder[C]) WithSchemaValidation(registry *validation.SchemaRegistry) *ClientBuilder[C] {
+	if registry == nil {
+		b.optionErrors.Append(-1, "WithSchemaValidation", fmt.Errorf("registry must not be nil"))
+		return b
+	}
+	b.schemaRegistry = registry
+	return b
+}
+
+// WithRateLimit limits outgoing calls on the built connection to rps calls per
+// second with bursts of up to burst calls. Calls wait for a token and fail with
+// the context error if the context ends first.
+func (b *ClientBuilder[C]) WithRateLimit(rps float64, burst int) *ClientBuilder[C] {
+	if rps <= 0 || burst < 1 {
+		b.optionErrors.Append(-1, "WithRateLimit", fmt.Errorf("rps and burst must be positive, got rps=%v burst=%d", rps, burst))
+		return b
+	}
+	b.rateLimiter = ratelimit.NewLimiter(rps, burst)
+	return b
+}
+
+// WithTenantRateLimit gives every tenant its own limit of rps calls per second
+// with bursts of up to burst calls. The tenant is read from the call context,
+// set with ratelimit.WithTenant(ctx, orgId); calls without a tenant share one
+// bucket. It can be combined with WithRateLimit, which then caps the total.
+func (b *ClientBuilder[C]) WithTenantRateLimit(rps float64, burst int) *ClientBuilder[C] {
+	if rps <= 0 || burst < 1 {
+		b.optionErrors.Append(-1, "WithTenantRateLimit", fmt.Errorf("rps and burst must be positive, got rps=%v burst=%d", rps, burst))
+		return b
+	}
+	b.tenantLimiter = ratelimit.NewKeyedLimiter(rps, burst, nil)
+	return b
+}
+
+// WithRateLimitRetry makes unary calls that the server throttles return a
+// *errors.RateLimitedError carrying the delay it asked for (RetryInfo, or the
+// "retry-after" or "grpc-retry-pushback-ms" trailers), and retries them up to
+// maxRetries times after that delay. Delays over maxWait, or past the call's
+// deadline, are not waited out; the error is returned for the caller to back
+// off. A maxRetries of 0 only converts the errors.
+func (b *ClientBuilder[C]) WithRateLimitRetry(maxRetries int, maxWait time.Duration) *ClientBuilder[C] {
+	if maxRetries < 0 || maxWait < 0 {
+		b.optionErrors.Append(-1, "WithRateLimitRetry", fmt.Errorf("maxRetries and maxWait must not be negative, got maxRetries=%d maxWait=%s", maxRetries, maxWait))
+		return b
+	}
+	b.rateLimitRetry = &rateLimitRetry{maxRetries: maxRetries, maxWait: maxWait}
+	return b
+}
+
+// WithCircuitBreaker fails calls fast with errors.ErrCircuitOpen while the
+// breaker is open. Pass the same Breaker to several builders (or to the RBAC
+// helpers) to share its state.
+func (b *ClientBuilder[C]) WithCircuitBreaker(breaker *circuitbreaker.Breaker) *ClientBuilder[C] {
+	b.circuitBreaker = breaker
+	return b
+}
+
+// WithStaticMetadata adds md to the gRPC metadata of every call on the built
+// connection. Calling it again adds to the previously configured metadata.
+// Per-call metadata can be added with inventory.WithCallMetadata.
+func (b *ClientBuilder[C]) WithStaticMetadata(md map[string]string) *ClientBuilder[C] {
+	b.staticMetadata = metadata.Join(b.staticMetadata, metadata.New(md))
+	return b
+}
+
+// WithDebugLogging logs every call through logger. The logger can be switched
+// on and off at runtime with its SetEnabled and SetPayloads methods.
+func (b *ClientBuilder[C]) WithDebugLogging(logger *debuglog.Logger) *ClientBuilder[C] {
+	b.debugLogger = logger
+	return b
+}
+
+// WithIdempotency attaches an idempotency key (a UUID, sent as the
+// "idempotency-key" metadata) to ReportResource and DeleteResource calls.
+// Retries of an identical request reuse the key until one succeeds or ttl
+// passes, so the server can recognise them. Every call is still sent.
+func (b *ClientBuilder[C]) WithIdempotency(ttl time.Duration) *ClientBuilder[C] {
+	if ttl <= 0 {
+		b.optionErrors.Append(-1, "WithIdempotency", fmt.Errorf("ttl must be positive, got %s", ttl))
+		return b
+	}
+	b.idempotency = newIdempotencyCache(ttl)
+	return b
+}
+
+// WithHedging sends a second attempt of Check, CheckSelf and CheckForUpdate
+// calls that have not completed after delay (e.g. their p99 latency) and uses
+// whichever succeeds first, trading extra server load for lower tail latency.
+// Mutating calls are never hedged. Interceptors added with
+// WithUnaryInterceptor see one call; per-RPC credentials are applied to each
+// attempt.
+func (b *ClientBuilder[C]) WithHedging(delay time.Duration) *ClientBuilder[C] {
+	if delay <= 0 {
+		b.optionErrors.Append(-1, "WithHedging", fmt.Errorf("delay must be positive, got %s", delay))
+		return b
+	}
+	b.hedgingDelay = delay
+	return b
+}
+
+// WithCostCenter tags every call with the consuming team's cost center (sent as
+// "x-kessel-cost-center") so Kessel operators can attribute load. Tags are
+// lowercase DNS labels such as "inventory-team", optionally prefixed with an
+// organisation label ("rhel/inventory-team"). Calling it again replaces the
+// tag.
+func (b *ClientBuilder[C]) WithCostCenter(tag string) *ClientBuilder[C] {
+	if !costCenterPattern.MatchString(tag) {
+		b.optionErrors.Append(-1, "WithCostCenter", fmt.Errorf("invalid cost center tag %q: must be a lowercase DNS label, optionally prefixed with \"<org>/\"", tag))
+		return b
+	}
+	b.staticMetadata = metadata.Join(b.staticMetadata)
+	b.staticMetadata.Set(costCenterMetadata, tag)
+	return b
+}
+
+// WithCompression compresses every request with the named compressor, which
+// must be registered with grpc/encoding. "gzip" is always available. Responses
+// are compressed only if the server chooses to. It helps most with large
+// ReportResource payloads and long StreamedListObjects responses.
+func (b *ClientBuilder[C]) WithCompression(name string) *ClientBuilder[C] {
+	if encoding.GetCompressor(name) == nil {
+		b.optionErrors.Append(-1, "WithCompression", fmt.Errorf("unknown compressor %q", name))
+		return b
+	}
+	b.compressor = name
+	return b
+}
+
+// WithMaxSendMessageSize limits requests to maxBytes once serialized. Larger
+// requests (and stream messages) fail before they are sent with a
+// *errors.MessageSizeError carrying the size and limit, which matches
+// errors.ErrMessageTooLarge and converts to ResourceExhausted, instead of the
+// transport's opaque ResourceExhausted. The limit is also set as gRPC's
+// MaxCallSendMsgSize. Without it, gRPC's default send limit applies.
+func (b *ClientBuilder[C]) WithMaxSendMessageSize(maxBytes int) *ClientBuilder[C] {
+	if maxBytes < 1 {
+		b.optionErrors.Append(-1, "WithMaxSendMessageSize", fmt.Errorf("maxBytes must be positive, got %d", maxBytes))
+		return b
+	}
+	b.maxSendSize = maxBytes
+	return b
+}
+
+// WithMaxConcurrentStreams opens additional connections, up to maxConns in
+// total, once the built connection has maxStreams streams in flight, so large
+// numbers of concurrent StreamedListObjects calls are not queued behind the
+// server's HTTP/2 stream limit. When every connection is full, new streams go
+// to the least busy one. The extra connections are closed when the connection
+// returned by Build is closed. Unary calls are not counted.
+func (b *ClientBuilder[C]) WithMaxConcurrentStreams(maxStreams int, maxConns int) *ClientBuilder[C] {
+	if maxStreams < 1 || maxConns < 1 {
+		b.optionErrors.Append(-1, "WithMaxConcurrentStreams", fmt.Errorf("maxStreams and maxConns must be positive, got maxStreams=%d maxConns=%d", maxStreams, maxConns))
+		return b
+	}
+	b.maxStreams = maxStreams
+	b.maxConns = maxConns
+	return b
+}
+
+// WithUnaryInterceptor adds caller interceptors (auditing, fault injection)
+// to unary calls. They run after the SDK's own interceptors, in the order
+// given, so they see the final call metadata and their failures count towards
+// the circuit breaker. Calling it again adds to the previously configured
+// interceptors.
+func (b *ClientBuilder[C]) WithUnaryInterceptor(interceptors ...grpc.UnaryClientInterceptor) *ClientBuilder[C] {
+	b.unaryInterceptors = append(b.unaryInterceptors, interceptors...)
+	return b
+}
+
+// WithStreamInterceptor adds caller interceptors to streams. They run after
+// the SDK's own interceptors, in the order given, and once per stream even
+// when WithMaxConcurrentStreams moves the stream to another connection.
+func (b *ClientBuilder[C]) WithStreamInterceptor(interceptors ...grpc.StreamClientInterceptor) *ClientBuilder[C] {
+	b.streamInterceptors = append(b.streamInterceptors, interceptors...)
+	return b
+}
+
+// WithStatsHandler installs gRPC stats handlers on the connection, e.g.
+// otelgrpc.NewClientHandler() for OpenTelemetry, or a custom handler that
+// counts wire bytes. Unlike interceptors, they observe transport-level events
+// such as the compressed payload size of every message and the connection
+// lifecycle. They also apply to the extra connections opened by
+// WithMaxConcurrentStreams. It cannot be combined with WithGrpcWeb.
+func (b *ClientBuilder[C]) WithStatsHandler(handlers ...stats.Handler) *ClientBuilder[C] {
+	for _, handler := range handlers {
+		if handler == nil {
+			b.optionErrors.Append(-1, "WithStatsHandler", fmt.Errorf("stats handler must not be nil"))
+			return b
+		}
+	}
+	b.statsHandlers = append(b.statsHandlers, handlers...)
+	return b
+}
+
+// WithGrpcWeb sends calls as binary grpc-web requests over HTTP/1.1 through
+// httpClient (http.DefaultClient when nil), for runtimes or proxies without
+// HTTP/2. Unary and server-streaming calls are supported. The target may be a
+// host:port, which is reached over https (http with Insecure), or a full URL.
+// TLS settings come from httpClient; the channel credentials given to the
+// authentication methods are ignored, while call credentials are still sent.
+// Such clients have no *grpc.ClientConn, so create them with BuildGrpcWeb;
+// Build rejects them. It cannot be combined with WithTargets, WithRoundRobin,
+// WithCompression, WithMaxConcurrentStreams, WithServiceConfig,
+// WithResolver, WithStatsHandler, WithAuthority or WithTLSServerName.
+func (b *ClientBuilder[C]) WithGrpcWeb(httpClient *http.Client) *ClientBuilder[C] {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	b.grpcWeb = httpClient
+	return b
+}
+
+func (b *ClientBuilder[C]) interceptors() ([]grpc.UnaryClientInterceptor, []grpc.StreamClientInterceptor) {
+	var unary []grpc.UnaryClientInterceptor
+	var stream []grpc.StreamClientInterceptor
+	if b.requestIds {
+		unary = append(unary, requestIdUnaryInterceptor())
+		stream = append(stream, requestIdStreamInterceptor())
+	}
+	if b.rateLimitRetry != nil {
+		unary = append(unary, b.rateLimitRetry.unaryInterceptor())
+	}
+	if b.unauthenticated != nil && b.perRPCCredentials == nil {
+		unary = append(unary, b.unauthenticated.unaryInterceptor())
+		stream = append(stream, b.unauthenticated.streamInterceptor())
+	}
+	if b.readOnly {
+		unary = append(unary, readOnlyUnaryInterceptor())
+	}
+	if b.clientValidation {
+		unary = append(unary, validation.UnaryClientInterceptor())
+		stream = append(stream, validation.StreamClientInterceptor())
+	}
+	if b.schemaRegistry != nil {
+		unary = append(unary, b.schemaRegistry.UnaryClientInterceptor())
+	}
+	if b.maxSendSize > 0 {
+		unary = append(unary, messageSizeUnaryInterceptor(b.maxSendSize))
+		stream = append(stream, messageSizeStreamInterceptor(b.maxSendSize))
+	}
+	if b.circuitBreaker != nil {
+		unary = append(unary, circuitbreaker.UnaryClientInterceptor(b.circuitBreaker))
+		stream = append(stream, circuitbreaker.StreamClientInterceptor(b.circuitBreaker))
+	}
+	if b.rateLimiter != nil {
+		unary = append(unary, ratelimit.UnaryClientInterceptor(b.rateLimiter))
+		stream = append(stream, ratelimit.StreamClientInterceptor(b.rateLimiter))
+	}
+	if b.tenantLimiter != nil {
+		unary = append(unary, ratelimit.KeyedUnaryClientInterceptor(b.tenantLimiter))
+		stream = append(stream, ratelimit.KeyedStreamClientInterceptor(b.tenantLimiter))
+	}
+	unary = append(unary, callmetadata.UnaryClientInterceptor(b.staticMetadata))
+	stream = append(stream, callmetadata.StreamClientInterceptor(b.staticMetadata))
+	if b.idempotency != nil {
+		unary = append(unary, b.idempotency.unaryInterceptor())
+	}
+	if b.debugLogger != nil {
+		unary = append(unary, debuglog.UnaryClientInterceptor(b.debugLogger))
+		stream = append(stream, debuglog.StreamClientInterceptor(b.debugLogger))
+	}
+	unary = append(unary, b.unaryInterceptors...)
+	stream = append(stream, b.streamInterceptors...)
+	if b.hedgingDelay > 0 {
+		unary = append(unary, hedgingUnaryInterceptor(b.hedgingDelay))
+	}
+	if b.maxStreams > 0 {
+		overflow := newStreamOverflow(b.maxStreams, b.maxConns, func() (*grpc.ClientConn, error) {
+			target, dialOpts := b.dialTarget()
+			return grpc.NewClient(target, append(dialOpts, b.baseDialOptions()...)...)
+		})
+		stream = append(stream, overflow.streamInterceptor())
 	}
+	return unary, stream
+}
 
+// baseDialOptions returns the dial options shared by every connection the
+// builder creates, without interceptors.
+func (b *ClientBuilder[C]) baseDialOptions() []grpc.DialOption {
 	var dialOpts []grpc.DialOption
 	// Transport security (TLS or insecure)
-	dialOpts = append(dialOpts, grpc.WithTransportCredentials(b.channelCredentials))
+	channelCredentials := b.channelCredentials
+	if b.tlsServerName != "" {
+		channelCredentials = &serverNameCredentials{TransportCredentials: channelCredentials, serverName: b.tlsServerName}
+	}
+	dialOpts = append(dialOpts, grpc.WithTransportCredentials(channelCredentials))
+	if b.authority != "" {
+		dialOpts = append(dialOpts, grpc.WithAuthority(b.authority))
+	}
+	// gRPC appends its own "grpc-go/<version>" to the user agent
+	dialOpts = append(dialOpts, grpc.WithUserAgent(version.UserAgent()))
 	// Apply only internal auth call credentials, no external customization hooks
 	if b.perRPCCredentials != nil {
 		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.PerRPCCredentials(b.perRPCCredentials)))
 	}
+	if b.compressor != "" {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor(b.compressor)))
+	}
+	if b.maxSendSize > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(b.maxSendSize)))
+	}
+	for _, handler := range b.statsHandlers {
+		dialOpts = append(dialOpts, grpc.WithStatsHandler(handler))
+	}
+	return dialOpts
+}
 
-	conn, err := grpc.NewClient(b.target, dialOpts...)
-	if err != nil {
-		return zero, nil, fmt.Errorf("failed to create gRPC client: %w", err)
+// addresses returns the target passed to NewClientBuilder, if any, followed
+// by the WithTargets addresses.
+func (b *ClientBuilder[C]) addresses() []string {
+	var addresses []string
+	if b.target != "" {
+		addresses = append(addresses, b.target)
 	}
+	return append(addresses, b.targets...)
+}
 
-	return b.newStub(conn), conn, nil
+// dialTarget returns the target to dial and the dial options that select how
+// it is resolved and balanced. Several targets are served by a manual resolver
+// created per connection, since resolvers passed with grpc.WithResolvers are
+// scoped to one ClientConn.
+func (b *ClientBuilder[C]) dialTarget() (string, []grpc.DialOption) {
+	addresses := b.addresses()
+
+	if len(addresses) == 1 {
+		var dialOpts []grpc.DialOption
+		if len(b.resolvers) > 0 {
+			dialOpts = append(dialOpts, grpc.WithResolvers(b.resolvers...))
+		}
+		if config, ok := b.defaultServiceConfig(b.roundRobin); ok {
+			dialOpts = append(dialOpts, grpc.WithDefaultServiceConfig(config))
+		}
+		return addresses[0], dialOpts
+	}
+
+	r := manual.NewBuilderWithScheme("kessel")
+	state := resolver.State{}
+	for _, address := range addresses {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: address})
+	}
+	r.InitialState(state)
+	config, _ := b.defaultServiceConfig(true)
+	return r.Scheme() + ":///" + addresses[0], []grpc.DialOption{
+		grpc.WithResolvers(r),
+		grpc.WithDefaultServiceConfig(config),
+	}
 }
 
-type oauth2PerRPCCreds struct {
-	creds    *auth.OAuth2ClientCredentials
-	insecure bool
+// defaultServiceConfig returns the service config set with WithServiceConfig,
+// with round_robin added when roundRobin is set and the config has no
+// load-balancing policy, or false when there is nothing to set.
+func (b *ClientBuilder[C]) defaultServiceConfig(roundRobin bool) (string, bool) {
+	if b.serviceConfig == nil {
+		return roundRobinServiceConfig, roundRobin
+	}
+	config := maps.Clone(b.serviceConfig)
+	_, hasConfig := config["loadBalancingConfig"]
+	_, hasPolicy := config["loadBalancingPolicy"]
+	if roundRobin && !hasConfig && !hasPolicy {
+		config["loadBalancingConfig"] = json.RawMessage(`[{"round_robin": {}}]`)
+	}
+	data, _ := json.Marshal(config)
+	return string(data), true
 }
 
-func (o *oauth2PerRPCCreds) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
-	tok, err := o.creds.GetToken(ctx, auth.GetTokenOptions{})
+// Validate checks the configuration without creating a connection, as a dry
+// run of Build: it returns the same *errors.Multi of every configuration
+// problem, or nil if Build would succeed in creating the client.
+func (b *ClientBuilder[C]) Validate() error {
+	errs := &kesselerrors.Multi{}
+	if b.target == "" && len(b.targets) == 0 {
+		errs.Append(-1, "", fmt.Errorf("target URI is required"))
+	}
+	if len(b.resolvers) > 0 && len(b.targets) > 0 {
+		errs.Append(-1, "WithResolver", fmt.Errorf("cannot be combined with WithTargets"))
+	}
+	if b.tlsServerName != "" && b.insecure {
+		errs.Append(-1, "WithTLSServerName", fmt.Errorf("cannot be combined with Insecure"))
+	}
+	if b.grpcWeb != nil {
+		conflicts := []struct {
+			option string
+			set    bool
+		}{
+			{"WithTargets", len(b.targets) > 0},
+			{"WithRoundRobin", b.roundRobin},
+			{"WithCompression", b.compressor != ""},
+			{"WithMaxConcurrentStreams", b.maxStreams > 0},
+			{"WithServiceConfig", b.serviceConfig != nil},
+			{"WithResolver", len(b.resolvers) > 0},
+			{"WithStatsHandler", len(b.statsHandlers) > 0},
+			{"WithAuthority", b.authority != ""},
+			{"WithTLSServerName", b.tlsServerName != ""},
+		}
+		for _, conflict := range conflicts {
+			if conflict.set {
+				errs.Append(-1, "WithGrpcWeb", fmt.Errorf("cannot be combined with %s", conflict.option))
+			}
+		}
+	}
+	b.validateInsecureAuth(errs)
+	errs.Errors = append(errs.Errors, b.optionErrors.Errors...)
+	return errs.ErrorOrNil()
+}
+
+func (b *ClientBuilder[C]) Build() (C, *grpc.ClientConn, error) {
+	var zero C
+	if b.grpcWeb != nil {
+		return zero, nil, fmt.Errorf("WithGrpcWeb clients have no *grpc.ClientConn; use BuildGrpcWeb")
+	}
+	if err := b.Validate(); err != nil {
+		return zero, nil, err
+	}
+	b.warnInsecureAuth()
+
+	unary, stream := b.interceptors()
+
+	target, dialOpts := b.dialTarget()
+	dialOpts = append(dialOpts, b.baseDialOptions()...)
+	if len(unary) > 0 {
+		dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(unary...))
+	}
+	if len(stream) > 0 {
+		dialOpts = append(dialOpts, grpc.WithChainStreamInterceptor(stream...))
+	}
+
+	conn, err := grpc.NewClient(target, dialOpts...)
 	if err != nil {
-		return nil, err
+		return zero, nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
-	return map[string]string{
-		"authorization": fmt.Sprintf("Bearer %s", tok.AccessToken),
-	}, nil
+
+	return b.newStub(conn), conn, nil
 }
 
-func (o *oauth2PerRPCCreds) RequireTransportSecurity() bool {
-	return !o.insecure
+// BuildGrpcWeb creates a client configured with WithGrpcWeb. The returned
+// io.Closer takes the place of the *grpc.ClientConn: close it when done with
+// the client.
+func (b *ClientBuilder[C]) BuildGrpcWeb() (C, io.Closer, error) {
+	var zero C
+	if b.grpcWeb == nil {
+		return zero, nil, fmt.Errorf("BuildGrpcWeb requires WithGrpcWeb; use Build")
+	}
+	if err := b.Validate(); err != nil {
+		return zero, nil, err
+	}
+	b.warnInsecureAuth()
+
+	unary, stream := b.interceptors()
+	conn := newGrpcWebConn(b.target, b.insecure, b.grpcWeb, b.perRPCCredentials, unary, stream)
+	return b.newStub(conn), conn, nil
 }
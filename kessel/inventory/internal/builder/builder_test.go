@@ -0,0 +1,506 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth/grpccreds"
+	"github.com/project-kessel/kessel-sdk-go/kessel/circuitbreaker"
+	"github.com/project-kessel/kessel-sdk-go/kessel/debuglog"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	kesselgrpc "github.com/project-kessel/kessel-sdk-go/kessel/grpc"
+	"github.com/project-kessel/kessel-sdk-go/kessel/validation"
+	"github.com/project-kessel/kessel-sdk-go/kessel/version"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+type testClient struct {
+	conn grpc.ClientConnInterface
+}
+
+func newTestClient(conn grpc.ClientConnInterface) *testClient {
+	return &testClient{conn: conn}
+}
+
+func TestBuild_requiresTarget(t *testing.T) {
+	client, conn, err := NewClientBuilder("", newTestClient).Insecure().Build()
+	if err == nil {
+		t.Fatal("Expected error for empty target")
+	}
+	if client != nil || conn != nil {
+		t.Errorf("Expected zero values on error, got client=%v conn=%v", client, conn)
+	}
+}
+
+func TestInsecure_clearsPerRPCCredentials(t *testing.T) {
+	creds := auth.NewOAuth2ClientCredentials("client", "secret", "https://example.com/token")
+	b := NewClientBuilder("localhost:9000", newTestClient).
+		OAuth2ClientAuthenticated(&creds, nil).
+		Insecure()
+
+	if b.perRPCCredentials != nil {
+		t.Error("Expected Insecure() to clear per-RPC credentials")
+	}
+	if !b.insecure {
+		t.Error("Expected builder to be insecure")
+	}
+}
+
+func TestOAuth2ClientAuthenticated_usesSharedAdapter(t *testing.T) {
+	creds := auth.NewOAuth2ClientCredentials("client", "secret", "https://example.com/token")
+	b := NewClientBuilder("localhost:9000", newTestClient).OAuth2ClientAuthenticated(&creds, nil)
+
+	perRPC, ok := b.perRPCCredentials.(*grpccreds.OAuth2)
+	if !ok {
+		t.Fatalf("Expected *grpccreds.OAuth2, got %T", b.perRPCCredentials)
+	}
+	if perRPC.Credentials() != &creds {
+		t.Error("Expected the adapter to hold the given credentials")
+	}
+	if !perRPC.RequireTransportSecurity() {
+		t.Error("Expected the adapter to require transport security")
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	b := NewClientBuilder("localhost:9000", newTestClient).Insecure()
+
+	unary, stream := b.interceptors()
+	if len(unary) != 1 || len(stream) != 1 {
+		t.Errorf("Expected only the call metadata interceptors by default, got %d unary and %d stream", len(unary), len(stream))
+	}
+
+	b.WithRateLimit(10, 5)
+	if b.rateLimiter == nil {
+		t.Fatal("Expected rate limiter to be configured")
+	}
+
+	unary, stream = b.interceptors()
+	if len(unary) != 2 || len(stream) != 2 {
+		t.Errorf("Expected 2 unary and 2 stream interceptors, got %d and %d", len(unary), len(stream))
+	}
+
+	client, conn, err := b.Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed, got %v", err)
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			t.Errorf("Failed to close connection: %v", closeErr)
+		}
+	}()
+	if client == nil {
+		t.Error("Expected client to be built")
+	}
+}
+
+func TestWithTenantRateLimit(t *testing.T) {
+	b := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithTenantRateLimit(10, 5)
+	if b.tenantLimiter == nil {
+		t.Fatal("Expected tenant rate limiter to be configured")
+	}
+
+	unary, stream := b.interceptors()
+	if len(unary) != 2 || len(stream) != 2 {
+		t.Errorf("Expected 2 unary and 2 stream interceptors, got %d and %d", len(unary), len(stream))
+	}
+
+	_, _, err := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithTenantRateLimit(0, 5).Build()
+	if err == nil {
+		t.Error("Expected error for non-positive rps")
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	breaker := circuitbreaker.New()
+	b := NewClientBuilder("localhost:9000", newTestClient).
+		Insecure().
+		WithRateLimit(10, 5).
+		WithCircuitBreaker(breaker)
+
+	if b.circuitBreaker != breaker {
+		t.Error("Expected circuit breaker to be configured")
+	}
+
+	unary, stream := b.interceptors()
+	if len(unary) != 3 || len(stream) != 3 {
+		t.Errorf("Expected 3 unary and 3 stream interceptors, got %d and %d", len(unary), len(stream))
+	}
+}
+
+func TestBuild_reportsAllValidationErrors(t *testing.T) {
+	_, _, err := NewClientBuilder("", newTestClient).
+		Insecure().
+		WithRateLimit(0, 1).
+		Build()
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	var multi *kesselerrors.Multi
+	if !errors.As(err, &multi) {
+		t.Fatalf("Expected *errors.Multi, got %T", err)
+	}
+	if multi.Len() != 2 {
+		t.Errorf("Expected 2 validation errors, got %d: %v", multi.Len(), err)
+	}
+	if multi.Errors[1].Key != "WithRateLimit" {
+		t.Errorf("Expected second error keyed by option name, got %q", multi.Errors[1].Key)
+	}
+}
+
+func TestBuild_singleValidationErrorMessage(t *testing.T) {
+	_, _, err := NewClientBuilder("", newTestClient).Insecure().Build()
+	if err == nil || err.Error() != "target URI is required" {
+		t.Errorf("Expected unchanged message for a single error, got %v", err)
+	}
+}
+
+func TestWithStaticMetadata(t *testing.T) {
+	b := NewClientBuilder("localhost:9000", newTestClient).
+		Insecure().
+		WithStaticMetadata(map[string]string{"x-team": "platform"}).
+		WithStaticMetadata(map[string]string{"x-org-id": "org1"})
+
+	if got := b.staticMetadata.Get("x-team"); len(got) != 1 || got[0] != "platform" {
+		t.Errorf("Expected x-team platform, got %v", got)
+	}
+	if got := b.staticMetadata.Get("x-org-id"); len(got) != 1 || got[0] != "org1" {
+		t.Errorf("Expected x-org-id org1, got %v", got)
+	}
+}
+
+func TestWithDebugLogging(t *testing.T) {
+	b := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithDebugLogging(debuglog.New(nil))
+
+	unary, stream := b.interceptors()
+	if len(unary) != 2 {
+		t.Errorf("Expected 2 unary interceptors, got %d", len(unary))
+	}
+	if len(stream) != 2 {
+		t.Errorf("Expected 2 stream interceptors, got %d", len(stream))
+	}
+}
+
+func TestWithClientValidation(t *testing.T) {
+	unary, stream := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithClientValidation().interceptors()
+	if len(unary) != 2 {
+		t.Errorf("Expected 2 unary interceptors, got %d", len(unary))
+	}
+	if len(stream) != 2 {
+		t.Errorf("Expected 2 stream interceptors, got %d", len(stream))
+	}
+}
+
+func TestWithSchemaValidation(t *testing.T) {
+	unary, stream := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithSchemaValidation(validation.NewSchemaRegistry()).interceptors()
+	if len(unary) != 2 {
+		t.Errorf("Expected 2 unary interceptors, got %d", len(unary))
+	}
+	if len(stream) != 1 {
+		t.Errorf("Expected 1 stream interceptor, got %d", len(stream))
+	}
+
+	_, _, err := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithSchemaValidation(nil).Build()
+	if err == nil || !strings.Contains(err.Error(), "WithSchemaValidation: registry must not be nil") {
+		t.Errorf("Expected nil registry error, got %v", err)
+	}
+}
+
+func TestWithCostCenter(t *testing.T) {
+	tests := []struct {
+		name          string
+		tag           string
+		expectedError bool
+	}{
+		{name: "team label", tag: "inventory-team"},
+		{name: "org prefixed", tag: "rhel/inventory-team"},
+		{name: "empty", tag: "", expectedError: true},
+		{name: "uppercase", tag: "Inventory", expectedError: true},
+		{name: "trailing hyphen", tag: "team-", expectedError: true},
+		{name: "spaces", tag: "my team", expectedError: true},
+		{name: "nested prefix", tag: "a/b/c", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithCostCenter(tt.tag)
+			err := b.Validate()
+			if tt.expectedError {
+				if err == nil {
+					t.Errorf("Expected error for tag %q", tt.tag)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := b.staticMetadata.Get(costCenterMetadata); len(got) != 1 || got[0] != tt.tag {
+				t.Errorf("Expected cost center metadata %q, got %v", tt.tag, got)
+			}
+		})
+	}
+}
+
+func TestWithCostCenter_replacesTag(t *testing.T) {
+	b := NewClientBuilder("localhost:9000", newTestClient).
+		WithStaticMetadata(map[string]string{"x-team": "a"}).
+		WithCostCenter("first").
+		WithCostCenter("second")
+
+	if got := b.staticMetadata.Get(costCenterMetadata); len(got) != 1 || got[0] != "second" {
+		t.Errorf("Expected only the last tag, got %v", got)
+	}
+	if got := b.staticMetadata.Get("x-team"); len(got) != 1 {
+		t.Errorf("Expected other static metadata to be kept, got %v", got)
+	}
+}
+
+// countingCompressor is gzip under another name that counts compressed
+// messages, so tests can observe that calls were compressed.
+type countingCompressor struct {
+	encoding.Compressor
+	compressed atomic.Int32
+}
+
+func (c *countingCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	c.compressed.Add(1)
+	return c.Compressor.Compress(w)
+}
+
+func (c *countingCompressor) Name() string {
+	return "counting-gzip"
+}
+
+func TestWithCompression(t *testing.T) {
+	compressor := &countingCompressor{Compressor: encoding.GetCompressor("gzip")}
+	encoding.RegisterCompressor(compressor)
+
+	var calls atomic.Int32
+	addr := startCountingServer(t, &calls)
+
+	client, conn, err := NewClientBuilder(addr, newTestClient).Insecure().WithCompression("counting-gzip").Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var response healthpb.HealthCheckResponse
+	if err := client.conn.Invoke(ctx, healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &response, grpc.WaitForReady(true)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if compressor.compressed.Load() == 0 {
+		t.Error("Expected the request to be compressed")
+	}
+}
+
+func TestWithCompression_unknownCompressor(t *testing.T) {
+	_, _, err := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithCompression("brotli").Build()
+	if err == nil || !strings.Contains(err.Error(), `unknown compressor "brotli"`) {
+		t.Errorf("Expected unknown compressor error, got %v", err)
+	}
+}
+
+func TestWithServiceConfig_retries(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	var calls atomic.Int32
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if calls.Add(1) == 1 {
+			return nil, status.Error(codes.Unavailable, "warming up")
+		}
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	serviceConfig, err := kesselgrpc.RetryServiceConfig(kesselgrpc.DefaultRetryPolicy, healthpb.Health_Check_FullMethodName)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client, conn, err := NewClientBuilder(listener.Addr().String(), newTestClient).Insecure().WithServiceConfig(serviceConfig).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var response healthpb.HealthCheckResponse
+	if err := client.conn.Invoke(ctx, healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &response, grpc.WaitForReady(true)); err != nil {
+		t.Fatalf("Expected the call to be retried, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestWithServiceConfig_invalid(t *testing.T) {
+	_, _, err := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithServiceConfig(`{"methodConfig":`).Build()
+	if err == nil || !strings.Contains(err.Error(), "invalid service config") {
+		t.Errorf("Expected invalid service config error, got %v", err)
+	}
+}
+
+func TestWithInterceptors(t *testing.T) {
+	var order []string
+	unaryInterceptor := func(name string) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			order = append(order, name)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+	streamInterceptor := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	var calls atomic.Int32
+	addr := startCountingServer(t, &calls)
+
+	b := NewClientBuilder(addr, newTestClient).
+		Insecure().
+		WithUnaryInterceptor(unaryInterceptor("first")).
+		WithUnaryInterceptor(unaryInterceptor("second")).
+		WithStreamInterceptor(streamInterceptor)
+
+	unary, stream := b.interceptors()
+	if len(unary) != 3 || len(stream) != 2 {
+		t.Errorf("Expected 3 unary and 2 stream interceptors, got %d and %d", len(unary), len(stream))
+	}
+
+	client, conn, err := b.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var response healthpb.HealthCheckResponse
+	if err := client.conn.Invoke(ctx, healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &response, grpc.WaitForReady(true)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("Expected interceptors to run in order, got %v", order)
+	}
+}
+
+// payloadCounter is a stats.Handler that counts outgoing messages.
+type payloadCounter struct {
+	payloads atomic.Int32
+}
+
+func (p *payloadCounter) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (p *payloadCounter) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if _, ok := s.(*stats.OutPayload); ok {
+		p.payloads.Add(1)
+	}
+}
+
+func (p *payloadCounter) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (p *payloadCounter) HandleConn(ctx context.Context, s stats.ConnStats) {}
+
+func TestWithStatsHandler(t *testing.T) {
+	var calls atomic.Int32
+	addr := startCountingServer(t, &calls)
+	counter := &payloadCounter{}
+
+	client, conn, err := NewClientBuilder(addr, newTestClient).Insecure().WithStatsHandler(counter).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var response healthpb.HealthCheckResponse
+	if err := client.conn.Invoke(ctx, healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &response, grpc.WaitForReady(true)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counter.payloads.Load() != 1 {
+		t.Error("Expected the stats handler to observe the request payload")
+	}
+}
+
+func TestWithStatsHandler_invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *ClientBuilder[*testClient]
+		expected string
+	}{
+		{
+			name:     "nil handler",
+			builder:  NewClientBuilder("localhost:9000", newTestClient).WithStatsHandler(nil),
+			expected: "WithStatsHandler: stats handler must not be nil",
+		},
+		{
+			name:     "grpc-web",
+			builder:  NewClientBuilder("localhost:9000", newTestClient).WithStatsHandler(&payloadCounter{}).WithGrpcWeb(nil),
+			expected: "WithGrpcWeb: cannot be combined with WithStatsHandler",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.builder.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestBuild_sendsUserAgent(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	userAgents := make(chan []string, 1)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		userAgents <- md.Get("user-agent")
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	client, conn, err := NewClientBuilder(listener.Addr().String(), newTestClient).Insecure().Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+	if err := client.conn.Invoke(context.Background(), healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	received := <-userAgents
+	if len(received) != 1 || !strings.HasPrefix(received[0], version.UserAgent()+" grpc-go/") {
+		t.Errorf("Expected user agent starting with %q, got %v", version.UserAgent(), received)
+	}
+}
@@ -0,0 +1,116 @@
+package builder
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth/grpccreds"
+)
+
+type configSnapshot struct {
+	Target                 string          `json:"target"`
+	Targets                []string        `json:"targets,omitempty"`
+	RoundRobin             bool            `json:"round_robin"`
+	ServiceConfig          json.RawMessage `json:"service_config,omitempty"`
+	Resolvers              []string        `json:"resolvers,omitempty"`
+	Transport              string          `json:"transport"`
+	Authority              string          `json:"authority,omitempty"`
+	TLSServerName          string          `json:"tls_server_name,omitempty"`
+	GrpcWeb                bool            `json:"grpc_web"`
+	Authentication         string          `json:"authentication"`
+	AllowInsecureAuth      bool            `json:"allow_insecure_auth"`
+	OAuth2                 json.RawMessage `json:"oauth2,omitempty"`
+	UnauthenticatedMethods []string        `json:"unauthenticated_methods,omitempty"`
+	ReadOnly               bool            `json:"read_only"`
+	RequestIds             bool            `json:"request_ids"`
+	ClientValidation       bool            `json:"client_validation"`
+	SchemaValidation       bool            `json:"schema_validation"`
+	CircuitBreaker         bool            `json:"circuit_breaker"`
+	RateLimit              bool            `json:"rate_limit"`
+	TenantRateLimit        bool            `json:"tenant_rate_limit"`
+	RateLimitRetries       *int            `json:"rate_limit_retries,omitempty"`
+	StaticMetadataKeys     []string        `json:"static_metadata_keys,omitempty"`
+	Idempotency            string          `json:"idempotency_ttl,omitempty"`
+	Hedging                string          `json:"hedging_delay,omitempty"`
+	DebugLogging           bool            `json:"debug_logging"`
+	Compression            string          `json:"compression,omitempty"`
+	MaxSendMessageSize     int             `json:"max_send_message_size,omitempty"`
+	MaxConcurrentStreams   int             `json:"max_concurrent_streams,omitempty"`
+	MaxConnections         int             `json:"max_connections,omitempty"`
+	UnaryInterceptors      int             `json:"unary_interceptors,omitempty"`
+	StreamInterceptors     int             `json:"stream_interceptors,omitempty"`
+	StatsHandlers          int             `json:"stats_handlers,omitempty"`
+}
+
+// ConfigSnapshot returns the effective configuration of the builder as JSON,
+// for startup logs and support bundles. The OAuth2 client secret is redacted
+// and only the keys of static metadata are included, since their values may
+// carry credentials.
+func (b *ClientBuilder[C]) ConfigSnapshot() ([]byte, error) {
+	snapshot := configSnapshot{
+		Target:             b.target,
+		Targets:            b.targets,
+		RoundRobin:         b.roundRobin,
+		Transport:          "tls",
+		Authority:          b.authority,
+		TLSServerName:      b.tlsServerName,
+		GrpcWeb:            b.grpcWeb != nil,
+		Authentication:     "none",
+		AllowInsecureAuth:  b.allowInsecureAuth,
+		ReadOnly:           b.readOnly,
+		RequestIds:         b.requestIds,
+		ClientValidation:   b.clientValidation,
+		SchemaValidation:   b.schemaRegistry != nil,
+		CircuitBreaker:     b.circuitBreaker != nil,
+		RateLimit:          b.rateLimiter != nil,
+		TenantRateLimit:    b.tenantLimiter != nil,
+		DebugLogging:       b.debugLogger != nil,
+		Compression:        b.compressor,
+		MaxSendMessageSize: b.maxSendSize,
+		UnaryInterceptors:  len(b.unaryInterceptors),
+		StreamInterceptors: len(b.streamInterceptors),
+		StatsHandlers:      len(b.statsHandlers),
+	}
+	if b.insecure {
+		snapshot.Transport = "insecure"
+	}
+	switch creds := b.perRPCCredentials.(type) {
+	case nil:
+		snapshot.UnauthenticatedMethods = slices.Sorted(maps.Keys(b.unauthenticated))
+	case *grpccreds.OAuth2:
+		snapshot.Authentication = "oauth2_client_credentials"
+		oauth2, err := creds.Credentials().Sanitized()
+		if err != nil {
+			return nil, err
+		}
+		snapshot.OAuth2 = oauth2
+	default:
+		snapshot.Authentication = "call_credentials"
+	}
+	snapshot.StaticMetadataKeys = slices.Sorted(maps.Keys(b.staticMetadata))
+	if b.idempotency != nil {
+		snapshot.Idempotency = b.idempotency.ttl.String()
+	}
+	if b.serviceConfig != nil {
+		serviceConfig, err := json.Marshal(b.serviceConfig)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.ServiceConfig = serviceConfig
+	}
+	for _, builder := range b.resolvers {
+		snapshot.Resolvers = append(snapshot.Resolvers, builder.Scheme())
+	}
+	if b.rateLimitRetry != nil {
+		snapshot.RateLimitRetries = &b.rateLimitRetry.maxRetries
+	}
+	if b.hedgingDelay > 0 {
+		snapshot.Hedging = b.hedgingDelay.String()
+	}
+	if b.maxStreams > 0 {
+		snapshot.MaxConcurrentStreams = b.maxStreams
+		snapshot.MaxConnections = b.maxConns
+	}
+	return json.Marshal(snapshot)
+}
@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+func TestConfigSnapshot(t *testing.T) {
+	creds := auth.NewOAuth2ClientCredentials("svc", "top-secret", "https://sso.example.com/token")
+
+	tests := []struct {
+		name     string
+		builder  *ClientBuilder[*testClient]
+		expected map[string]any
+	}{
+		{
+			name:    "oauth2 with tls",
+			builder: NewClientBuilder("kessel:9000", newTestClient).OAuth2ClientAuthenticated(&creds, nil).WithReadOnly(),
+			expected: map[string]any{
+				"target":         "kessel:9000",
+				"transport":      "tls",
+				"authentication": "oauth2_client_credentials",
+				"read_only":      true,
+			},
+		},
+		{
+			name: "insecure with options",
+			builder: NewClientBuilder("localhost:9000", newTestClient).Insecure().
+				WithUnauthenticatedMethods("/b", "/a").
+				WithStaticMetadata(map[string]string{"authorization": "Bearer abc"}).
+				WithIdempotency(time.Minute).
+				WithMaxConcurrentStreams(100, 2),
+			expected: map[string]any{
+				"transport":               "insecure",
+				"authentication":          "none",
+				"unauthenticated_methods": []any{"/a", "/b"},
+				"static_metadata_keys":    []any{"authorization"},
+				"idempotency_ttl":         "1m0s",
+				"max_concurrent_streams":  float64(100),
+				"max_connections":         float64(2),
+			},
+		},
+		{
+			name:    "custom resolver",
+			builder: NewClientBuilder("srv:///_grpc._tcp.kessel", newTestClient).WithResolver(manual.NewBuilderWithScheme("srv")),
+			expected: map[string]any{
+				"target":    "srv:///_grpc._tcp.kessel",
+				"resolvers": []any{"srv"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.builder.ConfigSnapshot()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Contains(string(data), "top-secret") || strings.Contains(string(data), "Bearer abc") {
+				t.Fatalf("Expected secrets to be redacted, got %s", data)
+			}
+
+			var snapshot map[string]any
+			if err := json.Unmarshal(data, &snapshot); err != nil {
+				t.Fatalf("Failed to decode snapshot: %v", err)
+			}
+			for key, expected := range tt.expected {
+				actual, _ := json.Marshal(snapshot[key])
+				want, _ := json.Marshal(expected)
+				if string(actual) != string(want) {
+					t.Errorf("Expected %s to be %s, got %s", key, want, actual)
+				}
+			}
+		})
+	}
+}
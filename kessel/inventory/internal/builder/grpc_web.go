@@ -0,0 +1,435 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/version"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	grpcWebContentType = "application/grpc-web+proto"
+	// grpcWebMaxMessageSize matches grpc-go's default receive limit.
+	grpcWebMaxMessageSize = 4 << 20
+	grpcWebTrailerFlag    = 0x80
+	grpcWebCompressedFlag = 0x01
+)
+
+// grpcWebConn sends unary and server-streaming calls as binary grpc-web
+// requests over HTTP/1.1. It runs the builder's interceptors itself, with a
+// nil *grpc.ClientConn, and applies the per-RPC credentials after them as the
+// gRPC transport would. Close fails later calls and closes the HTTP client's
+// idle connections.
+type grpcWebConn struct {
+	baseUrl    string
+	httpClient *http.Client
+	creds      credentials.PerRPCCredentials
+	unary      grpc.UnaryInvoker
+	stream     grpc.Streamer
+	closed     atomic.Bool
+}
+
+var (
+	_ grpc.ClientConnInterface = (*grpcWebConn)(nil)
+	_ io.Closer                = (*grpcWebConn)(nil)
+)
+
+func newGrpcWebConn(target string, insecure bool, httpClient *http.Client, creds credentials.PerRPCCredentials, unary []grpc.UnaryClientInterceptor, stream []grpc.StreamClientInterceptor) *grpcWebConn {
+	baseUrl := target
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		scheme := "https://"
+		if insecure {
+			scheme = "http://"
+		}
+		baseUrl = scheme + target
+	}
+	c := &grpcWebConn{
+		baseUrl:    strings.TrimRight(baseUrl, "/"),
+		httpClient: httpClient,
+		creds:      creds,
+	}
+	c.unary = chainUnaryInvoker(unary, c.invoke)
+	c.stream = chainStreamer(stream, c.newStream)
+	return c
+}
+
+func chainUnaryInvoker(interceptors []grpc.UnaryClientInterceptor, invoker grpc.UnaryInvoker) grpc.UnaryInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return interceptor(ctx, method, req, reply, cc, next, opts...)
+		}
+	}
+	return invoker
+}
+
+func chainStreamer(interceptors []grpc.StreamClientInterceptor, streamer grpc.Streamer) grpc.Streamer {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], streamer
+		streamer = func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return interceptor(ctx, desc, cc, method, next, opts...)
+		}
+	}
+	return streamer
+}
+
+func (c *grpcWebConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	return c.unary(ctx, method, args, reply, nil, opts...)
+}
+
+func (c *grpcWebConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.stream(ctx, desc, nil, method, opts...)
+}
+
+// Close makes later calls fail with Canceled, as on a closed
+// *grpc.ClientConn, and closes the HTTP client's idle connections. Calls in
+// flight are not interrupted.
+func (c *grpcWebConn) Close() error {
+	if c.closed.CompareAndSwap(false, true) {
+		c.httpClient.CloseIdleConnections()
+	}
+	return nil
+}
+
+func (c *grpcWebConn) invoke(ctx context.Context, method string, req, reply any, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+	response, err := c.post(ctx, method, req, opts)
+	if err != nil {
+		return err
+	}
+	defer response.close()
+	defer func() { setTrailerOptions(opts, response.trailer) }()
+
+	if err := response.recv(reply); err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.Internal, "grpc-web: response has no message")
+		}
+		return err
+	}
+	if err := response.recv(reply); !errors.Is(err, io.EOF) {
+		if err == nil {
+			return status.Error(codes.Internal, "grpc-web: unary response has more than one message")
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *grpcWebConn) newStream(ctx context.Context, desc *grpc.StreamDesc, _ *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if c.closed.Load() {
+		return nil, errGrpcWebClosed
+	}
+	if desc.ClientStreams {
+		return nil, status.Errorf(codes.Unimplemented, "grpc-web: client streaming is not supported for %s", method)
+	}
+	return &grpcWebStream{ctx: ctx, conn: c, method: method, opts: opts}, nil
+}
+
+var errGrpcWebClosed = status.Error(codes.Canceled, "grpc-web: the client connection is closing")
+
+// post sends req and returns the response once its headers arrive. A
+// response whose headers carry the status (trailers-only) is returned with
+// those headers as its trailer, so the status error comes from recv. The
+// response headers are stored in any grpc.Header call option, and, when the
+// call fails before a response, also in any grpc.Trailer call option so
+// interceptors can read headers such as Retry-After.
+func (c *grpcWebConn) post(ctx context.Context, method string, req any, opts []grpc.CallOption) (*grpcWebResponse, error) {
+	if c.closed.Load() {
+		return nil, errGrpcWebClosed
+	}
+	message, ok := req.(proto.Message)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "grpc-web: cannot marshal %T", req)
+	}
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "grpc-web: failed to marshal request: %v", err)
+	}
+	body := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(payload)))
+	body = append(body, payload...)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseUrl+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "grpc-web: %v", err)
+	}
+	request.Header.Set("Content-Type", grpcWebContentType)
+	request.Header.Set("Accept", grpcWebContentType)
+	request.Header.Set("X-Grpc-Web", "1")
+	request.Header.Set("User-Agent", version.UserAgent())
+	if deadline, ok := ctx.Deadline(); ok {
+		request.Header.Set("Grpc-Timeout", strconv.FormatInt(max(time.Until(deadline).Milliseconds(), 1), 10)+"m")
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			if strings.HasSuffix(key, "-bin") {
+				value = base64.StdEncoding.EncodeToString([]byte(value))
+			}
+			request.Header.Add(key, value)
+		}
+	}
+	if err := c.applyCredentials(ctx, request); err != nil {
+		return nil, err
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		return nil, status.Errorf(codes.Unavailable, "grpc-web: %v", err)
+	}
+	setHeaderOptions(opts, response.Header)
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		setTrailerOptions(opts, response.Header)
+		return nil, status.Errorf(httpStatusCode(response.StatusCode), "grpc-web: unexpected HTTP status %s", response.Status)
+	}
+	if response.Header.Get("Grpc-Status") != "" {
+		_ = response.Body.Close()
+		return &grpcWebResponse{header: response.Header, body: http.NoBody, trailer: response.Header}, nil
+	}
+	if !strings.HasPrefix(response.Header.Get("Content-Type"), "application/grpc-web") {
+		_ = response.Body.Close()
+		setTrailerOptions(opts, response.Header)
+		return nil, status.Errorf(codes.Unknown, "grpc-web: unexpected content type %q", response.Header.Get("Content-Type"))
+	}
+	return &grpcWebResponse{header: response.Header, body: response.Body}, nil
+}
+
+// setHeaderOptions stores header in the grpc.Header call options of a call.
+func setHeaderOptions(opts []grpc.CallOption, header http.Header) {
+	for _, opt := range opts {
+		if headerOpt, ok := opt.(grpc.HeaderCallOption); ok {
+			*headerOpt.HeaderAddr = headerMetadata(header)
+		}
+	}
+}
+
+// setTrailerOptions stores trailer in the grpc.Trailer call options of a
+// call. It does nothing before the trailer has been received.
+func setTrailerOptions(opts []grpc.CallOption, trailer http.Header) {
+	if trailer == nil {
+		return
+	}
+	for _, opt := range opts {
+		if trailerOpt, ok := opt.(grpc.TrailerCallOption); ok {
+			*trailerOpt.TrailerAddr = headerMetadata(trailer)
+		}
+	}
+}
+
+func (c *grpcWebConn) applyCredentials(ctx context.Context, request *http.Request) error {
+	if c.creds == nil {
+		return nil
+	}
+	if c.creds.RequireTransportSecurity() && request.URL.Scheme != "https" {
+		return status.Error(codes.Unauthenticated, "grpc-web: cannot send secure credentials over http")
+	}
+	md, err := c.creds.GetRequestMetadata(ctx, c.baseUrl)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.Unauthenticated, "grpc-web: per-RPC creds failed: %v", err)
+	}
+	for key, value := range md {
+		request.Header.Set(key, value)
+	}
+	return nil
+}
+
+// httpStatusCode maps a non-200 HTTP status to a gRPC code as described in
+// the gRPC HTTP/2 protocol spec.
+func httpStatusCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.Internal
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
+
+// headerStatus returns the status carried by grpc-status and grpc-message, or
+// nil when it is OK.
+func headerStatus(header http.Header) error {
+	code, err := strconv.Atoi(header.Get("Grpc-Status"))
+	if err != nil {
+		return status.Errorf(codes.Internal, "grpc-web: invalid grpc-status %q", header.Get("Grpc-Status"))
+	}
+	if code == int(codes.OK) {
+		return nil
+	}
+	message, err := url.PathUnescape(header.Get("Grpc-Message"))
+	if err != nil {
+		message = header.Get("Grpc-Message")
+	}
+	return status.Error(codes.Code(code), message)
+}
+
+// grpcWebResponse reads the frames of a grpc-web response body.
+type grpcWebResponse struct {
+	header  http.Header
+	body    io.ReadCloser
+	trailer http.Header
+}
+
+// recv reads the next message into m. After the trailer frame it returns
+// io.EOF if the call succeeded and the status error otherwise.
+func (r *grpcWebResponse) recv(m any) error {
+	if r.trailer != nil {
+		if err := headerStatus(r.trailer); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+
+	var prefix [5]byte
+	if _, err := io.ReadFull(r.body, prefix[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.Internal, "grpc-web: response ended without trailers")
+		}
+		return readError(err)
+	}
+	length := binary.BigEndian.Uint32(prefix[1:])
+	if length > grpcWebMaxMessageSize {
+		return status.Errorf(codes.ResourceExhausted, "grpc-web: received message larger than max (%d vs. %d)", length, grpcWebMaxMessageSize)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r.body, payload); err != nil {
+		return readError(err)
+	}
+
+	if prefix[0]&grpcWebTrailerFlag != 0 {
+		r.trailer = parseTrailer(payload)
+		return r.recv(m)
+	}
+	if prefix[0]&grpcWebCompressedFlag != 0 {
+		return status.Error(codes.Internal, "grpc-web: compressed responses are not supported")
+	}
+	message, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "grpc-web: cannot unmarshal into %T", m)
+	}
+	if err := proto.Unmarshal(payload, message); err != nil {
+		return status.Errorf(codes.Internal, "grpc-web: failed to unmarshal response: %v", err)
+	}
+	return nil
+}
+
+func (r *grpcWebResponse) close() {
+	_ = r.body.Close()
+}
+
+func readError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Errorf(codes.Unavailable, "grpc-web: %v", err)
+}
+
+// parseTrailer parses the "key: value\r\n" lines of a trailer frame.
+func parseTrailer(payload []byte) http.Header {
+	trailer := http.Header{}
+	for line := range strings.SplitSeq(string(payload), "\r\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok {
+			trailer.Add(strings.TrimSpace(key), strings.TrimSpace(value))
+		}
+	}
+	return trailer
+}
+
+// grpcWebStream is a server-streaming call. The request is sent when the
+// client closes its side of the stream.
+type grpcWebStream struct {
+	ctx      context.Context
+	conn     *grpcWebConn
+	method   string
+	opts     []grpc.CallOption
+	request  any
+	response *grpcWebResponse
+}
+
+func (s *grpcWebStream) Context() context.Context { return s.ctx }
+
+func (s *grpcWebStream) Header() (metadata.MD, error) {
+	if s.response == nil {
+		return nil, nil
+	}
+	return headerMetadata(s.response.header), nil
+}
+
+func (s *grpcWebStream) Trailer() metadata.MD {
+	if s.response == nil || s.response.trailer == nil {
+		return nil
+	}
+	return headerMetadata(s.response.trailer)
+}
+
+func (s *grpcWebStream) SendMsg(m any) error {
+	if s.request != nil || s.response != nil {
+		return status.Error(codes.Unimplemented, "grpc-web: only one request message can be sent")
+	}
+	s.request = m
+	return nil
+}
+
+func (s *grpcWebStream) CloseSend() error {
+	if s.response != nil {
+		return nil
+	}
+	if s.request == nil {
+		return status.Error(codes.Internal, "grpc-web: stream closed before a request was sent")
+	}
+	response, err := s.conn.post(s.ctx, s.method, s.request, s.opts)
+	if err != nil {
+		return err
+	}
+	s.response = response
+	return nil
+}
+
+func (s *grpcWebStream) RecvMsg(m any) error {
+	if err := s.CloseSend(); err != nil {
+		return err
+	}
+	err := s.response.recv(m)
+	if err != nil {
+		s.response.close()
+		setTrailerOptions(s.opts, s.response.trailer)
+	}
+	return err
+}
+
+func headerMetadata(header http.Header) metadata.MD {
+	md := metadata.MD{}
+	for key, values := range header {
+		md.Append(strings.ToLower(key), values...)
+	}
+	return md
+}
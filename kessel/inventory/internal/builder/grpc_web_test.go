@@ -0,0 +1,402 @@
+package builder
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"github.com/project-kessel/kessel-sdk-go/kessel/version"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func grpcWebFrame(t *testing.T, flag byte, payload []byte) []byte {
+	t.Helper()
+	frame := make([]byte, 5, 5+len(payload))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+// startGrpcWebServer answers Health/Check with the status of the requested
+// service name, and Health/Watch with one message per status followed by the
+// trailer. Requests are passed to inspect before they are answered.
+func startGrpcWebServer(t *testing.T, trailer string, inspect func(*http.Request)) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if inspect != nil {
+			inspect(r)
+		}
+		body, _ := io.ReadAll(r.Body)
+		request := &healthpb.HealthCheckRequest{}
+		if len(body) < 5 || proto.Unmarshal(body[5:], request) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/grpc-web+proto")
+		var statuses []healthpb.HealthCheckResponse_ServingStatus
+		switch r.URL.Path {
+		case healthpb.Health_Check_FullMethodName:
+			statuses = []healthpb.HealthCheckResponse_ServingStatus{healthpb.HealthCheckResponse_SERVING}
+		case healthpb.Health_Watch_FullMethodName:
+			statuses = []healthpb.HealthCheckResponse_ServingStatus{healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_NOT_SERVING}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		for _, servingStatus := range statuses {
+			payload, _ := proto.Marshal(&healthpb.HealthCheckResponse{Status: servingStatus})
+			_, _ = w.Write(grpcWebFrame(t, 0, payload))
+		}
+		_, _ = w.Write(grpcWebFrame(t, grpcWebTrailerFlag, []byte(trailer)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithGrpcWeb_unary(t *testing.T) {
+	var header http.Header
+	server := startGrpcWebServer(t, "grpc-status: 0\r\n", func(r *http.Request) { header = r.Header })
+
+	var intercepted bool
+	client, conn, err := NewClientBuilder(server.URL, newTestClient).
+		Insecure().
+		WithGrpcWeb(nil).
+		WithStaticMetadata(map[string]string{"x-team": "inventory"}).
+		WithUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			intercepted = true
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}).
+		BuildGrpcWeb()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	response := &healthpb.HealthCheckResponse{}
+	if err := client.conn.Invoke(context.Background(), healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, response); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING, got %v", response.GetStatus())
+	}
+	if !intercepted {
+		t.Error("Expected caller interceptor to run")
+	}
+	if header.Get("Content-Type") != "application/grpc-web+proto" || header.Get("X-Grpc-Web") != "1" {
+		t.Errorf("Expected grpc-web headers, got %v", header)
+	}
+	if header.Get("X-Team") != "inventory" {
+		t.Errorf("Expected static metadata as a header, got %v", header)
+	}
+}
+
+func TestWithGrpcWeb_serverStreaming(t *testing.T) {
+	server := startGrpcWebServer(t, "grpc-status: 0\r\n", nil)
+	client, _, err := NewClientBuilder(server.URL, newTestClient).Insecure().WithGrpcWeb(server.Client()).BuildGrpcWeb()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stream, err := client.conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, healthpb.Health_Watch_FullMethodName)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := stream.SendMsg(&healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var received []healthpb.HealthCheckResponse_ServingStatus
+	for {
+		response := &healthpb.HealthCheckResponse{}
+		err := stream.RecvMsg(response)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		received = append(received, response.GetStatus())
+	}
+	if len(received) != 2 {
+		t.Errorf("Expected 2 messages, got %v", received)
+	}
+	if got := stream.Trailer().Get("grpc-status"); len(got) != 1 || got[0] != "0" {
+		t.Errorf("Expected grpc-status trailer, got %v", stream.Trailer())
+	}
+}
+
+func TestWithGrpcWeb_errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		expectedCode codes.Code
+		expectedMsg  string
+	}{
+		{
+			name: "status in trailer",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/grpc-web+proto")
+				_, _ = w.Write(grpcWebFrame(t, grpcWebTrailerFlag, []byte("grpc-status: 7\r\ngrpc-message: no%20access\r\n")))
+			},
+			expectedCode: codes.PermissionDenied,
+			expectedMsg:  "no access",
+		},
+		{
+			name: "trailers-only response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/grpc-web+proto")
+				w.Header().Set("Grpc-Status", "5")
+				w.Header().Set("Grpc-Message", "missing")
+			},
+			expectedCode: codes.NotFound,
+			expectedMsg:  "missing",
+		},
+		{
+			name:         "HTTP status",
+			handler:      func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) },
+			expectedCode: codes.Unavailable,
+		},
+		{
+			name: "missing trailer",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/grpc-web+proto")
+			},
+			expectedCode: codes.Internal,
+		},
+		{
+			name: "not grpc-web",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+			},
+			expectedCode: codes.Unknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client, _, err := NewClientBuilder(server.URL, newTestClient).WithGrpcWeb(server.Client()).BuildGrpcWeb()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			err = client.conn.Invoke(context.Background(), healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+			if status.Code(err) != tt.expectedCode {
+				t.Fatalf("Expected code %v, got %v", tt.expectedCode, err)
+			}
+			if tt.expectedMsg != "" && status.Convert(err).Message() != tt.expectedMsg {
+				t.Errorf("Expected message %q, got %q", tt.expectedMsg, status.Convert(err).Message())
+			}
+		})
+	}
+}
+
+type staticCreds struct {
+	secure bool
+}
+
+func (s staticCreds) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer token"}, nil
+}
+
+func (s staticCreds) RequireTransportSecurity() bool {
+	return s.secure
+}
+
+func TestWithGrpcWeb_callCredentials(t *testing.T) {
+	var authorization, userAgent string
+	server := startGrpcWebServer(t, "grpc-status: 0\r\n", func(r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		userAgent = r.Header.Get("User-Agent")
+	})
+
+	client, _, err := NewClientBuilder(server.URL, newTestClient).Authenticated(staticCreds{}, nil).WithGrpcWeb(server.Client()).BuildGrpcWeb()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request", "1")
+	if err := client.conn.Invoke(ctx, healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if authorization != "Bearer token" {
+		t.Errorf("Expected authorization header, got %q", authorization)
+	}
+	if userAgent != version.UserAgent() {
+		t.Errorf("Expected user agent %q, got %q", version.UserAgent(), userAgent)
+	}
+
+	client, _, err = NewClientBuilder(server.URL, newTestClient).Authenticated(staticCreds{secure: true}, nil).WithGrpcWeb(server.Client()).BuildGrpcWeb()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = client.conn.Invoke(context.Background(), healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated for secure credentials over http, got %v", err)
+	}
+}
+
+func TestWithGrpcWeb_clientStreamingUnsupported(t *testing.T) {
+	client, _, err := NewClientBuilder("localhost:8000", newTestClient).WithGrpcWeb(nil).BuildGrpcWeb()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err = client.conn.NewStream(context.Background(), &grpc.StreamDesc{ClientStreams: true}, "/svc/Upload")
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented, got %v", err)
+	}
+}
+
+func TestWithGrpcWeb_incompatibleOptions(t *testing.T) {
+	_, _, err := NewClientBuilder("localhost:8000", newTestClient).
+		WithGrpcWeb(nil).
+		WithRoundRobin().
+		WithMaxConcurrentStreams(10, 2).
+		BuildGrpcWeb()
+
+	var multi *kesselerrors.Multi
+	if !errors.As(err, &multi) || multi.Len() != 2 {
+		t.Fatalf("Expected 2 validation errors, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "WithRoundRobin") || !strings.Contains(err.Error(), "WithMaxConcurrentStreams") {
+		t.Errorf("Expected both conflicts to be reported, got %v", err)
+	}
+}
+
+func TestNewGrpcWebConn_baseUrl(t *testing.T) {
+	tests := []struct {
+		target   string
+		insecure bool
+		expected string
+	}{
+		{target: "kessel:8000", expected: "https://kessel:8000"},
+		{target: "kessel:8000", insecure: true, expected: "http://kessel:8000"},
+		{target: "https://kessel.example.com/grpc/", expected: "https://kessel.example.com/grpc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			conn := newGrpcWebConn(tt.target, tt.insecure, http.DefaultClient, nil, nil, nil)
+			if conn.baseUrl != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, conn.baseUrl)
+			}
+		})
+	}
+}
+
+func TestBuildGrpcWeb_close(t *testing.T) {
+	server := startGrpcWebServer(t, "grpc-status: 0\r\n", nil)
+	client, closer, err := NewClientBuilder(server.URL, newTestClient).Insecure().WithGrpcWeb(server.Client()).BuildGrpcWeb()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := client.conn.Invoke(context.Background(), healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := closer.Close(); err != nil {
+		t.Errorf("Expected a second Close to succeed, got %v", err)
+	}
+	err = client.conn.Invoke(context.Background(), healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("Expected Canceled after Close, got %v", err)
+	}
+	_, err = client.conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, healthpb.Health_Watch_FullMethodName)
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("Expected Canceled after Close, got %v", err)
+	}
+}
+
+func TestBuild_rejectsGrpcWeb(t *testing.T) {
+	_, conn, err := NewClientBuilder("localhost:8000", newTestClient).WithGrpcWeb(nil).Build()
+	if err == nil || !strings.Contains(err.Error(), "use BuildGrpcWeb") {
+		t.Errorf("Expected Build to point to BuildGrpcWeb, got %v", err)
+	}
+	if conn != nil {
+		t.Errorf("Expected no connection, got %v", conn)
+	}
+
+	_, _, err = NewClientBuilder("localhost:8000", newTestClient).Insecure().BuildGrpcWeb()
+	if err == nil || !strings.Contains(err.Error(), "requires WithGrpcWeb") {
+		t.Errorf("Expected BuildGrpcWeb to require WithGrpcWeb, got %v", err)
+	}
+}
+
+func TestWithGrpcWeb_headerAndTrailerCallOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc-web+proto")
+		w.Header().Set("X-Served-By", "gateway")
+		payload, _ := proto.Marshal(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+		_, _ = w.Write(grpcWebFrame(t, 0, payload))
+		_, _ = w.Write(grpcWebFrame(t, grpcWebTrailerFlag, []byte("grpc-status: 0\r\nx-cost: 3\r\n")))
+	}))
+	defer server.Close()
+
+	client, closer, err := NewClientBuilder(server.URL, newTestClient).WithGrpcWeb(server.Client()).BuildGrpcWeb()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer closer.Close()
+
+	var header, trailer metadata.MD
+	err = client.conn.Invoke(context.Background(), healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{}, grpc.Header(&header), grpc.Trailer(&trailer))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := header.Get("x-served-by"); len(got) != 1 || got[0] != "gateway" {
+		t.Errorf("Expected the response header, got %v", header)
+	}
+	if got := trailer.Get("x-cost"); len(got) != 1 || got[0] != "3" {
+		t.Errorf("Expected the response trailer, got %v", trailer)
+	}
+}
+
+func TestWithGrpcWeb_rateLimitRetry(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/grpc-web+proto")
+		if calls == 1 {
+			w.Header().Set("Grpc-Status", "8")
+			w.Header().Set("Grpc-Message", "slow down")
+			w.Header().Set("Grpc-Retry-Pushback-Ms", "1")
+			return
+		}
+		payload, _ := proto.Marshal(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+		_, _ = w.Write(grpcWebFrame(t, 0, payload))
+		_, _ = w.Write(grpcWebFrame(t, grpcWebTrailerFlag, []byte("grpc-status: 0\r\n")))
+	}))
+	defer server.Close()
+
+	client, closer, err := NewClientBuilder(server.URL, newTestClient).WithGrpcWeb(server.Client()).WithRateLimitRetry(1, time.Second).BuildGrpcWeb()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer closer.Close()
+
+	if err := client.conn.Invoke(context.Background(), healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the throttled call to be retried once, got %d calls", calls)
+	}
+}
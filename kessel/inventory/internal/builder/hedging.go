@@ -0,0 +1,74 @@
+package builder
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// hedgedMethods are the read-only checks that may be sent twice. They are
+// spelled out rather than taken from the v1beta2 constants because v1beta2
+// imports this package.
+var hedgedMethods = map[string]bool{
+	"/kessel.inventory.v1beta2.KesselInventoryService/Check":          true,
+	"/kessel.inventory.v1beta2.KesselInventoryService/CheckSelf":      true,
+	"/kessel.inventory.v1beta2.KesselInventoryService/CheckForUpdate": true,
+}
+
+type hedgedAttempt struct {
+	reply proto.Message
+	err   error
+}
+
+// hedgingUnaryInterceptor sends a second attempt of a hedged call that has
+// not completed after delay and returns the first successful reply, canceling
+// the other attempt. A call that fails before delay is not hedged.
+func hedgingUnaryInterceptor(delay time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		message, ok := reply.(proto.Message)
+		if !hedgedMethods[method] || !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		attempts := make(chan hedgedAttempt, 2)
+		send := func() {
+			attemptReply := message.ProtoReflect().New().Interface()
+			go func() {
+				err := invoker(ctx, method, req, attemptReply, cc, opts...)
+				attempts <- hedgedAttempt{reply: attemptReply, err: err}
+			}()
+		}
+		send()
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		pending, hedged := 1, false
+		var firstErr error
+		for {
+			select {
+			case <-timer.C:
+				hedged = true
+				pending++
+				send()
+			case attempt := <-attempts:
+				pending--
+				if attempt.err == nil {
+					proto.Merge(message, attempt.reply)
+					return nil
+				}
+				if firstErr == nil {
+					firstErr = attempt.err
+				}
+				if !hedged || pending == 0 {
+					return firstErr
+				}
+			}
+		}
+	}
+}
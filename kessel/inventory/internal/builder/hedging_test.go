@@ -0,0 +1,145 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const hedgedCheckMethod = "/kessel.inventory.v1beta2.KesselInventoryService/Check"
+
+// attemptInvoker runs the behavior for each attempt in turn, counting them.
+func attemptInvoker(attempts *atomic.Int32, behaviors ...func(ctx context.Context, reply *healthpb.HealthCheckResponse) error) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		n := attempts.Add(1)
+		return behaviors[n-1](ctx, reply.(*healthpb.HealthCheckResponse))
+	}
+}
+
+func hang(ctx context.Context, reply *healthpb.HealthCheckResponse) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func serve(status healthpb.HealthCheckResponse_ServingStatus) func(context.Context, *healthpb.HealthCheckResponse) error {
+	return func(ctx context.Context, reply *healthpb.HealthCheckResponse) error {
+		reply.Status = status
+		return nil
+	}
+}
+
+func fail(err error) func(context.Context, *healthpb.HealthCheckResponse) error {
+	return func(ctx context.Context, reply *healthpb.HealthCheckResponse) error {
+		return err
+	}
+}
+
+func TestHedgingUnaryInterceptor(t *testing.T) {
+	attemptErr := errors.New("attempt failed")
+
+	tests := []struct {
+		name             string
+		method           string
+		behaviors        []func(context.Context, *healthpb.HealthCheckResponse) error
+		expectedAttempts int32
+		expectedErr      error
+		expectedStatus   healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{
+			name:             "fast call is not hedged",
+			method:           hedgedCheckMethod,
+			behaviors:        []func(context.Context, *healthpb.HealthCheckResponse) error{serve(healthpb.HealthCheckResponse_SERVING)},
+			expectedAttempts: 1,
+			expectedStatus:   healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name:             "slow call is hedged",
+			method:           hedgedCheckMethod,
+			behaviors:        []func(context.Context, *healthpb.HealthCheckResponse) error{hang, serve(healthpb.HealthCheckResponse_NOT_SERVING)},
+			expectedAttempts: 2,
+			expectedStatus:   healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:             "fast failure is not hedged",
+			method:           hedgedCheckMethod,
+			behaviors:        []func(context.Context, *healthpb.HealthCheckResponse) error{fail(attemptErr)},
+			expectedAttempts: 1,
+			expectedErr:      attemptErr,
+		},
+		{
+			name:   "hedge failure waits for the first attempt",
+			method: hedgedCheckMethod,
+			behaviors: []func(context.Context, *healthpb.HealthCheckResponse) error{
+				func(ctx context.Context, reply *healthpb.HealthCheckResponse) error {
+					time.Sleep(50 * time.Millisecond)
+					reply.Status = healthpb.HealthCheckResponse_SERVING
+					return nil
+				},
+				fail(attemptErr),
+			},
+			expectedAttempts: 2,
+			expectedStatus:   healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name:             "mutating call is not hedged",
+			method:           "/kessel.inventory.v1beta2.KesselInventoryService/ReportResource",
+			behaviors:        []func(context.Context, *healthpb.HealthCheckResponse) error{fail(attemptErr)},
+			expectedAttempts: 1,
+			expectedErr:      attemptErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			var reply healthpb.HealthCheckResponse
+
+			err := hedgingUnaryInterceptor(10*time.Millisecond)(context.Background(), tt.method, &healthpb.HealthCheckRequest{}, &reply, nil, attemptInvoker(&attempts, tt.behaviors...))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if reply.Status != tt.expectedStatus {
+				t.Errorf("Expected status %s, got %s", tt.expectedStatus, reply.Status)
+			}
+			if got := attempts.Load(); got != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, got)
+			}
+		})
+	}
+}
+
+func TestHedgingUnaryInterceptor_cancelsLosingAttempt(t *testing.T) {
+	canceled := make(chan struct{})
+	var attempts atomic.Int32
+	invoker := attemptInvoker(&attempts,
+		func(ctx context.Context, reply *healthpb.HealthCheckResponse) error {
+			<-ctx.Done()
+			close(canceled)
+			return ctx.Err()
+		},
+		serve(healthpb.HealthCheckResponse_SERVING),
+	)
+
+	err := hedgingUnaryInterceptor(time.Millisecond)(context.Background(), hedgedCheckMethod, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{}, nil, invoker)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("Expected the losing attempt to be canceled")
+	}
+}
+
+func TestWithHedging_invalidDelay(t *testing.T) {
+	_, _, err := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithHedging(0).Build()
+	if err == nil || !strings.Contains(err.Error(), "delay must be positive") {
+		t.Errorf("Expected invalid delay error, got %v", err)
+	}
+}
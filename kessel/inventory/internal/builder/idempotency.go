@@ -0,0 +1,114 @@
+package builder
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// idempotencyKeyMetadata is the metadata key the idempotency key is sent under.
+const idempotencyKeyMetadata = "idempotency-key"
+
+// idempotentMethods are the calls that get idempotency keys. They are spelled
+// out rather than taken from the v1beta2 constants because v1beta2 imports
+// this package.
+var idempotentMethods = map[string]bool{
+	"/kessel.inventory.v1beta2.KesselInventoryService/ReportResource": true,
+	"/kessel.inventory.v1beta2.KesselInventoryService/DeleteResource": true,
+}
+
+// idempotencyCache remembers, per request content, the idempotency key sent
+// with it until a call with that content succeeds.
+type idempotencyCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[[sha256.Size]byte]*idempotencyEntry
+	lastSweep time.Time
+}
+
+type idempotencyEntry struct {
+	key     string
+	expires time.Time
+}
+
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[[sha256.Size]byte]*idempotencyEntry{},
+	}
+}
+
+// lookup returns the idempotency key for the request digest, reusing the key
+// of an identical call that has not succeeded yet.
+func (c *idempotencyCache) lookup(digest [sha256.Size]byte) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastSweep) >= c.ttl {
+		for d, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, d)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	entry, ok := c.entries[digest]
+	if !ok || !now.Before(entry.expires) {
+		entry = &idempotencyEntry{key: uuid.NewString(), expires: now.Add(c.ttl)}
+		c.entries[digest] = entry
+	}
+	return entry.key
+}
+
+// succeeded forgets the key of a request once it has been applied, so that
+// sending the same content again later is a new write with a new key.
+func (c *idempotencyCache) succeeded(digest [sha256.Size]byte, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[digest]; ok && entry.key == key {
+		delete(c.entries, digest)
+	}
+}
+
+// unaryInterceptor attaches an idempotency key to ReportResource and
+// DeleteResource calls. Identical requests reuse the key until one succeeds or
+// the TTL passes, so the server can recognise retries. Every call is sent; the
+// server, not the client, decides what is a duplicate. A key already on the
+// outgoing metadata is left alone.
+func (c *idempotencyCache) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		request, ok := req.(proto.Message)
+		if !idempotentMethods[method] || !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+		if err != nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		digest := sha256.Sum256(append([]byte(method), data...))
+
+		key := c.lookup(digest)
+		if md, _ := metadata.FromOutgoingContext(ctx); len(md.Get(idempotencyKeyMetadata)) == 0 {
+			ctx = metadata.AppendToOutgoingContext(ctx, idempotencyKeyMetadata, key)
+		}
+
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return err
+		}
+		c.succeeded(digest, key)
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const reportResourceMethod = "/kessel.inventory.v1beta2.KesselInventoryService/ReportResource"
+
+type recordingInvoker struct {
+	keys []string
+	errs []error
+}
+
+func (r *recordingInvoker) invoke(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	r.keys = append(r.keys, md.Get(idempotencyKeyMetadata)...)
+	reply.(*structpb.Struct).Fields = map[string]*structpb.Value{"ok": structpb.NewBoolValue(true)}
+	if len(r.errs) > 0 {
+		err := r.errs[0]
+		r.errs = r.errs[1:]
+		return err
+	}
+	return nil
+}
+
+func newRequest(t *testing.T, id string) *structpb.Struct {
+	t.Helper()
+	req, err := structpb.NewStruct(map[string]any{"resource_id": id})
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	return req
+}
+
+func TestIdempotency_retriesReuseKey(t *testing.T) {
+	cache := newIdempotencyCache(time.Minute)
+	interceptor := cache.unaryInterceptor()
+	invoker := &recordingInvoker{errs: []error{errors.New("unavailable")}}
+
+	err := interceptor(context.Background(), reportResourceMethod, newRequest(t, "host-1"), &structpb.Struct{}, nil, invoker.invoke)
+	if err == nil {
+		t.Fatal("Expected the first attempt to fail")
+	}
+	if err := interceptor(context.Background(), reportResourceMethod, newRequest(t, "host-1"), &structpb.Struct{}, nil, invoker.invoke); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(invoker.keys) != 2 {
+		t.Fatalf("Expected 2 calls with keys, got %v", invoker.keys)
+	}
+	if invoker.keys[0] == "" || invoker.keys[0] != invoker.keys[1] {
+		t.Errorf("Expected the retry to reuse the key, got %v", invoker.keys)
+	}
+}
+
+func TestIdempotency_sendsEveryWrite(t *testing.T) {
+	cache := newIdempotencyCache(time.Minute)
+	interceptor := cache.unaryInterceptor()
+	invoker := &recordingInvoker{}
+
+	for _, id := range []string{"v1", "v2", "v1"} {
+		if err := interceptor(context.Background(), reportResourceMethod, newRequest(t, id), &structpb.Struct{}, nil, invoker.invoke); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if len(invoker.keys) != 3 {
+		t.Fatalf("Expected every write to be sent, got %d calls", len(invoker.keys))
+	}
+	if invoker.keys[0] == invoker.keys[2] {
+		t.Errorf("Expected a repeat after a success to be a new write with a new key, got %v", invoker.keys)
+	}
+	if len(cache.entries) != 0 {
+		t.Errorf("Expected succeeded requests to be forgotten, got %d entries", len(cache.entries))
+	}
+}
+
+func TestIdempotency_newKeyAfterTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	cache := newIdempotencyCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	interceptor := cache.unaryInterceptor()
+	invoker := &recordingInvoker{errs: []error{errors.New("unavailable"), errors.New("unavailable")}}
+
+	_ = interceptor(context.Background(), reportResourceMethod, newRequest(t, "host-1"), &structpb.Struct{}, nil, invoker.invoke)
+	now = now.Add(2 * time.Minute)
+	_ = interceptor(context.Background(), reportResourceMethod, newRequest(t, "host-1"), &structpb.Struct{}, nil, invoker.invoke)
+
+	if len(invoker.keys) != 2 || invoker.keys[0] == invoker.keys[1] {
+		t.Errorf("Expected the request to be sent with a new key after the TTL, got %v", invoker.keys)
+	}
+}
+
+func TestIdempotency_ignoresOtherMethodsAndExistingKeys(t *testing.T) {
+	cache := newIdempotencyCache(time.Minute)
+	interceptor := cache.unaryInterceptor()
+	invoker := &recordingInvoker{}
+
+	_ = interceptor(context.Background(), "/kessel.inventory.v1beta2.KesselInventoryService/Check", newRequest(t, "host-1"), &structpb.Struct{}, nil, invoker.invoke)
+	if len(invoker.keys) != 0 {
+		t.Errorf("Expected no key on other methods, got %v", invoker.keys)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), idempotencyKeyMetadata, "caller-key")
+	_ = interceptor(ctx, reportResourceMethod, newRequest(t, "host-1"), &structpb.Struct{}, nil, invoker.invoke)
+	if len(invoker.keys) != 1 || invoker.keys[0] != "caller-key" {
+		t.Errorf("Expected the caller's key to be kept, got %v", invoker.keys)
+	}
+}
+
+func TestWithIdempotency_rejectsNonPositiveTTL(t *testing.T) {
+	_, _, err := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithIdempotency(0).Build()
+	if err == nil {
+		t.Error("Expected error for non-positive ttl")
+	}
+}
@@ -0,0 +1,75 @@
+package builder
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+)
+
+// validateInsecureAuth requires AllowInsecureAuth for clients without
+// per-RPC credentials whose targets are not local, so a production endpoint
+// is never called anonymously by mistake.
+func (b *ClientBuilder[C]) validateInsecureAuth(errs *kesselerrors.Multi) {
+	if b.perRPCCredentials != nil || b.allowInsecureAuth {
+		return
+	}
+	mode := "Unauthenticated"
+	if b.insecure {
+		mode = "Insecure"
+	}
+	for _, address := range b.addresses() {
+		if !isLocalTarget(address) {
+			errs.Append(-1, mode, fmt.Errorf("target %q is not local; call AllowInsecureAuth to send calls without credentials", address))
+		}
+	}
+}
+
+// warnInsecureAuth logs that the client sends calls without credentials.
+func (b *ClientBuilder[C]) warnInsecureAuth() {
+	if b.perRPCCredentials != nil {
+		return
+	}
+	logger := b.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	transport := "tls"
+	if b.insecure {
+		transport = "insecure"
+	}
+	logger.Warn("kessel: building a client without authentication",
+		slog.Any("targets", b.addresses()),
+		slog.String("transport", transport),
+		slog.Bool("allow_insecure_auth", b.allowInsecureAuth),
+	)
+}
+
+// isLocalTarget reports whether target names this machine: a unix socket,
+// localhost, or a loopback address, with or without a resolver scheme such
+// as "dns:///" or a grpc-web URL.
+func isLocalTarget(target string) bool {
+	if strings.HasPrefix(target, "unix:") || strings.HasPrefix(target, "unix-abstract:") {
+		return true
+	}
+	address := target
+	if parsed, err := url.Parse(target); err == nil && parsed.Scheme != "" && strings.Contains(target, "://") {
+		address = parsed.Host
+		if address == "" {
+			address = strings.TrimPrefix(parsed.Path, "/")
+		}
+	}
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,115 @@
+package builder
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+)
+
+func TestIsLocalTarget(t *testing.T) {
+	tests := []struct {
+		target   string
+		expected bool
+	}{
+		{target: "localhost:9000", expected: true},
+		{target: "inventory.localhost:9000", expected: true},
+		{target: "127.0.0.1:9000", expected: true},
+		{target: "[::1]:9000", expected: true},
+		{target: "dns:///localhost:9000", expected: true},
+		{target: "passthrough:///127.0.0.1:9000", expected: true},
+		{target: "http://localhost:8000", expected: true},
+		{target: "unix:///var/run/kessel.sock", expected: true},
+		{target: "kessel:9000"},
+		{target: "dns:///kessel-inventory.svc:9000"},
+		{target: "https://kessel.example.com"},
+		{target: "10.0.0.1:9000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if got := isLocalTarget(tt.target); got != tt.expected {
+				t.Errorf("Expected isLocalTarget(%q) to be %v", tt.target, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidate_requiresAllowInsecureAuth(t *testing.T) {
+	creds := auth.NewOAuth2ClientCredentials("client", "secret", "https://example.com/token")
+
+	tests := []struct {
+		name        string
+		builder     *ClientBuilder[*testClient]
+		expectedKey string
+	}{
+		{name: "insecure remote", builder: NewClientBuilder("kessel:9000", newTestClient).Insecure(), expectedKey: "Insecure"},
+		{name: "unauthenticated remote", builder: NewClientBuilder("kessel:9000", newTestClient).Unauthenticated(nil), expectedKey: "Unauthenticated"},
+		{name: "remote in targets", builder: NewClientBuilder("localhost:9000", newTestClient).Insecure().WithTargets([]string{"kessel:9000"}), expectedKey: "Insecure"},
+		{name: "insecure local", builder: NewClientBuilder("localhost:9000", newTestClient).Insecure()},
+		{name: "allowed remote", builder: NewClientBuilder("kessel:9000", newTestClient).Insecure().AllowInsecureAuth()},
+		{name: "authenticated remote", builder: NewClientBuilder("kessel:9000", newTestClient).OAuth2ClientAuthenticated(&creds, nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.builder.Validate()
+			if tt.expectedKey == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			var multi *kesselerrors.Multi
+			if !errors.As(err, &multi) || multi.Errors[0].Key != tt.expectedKey {
+				t.Fatalf("Expected an error keyed %s, got %v", tt.expectedKey, err)
+			}
+			if !strings.Contains(err.Error(), "kessel:9000") {
+				t.Errorf("Expected the remote target in the error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBuild_warnsWithoutAuthentication(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	_, conn, err := NewClientBuilder("localhost:9000", newTestClient).
+		Insecure().
+		WithLogger(logger).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	output := buf.String()
+	for _, expected := range []string{`"level":"WARN"`, `"targets":["localhost:9000"]`, `"transport":"insecure"`, `"allow_insecure_auth":false`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s in warning, got %s", expected, output)
+		}
+	}
+}
+
+func TestBuild_noWarningWithCredentials(t *testing.T) {
+	var buf bytes.Buffer
+	creds := auth.NewOAuth2ClientCredentials("client", "secret", "https://example.com/token")
+
+	_, conn, err := NewClientBuilder("kessel:9000", newTestClient).
+		OAuth2ClientAuthenticated(&creds, nil).
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no warning, got %s", buf.String())
+	}
+}
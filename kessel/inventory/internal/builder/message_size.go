@@ -0,0 +1,54 @@
+package builder
+
+import (
+	"context"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// checkMessageSize returns a *errors.MessageSizeError if message serializes
+// to more than limit bytes.
+func checkMessageSize(method string, message any, limit int) error {
+	m, ok := message.(proto.Message)
+	if !ok {
+		return nil
+	}
+	if size := proto.Size(m); size > limit {
+		return &kesselerrors.MessageSizeError{Method: method, Size: size, Limit: limit}
+	}
+	return nil
+}
+
+func messageSizeUnaryInterceptor(limit int) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := checkMessageSize(method, req, limit); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func messageSizeStreamInterceptor(limit int) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &messageSizeStream{ClientStream: stream, method: method, limit: limit}, nil
+	}
+}
+
+type messageSizeStream struct {
+	grpc.ClientStream
+	method string
+	limit  int
+}
+
+func (s *messageSizeStream) SendMsg(m any) error {
+	if err := checkMessageSize(s.method, m, s.limit); err != nil {
+		return err
+	}
+	return s.ClientStream.SendMsg(m)
+}
@@ -0,0 +1,122 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestMessageSizeUnaryInterceptor(t *testing.T) {
+	tests := []struct {
+		name          string
+		service       string
+		expectedError bool
+	}{
+		{name: "under the limit", service: "small"},
+		{name: "at the limit", service: strings.Repeat("a", 30)},
+		{name: "over the limit", service: strings.Repeat("a", 64), expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoked := false
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				invoked = true
+				return nil
+			}
+
+			err := messageSizeUnaryInterceptor(32)(context.Background(), healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{Service: tt.service}, nil, nil, invoker)
+			if !tt.expectedError {
+				if err != nil || !invoked {
+					t.Errorf("Expected the call to go through, got %v", err)
+				}
+				return
+			}
+			var sizeErr *kesselerrors.MessageSizeError
+			if !errors.As(err, &sizeErr) {
+				t.Fatalf("Expected MessageSizeError, got %v", err)
+			}
+			if sizeErr.Method != healthpb.Health_Check_FullMethodName || sizeErr.Size != 66 || sizeErr.Limit != 32 {
+				t.Errorf("Unexpected error fields: %+v", sizeErr)
+			}
+			if invoked {
+				t.Error("Expected the call not to reach the server")
+			}
+		})
+	}
+}
+
+type recordingClientStream struct {
+	grpc.ClientStream
+	sent int
+}
+
+func (s *recordingClientStream) SendMsg(m any) error {
+	s.sent++
+	return nil
+}
+
+func TestMessageSizeStreamInterceptor(t *testing.T) {
+	inner := &recordingClientStream{}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return inner, nil
+	}
+
+	stream, err := messageSizeStreamInterceptor(32)(context.Background(), &grpc.StreamDesc{}, nil, healthpb.Health_Watch_FullMethodName, streamer)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := stream.SendMsg(&healthpb.HealthCheckRequest{Service: "small"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := stream.SendMsg(&healthpb.HealthCheckRequest{Service: strings.Repeat("a", 64)}); !errors.Is(err, kesselerrors.ErrMessageTooLarge) {
+		t.Errorf("Expected ErrMessageTooLarge, got %v", err)
+	}
+	if inner.sent != 1 {
+		t.Errorf("Expected 1 message to be sent, got %d", inner.sent)
+	}
+}
+
+func TestWithMaxSendMessageSize(t *testing.T) {
+	var calls atomic.Int32
+	addr := startCountingServer(t, &calls)
+
+	client, conn, err := NewClientBuilder(addr, newTestClient).Insecure().WithMaxSendMessageSize(32).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var response healthpb.HealthCheckResponse
+	if err := client.conn.Invoke(ctx, healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &response, grpc.WaitForReady(true)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = client.conn.Invoke(ctx, healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{Service: strings.Repeat("a", 64)}, &response, grpc.WaitForReady(true))
+	if !errors.Is(err, kesselerrors.ErrMessageTooLarge) {
+		t.Errorf("Expected ErrMessageTooLarge, got %v", err)
+	}
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, got %v", status.Code(err))
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected only the small request to reach the server, got %d calls", calls.Load())
+	}
+}
+
+func TestWithMaxSendMessageSize_invalid(t *testing.T) {
+	_, _, err := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithMaxSendMessageSize(0).Build()
+	if err == nil || !strings.Contains(err.Error(), "maxBytes must be positive") {
+		t.Errorf("Expected invalid size error, got %v", err)
+	}
+}
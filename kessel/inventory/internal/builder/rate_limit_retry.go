@@ -0,0 +1,42 @@
+package builder
+
+import (
+	"context"
+	"time"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// rateLimitRetry converts throttling errors into *kesselerrors.RateLimitedError
+// and retries calls the server asked to be retried after a delay.
+type rateLimitRetry struct {
+	maxRetries int
+	maxWait    time.Duration
+}
+
+func (r *rateLimitRetry) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		for attempt := 0; ; attempt++ {
+			var trailer metadata.MD
+			callOpts := append(opts[:len(opts):len(opts)], grpc.Trailer(&trailer))
+			err := kesselerrors.FromGRPCTrailer(invoker(ctx, method, req, reply, cc, callOpts...), trailer)
+			retryAfter, ok := kesselerrors.RetryAfter(err)
+			if !ok || attempt >= r.maxRetries || retryAfter > r.maxWait {
+				return err
+			}
+			if deadline, hasDeadline := ctx.Deadline(); hasDeadline && time.Until(deadline) < retryAfter {
+				return err
+			}
+
+			timer := time.NewTimer(retryAfter)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package builder
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// throttlingInvoker fails the first failures calls with ResourceExhausted and
+// the given trailer.
+func throttlingInvoker(failures int, trailerMD metadata.MD, calls *int) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls > failures {
+			return nil
+		}
+		for _, opt := range opts {
+			if trailer, ok := opt.(grpc.TrailerCallOption); ok {
+				*trailer.TrailerAddr = trailerMD
+			}
+		}
+		return status.Error(codes.ResourceExhausted, "slow down")
+	}
+}
+
+func TestRateLimitRetryUnaryInterceptor(t *testing.T) {
+	tests := []struct {
+		name          string
+		retry         rateLimitRetry
+		failures      int
+		trailer       metadata.MD
+		expectedCalls int
+		expectedErr   bool
+	}{
+		{name: "retries after delay", retry: rateLimitRetry{maxRetries: 2, maxWait: time.Second}, failures: 2, trailer: metadata.Pairs("grpc-retry-pushback-ms", "1"), expectedCalls: 3},
+		{name: "gives up after max retries", retry: rateLimitRetry{maxRetries: 1, maxWait: time.Second}, failures: 5, trailer: metadata.Pairs("grpc-retry-pushback-ms", "1"), expectedCalls: 2, expectedErr: true},
+		{name: "delay over max wait", retry: rateLimitRetry{maxRetries: 3, maxWait: time.Second}, failures: 1, trailer: metadata.Pairs("retry-after", "60"), expectedCalls: 1, expectedErr: true},
+		{name: "convert only", retry: rateLimitRetry{maxWait: time.Second}, failures: 1, trailer: metadata.Pairs("retry-after", "1"), expectedCalls: 1, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := tt.retry.unaryInterceptor()(context.Background(), "/test", nil, nil, nil, throttlingInvoker(tt.failures, tt.trailer, &calls))
+			if calls != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+			if !tt.expectedErr {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if !kesselerrors.IsRateLimited(err) {
+				t.Errorf("Expected a rate-limited error, got %v", err)
+			}
+			if status.Code(err) != codes.ResourceExhausted {
+				t.Errorf("Expected ResourceExhausted, got %v", status.Code(err))
+			}
+		})
+	}
+}
+
+func TestRateLimitRetryUnaryInterceptor_deadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	retry := rateLimitRetry{maxRetries: 3, maxWait: time.Minute}
+	err := retry.unaryInterceptor()(ctx, "/test", nil, nil, nil, throttlingInvoker(1, metadata.Pairs("retry-after", "5"), &calls))
+	if calls != 1 {
+		t.Errorf("Expected no retry past the deadline, got %d calls", calls)
+	}
+	if retryAfter, ok := kesselerrors.RetryAfter(err); !ok || retryAfter != 5*time.Second {
+		t.Errorf("Expected RetryAfter 5s, got %s (%v)", retryAfter, ok)
+	}
+}
+
+func TestWithRateLimitRetry_invalid(t *testing.T) {
+	b := NewClientBuilder("localhost:9000", newTestClient).Insecure().AllowInsecureAuth().WithRateLimitRetry(-1, time.Second)
+	if _, _, err := b.Build(); err == nil || !strings.Contains(err.Error(), "must not be negative") {
+		t.Errorf("Expected an option error, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+)
+
+// mutatingMethods are the calls refused by read-only clients. They are
+// spelled out rather than taken from the v1beta2 constants because v1beta2
+// imports this package.
+var mutatingMethods = map[string]bool{
+	"/kessel.inventory.v1beta2.KesselInventoryService/ReportResource": true,
+	"/kessel.inventory.v1beta2.KesselInventoryService/DeleteResource": true,
+	"/kessel.inventory.v1beta2.KesselTupleService/CreateTuples":       true,
+	"/kessel.inventory.v1beta2.KesselTupleService/DeleteTuples":       true,
+	"/kessel.inventory.v1beta2.KesselTupleService/AcquireLock":        true,
+}
+
+func readOnlyUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if mutatingMethods[method] {
+			return fmt.Errorf("%w: %s is not allowed", kesselerrors.ErrReadOnlyClient, method)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+)
+
+func TestReadOnlyUnaryInterceptor(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		expectedError bool
+	}{
+		{name: "report resource", method: "/kessel.inventory.v1beta2.KesselInventoryService/ReportResource", expectedError: true},
+		{name: "delete resource", method: "/kessel.inventory.v1beta2.KesselInventoryService/DeleteResource", expectedError: true},
+		{name: "create tuples", method: "/kessel.inventory.v1beta2.KesselTupleService/CreateTuples", expectedError: true},
+		{name: "check", method: "/kessel.inventory.v1beta2.KesselInventoryService/Check"},
+		{name: "read tuples", method: "/kessel.inventory.v1beta2.KesselTupleService/ReadTuples"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoked := false
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				invoked = true
+				return nil
+			}
+
+			err := readOnlyUnaryInterceptor()(context.Background(), tt.method, nil, nil, nil, invoker)
+			if tt.expectedError {
+				if !errors.Is(err, kesselerrors.ErrReadOnlyClient) {
+					t.Errorf("Expected ErrReadOnlyClient, got %v", err)
+				}
+				if invoked {
+					t.Error("Expected the call not to reach the server")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !invoked {
+				t.Error("Expected the call to go through")
+			}
+		})
+	}
+}
+
+func TestWithReadOnly(t *testing.T) {
+	unary, _ := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithReadOnly().interceptors()
+	if len(unary) != 2 {
+		t.Errorf("Expected 2 unary interceptors, got %d", len(unary))
+	}
+}
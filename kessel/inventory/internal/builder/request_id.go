@@ -0,0 +1,48 @@
+package builder
+
+import (
+	"context"
+	"io"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/callmetadata"
+	"google.golang.org/grpc"
+)
+
+func requestIdUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, requestId := callmetadata.EnsureRequestId(ctx)
+		return withRequestId(requestId, invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+func requestIdStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, requestId := callmetadata.EnsureRequestId(ctx)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, withRequestId(requestId, err)
+		}
+		return &requestIdStream{ClientStream: stream, requestId: requestId}, nil
+	}
+}
+
+type requestIdStream struct {
+	grpc.ClientStream
+	requestId string
+}
+
+func (s *requestIdStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == io.EOF {
+		return err
+	}
+	return withRequestId(s.requestId, err)
+}
+
+func withRequestId(requestId string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kesselerrors.RequestIdError{RequestId: requestId, Err: err}
+}
@@ -0,0 +1,109 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/callmetadata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestIdUnaryInterceptor(t *testing.T) {
+	tests := []struct {
+		name       string
+		requestId  string
+		invokerErr error
+	}{
+		{name: "generated id", invokerErr: status.Error(codes.Unavailable, "down")},
+		{name: "caller id", requestId: "req-1", invokerErr: status.Error(codes.Unavailable, "down")},
+		{name: "success", requestId: "req-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.requestId != "" {
+				ctx = callmetadata.WithRequestId(ctx, tt.requestId)
+			}
+
+			var sent string
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				sent = callmetadata.RequestId(ctx)
+				return tt.invokerErr
+			}
+
+			err := requestIdUnaryInterceptor()(ctx, "/test", nil, nil, nil, invoker)
+			if sent == "" {
+				t.Fatal("Expected a request ID to be sent")
+			}
+			if tt.requestId != "" && sent != tt.requestId {
+				t.Errorf("Expected request ID %q, got %q", tt.requestId, sent)
+			}
+			if tt.invokerErr == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if got := kesselerrors.RequestId(err); got != sent {
+				t.Errorf("Expected error to carry request ID %q, got %q", sent, got)
+			}
+			if status.Code(err) != codes.Unavailable {
+				t.Errorf("Expected the gRPC code to be preserved, got %v", status.Code(err))
+			}
+		})
+	}
+}
+
+type recvErrStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s *recvErrStream) RecvMsg(any) error {
+	return s.err
+}
+
+func TestRequestIdStreamInterceptor(t *testing.T) {
+	ctx := callmetadata.WithRequestId(context.Background(), "req-2")
+
+	tests := []struct {
+		name        string
+		recvErr     error
+		expectedErr func(error) bool
+	}{
+		{name: "end of stream", recvErr: io.EOF, expectedErr: func(err error) bool { return err == io.EOF }},
+		{name: "stream error", recvErr: errors.New("reset"), expectedErr: func(err error) bool { return kesselerrors.RequestId(err) == "req-2" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return &recvErrStream{err: tt.recvErr}, nil
+			}
+
+			stream, err := requestIdStreamInterceptor()(ctx, &grpc.StreamDesc{}, nil, "/test", streamer)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err := stream.RecvMsg(nil); !tt.expectedErr(err) {
+				t.Errorf("Unexpected RecvMsg error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithRequestIds(t *testing.T) {
+	unary, stream := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithRequestIds().interceptors()
+	if len(unary) != 2 {
+		t.Errorf("Expected 2 unary interceptors, got %d", len(unary))
+	}
+	if len(stream) != 2 {
+		t.Errorf("Expected 2 stream interceptors, got %d", len(stream))
+	}
+}
@@ -0,0 +1,24 @@
+package builder
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// serverNameCredentials verifies the server's certificate against serverName
+// instead of the dial target's host or the authority. Credentials whose
+// tls.Config sets ServerName keep it, as they would for the authority.
+type serverNameCredentials struct {
+	credentials.TransportCredentials
+	serverName string
+}
+
+func (c *serverNameCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return c.TransportCredentials.ClientHandshake(ctx, c.serverName, conn)
+}
+
+func (c *serverNameCredentials) Clone() credentials.TransportCredentials {
+	return &serverNameCredentials{TransportCredentials: c.TransportCredentials.Clone(), serverName: c.serverName}
+}
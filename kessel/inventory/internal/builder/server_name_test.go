@@ -0,0 +1,152 @@
+package builder
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// startHealthServer serves the health service on a local port and sends the
+// :authority of every call on authorities.
+func startHealthServer(t *testing.T, creds credentials.TransportCredentials, authorities chan<- string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	var options []grpc.ServerOption
+	if creds != nil {
+		options = append(options, grpc.Creds(creds))
+	}
+	options = append(options, grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(":authority"); len(values) > 0 {
+			authorities <- values[0]
+		}
+		return handler(ctx, req)
+	}))
+	server := grpc.NewServer(options...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+// selfSignedCert returns a certificate valid only for dnsName.
+func selfSignedCert(t *testing.T, dnsName string) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: dnsName},
+		DNSNames:              []string{dnsName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+func checkHealth(t *testing.T, client *testClient) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return client.conn.Invoke(ctx, healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+}
+
+func TestWithAuthority(t *testing.T) {
+	authorities := make(chan string, 1)
+	addr := startHealthServer(t, nil, authorities)
+
+	client, conn, err := NewClientBuilder(addr, newTestClient).Insecure().AllowInsecureAuth().WithAuthority("kessel.internal").Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+	if err := checkHealth(t, client); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := <-authorities; got != "kessel.internal" {
+		t.Errorf("Expected authority %q, got %q", "kessel.internal", got)
+	}
+}
+
+func TestWithTLSServerName(t *testing.T) {
+	cert, pool := selfSignedCert(t, "kessel.internal")
+	authorities := make(chan string, 1)
+	addr := startHealthServer(t, credentials.NewServerTLSFromCert(&cert), authorities)
+	channelCredentials := credentials.NewTLS(&tls.Config{RootCAs: pool})
+
+	client, conn, err := NewClientBuilder(addr, newTestClient).Unauthenticated(channelCredentials).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := checkHealth(t, client); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("Expected a certificate error without the server name, got %v", err)
+	}
+	_ = conn.Close()
+
+	client, conn, err = NewClientBuilder(addr, newTestClient).Unauthenticated(channelCredentials).WithTLSServerName("kessel.internal").Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+	if err := checkHealth(t, client); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := <-authorities; got != addr {
+		t.Errorf("Expected the authority to stay %q, got %q", addr, got)
+	}
+}
+
+func TestWithAuthority_invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *ClientBuilder[*testClient]
+		expected string
+	}{
+		{name: "empty authority", builder: NewClientBuilder("localhost:9000", newTestClient).WithAuthority(""), expected: "authority must not be empty"},
+		{name: "empty server name", builder: NewClientBuilder("localhost:9000", newTestClient).WithTLSServerName(""), expected: "server name must not be empty"},
+		{name: "server name with insecure", builder: NewClientBuilder("localhost:9000", newTestClient).Insecure().WithTLSServerName("kessel.internal"), expected: "cannot be combined with Insecure"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.builder.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package builder
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// streamOverflow spreads streams over extra connections once the connection
+// built by Build (the primary) has maxStreams active streams. It must be the
+// innermost stream interceptor: streams moved to an overflow connection are
+// opened with NewStream, which does not rerun the primary's interceptors, and
+// overflow connections are dialed without interceptors so nothing runs twice.
+type streamOverflow struct {
+	maxStreams int
+	maxConns   int
+	dial       func() (*grpc.ClientConn, error)
+
+	mu       sync.Mutex
+	primary  *trackedConn
+	overflow []*trackedConn
+}
+
+type trackedConn struct {
+	conn   *grpc.ClientConn
+	active int
+}
+
+func newStreamOverflow(maxStreams int, maxConns int, dial func() (*grpc.ClientConn, error)) *streamOverflow {
+	return &streamOverflow{maxStreams: maxStreams, maxConns: maxConns, dial: dial}
+}
+
+// acquire picks the connection for a new stream and reserves a slot on it.
+func (s *streamOverflow) acquire(primary *grpc.ClientConn) (*trackedConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.primary == nil {
+		s.primary = &trackedConn{conn: primary}
+	}
+
+	least := s.primary
+	for _, candidate := range append([]*trackedConn{s.primary}, s.overflow...) {
+		if candidate.active < s.maxStreams {
+			candidate.active++
+			return candidate, nil
+		}
+		if candidate.active < least.active {
+			least = candidate
+		}
+	}
+
+	if 1+len(s.overflow) < s.maxConns {
+		conn, err := s.dial()
+		if err != nil {
+			return nil, err
+		}
+		if len(s.overflow) == 0 {
+			go s.closeWithPrimary()
+		}
+		tracked := &trackedConn{conn: conn, active: 1}
+		s.overflow = append(s.overflow, tracked)
+		return tracked, nil
+	}
+
+	least.active++
+	return least, nil
+}
+
+func (s *streamOverflow) release(tracked *trackedConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tracked.active--
+}
+
+// closeWithPrimary closes the overflow connections once the primary
+// connection has been closed by its owner.
+func (s *streamOverflow) closeWithPrimary() {
+	s.mu.Lock()
+	primary := s.primary.conn
+	s.mu.Unlock()
+
+	for state := primary.GetState(); state != connectivity.Shutdown; state = primary.GetState() {
+		primary.WaitForStateChange(context.Background(), state)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tracked := range s.overflow {
+		_ = tracked.conn.Close()
+	}
+}
+
+func (s *streamOverflow) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		tracked, err := s.acquire(cc)
+		if err != nil {
+			return nil, err
+		}
+
+		var once sync.Once
+		release := func() { once.Do(func() { s.release(tracked) }) }
+
+		var stream grpc.ClientStream
+		if tracked.conn == cc {
+			stream, err = streamer(ctx, desc, cc, method, opts...)
+		} else {
+			stream, err = tracked.conn.NewStream(ctx, desc, method, opts...)
+		}
+		if err != nil {
+			release()
+			return nil, err
+		}
+
+		stop := context.AfterFunc(ctx, release)
+		return &trackedStream{ClientStream: stream, release: func() {
+			stop()
+			release()
+		}}, nil
+	}
+}
+
+// trackedStream releases its connection slot when the stream ends.
+type trackedStream struct {
+	grpc.ClientStream
+	release func()
+}
+
+func (t *trackedStream) RecvMsg(m any) error {
+	err := t.ClientStream.RecvMsg(m)
+	if err != nil {
+		t.release()
+	}
+	return err
+}
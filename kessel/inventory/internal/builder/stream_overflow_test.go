@@ -0,0 +1,99 @@
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func newIdleConn(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.NewClient("localhost:0", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to create client conn: %v", err)
+	}
+	return conn
+}
+
+func TestStreamOverflow_acquire(t *testing.T) {
+	primary := newIdleConn(t)
+	defer primary.Close()
+
+	dials := 0
+	s := newStreamOverflow(2, 2, func() (*grpc.ClientConn, error) {
+		dials++
+		return newIdleConn(t), nil
+	})
+
+	first, _ := s.acquire(primary)
+	second, _ := s.acquire(primary)
+	if first.conn != primary || second.conn != primary {
+		t.Fatal("Expected the first two streams on the primary connection")
+	}
+
+	third, err := s.acquire(primary)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if third.conn == primary || dials != 1 {
+		t.Fatalf("Expected an overflow connection to be dialed, dials = %d", dials)
+	}
+
+	s.acquire(primary)
+	// Both connections are full and maxConns is reached: use the least busy.
+	fifth, _ := s.acquire(primary)
+	if dials != 1 {
+		t.Errorf("Expected no further dials, got %d", dials)
+	}
+	if fifth.active != 3 {
+		t.Errorf("Expected the fifth stream to share a full connection, active = %d", fifth.active)
+	}
+
+	s.release(first)
+	s.release(second)
+	if next, _ := s.acquire(primary); next.conn != primary {
+		t.Error("Expected released slots on the primary to be reused")
+	}
+}
+
+func TestStreamOverflow_closesOverflowWithPrimary(t *testing.T) {
+	primary := newIdleConn(t)
+	var overflow *grpc.ClientConn
+	s := newStreamOverflow(1, 2, func() (*grpc.ClientConn, error) {
+		overflow = newIdleConn(t)
+		return overflow, nil
+	})
+
+	s.acquire(primary)
+	s.acquire(primary)
+	if overflow == nil {
+		t.Fatal("Expected an overflow connection")
+	}
+
+	primary.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for overflow.GetState() != connectivity.Shutdown {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the overflow connection to be closed with the primary")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWithMaxConcurrentStreams(t *testing.T) {
+	b := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithMaxConcurrentStreams(100, 4)
+
+	_, stream := b.interceptors()
+	if len(stream) != 2 {
+		t.Errorf("Expected 2 stream interceptors, got %d", len(stream))
+	}
+
+	_, _, err := NewClientBuilder("localhost:9000", newTestClient).Insecure().WithMaxConcurrentStreams(0, 4).Build()
+	if err == nil {
+		t.Error("Expected error for non-positive maxStreams")
+	}
+}
@@ -0,0 +1,233 @@
+package builder
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+func startCountingServer(t *testing.T, calls *atomic.Int32) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		calls.Add(1)
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestWithTargets_spreadsCallsAcrossTargets(t *testing.T) {
+	var first, second atomic.Int32
+	firstAddr := startCountingServer(t, &first)
+	secondAddr := startCountingServer(t, &second)
+
+	client, conn, err := NewClientBuilder(firstAddr, newTestClient).
+		Insecure().
+		WithTargets([]string{secondAddr}).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 20; i++ {
+		var response healthpb.HealthCheckResponse
+		err := client.conn.Invoke(ctx, healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &response, grpc.WaitForReady(true))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if first.Load() == 0 || second.Load() == 0 {
+		t.Errorf("Expected calls on both targets, got %d and %d", first.Load(), second.Load())
+	}
+}
+
+func TestWithResolver_resolvesCustomScheme(t *testing.T) {
+	var calls atomic.Int32
+	addr := startCountingServer(t, &calls)
+	r := manual.NewBuilderWithScheme("discovery")
+	r.InitialState(resolver.State{Addresses: []resolver.Address{{Addr: addr}}})
+
+	client, conn, err := NewClientBuilder("discovery:///kessel-inventory", newTestClient).
+		Insecure().
+		AllowInsecureAuth().
+		WithResolver(r).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var response healthpb.HealthCheckResponse
+	if err := client.conn.Invoke(ctx, healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, &response, grpc.WaitForReady(true)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected the call to reach the resolved address, got %d calls", calls.Load())
+	}
+}
+
+func TestWithResolver_invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *ClientBuilder[*testClient]
+		expected string
+	}{
+		{
+			name:     "nil builder",
+			builder:  NewClientBuilder("discovery:///kessel", newTestClient).WithResolver(nil),
+			expected: "resolver builder must not be nil",
+		},
+		{
+			name:     "with targets",
+			builder:  NewClientBuilder("discovery:///kessel", newTestClient).WithResolver(manual.NewBuilderWithScheme("discovery")).WithTargets([]string{"localhost:9001"}),
+			expected: "WithResolver: cannot be combined with WithTargets",
+		},
+		{
+			name:     "with grpc-web",
+			builder:  NewClientBuilder("discovery:///kessel", newTestClient).WithResolver(manual.NewBuilderWithScheme("discovery")).WithGrpcWeb(nil),
+			expected: "cannot be combined with WithResolver",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.builder.Insecure().Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestWithTargets_doesNotRequireBuilderTarget(t *testing.T) {
+	_, conn, err := NewClientBuilder("", newTestClient).
+		Insecure().
+		WithTargets([]string{"localhost:9000", "localhost:9001"}).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+}
+
+func TestDialTarget(t *testing.T) {
+	tests := []struct {
+		name            string
+		builder         *ClientBuilder[*testClient]
+		expectedTarget  string
+		expectedOptions int
+	}{
+		{
+			name:            "single target",
+			builder:         NewClientBuilder("localhost:9000", newTestClient),
+			expectedTarget:  "localhost:9000",
+			expectedOptions: 0,
+		},
+		{
+			name:            "single target with round robin",
+			builder:         NewClientBuilder("dns:///kessel:9000", newTestClient).WithRoundRobin(),
+			expectedTarget:  "dns:///kessel:9000",
+			expectedOptions: 1,
+		},
+		{
+			name:            "multiple targets",
+			builder:         NewClientBuilder("localhost:9000", newTestClient).WithTargets([]string{"localhost:9001"}),
+			expectedTarget:  "kessel:///localhost:9000",
+			expectedOptions: 2,
+		},
+		{
+			name:            "single target with resolver",
+			builder:         NewClientBuilder("discovery:///kessel", newTestClient).WithResolver(manual.NewBuilderWithScheme("discovery")),
+			expectedTarget:  "discovery:///kessel",
+			expectedOptions: 1,
+		},
+		{
+			name:            "single target with service config",
+			builder:         NewClientBuilder("localhost:9000", newTestClient).WithServiceConfig(`{"methodConfig": []}`),
+			expectedTarget:  "localhost:9000",
+			expectedOptions: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, options := tt.builder.dialTarget()
+			if target != tt.expectedTarget {
+				t.Errorf("Expected target %s, got %s", tt.expectedTarget, target)
+			}
+			if len(options) != tt.expectedOptions {
+				t.Errorf("Expected %d dial options, got %d", tt.expectedOptions, len(options))
+			}
+		})
+	}
+}
+
+func TestDefaultServiceConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		serviceConfig string
+		roundRobin    bool
+		expected      string
+		expectedOk    bool
+	}{
+		{name: "nothing to set"},
+		{name: "round robin only", roundRobin: true, expected: roundRobinServiceConfig, expectedOk: true},
+		{
+			name:          "service config only",
+			serviceConfig: `{"methodConfig": [{"name": [{"service": "s"}], "timeout": "1s"}]}`,
+			expected:      `{"methodConfig":[{"name":[{"service":"s"}],"timeout":"1s"}]}`,
+			expectedOk:    true,
+		},
+		{
+			name:          "round robin added to service config",
+			serviceConfig: `{"methodConfig": []}`,
+			roundRobin:    true,
+			expected:      `{"loadBalancingConfig":[{"round_robin":{}}],"methodConfig":[]}`,
+			expectedOk:    true,
+		},
+		{
+			name:          "service config chooses the policy",
+			serviceConfig: `{"loadBalancingConfig": [{"pick_first": {}}]}`,
+			roundRobin:    true,
+			expected:      `{"loadBalancingConfig":[{"pick_first":{}}]}`,
+			expectedOk:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewClientBuilder("localhost:9000", newTestClient)
+			if tt.serviceConfig != "" {
+				builder.WithServiceConfig(tt.serviceConfig)
+			}
+			config, ok := builder.defaultServiceConfig(tt.roundRobin)
+			if ok != tt.expectedOk {
+				t.Fatalf("Expected ok %v, got %v", tt.expectedOk, ok)
+			}
+			if ok && config != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, config)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/resume"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"github.com/project-kessel/kessel-sdk-go/kessel/ratelimit"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultObjectsPageSize = 1000
+
+// errStopObjects ends forEachObject early without reporting an error.
+var errStopObjects = errors.New("stop listing objects")
+
+// CollectObjectsOption configures CollectObjects and StreamObjects.
+type CollectObjectsOption func(*collectObjectsOptions)
+
+type collectObjectsOptions struct {
+	maxItems   int
+	pageSize   uint32
+	bufferSize int
+	maxResumes int
+	onPage     func([]*v1beta2.StreamedListObjectsResponse) error
+	onProgress func(ListProgress)
+	limiter    *ratelimit.Limiter
+}
+
+// WithMaxItems stops listing after n objects. Zero (the default) lists every
+// object.
+func WithMaxItems(n int) CollectObjectsOption {
+	return func(o *collectObjectsOptions) {
+		o.maxItems = n
+	}
+}
+
+// WithPageSize sets how many objects are requested per StreamedListObjects
+// call (default 1000). It bounds how many objects are held in memory per page.
+func WithPageSize(n uint32) CollectObjectsOption {
+	return func(o *collectObjectsOptions) {
+		o.pageSize = n
+	}
+}
+
+// WithPageCallback hands each page to callback as soon as it has been received
+// instead of accumulating it, so CollectObjects holds at most one page in
+// memory and returns a nil slice. An error from callback stops listing and is
+// returned by CollectObjects. The page slice is not reused after callback
+// returns.
+func WithPageCallback(callback func(page []*v1beta2.StreamedListObjectsResponse) error) CollectObjectsOption {
+	return func(o *collectObjectsOptions) {
+		o.onPage = callback
+	}
+}
+
+// WithBufferSize sets the capacity of the channel returned by StreamObjects
+// (default 0, unbuffered). Listing pauses while the buffer is full, so a slow
+// consumer holds back the stream instead of growing memory.
+func WithBufferSize(n int) CollectObjectsOption {
+	return func(o *collectObjectsOptions) {
+		o.bufferSize = n
+	}
+}
+
+// WithResume reopens the stream from the last received continuation token when
+// it breaks with Unavailable or a stream reset, e.g. during a rolling restart
+// of the server, waiting with exponential backoff between attempts. Up to
+// maxAttempts resumes are made in a row; receiving an object resets the count.
+// A page that breaks after delivering objects without a continuation token is
+// not resumed, since that would repeat them.
+func WithResume(maxAttempts int) CollectObjectsOption {
+	return func(o *collectObjectsOptions) {
+		o.maxResumes = maxAttempts
+	}
+}
+
+// ListProgress reports how far a listing has got, after each page.
+type ListProgress struct {
+	// Pages completed so far.
+	Pages int
+	// Objects delivered so far.
+	Items int
+	// Time since listing started.
+	Elapsed time.Duration
+	// Continuation token of the last object delivered, empty once the last
+	// page is done. To resume a stopped job, set it as the request's
+	// Pagination.ContinuationToken.
+	ContinuationToken string
+}
+
+// WithProgress calls onPage with a ListProgress after each page, so
+// operators can monitor long listings and record where to resume them. For
+// StreamObjects it runs on the listing goroutine, so keep it fast.
+func WithProgress(onPage func(ListProgress)) CollectObjectsOption {
+	return func(o *collectObjectsOptions) {
+		o.onProgress = onPage
+	}
+}
+
+// WithPageRateLimit waits on limiter before every stream after the first
+// (each page and each WithResume attempt), so a large listing does not
+// monopolise the server. Share a limiter
+// across listings to bound them together.
+func WithPageRateLimit(limiter *ratelimit.Limiter) CollectObjectsOption {
+	return func(o *collectObjectsOptions) {
+		o.limiter = limiter
+	}
+}
+
+// ObjectResult is a single value received from StreamObjects. Exactly one of
+// Object and Err is set; an Err is always the last value before the channel
+// closes.
+type ObjectResult struct {
+	Object *v1beta2.StreamedListObjectsResponse
+	Err    error
+}
+
+// CollectObjects lists every object matching request, following continuation
+// tokens across pages. The request's own pagination, if any, is used for the
+// first page; request is not modified. Without WithPageCallback all objects
+// are returned in one slice, so use WithMaxItems, WithPageCallback or
+// StreamObjects for very large results.
+func CollectObjects(ctx context.Context, client v1beta2.KesselInventoryServiceClient, request *v1beta2.StreamedListObjectsRequest, opts ...CollectObjectsOption) ([]*v1beta2.StreamedListObjectsResponse, error) {
+	options := newCollectObjectsOptions(opts)
+
+	var all []*v1beta2.StreamedListObjectsResponse
+	var page []*v1beta2.StreamedListObjectsResponse
+	flush := func() error {
+		if len(page) == 0 {
+			return nil
+		}
+		err := options.onPage(page)
+		page = nil
+		return err
+	}
+
+	err := forEachObject(ctx, client, request, options, func(object *v1beta2.StreamedListObjectsResponse) error {
+		if options.onPage == nil {
+			all = append(all, object)
+		} else {
+			page = append(page, object)
+		}
+		return nil
+	}, func() error {
+		if options.onPage == nil {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// StreamObjects lists objects like CollectObjects but delivers them on a
+// channel as they arrive, buffering at most WithBufferSize objects. The
+// channel is closed when listing finishes, fails (after an ObjectResult with
+// Err) or ctx ends. Consumers that stop reading early must cancel ctx so the
+// listing goroutine exits.
+func StreamObjects(ctx context.Context, client v1beta2.KesselInventoryServiceClient, request *v1beta2.StreamedListObjectsRequest, opts ...CollectObjectsOption) <-chan ObjectResult {
+	options := newCollectObjectsOptions(opts)
+	results := make(chan ObjectResult, options.bufferSize)
+
+	send := func(result ObjectResult) error {
+		select {
+		case results <- result:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	go func() {
+		defer close(results)
+		err := forEachObject(ctx, client, request, options, func(object *v1beta2.StreamedListObjectsResponse) error {
+			return send(ObjectResult{Object: object})
+		}, func() error { return nil })
+		if err != nil && ctx.Err() == nil {
+			_ = send(ObjectResult{Err: err})
+		}
+	}()
+	return results
+}
+
+func newCollectObjectsOptions(opts []CollectObjectsOption) collectObjectsOptions {
+	options := collectObjectsOptions{pageSize: defaultObjectsPageSize}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// forEachObject calls item for every listed object and pageDone after each
+// page (including the last, partial one when WithMaxItems stops listing).
+func forEachObject(
+	ctx context.Context,
+	client v1beta2.KesselInventoryServiceClient,
+	request *v1beta2.StreamedListObjectsRequest,
+	options collectObjectsOptions,
+	item func(*v1beta2.StreamedListObjectsResponse) error,
+	pageDone func() error,
+) error {
+	pageRequest := proto.Clone(request).(*v1beta2.StreamedListObjectsRequest)
+	if pageRequest.Pagination == nil {
+		pageRequest.Pagination = &v1beta2.RequestPagination{Limit: options.pageSize}
+	}
+
+	start := time.Now()
+	count := 0
+	pages := 0
+	resumes := 0
+	for first := true; ; first = false {
+		if !first {
+			if err := options.limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		request := pageRequest
+		var lastToken string
+		received := false
+		var interrupted error
+		err := func() error {
+			// Cancelling ends the stream when listing stops mid-page.
+			streamCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			stream, err := client.StreamedListObjects(streamCtx, request)
+			if err != nil {
+				interrupted = err
+				return fmt.Errorf("failed to start stream: %w", err)
+			}
+			for {
+				response, err := stream.Recv()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					interrupted = err
+					return fmt.Errorf("error receiving from stream: %w", err)
+				}
+				received = true
+				resumes = 0
+
+				if err := item(response); err != nil {
+					return err
+				}
+				count++
+				if response.GetPagination() != nil {
+					lastToken = response.GetPagination().GetContinuationToken()
+				}
+				if options.maxItems > 0 && count >= options.maxItems {
+					return errStopObjects
+				}
+			}
+		}()
+		if interrupted != nil && (lastToken != "" || !received) && resume.Retry(ctx, interrupted, &resumes, options.maxResumes) {
+			if lastToken != "" {
+				pageRequest = nextObjectsPage(request, options.pageSize, lastToken)
+			}
+			continue
+		}
+		stopped := errors.Is(err, errStopObjects)
+		if err != nil && !stopped {
+			return err
+		}
+		if err := pageDone(); err != nil {
+			return err
+		}
+		pages++
+		if options.onProgress != nil {
+			options.onProgress(ListProgress{Pages: pages, Items: count, Elapsed: time.Since(start), ContinuationToken: lastToken})
+		}
+		if stopped || lastToken == "" {
+			return nil
+		}
+
+		pageRequest = nextObjectsPage(request, options.pageSize, lastToken)
+	}
+}
+
+// nextObjectsPage returns a copy of request that continues after token.
+func nextObjectsPage(request *v1beta2.StreamedListObjectsRequest, pageSize uint32, token string) *v1beta2.StreamedListObjectsRequest {
+	next := proto.Clone(request).(*v1beta2.StreamedListObjectsRequest)
+	next.Pagination = &v1beta2.RequestPagination{
+		Limit:             pageSize,
+		ContinuationToken: &token,
+	}
+	return next
+}
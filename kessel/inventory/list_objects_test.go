@@ -0,0 +1,320 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"github.com/project-kessel/kessel-sdk-go/kessel/ratelimit"
+)
+
+type mockObjectsStream struct {
+	grpc.ServerStreamingClient[v1beta2.StreamedListObjectsResponse]
+	responses []*v1beta2.StreamedListObjectsResponse
+	index     int
+	err       error
+}
+
+func (m *mockObjectsStream) Recv() (*v1beta2.StreamedListObjectsResponse, error) {
+	if m.index >= len(m.responses) {
+		if m.err != nil {
+			return nil, m.err
+		}
+		return nil, io.EOF
+	}
+	response := m.responses[m.index]
+	m.index++
+	return response, nil
+}
+
+type mockListObjectsClient struct {
+	v1beta2.KesselInventoryServiceClient
+	pages [][]*v1beta2.StreamedListObjectsResponse
+	err   error
+	// streamErrs[i], if set, ends stream i after its page instead of io.EOF.
+	streamErrs       []error
+	capturedRequests []*v1beta2.StreamedListObjectsRequest
+}
+
+func (m *mockListObjectsClient) StreamedListObjects(ctx context.Context, in *v1beta2.StreamedListObjectsRequest, opts ...grpc.CallOption) (v1beta2.KesselInventoryService_StreamedListObjectsClient, error) {
+	page := len(m.capturedRequests)
+	m.capturedRequests = append(m.capturedRequests, in)
+	if m.err != nil {
+		return nil, m.err
+	}
+	stream := &mockObjectsStream{responses: m.pages[page]}
+	if page < len(m.streamErrs) {
+		stream.err = m.streamErrs[page]
+	}
+	return stream, nil
+}
+
+// objectPages returns pages of sequentially numbered objects, each page
+// carrying a continuation token except the last.
+func objectPages(sizes ...int) [][]*v1beta2.StreamedListObjectsResponse {
+	var pages [][]*v1beta2.StreamedListObjectsResponse
+	id := 0
+	for i, size := range sizes {
+		token := ""
+		if i < len(sizes)-1 {
+			token = "page-" + strconv.Itoa(i+2)
+		}
+		var page []*v1beta2.StreamedListObjectsResponse
+		for j := 0; j < size; j++ {
+			page = append(page, &v1beta2.StreamedListObjectsResponse{
+				Object:     &v1beta2.ResourceReference{ResourceId: strconv.Itoa(id)},
+				Pagination: &v1beta2.ResponsePagination{ContinuationToken: token},
+			})
+			id++
+		}
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+func objectIds(objects []*v1beta2.StreamedListObjectsResponse) []string {
+	var ids []string
+	for _, object := range objects {
+		ids = append(ids, object.GetObject().GetResourceId())
+	}
+	return ids
+}
+
+func TestCollectObjects(t *testing.T) {
+	tests := []struct {
+		name             string
+		pages            [][]*v1beta2.StreamedListObjectsResponse
+		opts             []CollectObjectsOption
+		expectedIds      []string
+		expectedRequests int
+	}{
+		{
+			name:             "all pages",
+			pages:            objectPages(2, 1),
+			expectedIds:      []string{"0", "1", "2"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "max items stops mid-page",
+			pages:            objectPages(2, 2),
+			opts:             []CollectObjectsOption{WithMaxItems(3)},
+			expectedIds:      []string{"0", "1", "2"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "max items on page boundary",
+			pages:            objectPages(2, 2),
+			opts:             []CollectObjectsOption{WithMaxItems(2)},
+			expectedIds:      []string{"0", "1"},
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockListObjectsClient{pages: tt.pages}
+			request := &v1beta2.StreamedListObjectsRequest{Relation: "member"}
+
+			objects, err := CollectObjects(context.Background(), client, request, tt.opts...)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIds, objectIds(objects))
+			assert.Len(t, client.capturedRequests, tt.expectedRequests)
+			assert.Nil(t, request.GetPagination(), "the caller's request must not be modified")
+		})
+	}
+}
+
+func TestCollectObjects_pagination(t *testing.T) {
+	client := &mockListObjectsClient{pages: objectPages(1, 1)}
+
+	_, err := CollectObjects(context.Background(), client, &v1beta2.StreamedListObjectsRequest{}, WithPageSize(50))
+
+	require.NoError(t, err)
+	require.Len(t, client.capturedRequests, 2)
+	assert.Equal(t, uint32(50), client.capturedRequests[0].GetPagination().GetLimit())
+	assert.Nil(t, client.capturedRequests[0].GetPagination().ContinuationToken)
+	assert.Equal(t, uint32(50), client.capturedRequests[1].GetPagination().GetLimit())
+	assert.Equal(t, "page-2", client.capturedRequests[1].GetPagination().GetContinuationToken())
+}
+
+func TestCollectObjects_pageCallback(t *testing.T) {
+	client := &mockListObjectsClient{pages: objectPages(2, 1)}
+
+	var pages [][]string
+	objects, err := CollectObjects(context.Background(), client, &v1beta2.StreamedListObjectsRequest{},
+		WithPageCallback(func(page []*v1beta2.StreamedListObjectsResponse) error {
+			pages = append(pages, objectIds(page))
+			return nil
+		}))
+
+	require.NoError(t, err)
+	assert.Nil(t, objects)
+	assert.Equal(t, [][]string{{"0", "1"}, {"2"}}, pages)
+}
+
+func TestCollectObjects_errors(t *testing.T) {
+	callbackErr := errors.New("stop")
+
+	tests := []struct {
+		name          string
+		client        *mockListObjectsClient
+		opts          []CollectObjectsOption
+		expectedError error
+	}{
+		{
+			name:   "stream error",
+			client: &mockListObjectsClient{err: errors.New("unavailable")},
+		},
+		{
+			name:   "callback error",
+			client: &mockListObjectsClient{pages: objectPages(1, 1)},
+			opts: []CollectObjectsOption{WithPageCallback(func([]*v1beta2.StreamedListObjectsResponse) error {
+				return callbackErr
+			})},
+			expectedError: callbackErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, err := CollectObjects(context.Background(), tt.client, &v1beta2.StreamedListObjectsRequest{}, tt.opts...)
+
+			require.Error(t, err)
+			assert.Nil(t, objects)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestStreamObjects(t *testing.T) {
+	client := &mockListObjectsClient{pages: objectPages(2, 2)}
+
+	var ids []string
+	for result := range StreamObjects(context.Background(), client, &v1beta2.StreamedListObjectsRequest{}, WithBufferSize(1)) {
+		require.NoError(t, result.Err)
+		ids = append(ids, result.Object.GetObject().GetResourceId())
+	}
+
+	assert.Equal(t, []string{"0", "1", "2", "3"}, ids)
+}
+
+func TestStreamObjects_error(t *testing.T) {
+	client := &mockListObjectsClient{err: errors.New("unavailable")}
+
+	var results []ObjectResult
+	for result := range StreamObjects(context.Background(), client, &v1beta2.StreamedListObjectsRequest{}) {
+		results = append(results, result)
+	}
+
+	require.Len(t, results, 1)
+	assert.ErrorContains(t, results[0].Err, "failed to start stream")
+}
+
+func TestStreamObjects_cancel(t *testing.T) {
+	client := &mockListObjectsClient{pages: objectPages(3)}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := StreamObjects(ctx, client, &v1beta2.StreamedListObjectsRequest{})
+	first := <-results
+	require.NoError(t, first.Err)
+	cancel()
+
+	// The channel must close once the consumer cancels, even though objects
+	// remain unsent.
+	for range results {
+	}
+}
+
+func TestCollectObjects_resume(t *testing.T) {
+	unavailable := grpcstatus.Error(codes.Unavailable, "server restarting")
+	pages := objectPages(2, 2)
+	// The first stream breaks after one object, whose token resumes the page.
+	pages[0][0].Pagination.ContinuationToken = "after-0"
+	client := &mockListObjectsClient{
+		pages:      [][]*v1beta2.StreamedListObjectsResponse{pages[0][:1], pages[0][1:], pages[1]},
+		streamErrs: []error{unavailable},
+	}
+
+	objects, err := CollectObjects(context.Background(), client, &v1beta2.StreamedListObjectsRequest{Relation: "view"}, WithResume(1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2", "3"}, objectIds(objects))
+	require.Len(t, client.capturedRequests, 3)
+	assert.Equal(t, "after-0", client.capturedRequests[1].GetPagination().GetContinuationToken())
+	assert.Equal(t, "view", client.capturedRequests[1].GetRelation())
+	assert.Equal(t, "page-2", client.capturedRequests[2].GetPagination().GetContinuationToken())
+}
+
+func TestCollectObjects_resumeExhausted(t *testing.T) {
+	unavailable := grpcstatus.Error(codes.Unavailable, "server restarting")
+	client := &mockListObjectsClient{
+		pages:      [][]*v1beta2.StreamedListObjectsResponse{nil, nil},
+		streamErrs: []error{unavailable, unavailable},
+	}
+
+	_, err := CollectObjects(context.Background(), client, &v1beta2.StreamedListObjectsRequest{}, WithResume(1))
+	assert.Equal(t, codes.Unavailable, grpcstatus.Code(err))
+	assert.Len(t, client.capturedRequests, 2)
+}
+
+func TestCollectObjects_progress(t *testing.T) {
+	client := &mockListObjectsClient{pages: objectPages(2, 2, 1)}
+
+	var progress []ListProgress
+	_, err := CollectObjects(context.Background(), client, &v1beta2.StreamedListObjectsRequest{},
+		WithProgress(func(p ListProgress) {
+			progress = append(progress, p)
+		}))
+
+	require.NoError(t, err)
+	require.Len(t, progress, 3)
+	for i, expected := range []ListProgress{
+		{Pages: 1, Items: 2, ContinuationToken: "page-2"},
+		{Pages: 2, Items: 4, ContinuationToken: "page-3"},
+		{Pages: 3, Items: 5},
+	} {
+		assert.Positive(t, progress[i].Elapsed)
+		progress[i].Elapsed = 0
+		assert.Equal(t, expected, progress[i])
+	}
+}
+
+func TestCollectObjects_progressStopsAtMaxItems(t *testing.T) {
+	pages := objectPages(3, 1)
+	pages[0][1].Pagination.ContinuationToken = "after-1"
+	client := &mockListObjectsClient{pages: pages}
+
+	var last ListProgress
+	_, err := CollectObjects(context.Background(), client, &v1beta2.StreamedListObjectsRequest{},
+		WithMaxItems(2), WithProgress(func(p ListProgress) { last = p }))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, last.Items)
+	assert.Equal(t, "after-1", last.ContinuationToken, "the token resumes after the last delivered object")
+}
+
+func TestCollectObjects_pageRateLimit(t *testing.T) {
+	client := &mockListObjectsClient{pages: objectPages(1, 1, 1)}
+	// One token per hour: only the first page may be requested.
+	limiter := ratelimit.NewLimiter(1.0/3600, 1)
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := CollectObjects(ctx, client, &v1beta2.StreamedListObjectsRequest{}, WithPageRateLimit(limiter))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, client.capturedRequests, 1)
+}
@@ -0,0 +1,44 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/callmetadata"
+	"google.golang.org/grpc/metadata"
+)
+
+// WithCallMetadata returns a copy of ctx carrying md. Clients built with the
+// SDK ClientBuilder send it as gRPC metadata, and the RBAC helpers send it as
+// HTTP headers, on every call made with the returned context. Calling it again
+// on the returned context adds to the existing metadata.
+//
+//	ctx = inventory.WithCallMetadata(ctx, metadata.Pairs("x-rh-insights-request-id", requestId))
+func WithCallMetadata(ctx context.Context, md metadata.MD) context.Context {
+	return callmetadata.NewContext(ctx, md)
+}
+
+// CallMetadataFromContext returns the metadata set with WithCallMetadata, or nil.
+func CallMetadataFromContext(ctx context.Context) metadata.MD {
+	return callmetadata.FromContext(ctx)
+}
+
+// RequestIdKey is the metadata key and HTTP header used for request IDs.
+const RequestIdKey = callmetadata.RequestIdKey
+
+// WithRequestId returns a copy of ctx whose calls carry id as their
+// x-rh-insights-request-id, replacing any request ID already set. Pass on the
+// ID of the incoming request so traces can be stitched across services.
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return callmetadata.WithRequestId(ctx, id)
+}
+
+// RequestIdFromContext returns the request ID set on ctx, or "".
+func RequestIdFromContext(ctx context.Context) string {
+	return callmetadata.RequestId(ctx)
+}
+
+// EnsureRequestId returns ctx and its request ID, generating a random ID when
+// ctx has none. Use it to log the ID before making calls.
+func EnsureRequestId(ctx context.Context) (context.Context, string) {
+	return callmetadata.EnsureRequestId(ctx)
+}
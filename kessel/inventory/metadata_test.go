@@ -0,0 +1,22 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithCallMetadata(t *testing.T) {
+	ctx := WithCallMetadata(context.Background(), metadata.Pairs("x-org-id", "org1"))
+	ctx = WithCallMetadata(ctx, metadata.Pairs("x-request-id", "req1"))
+
+	md := CallMetadataFromContext(ctx)
+	assert.Equal(t, []string{"org1"}, md.Get("x-org-id"))
+	assert.Equal(t, []string{"req1"}, md.Get("x-request-id"))
+}
+
+func TestCallMetadataFromContext_empty(t *testing.T) {
+	assert.Nil(t, CallMetadataFromContext(context.Background()))
+}
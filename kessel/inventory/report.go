@@ -0,0 +1,138 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// BulkOptions configures BulkReport.
+type BulkOptions struct {
+	// Maximum number of reports in flight. Values below 1 are treated as 1.
+	Concurrency int
+	// Called after each report completes with the totals so far. Calls are
+	// serialized, so the callback does not need its own locking, but it
+	// should return quickly because it holds up the other workers.
+	OnProgress func(BulkStats)
+	// Stops starting new reports after the first failure. Reports already
+	// in flight run to completion; the rest are counted as skipped.
+	StopOnError bool
+}
+
+// BulkStats summarizes a BulkReport run.
+type BulkStats struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	Skipped   int
+	Elapsed   time.Duration
+}
+
+// Completed returns the number of reports that have finished, successfully
+// or not.
+func (s BulkStats) Completed() int {
+	return s.Succeeded + s.Failed
+}
+
+// Throughput returns completed reports per second.
+func (s BulkStats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Completed()) / s.Elapsed.Seconds()
+}
+
+// BulkReport reports every resource in requests using a pool of
+// options.Concurrency workers, for seeding and migration jobs that report tens
+// of thousands of resources. It returns the final stats together with a
+// *errors.Multi of the failures, whose items carry the index of the request
+// in requests. If ctx ends, the reports that have not started yet fail with
+// the context error.
+func BulkReport(
+	ctx context.Context,
+	client v1beta2.KesselInventoryServiceClient,
+	requests []*v1beta2.ReportResourceRequest,
+	options BulkOptions,
+) (BulkStats, error) {
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	start := time.Now()
+	stats := BulkStats{Total: len(requests)}
+	errs := make([]error, len(requests))
+	var mu sync.Mutex
+	stopped := false
+
+	record := func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			stats.Failed++
+			errs[i] = err
+			stopped = stopped || options.StopOnError
+		} else {
+			stats.Succeeded++
+		}
+		stats.Elapsed = time.Since(start)
+		if options.OnProgress != nil {
+			options.OnProgress(stats)
+		}
+	}
+	isStopped := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return stopped
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(requests)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if isStopped() {
+					continue
+				}
+				_, err := client.ReportResource(ctx, requests[i])
+				record(i, err)
+			}
+		}()
+	}
+
+	for i := range requests {
+		if isStopped() {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			record(i, err)
+			continue
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			record(i, ctx.Err())
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	stats.Skipped = stats.Total - stats.Completed()
+	stats.Elapsed = time.Since(start)
+
+	result := &kesselerrors.Multi{}
+	for i, err := range errs {
+		result.Append(i, reportKey(requests[i]), err)
+	}
+	return stats, result.ErrorOrNil()
+}
+
+func reportKey(request *v1beta2.ReportResourceRequest) string {
+	return fmt.Sprintf("%s/%s", request.GetType(), request.GetRepresentations().GetMetadata().GetLocalResourceId())
+}
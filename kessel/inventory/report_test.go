@@ -0,0 +1,175 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type mockReportClient struct {
+	v1beta2.KesselInventoryServiceClient
+	failIDs     map[string]bool
+	reported    atomic.Int32
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	block       chan struct{}
+}
+
+func (m *mockReportClient) ReportResource(ctx context.Context, in *v1beta2.ReportResourceRequest, opts ...grpc.CallOption) (*v1beta2.ReportResourceResponse, error) {
+	current := m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+	for {
+		max := m.maxInFlight.Load()
+		if current <= max || m.maxInFlight.CompareAndSwap(max, current) {
+			break
+		}
+	}
+	if m.block != nil {
+		<-m.block
+	}
+
+	if m.failIDs[in.GetRepresentations().GetMetadata().GetLocalResourceId()] {
+		return nil, status.Error(codes.InvalidArgument, "invalid")
+	}
+	m.reported.Add(1)
+	return &v1beta2.ReportResourceResponse{}, nil
+}
+
+func hostReport(id string) *v1beta2.ReportResourceRequest {
+	return &v1beta2.ReportResourceRequest{
+		Type:         "host",
+		ReporterType: "hbi",
+		Representations: &v1beta2.ResourceRepresentations{
+			Metadata: &v1beta2.RepresentationMetadata{LocalResourceId: id},
+		},
+	}
+}
+
+func hostReports(ids ...string) []*v1beta2.ReportResourceRequest {
+	requests := make([]*v1beta2.ReportResourceRequest, len(ids))
+	for i, id := range ids {
+		requests[i] = hostReport(id)
+	}
+	return requests
+}
+
+func TestBulkReport(t *testing.T) {
+	tests := []struct {
+		name           string
+		ids            []string
+		failIDs        map[string]bool
+		options        BulkOptions
+		expectedStats  BulkStats
+		expectedFailed []int
+	}{
+		{
+			name:          "reports all requests",
+			ids:           []string{"a", "b", "c"},
+			options:       BulkOptions{Concurrency: 2},
+			expectedStats: BulkStats{Total: 3, Succeeded: 3},
+		},
+		{
+			name:           "aggregates per-item errors in input order",
+			ids:            []string{"a", "b", "c", "d"},
+			failIDs:        map[string]bool{"b": true, "d": true},
+			options:        BulkOptions{Concurrency: 4},
+			expectedStats:  BulkStats{Total: 4, Succeeded: 2, Failed: 2},
+			expectedFailed: []int{1, 3},
+		},
+		{
+			name:           "stop on error skips the rest",
+			ids:            []string{"a", "b", "c", "d"},
+			failIDs:        map[string]bool{"b": true},
+			options:        BulkOptions{StopOnError: true},
+			expectedStats:  BulkStats{Total: 4, Succeeded: 1, Failed: 1, Skipped: 2},
+			expectedFailed: []int{1},
+		},
+		{
+			name:          "empty input",
+			options:       BulkOptions{Concurrency: 2},
+			expectedStats: BulkStats{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockReportClient{failIDs: tt.failIDs}
+
+			stats, err := BulkReport(context.Background(), client, hostReports(tt.ids...), tt.options)
+			assert.Equal(t, tt.expectedStats.Total, stats.Total)
+			assert.Equal(t, tt.expectedStats.Succeeded, stats.Succeeded)
+			assert.Equal(t, tt.expectedStats.Failed, stats.Failed)
+			assert.Equal(t, tt.expectedStats.Skipped, stats.Skipped)
+
+			if len(tt.expectedFailed) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var multi *kesselerrors.Multi
+			require.True(t, errors.As(err, &multi))
+			require.Equal(t, len(tt.expectedFailed), multi.Len())
+			for i, index := range tt.expectedFailed {
+				assert.Equal(t, index, multi.Errors[i].Index)
+				assert.Equal(t, "host/"+tt.ids[index], multi.Errors[i].Key)
+				assert.Equal(t, codes.InvalidArgument, status.Code(multi.Errors[i].Err))
+			}
+		})
+	}
+}
+
+func TestBulkReport_boundsConcurrency(t *testing.T) {
+	client := &mockReportClient{block: make(chan struct{})}
+	requests := hostReports("a", "b", "c", "d", "e", "f", "g", "h", "i", "j")
+
+	done := make(chan error)
+	go func() {
+		_, err := BulkReport(context.Background(), client, requests, BulkOptions{Concurrency: 3})
+		done <- err
+	}()
+	for range requests {
+		client.block <- struct{}{}
+	}
+
+	require.NoError(t, <-done)
+	assert.LessOrEqual(t, client.maxInFlight.Load(), int32(3))
+}
+
+func TestBulkReport_progress(t *testing.T) {
+	var progress []BulkStats
+	stats, err := BulkReport(context.Background(), &mockReportClient{}, hostReports("a", "b", "c"), BulkOptions{
+		Concurrency: 2,
+		OnProgress:  func(stats BulkStats) { progress = append(progress, stats) },
+	})
+	require.NoError(t, err)
+
+	require.Len(t, progress, 3)
+	for i, p := range progress {
+		assert.Equal(t, i+1, p.Completed())
+		assert.Equal(t, 3, p.Total)
+	}
+	assert.Equal(t, 3, stats.Completed())
+	assert.Greater(t, stats.Throughput(), 0.0)
+}
+
+func TestBulkReport_cancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := &mockReportClient{}
+	stats, err := BulkReport(ctx, client, hostReports("a", "b"), BulkOptions{Concurrency: 1})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, stats.Failed)
+	assert.Zero(t, client.reported.Load())
+}
@@ -0,0 +1,79 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultWaitPollInterval = 100 * time.Millisecond
+	defaultWaitTimeout      = 30 * time.Second
+)
+
+type WaitOptions struct {
+	// How often the probe is checked. Defaults to 100ms.
+	PollInterval time.Duration
+	// How long to wait for the probe to be allowed. Defaults to 30s.
+	Timeout time.Duration
+}
+
+// ReportResourceAndWait reports a resource and then polls probe until it is
+// allowed, returning how long the change took to become visible. It is meant
+// for migration and smoke-test tooling that needs to observe propagation.
+//
+// ReportResourceResponse carries no consistency token, so visibility is
+// observed with CheckForUpdate, which always evaluates against the latest
+// state. NotFound responses are treated as not yet visible; any other probe
+// error is returned immediately. If the probe is not allowed within the
+// timeout, the context error is returned along with the elapsed time.
+func ReportResourceAndWait(
+	ctx context.Context,
+	client v1beta2.KesselInventoryServiceClient,
+	request *v1beta2.ReportResourceRequest,
+	probe *v1beta2.CheckForUpdateRequest,
+	options WaitOptions,
+) (time.Duration, error) {
+	pollInterval := options.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultWaitPollInterval
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
+	}
+
+	if _, err := client.ReportResource(ctx, request); err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		response, err := client.CheckForUpdate(ctx, probe)
+		if err == nil && response.GetAllowed() == v1beta2.Allowed_ALLOWED_TRUE {
+			return time.Since(start), nil
+		}
+		if err != nil && status.Code(err) != codes.NotFound {
+			if ctx.Err() != nil {
+				return time.Since(start), fmt.Errorf("relation not visible after %s: %w", time.Since(start), ctx.Err())
+			}
+			return time.Since(start), err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return time.Since(start), fmt.Errorf("relation not visible after %s: %w", time.Since(start), ctx.Err())
+		}
+	}
+}
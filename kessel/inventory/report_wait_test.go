@@ -0,0 +1,121 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type mockReportWaitClient struct {
+	v1beta2.KesselInventoryServiceClient
+	reportErr    error
+	visibleAfter int
+	probeErrs    []error
+	reports      int
+	probes       int
+}
+
+func (m *mockReportWaitClient) ReportResource(ctx context.Context, in *v1beta2.ReportResourceRequest, opts ...grpc.CallOption) (*v1beta2.ReportResourceResponse, error) {
+	m.reports++
+	if m.reportErr != nil {
+		return nil, m.reportErr
+	}
+	return &v1beta2.ReportResourceResponse{}, nil
+}
+
+func (m *mockReportWaitClient) CheckForUpdate(ctx context.Context, in *v1beta2.CheckForUpdateRequest, opts ...grpc.CallOption) (*v1beta2.CheckForUpdateResponse, error) {
+	m.probes++
+	if m.probes <= len(m.probeErrs) && m.probeErrs[m.probes-1] != nil {
+		return nil, m.probeErrs[m.probes-1]
+	}
+	if m.visibleAfter >= 0 && m.probes > m.visibleAfter {
+		return &v1beta2.CheckForUpdateResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}, nil
+	}
+	return &v1beta2.CheckForUpdateResponse{Allowed: v1beta2.Allowed_ALLOWED_FALSE}, nil
+}
+
+func TestReportResourceAndWait(t *testing.T) {
+	tests := []struct {
+		name           string
+		client         *mockReportWaitClient
+		expectedError  bool
+		expectedCode   codes.Code
+		expectedProbes int
+	}{
+		{
+			name:           "visible immediately",
+			client:         &mockReportWaitClient{visibleAfter: 0},
+			expectedProbes: 1,
+		},
+		{
+			name:           "visible after polling",
+			client:         &mockReportWaitClient{visibleAfter: 2},
+			expectedProbes: 3,
+		},
+		{
+			name: "not found is retried",
+			client: &mockReportWaitClient{
+				visibleAfter: 1,
+				probeErrs:    []error{status.Error(codes.NotFound, "not yet")},
+			},
+			expectedProbes: 2,
+		},
+		{
+			name:           "report error stops before probing",
+			client:         &mockReportWaitClient{reportErr: status.Error(codes.InvalidArgument, "bad")},
+			expectedError:  true,
+			expectedCode:   codes.InvalidArgument,
+			expectedProbes: 0,
+		},
+		{
+			name: "probe error is returned",
+			client: &mockReportWaitClient{
+				visibleAfter: -1,
+				probeErrs:    []error{status.Error(codes.PermissionDenied, "no")},
+			},
+			expectedError:  true,
+			expectedCode:   codes.PermissionDenied,
+			expectedProbes: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elapsed, err := ReportResourceAndWait(context.Background(), tt.client, &v1beta2.ReportResourceRequest{}, &v1beta2.CheckForUpdateRequest{}, WaitOptions{
+				PollInterval: time.Millisecond,
+				Timeout:      time.Second,
+			})
+
+			assert.Equal(t, 1, tt.client.reports)
+			assert.Equal(t, tt.expectedProbes, tt.client.probes)
+			if tt.expectedError {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, elapsed, time.Duration(0))
+		})
+	}
+}
+
+func TestReportResourceAndWait_timeout(t *testing.T) {
+	client := &mockReportWaitClient{visibleAfter: -1}
+
+	elapsed, err := ReportResourceAndWait(context.Background(), client, &v1beta2.ReportResourceRequest{}, &v1beta2.CheckForUpdateRequest{}, WaitOptions{
+		PollInterval: time.Millisecond,
+		Timeout:      20 * time.Millisecond,
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, elapsed, 20*time.Millisecond)
+	assert.Greater(t, client.probes, 1)
+}
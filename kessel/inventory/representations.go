@@ -0,0 +1,86 @@
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// HostCommon is the common representation of an HBI host.
+type HostCommon struct {
+	WorkspaceId string `json:"workspace_id"`
+}
+
+// HostReporter is the HBI reporter representation of a host.
+type HostReporter struct {
+	SatelliteId         string `json:"satellite_id,omitempty"`
+	SubManagerId        string `json:"sub_manager_id,omitempty"`
+	InsightsInventoryId string `json:"insights_inventory_id,omitempty"`
+	AnsibleHost         string `json:"ansible_host,omitempty"`
+}
+
+// WorkspaceReporter is the RBAC reporter representation of a workspace.
+type WorkspaceReporter struct {
+	Name     string `json:"name,omitempty"`
+	ParentId string `json:"parent_id,omitempty"`
+}
+
+// ToStruct converts v to a Struct for ResourceRepresentations. v is either a
+// map[string]any of JSON-compatible values or a value that encodes to a JSON
+// object, such as HostCommon or any struct with json tags.
+func ToStruct(v any) (*structpb.Struct, error) {
+	if fields, ok := v.(map[string]any); ok {
+		return structpb.NewStruct(fields)
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("representation must encode to a JSON object: %w", err)
+	}
+	return structpb.NewStruct(fields)
+}
+
+// FromStruct decodes s into v, which must be a pointer as for
+// json.Unmarshal. A nil Struct leaves v unchanged.
+func FromStruct(s *structpb.Struct, v any) error {
+	if s == nil {
+		return nil
+	}
+	encoded, err := s.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, v)
+}
+
+// NewRepresentations builds ResourceRepresentations from typed common and
+// reporter values, converting each with ToStruct. A nil common or reporter
+// leaves that representation unset.
+//
+//	representations, err := inventory.NewRepresentations(metadata,
+//		inventory.HostCommon{WorkspaceId: workspaceId},
+//		inventory.HostReporter{InsightsInventoryId: id})
+func NewRepresentations(metadata *v1beta2.RepresentationMetadata, common any, reporter any) (*v1beta2.ResourceRepresentations, error) {
+	representations := &v1beta2.ResourceRepresentations{Metadata: metadata}
+	if common != nil {
+		s, err := ToStruct(common)
+		if err != nil {
+			return nil, fmt.Errorf("common representation: %w", err)
+		}
+		representations.Common = s
+	}
+	if reporter != nil {
+		s, err := ToStruct(reporter)
+		if err != nil {
+			return nil, fmt.Errorf("reporter representation: %w", err)
+		}
+		representations.Reporter = s
+	}
+	return representations, nil
+}
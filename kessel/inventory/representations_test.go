@@ -0,0 +1,78 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+func TestToStruct(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         any
+		expected      map[string]any
+		expectedError bool
+	}{
+		{
+			name:     "map",
+			value:    map[string]any{"workspace_id": "ws-1", "count": 2},
+			expected: map[string]any{"workspace_id": "ws-1", "count": 2.0},
+		},
+		{
+			name:     "typed struct omits empty fields",
+			value:    HostReporter{InsightsInventoryId: "inv-1", AnsibleHost: "host-1"},
+			expected: map[string]any{"insights_inventory_id": "inv-1", "ansible_host": "host-1"},
+		},
+		{
+			name:     "pointer to struct",
+			value:    &HostCommon{WorkspaceId: "ws-1"},
+			expected: map[string]any{"workspace_id": "ws-1"},
+		},
+		{name: "not an object", value: []string{"a"}, expectedError: true},
+		{name: "unsupported value", value: map[string]any{"c": make(chan int)}, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ToStruct(tt.value)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, s.AsMap())
+		})
+	}
+}
+
+func TestFromStruct(t *testing.T) {
+	s, err := structpb.NewStruct(map[string]any{"name": "Engineering", "parent_id": "ws-root", "extra": true})
+	require.NoError(t, err)
+
+	var workspace WorkspaceReporter
+	require.NoError(t, FromStruct(s, &workspace))
+	assert.Equal(t, WorkspaceReporter{Name: "Engineering", ParentId: "ws-root"}, workspace)
+
+	unchanged := HostCommon{WorkspaceId: "ws-1"}
+	require.NoError(t, FromStruct(nil, &unchanged))
+	assert.Equal(t, "ws-1", unchanged.WorkspaceId)
+
+	assert.Error(t, FromStruct(s, workspace))
+}
+
+func TestNewRepresentations(t *testing.T) {
+	metadata := &v1beta2.RepresentationMetadata{LocalResourceId: "host-1"}
+	representations, err := NewRepresentations(metadata, HostCommon{WorkspaceId: "ws-1"}, nil)
+	require.NoError(t, err)
+
+	assert.Same(t, metadata, representations.Metadata)
+	assert.Equal(t, "ws-1", representations.GetCommon().GetFields()["workspace_id"].GetStringValue())
+	assert.Nil(t, representations.Reporter)
+
+	_, err = NewRepresentations(metadata, nil, "not an object")
+	assert.ErrorContains(t, err, "reporter representation")
+}
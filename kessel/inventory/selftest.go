@@ -0,0 +1,113 @@
+package inventory
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/grpc"
+)
+
+// Self-test stage names, in the order they run.
+const (
+	StageDiscovery  = "discovery"
+	StageToken      = "token"
+	StageConnection = "connection"
+	StageCheck      = "check"
+)
+
+type SelfTestOptions struct {
+	// OIDC issuer to run discovery against. Discovery is skipped when empty.
+	IssuerUrl string
+	// Credentials to get a token from. A cached token that has not expired is
+	// reused, so frequent probes do not each call the token endpoint. The
+	// token stage is skipped when nil.
+	Credentials *auth.OAuth2ClientCredentials
+	// Optionally specify an http.Client or use http.DefaultClient
+	HttpClient *http.Client
+	// Connection returned by the client builder. The connection stage is
+	// skipped when nil.
+	Conn *grpc.ClientConn
+	// Client used for the sample check. The check stage is skipped when nil.
+	Client v1beta2.KesselInventoryServiceClient
+}
+
+// StageResult is the outcome of one self-test stage.
+type StageResult struct {
+	Stage    string
+	Skipped  bool
+	Duration time.Duration
+	// Human-readable outcome, e.g. the discovered token endpoint.
+	Detail string
+	Err    error
+}
+
+// SelfTestReport holds one StageResult per stage, in the order they ran.
+type SelfTestReport struct {
+	Stages []StageResult
+}
+
+// Ok reports whether no stage failed.
+func (r SelfTestReport) Ok() bool {
+	return r.Err() == nil
+}
+
+// Err returns a *kesselerrors.Multi with one entry per failed stage, keyed by
+// stage name, or nil when no stage failed.
+func (r SelfTestReport) Err() error {
+	var errs kesselerrors.Multi
+	for i, stage := range r.Stages {
+		errs.Append(i, stage.Stage, stage.Err)
+	}
+	return errs.ErrorOrNil()
+}
+
+// SelfTest verifies that the SDK can reach Kessel end to end: OIDC discovery,
+// getting a token, the gRPC connection becoming ready, and sampleCheck.
+// Stages that are not configured are reported as skipped, and the check is
+// skipped when the connection stage failed. The connection stage waits like
+// WaitForReady, through TRANSIENT_FAILURE, so give ctx a deadline. The report
+// is suitable for readiness probes and canary jobs; it never returns early on
+// failure, so every configured stage is reported.
+func SelfTest(ctx context.Context, sampleCheck *v1beta2.CheckRequest, options SelfTestOptions) SelfTestReport {
+	var report SelfTestReport
+
+	report.Stages = append(report.Stages, runStage(StageDiscovery, options.IssuerUrl == "", func() (string, error) {
+		metadata, err := auth.FetchOIDCDiscovery(ctx, options.IssuerUrl, auth.FetchOIDCDiscoveryOptions{HttpClient: options.HttpClient})
+		return "token endpoint " + metadata.TokenEndpoint, err
+	}))
+
+	report.Stages = append(report.Stages, runStage(StageToken, options.Credentials == nil, func() (string, error) {
+		token, err := options.Credentials.GetToken(ctx, auth.GetTokenOptions{HttpClient: options.HttpClient})
+		return "expires at " + token.ExpiresAt.Format(time.RFC3339), err
+	}))
+
+	connection := runStage(StageConnection, options.Conn == nil, func() (string, error) {
+		return options.Conn.Target(), WaitForReady(ctx, options.Conn)
+	})
+	report.Stages = append(report.Stages, connection)
+
+	skipCheck := options.Client == nil || sampleCheck == nil || connection.Err != nil
+	report.Stages = append(report.Stages, runStage(StageCheck, skipCheck, func() (string, error) {
+		response, err := options.Client.Check(ctx, sampleCheck)
+		return response.GetAllowed().String(), err
+	}))
+
+	return report
+}
+
+func runStage(stage string, skip bool, run func() (string, error)) StageResult {
+	if skip {
+		return StageResult{Stage: stage, Skipped: true}
+	}
+	start := time.Now()
+	detail, err := run()
+	result := StageResult{Stage: stage, Duration: time.Since(start), Err: err}
+	if err == nil {
+		result.Detail = detail
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type mockSelfTestClient struct {
+	v1beta2.KesselInventoryServiceClient
+	err error
+}
+
+func (m *mockSelfTestClient) Check(ctx context.Context, in *v1beta2.CheckRequest, opts ...grpc.CallOption) (*v1beta2.CheckResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}, nil
+}
+
+func newOIDCServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return newCountingOIDCServer(t, new(atomic.Int32))
+}
+
+// newCountingOIDCServer counts the token requests it answers in tokenRequests.
+func newCountingOIDCServer(t *testing.T, tokenRequests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{"issuer": server.URL, "token_endpoint": server.URL + "/token"})
+		case "/token":
+			tokenRequests.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token", "token_type": "Bearer", "expires_in": 300})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newReadyConn(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func stageByName(report SelfTestReport, name string) StageResult {
+	for _, stage := range report.Stages {
+		if stage.Stage == name {
+			return stage
+		}
+	}
+	return StageResult{}
+}
+
+func TestSelfTest_allStagesPass(t *testing.T) {
+	server := newOIDCServer(t)
+	credentials := auth.NewOAuth2ClientCredentials("id", "secret", server.URL+"/token")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	report := SelfTest(ctx, &v1beta2.CheckRequest{}, SelfTestOptions{
+		IssuerUrl:   server.URL,
+		Credentials: &credentials,
+		Conn:        newReadyConn(t),
+		Client:      &mockSelfTestClient{},
+	})
+
+	require.Len(t, report.Stages, 4)
+	assert.True(t, report.Ok(), "unexpected error: %v", report.Err())
+	for _, stage := range report.Stages {
+		assert.False(t, stage.Skipped, stage.Stage)
+		assert.NotEmpty(t, stage.Detail, stage.Stage)
+	}
+	assert.Contains(t, stageByName(report, StageDiscovery).Detail, server.URL+"/token")
+	assert.Equal(t, "ALLOWED_TRUE", stageByName(report, StageCheck).Detail)
+}
+
+func TestSelfTest_skipsUnconfiguredStages(t *testing.T) {
+	report := SelfTest(context.Background(), nil, SelfTestOptions{})
+
+	require.Len(t, report.Stages, 4)
+	assert.True(t, report.Ok())
+	for _, stage := range report.Stages {
+		assert.True(t, stage.Skipped, stage.Stage)
+	}
+}
+
+func TestSelfTest_reportsFailures(t *testing.T) {
+	checkErr := errors.New("check failed")
+	report := SelfTest(context.Background(), &v1beta2.CheckRequest{}, SelfTestOptions{
+		IssuerUrl: "http://127.0.0.1:0",
+		Client:    &mockSelfTestClient{err: checkErr},
+	})
+
+	assert.False(t, report.Ok())
+	assert.Error(t, stageByName(report, StageDiscovery).Err)
+	assert.ErrorIs(t, stageByName(report, StageCheck).Err, checkErr)
+	assert.ErrorIs(t, report.Err(), checkErr)
+}
+
+func TestSelfTest_skipsCheckWhenConnectionFails(t *testing.T) {
+	conn, err := grpc.NewClient("127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	report := SelfTest(ctx, &v1beta2.CheckRequest{}, SelfTestOptions{Conn: conn, Client: &mockSelfTestClient{}})
+
+	assert.ErrorIs(t, stageByName(report, StageConnection).Err, context.DeadlineExceeded)
+	assert.True(t, stageByName(report, StageCheck).Skipped)
+}
+
+func TestSelfTest_reusesCachedToken(t *testing.T) {
+	var tokenRequests atomic.Int32
+	server := newCountingOIDCServer(t, &tokenRequests)
+	credentials := auth.NewOAuth2ClientCredentials("id", "secret", server.URL+"/token")
+
+	for range 3 {
+		report := SelfTest(context.Background(), nil, SelfTestOptions{Credentials: &credentials})
+		require.True(t, report.Ok(), "unexpected error: %v", report.Err())
+	}
+	assert.Equal(t, int32(1), tokenRequests.Load())
+}
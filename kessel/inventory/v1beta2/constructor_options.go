@@ -0,0 +1,31 @@
+package v1beta2
+
+//go:generate go run ./internal/genconstructors
+
+// WithConsistencyMinimizeLatency lets the server answer from its fastest
+// available snapshot.
+func WithConsistencyMinimizeLatency() ConsistencyOption {
+	return WithConsistency(&Consistency{Requirement: &Consistency_MinimizeLatency{MinimizeLatency: true}})
+}
+
+// WithConsistencyAtLeastAsFresh requires an answer at least as fresh as token,
+// e.g. one returned by an earlier write or CheckForUpdate.
+func WithConsistencyAtLeastAsFresh(token *ConsistencyToken) ConsistencyOption {
+	return WithConsistency(&Consistency{Requirement: &Consistency_AtLeastAsFresh{AtLeastAsFresh: token}})
+}
+
+// WithConsistencyAtLeastAsAcknowledged requires an answer that reflects every
+// write the server has acknowledged.
+func WithConsistencyAtLeastAsAcknowledged() ConsistencyOption {
+	return WithConsistency(&Consistency{Requirement: &Consistency_AtLeastAsAcknowledged{AtLeastAsAcknowledged: true}})
+}
+
+// WithPage requests pages of at most limit results, continuing after
+// continuationToken when it is not empty.
+func WithPage(limit uint32, continuationToken string) PaginationOption {
+	pagination := &RequestPagination{Limit: limit}
+	if continuationToken != "" {
+		pagination.ContinuationToken = &continuationToken
+	}
+	return WithPagination(pagination)
+}
@@ -0,0 +1,505 @@
+// Code generated by genconstructors. DO NOT EDIT.
+
+package v1beta2
+
+// CheckRequestOption sets a field of a CheckRequest built by NewCheckRequest.
+type CheckRequestOption interface {
+	applyCheckRequest(*CheckRequest)
+}
+
+// NewCheckRequest returns a CheckRequest with options applied in order.
+func NewCheckRequest(options ...CheckRequestOption) *CheckRequest {
+	m := &CheckRequest{}
+	for _, option := range options {
+		option.applyCheckRequest(m)
+	}
+	return m
+}
+
+// CheckSelfRequestOption sets a field of a CheckSelfRequest built by NewCheckSelfRequest.
+type CheckSelfRequestOption interface {
+	applyCheckSelfRequest(*CheckSelfRequest)
+}
+
+// NewCheckSelfRequest returns a CheckSelfRequest with options applied in order.
+func NewCheckSelfRequest(options ...CheckSelfRequestOption) *CheckSelfRequest {
+	m := &CheckSelfRequest{}
+	for _, option := range options {
+		option.applyCheckSelfRequest(m)
+	}
+	return m
+}
+
+// CheckForUpdateRequestOption sets a field of a CheckForUpdateRequest built by NewCheckForUpdateRequest.
+type CheckForUpdateRequestOption interface {
+	applyCheckForUpdateRequest(*CheckForUpdateRequest)
+}
+
+// NewCheckForUpdateRequest returns a CheckForUpdateRequest with options applied in order.
+func NewCheckForUpdateRequest(options ...CheckForUpdateRequestOption) *CheckForUpdateRequest {
+	m := &CheckForUpdateRequest{}
+	for _, option := range options {
+		option.applyCheckForUpdateRequest(m)
+	}
+	return m
+}
+
+// CheckBulkRequestOption sets a field of a CheckBulkRequest built by NewCheckBulkRequest.
+type CheckBulkRequestOption interface {
+	applyCheckBulkRequest(*CheckBulkRequest)
+}
+
+// NewCheckBulkRequest returns a CheckBulkRequest with options applied in order.
+func NewCheckBulkRequest(options ...CheckBulkRequestOption) *CheckBulkRequest {
+	m := &CheckBulkRequest{}
+	for _, option := range options {
+		option.applyCheckBulkRequest(m)
+	}
+	return m
+}
+
+// CheckBulkRequestItemOption sets a field of a CheckBulkRequestItem built by NewCheckBulkRequestItem.
+type CheckBulkRequestItemOption interface {
+	applyCheckBulkRequestItem(*CheckBulkRequestItem)
+}
+
+// NewCheckBulkRequestItem returns a CheckBulkRequestItem with options applied in order.
+func NewCheckBulkRequestItem(options ...CheckBulkRequestItemOption) *CheckBulkRequestItem {
+	m := &CheckBulkRequestItem{}
+	for _, option := range options {
+		option.applyCheckBulkRequestItem(m)
+	}
+	return m
+}
+
+// CheckSelfBulkRequestOption sets a field of a CheckSelfBulkRequest built by NewCheckSelfBulkRequest.
+type CheckSelfBulkRequestOption interface {
+	applyCheckSelfBulkRequest(*CheckSelfBulkRequest)
+}
+
+// NewCheckSelfBulkRequest returns a CheckSelfBulkRequest with options applied in order.
+func NewCheckSelfBulkRequest(options ...CheckSelfBulkRequestOption) *CheckSelfBulkRequest {
+	m := &CheckSelfBulkRequest{}
+	for _, option := range options {
+		option.applyCheckSelfBulkRequest(m)
+	}
+	return m
+}
+
+// CheckSelfBulkRequestItemOption sets a field of a CheckSelfBulkRequestItem built by NewCheckSelfBulkRequestItem.
+type CheckSelfBulkRequestItemOption interface {
+	applyCheckSelfBulkRequestItem(*CheckSelfBulkRequestItem)
+}
+
+// NewCheckSelfBulkRequestItem returns a CheckSelfBulkRequestItem with options applied in order.
+func NewCheckSelfBulkRequestItem(options ...CheckSelfBulkRequestItemOption) *CheckSelfBulkRequestItem {
+	m := &CheckSelfBulkRequestItem{}
+	for _, option := range options {
+		option.applyCheckSelfBulkRequestItem(m)
+	}
+	return m
+}
+
+// CheckForUpdateBulkRequestOption sets a field of a CheckForUpdateBulkRequest built by NewCheckForUpdateBulkRequest.
+type CheckForUpdateBulkRequestOption interface {
+	applyCheckForUpdateBulkRequest(*CheckForUpdateBulkRequest)
+}
+
+// NewCheckForUpdateBulkRequest returns a CheckForUpdateBulkRequest with options applied in order.
+func NewCheckForUpdateBulkRequest(options ...CheckForUpdateBulkRequestOption) *CheckForUpdateBulkRequest {
+	m := &CheckForUpdateBulkRequest{}
+	for _, option := range options {
+		option.applyCheckForUpdateBulkRequest(m)
+	}
+	return m
+}
+
+// ReportResourceRequestOption sets a field of a ReportResourceRequest built by NewReportResourceRequest.
+type ReportResourceRequestOption interface {
+	applyReportResourceRequest(*ReportResourceRequest)
+}
+
+// NewReportResourceRequest returns a ReportResourceRequest with options applied in order.
+func NewReportResourceRequest(options ...ReportResourceRequestOption) *ReportResourceRequest {
+	m := &ReportResourceRequest{}
+	for _, option := range options {
+		option.applyReportResourceRequest(m)
+	}
+	return m
+}
+
+// DeleteResourceRequestOption sets a field of a DeleteResourceRequest built by NewDeleteResourceRequest.
+type DeleteResourceRequestOption interface {
+	applyDeleteResourceRequest(*DeleteResourceRequest)
+}
+
+// NewDeleteResourceRequest returns a DeleteResourceRequest with options applied in order.
+func NewDeleteResourceRequest(options ...DeleteResourceRequestOption) *DeleteResourceRequest {
+	m := &DeleteResourceRequest{}
+	for _, option := range options {
+		option.applyDeleteResourceRequest(m)
+	}
+	return m
+}
+
+// StreamedListObjectsRequestOption sets a field of a StreamedListObjectsRequest built by NewStreamedListObjectsRequest.
+type StreamedListObjectsRequestOption interface {
+	applyStreamedListObjectsRequest(*StreamedListObjectsRequest)
+}
+
+// NewStreamedListObjectsRequest returns a StreamedListObjectsRequest with options applied in order.
+func NewStreamedListObjectsRequest(options ...StreamedListObjectsRequestOption) *StreamedListObjectsRequest {
+	m := &StreamedListObjectsRequest{}
+	for _, option := range options {
+		option.applyStreamedListObjectsRequest(m)
+	}
+	return m
+}
+
+// StreamedListSubjectsRequestOption sets a field of a StreamedListSubjectsRequest built by NewStreamedListSubjectsRequest.
+type StreamedListSubjectsRequestOption interface {
+	applyStreamedListSubjectsRequest(*StreamedListSubjectsRequest)
+}
+
+// NewStreamedListSubjectsRequest returns a StreamedListSubjectsRequest with options applied in order.
+func NewStreamedListSubjectsRequest(options ...StreamedListSubjectsRequestOption) *StreamedListSubjectsRequest {
+	m := &StreamedListSubjectsRequest{}
+	for _, option := range options {
+		option.applyStreamedListSubjectsRequest(m)
+	}
+	return m
+}
+
+// ConsistencyOption sets the Consistency field. It is created by WithConsistency.
+type ConsistencyOption struct {
+	value *Consistency
+}
+
+// WithConsistency sets Consistency on CheckRequest, CheckSelfRequest, CheckBulkRequest, CheckSelfBulkRequest, StreamedListObjectsRequest, StreamedListSubjectsRequest.
+func WithConsistency(value *Consistency) ConsistencyOption {
+	return ConsistencyOption{value: value}
+}
+
+func (o ConsistencyOption) applyCheckRequest(m *CheckRequest) {
+	m.Consistency = o.value
+}
+
+func (o ConsistencyOption) applyCheckSelfRequest(m *CheckSelfRequest) {
+	m.Consistency = o.value
+}
+
+func (o ConsistencyOption) applyCheckBulkRequest(m *CheckBulkRequest) {
+	m.Consistency = o.value
+}
+
+func (o ConsistencyOption) applyCheckSelfBulkRequest(m *CheckSelfBulkRequest) {
+	m.Consistency = o.value
+}
+
+func (o ConsistencyOption) applyStreamedListObjectsRequest(m *StreamedListObjectsRequest) {
+	m.Consistency = o.value
+}
+
+func (o ConsistencyOption) applyStreamedListSubjectsRequest(m *StreamedListSubjectsRequest) {
+	m.Consistency = o.value
+}
+
+// InventoryIdOption sets the InventoryId field. It is created by WithInventoryId.
+type InventoryIdOption struct {
+	value string
+}
+
+// WithInventoryId sets InventoryId on ReportResourceRequest.
+func WithInventoryId(value string) InventoryIdOption {
+	return InventoryIdOption{value: value}
+}
+
+func (o InventoryIdOption) applyReportResourceRequest(m *ReportResourceRequest) {
+	value := o.value
+	m.InventoryId = &value
+}
+
+// ItemsOption sets the Items field. It is created by WithItems.
+type ItemsOption struct {
+	value []*CheckBulkRequestItem
+}
+
+// WithItems sets Items on CheckBulkRequest, CheckForUpdateBulkRequest.
+func WithItems(values ...*CheckBulkRequestItem) ItemsOption {
+	return ItemsOption{value: values}
+}
+
+func (o ItemsOption) applyCheckBulkRequest(m *CheckBulkRequest) {
+	m.Items = o.value
+}
+
+func (o ItemsOption) applyCheckForUpdateBulkRequest(m *CheckForUpdateBulkRequest) {
+	m.Items = o.value
+}
+
+// ObjectOption sets the Object field. It is created by WithObject.
+type ObjectOption struct {
+	value *ResourceReference
+}
+
+// WithObject sets Object on CheckRequest, CheckSelfRequest, CheckForUpdateRequest, CheckBulkRequestItem, CheckSelfBulkRequestItem.
+func WithObject(value *ResourceReference) ObjectOption {
+	return ObjectOption{value: value}
+}
+
+func (o ObjectOption) applyCheckRequest(m *CheckRequest) {
+	m.Object = o.value
+}
+
+func (o ObjectOption) applyCheckSelfRequest(m *CheckSelfRequest) {
+	m.Object = o.value
+}
+
+func (o ObjectOption) applyCheckForUpdateRequest(m *CheckForUpdateRequest) {
+	m.Object = o.value
+}
+
+func (o ObjectOption) applyCheckBulkRequestItem(m *CheckBulkRequestItem) {
+	m.Object = o.value
+}
+
+func (o ObjectOption) applyCheckSelfBulkRequestItem(m *CheckSelfBulkRequestItem) {
+	m.Object = o.value
+}
+
+// ObjectTypeOption sets the ObjectType field. It is created by WithObjectType.
+type ObjectTypeOption struct {
+	value *RepresentationType
+}
+
+// WithObjectType sets ObjectType on StreamedListObjectsRequest.
+func WithObjectType(value *RepresentationType) ObjectTypeOption {
+	return ObjectTypeOption{value: value}
+}
+
+func (o ObjectTypeOption) applyStreamedListObjectsRequest(m *StreamedListObjectsRequest) {
+	m.ObjectType = o.value
+}
+
+// PaginationOption sets the Pagination field. It is created by WithPagination.
+type PaginationOption struct {
+	value *RequestPagination
+}
+
+// WithPagination sets Pagination on StreamedListObjectsRequest, StreamedListSubjectsRequest.
+func WithPagination(value *RequestPagination) PaginationOption {
+	return PaginationOption{value: value}
+}
+
+func (o PaginationOption) applyStreamedListObjectsRequest(m *StreamedListObjectsRequest) {
+	m.Pagination = o.value
+}
+
+func (o PaginationOption) applyStreamedListSubjectsRequest(m *StreamedListSubjectsRequest) {
+	m.Pagination = o.value
+}
+
+// ReferenceOption sets the Reference field. It is created by WithReference.
+type ReferenceOption struct {
+	value *ResourceReference
+}
+
+// WithReference sets Reference on DeleteResourceRequest.
+func WithReference(value *ResourceReference) ReferenceOption {
+	return ReferenceOption{value: value}
+}
+
+func (o ReferenceOption) applyDeleteResourceRequest(m *DeleteResourceRequest) {
+	m.Reference = o.value
+}
+
+// RelationOption sets the Relation field. It is created by WithRelation.
+type RelationOption struct {
+	value string
+}
+
+// WithRelation sets Relation on CheckRequest, CheckSelfRequest, CheckForUpdateRequest, CheckBulkRequestItem, CheckSelfBulkRequestItem, StreamedListObjectsRequest, StreamedListSubjectsRequest.
+func WithRelation(value string) RelationOption {
+	return RelationOption{value: value}
+}
+
+func (o RelationOption) applyCheckRequest(m *CheckRequest) {
+	m.Relation = o.value
+}
+
+func (o RelationOption) applyCheckSelfRequest(m *CheckSelfRequest) {
+	m.Relation = o.value
+}
+
+func (o RelationOption) applyCheckForUpdateRequest(m *CheckForUpdateRequest) {
+	m.Relation = o.value
+}
+
+func (o RelationOption) applyCheckBulkRequestItem(m *CheckBulkRequestItem) {
+	m.Relation = o.value
+}
+
+func (o RelationOption) applyCheckSelfBulkRequestItem(m *CheckSelfBulkRequestItem) {
+	m.Relation = o.value
+}
+
+func (o RelationOption) applyStreamedListObjectsRequest(m *StreamedListObjectsRequest) {
+	m.Relation = o.value
+}
+
+func (o RelationOption) applyStreamedListSubjectsRequest(m *StreamedListSubjectsRequest) {
+	m.Relation = o.value
+}
+
+// ReporterInstanceIdOption sets the ReporterInstanceId field. It is created by WithReporterInstanceId.
+type ReporterInstanceIdOption struct {
+	value string
+}
+
+// WithReporterInstanceId sets ReporterInstanceId on ReportResourceRequest.
+func WithReporterInstanceId(value string) ReporterInstanceIdOption {
+	return ReporterInstanceIdOption{value: value}
+}
+
+func (o ReporterInstanceIdOption) applyReportResourceRequest(m *ReportResourceRequest) {
+	m.ReporterInstanceId = o.value
+}
+
+// ReporterTypeOption sets the ReporterType field. It is created by WithReporterType.
+type ReporterTypeOption struct {
+	value string
+}
+
+// WithReporterType sets ReporterType on ReportResourceRequest.
+func WithReporterType(value string) ReporterTypeOption {
+	return ReporterTypeOption{value: value}
+}
+
+func (o ReporterTypeOption) applyReportResourceRequest(m *ReportResourceRequest) {
+	m.ReporterType = o.value
+}
+
+// RepresentationsOption sets the Representations field. It is created by WithRepresentations.
+type RepresentationsOption struct {
+	value *ResourceRepresentations
+}
+
+// WithRepresentations sets Representations on ReportResourceRequest.
+func WithRepresentations(value *ResourceRepresentations) RepresentationsOption {
+	return RepresentationsOption{value: value}
+}
+
+func (o RepresentationsOption) applyReportResourceRequest(m *ReportResourceRequest) {
+	m.Representations = o.value
+}
+
+// ResourceOption sets the Resource field. It is created by WithResource.
+type ResourceOption struct {
+	value *ResourceReference
+}
+
+// WithResource sets Resource on StreamedListSubjectsRequest.
+func WithResource(value *ResourceReference) ResourceOption {
+	return ResourceOption{value: value}
+}
+
+func (o ResourceOption) applyStreamedListSubjectsRequest(m *StreamedListSubjectsRequest) {
+	m.Resource = o.value
+}
+
+// SelfItemsOption sets the Items field. It is created by WithSelfItems.
+type SelfItemsOption struct {
+	value []*CheckSelfBulkRequestItem
+}
+
+// WithSelfItems sets Items on CheckSelfBulkRequest.
+func WithSelfItems(values ...*CheckSelfBulkRequestItem) SelfItemsOption {
+	return SelfItemsOption{value: values}
+}
+
+func (o SelfItemsOption) applyCheckSelfBulkRequest(m *CheckSelfBulkRequest) {
+	m.Items = o.value
+}
+
+// SubjectOption sets the Subject field. It is created by WithSubject.
+type SubjectOption struct {
+	value *SubjectReference
+}
+
+// WithSubject sets Subject on CheckRequest, CheckForUpdateRequest, CheckBulkRequestItem, StreamedListObjectsRequest.
+func WithSubject(value *SubjectReference) SubjectOption {
+	return SubjectOption{value: value}
+}
+
+func (o SubjectOption) applyCheckRequest(m *CheckRequest) {
+	m.Subject = o.value
+}
+
+func (o SubjectOption) applyCheckForUpdateRequest(m *CheckForUpdateRequest) {
+	m.Subject = o.value
+}
+
+func (o SubjectOption) applyCheckBulkRequestItem(m *CheckBulkRequestItem) {
+	m.Subject = o.value
+}
+
+func (o SubjectOption) applyStreamedListObjectsRequest(m *StreamedListObjectsRequest) {
+	m.Subject = o.value
+}
+
+// SubjectRelationOption sets the SubjectRelation field. It is created by WithSubjectRelation.
+type SubjectRelationOption struct {
+	value string
+}
+
+// WithSubjectRelation sets SubjectRelation on StreamedListSubjectsRequest.
+func WithSubjectRelation(value string) SubjectRelationOption {
+	return SubjectRelationOption{value: value}
+}
+
+func (o SubjectRelationOption) applyStreamedListSubjectsRequest(m *StreamedListSubjectsRequest) {
+	value := o.value
+	m.SubjectRelation = &value
+}
+
+// SubjectTypeOption sets the SubjectType field. It is created by WithSubjectType.
+type SubjectTypeOption struct {
+	value *RepresentationType
+}
+
+// WithSubjectType sets SubjectType on StreamedListSubjectsRequest.
+func WithSubjectType(value *RepresentationType) SubjectTypeOption {
+	return SubjectTypeOption{value: value}
+}
+
+func (o SubjectTypeOption) applyStreamedListSubjectsRequest(m *StreamedListSubjectsRequest) {
+	m.SubjectType = o.value
+}
+
+// TypeOption sets the Type field. It is created by WithType.
+type TypeOption struct {
+	value string
+}
+
+// WithType sets Type on ReportResourceRequest.
+func WithType(value string) TypeOption {
+	return TypeOption{value: value}
+}
+
+func (o TypeOption) applyReportResourceRequest(m *ReportResourceRequest) {
+	m.Type = o.value
+}
+
+// WriteVisibilityOption sets the WriteVisibility field. It is created by WithWriteVisibility.
+type WriteVisibilityOption struct {
+	value WriteVisibility
+}
+
+// WithWriteVisibility sets WriteVisibility on ReportResourceRequest.
+func WithWriteVisibility(value WriteVisibility) WriteVisibilityOption {
+	return WriteVisibilityOption{value: value}
+}
+
+func (o WriteVisibilityOption) applyReportResourceRequest(m *ReportResourceRequest) {
+	m.WriteVisibility = o.value
+}
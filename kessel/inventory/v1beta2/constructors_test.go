@@ -0,0 +1,53 @@
+package v1beta2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewCheckRequest(t *testing.T) {
+	object := &ResourceReference{ResourceType: "host", ResourceId: "h1", Reporter: &ReporterReference{Type: "hbi"}}
+	subject := &SubjectReference{Resource: &ResourceReference{ResourceType: "principal", ResourceId: "redhat/alice", Reporter: &ReporterReference{Type: "rbac"}}}
+
+	request := NewCheckRequest(WithObject(object), WithRelation("view"), WithSubject(subject), WithConsistencyMinimizeLatency())
+
+	expected := &CheckRequest{
+		Object:      object,
+		Relation:    "view",
+		Subject:     subject,
+		Consistency: &Consistency{Requirement: &Consistency_MinimizeLatency{MinimizeLatency: true}},
+	}
+	assert.True(t, proto.Equal(expected, request), "got %v", request)
+}
+
+func TestNewCheckBulkRequest(t *testing.T) {
+	token := &ConsistencyToken{Token: "tok"}
+	item := NewCheckBulkRequestItem(WithRelation("view"))
+
+	request := NewCheckBulkRequest(WithItems(item, item), WithConsistencyAtLeastAsFresh(token))
+
+	assert.Len(t, request.GetItems(), 2)
+	assert.Same(t, token, request.GetConsistency().GetAtLeastAsFresh())
+	assert.Len(t, NewCheckSelfBulkRequest(WithSelfItems(NewCheckSelfBulkRequestItem())).GetItems(), 1)
+}
+
+func TestNewStreamedListObjectsRequest_pagination(t *testing.T) {
+	request := NewStreamedListObjectsRequest(WithPage(100, "next"), WithConsistencyAtLeastAsAcknowledged())
+
+	assert.Equal(t, uint32(100), request.GetPagination().GetLimit())
+	assert.Equal(t, "next", request.GetPagination().GetContinuationToken())
+	assert.True(t, request.GetConsistency().GetAtLeastAsAcknowledged())
+
+	first := NewStreamedListObjectsRequest(WithPage(10, ""))
+	assert.Nil(t, first.GetPagination().ContinuationToken)
+}
+
+func TestNewReportResourceRequest_optionalScalar(t *testing.T) {
+	request := NewReportResourceRequest(WithType("host"), WithInventoryId("inv-1"), WithWriteVisibility(WriteVisibility_IMMEDIATE))
+
+	assert.Equal(t, "host", request.GetType())
+	assert.Equal(t, "inv-1", request.GetInventoryId())
+	assert.Equal(t, WriteVisibility_IMMEDIATE, request.GetWriteVisibility())
+}
@@ -0,0 +1,80 @@
+// Package encoding reads and writes v1beta2 requests as JSON and YAML using
+// protojson, so fixtures and CLI payloads can be authored by hand. Field names
+// are written in lowerCamelCase; both lowerCamelCase and the original
+// snake_case names are accepted when reading, and unknown fields are ignored.
+package encoding
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	marshalOptions   = protojson.MarshalOptions{}
+	unmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// MarshalRequestJSON encodes message as JSON.
+func MarshalRequestJSON(message proto.Message) ([]byte, error) {
+	return marshalOptions.Marshal(message)
+}
+
+// UnmarshalRequestJSON decodes JSON into message, replacing its contents.
+func UnmarshalRequestJSON(data []byte, message proto.Message) error {
+	return unmarshalOptions.Unmarshal(data, message)
+}
+
+// MarshalRequestYAML encodes message as YAML with the same field names and
+// value formats as MarshalRequestJSON.
+func MarshalRequestYAML(message proto.Message) ([]byte, error) {
+	data, err := MarshalRequestJSON(message)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(value)
+}
+
+// UnmarshalRequestYAML decodes YAML into message, replacing its contents. The
+// YAML is converted to JSON first, so values follow protojson rules (e.g.
+// enums by name, timestamps as RFC 3339 strings).
+func UnmarshalRequestYAML(data []byte, message proto.Message) error {
+	var value any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	converted, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to convert YAML to JSON: %w", err)
+	}
+	return UnmarshalRequestJSON(converted, message)
+}
+
+// ValidateRoundTrip checks that message survives being encoded to JSON and
+// decoded again unchanged. It catches payloads that only look right, such as
+// a oneof set to an empty value or a Struct holding values JSON cannot
+// represent.
+func ValidateRoundTrip(message proto.Message) error {
+	data, err := MarshalRequestJSON(message)
+	if err != nil {
+		return err
+	}
+
+	decoded := message.ProtoReflect().New().Interface()
+	if err := UnmarshalRequestJSON(data, decoded); err != nil {
+		return err
+	}
+	if !proto.Equal(message, decoded) {
+		return fmt.Errorf("%s does not survive a JSON round trip", message.ProtoReflect().Descriptor().FullName())
+	}
+	return nil
+}
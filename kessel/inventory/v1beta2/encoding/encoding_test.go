@@ -0,0 +1,108 @@
+package encoding
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"github.com/project-kessel/kessel-sdk-go/kessel/testutil"
+)
+
+func reportRequest(t *testing.T) *v1beta2.ReportResourceRequest {
+	t.Helper()
+	common, err := structpb.NewStruct(map[string]any{"workspace_id": "ws-1"})
+	require.NoError(t, err)
+	return &v1beta2.ReportResourceRequest{
+		Type:               "host",
+		ReporterType:       "hbi",
+		ReporterInstanceId: "instance-1",
+		Representations: &v1beta2.ResourceRepresentations{
+			Metadata: &v1beta2.RepresentationMetadata{LocalResourceId: "host-1", ApiHref: "https://example.com/api"},
+			Common:   common,
+		},
+	}
+}
+
+func TestJSON_roundTrip(t *testing.T) {
+	request := reportRequest(t)
+
+	data, err := MarshalRequestJSON(request)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"reporterType"`)
+
+	decoded := &v1beta2.ReportResourceRequest{}
+	require.NoError(t, UnmarshalRequestJSON(data, decoded))
+	testutil.AssertProtoEqual(t, request, decoded)
+}
+
+func TestUnmarshalRequestJSON_acceptsSnakeCaseAndUnknownFields(t *testing.T) {
+	decoded := &v1beta2.CheckRequest{}
+
+	err := UnmarshalRequestJSON([]byte(`{"relation": "view", "object": {"resource_type": "host", "resource_id": "1"}, "comment": "ignored"}`), decoded)
+
+	require.NoError(t, err)
+	assert.Equal(t, "view", decoded.Relation)
+	assert.Equal(t, "host", decoded.Object.ResourceType)
+}
+
+func TestYAML_roundTrip(t *testing.T) {
+	request := reportRequest(t)
+
+	data, err := MarshalRequestYAML(request)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "reporterType: hbi")
+
+	decoded := &v1beta2.ReportResourceRequest{}
+	require.NoError(t, UnmarshalRequestYAML(data, decoded))
+	testutil.AssertProtoEqual(t, request, decoded)
+}
+
+func TestUnmarshalRequestYAML(t *testing.T) {
+	tests := []struct {
+		name          string
+		yaml          string
+		expectedError bool
+	}{
+		{
+			name: "valid request",
+			yaml: "relation: view\nobject:\n  resourceType: host\n  resourceId: \"1\"\nconsistency:\n  minimizeLatency: true\n",
+		},
+		{
+			name:          "invalid yaml",
+			yaml:          "relation: [",
+			expectedError: true,
+		},
+		{
+			name:          "wrong field type",
+			yaml:          "relation:\n  nested: true\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded := &v1beta2.CheckRequest{}
+			err := UnmarshalRequestYAML([]byte(tt.yaml), decoded)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "view", decoded.Relation)
+			assert.Equal(t, "1", decoded.Object.ResourceId)
+			assert.True(t, decoded.Consistency.GetMinimizeLatency())
+		})
+	}
+}
+
+func TestValidateRoundTrip(t *testing.T) {
+	assert.NoError(t, ValidateRoundTrip(reportRequest(t)))
+
+	request := reportRequest(t)
+	request.Representations.Common.Fields["bad"] = structpb.NewNumberValue(math.NaN())
+	assert.Error(t, ValidateRoundTrip(request))
+}
@@ -0,0 +1,177 @@
+// Command genconstructors writes constructors.go in the v1beta2 package: a
+// New<Message>(options...) constructor for each main request message and a
+// With<Field> option for each of their fields. It reads the message structs
+// by reflection, so run it (via go generate) after make generate.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// messages are the request messages that get constructors.
+var messages = []any{
+	v1beta2.CheckRequest{},
+	v1beta2.CheckSelfRequest{},
+	v1beta2.CheckForUpdateRequest{},
+	v1beta2.CheckBulkRequest{},
+	v1beta2.CheckBulkRequestItem{},
+	v1beta2.CheckSelfBulkRequest{},
+	v1beta2.CheckSelfBulkRequestItem{},
+	v1beta2.CheckForUpdateBulkRequest{},
+	v1beta2.ReportResourceRequest{},
+	v1beta2.DeleteResourceRequest{},
+	v1beta2.StreamedListObjectsRequest{},
+	v1beta2.StreamedListSubjectsRequest{},
+}
+
+// optionNames overrides the option name of a message field whose name is
+// shared with a field of another type.
+var optionNames = map[string]string{
+	"CheckSelfBulkRequest.Items": "SelfItems",
+}
+
+type option struct {
+	name     string
+	field    string
+	goType   string
+	variadic bool
+	pointer  bool
+	messages []string
+}
+
+func main() {
+	output := flag.String("output", "constructors.go", "file to write")
+	flag.Parse()
+
+	source, err := generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate() ([]byte, error) {
+	options := map[string]*option{}
+	var messageNames []string
+	for _, message := range messages {
+		messageType := reflect.TypeOf(message)
+		messageNames = append(messageNames, messageType.Name())
+		for i := range messageType.NumField() {
+			field := messageType.Field(i)
+			if !field.IsExported() || field.Tag.Get("protobuf") == "" {
+				continue
+			}
+			o, err := fieldOption(messageType.Name(), field)
+			if err != nil {
+				return nil, err
+			}
+			existing, ok := options[o.name]
+			if !ok {
+				options[o.name] = o
+				continue
+			}
+			if existing.goType != o.goType || existing.field != o.field {
+				return nil, fmt.Errorf("option With%s: %s.%s is %s but %s has %s; add it to optionNames", o.name, messageType.Name(), field.Name, o.goType, strings.Join(existing.messages, ", "), existing.goType)
+			}
+			existing.messages = append(existing.messages, o.messages...)
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by genconstructors. DO NOT EDIT.\n\npackage v1beta2\n")
+	for _, name := range messageNames {
+		fmt.Fprintf(&b, `
+// %[1]sOption sets a field of a %[1]s built by New%[1]s.
+type %[1]sOption interface {
+	apply%[1]s(*%[1]s)
+}
+
+// New%[1]s returns a %[1]s with options applied in order.
+func New%[1]s(options ...%[1]sOption) *%[1]s {
+	m := &%[1]s{}
+	for _, option := range options {
+		option.apply%[1]s(m)
+	}
+	return m
+}
+`, name)
+	}
+
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		o := options[name]
+		valueType := o.goType
+		parameter := "value " + valueType
+		if o.variadic {
+			parameter = "values ..." + strings.TrimPrefix(valueType, "[]")
+		} else if o.pointer {
+			valueType = strings.TrimPrefix(valueType, "*")
+			parameter = "value " + valueType
+		}
+		argument := "value"
+		if o.variadic {
+			argument = "values"
+		}
+		fmt.Fprintf(&b, `
+// %[1]sOption sets the %[2]s field. It is created by With%[1]s.
+type %[1]sOption struct {
+	value %[3]s
+}
+
+// With%[1]s sets %[2]s on %[4]s.
+func With%[1]s(%[5]s) %[1]sOption {
+	return %[1]sOption{value: %[6]s}
+}
+`, o.name, o.field, valueType, strings.Join(o.messages, ", "), parameter, argument)
+		for _, message := range o.messages {
+			assignment := "m." + o.field + " = o.value"
+			if o.pointer {
+				assignment = "value := o.value\n\tm." + o.field + " = &value"
+			}
+			fmt.Fprintf(&b, `
+func (o %[1]sOption) apply%[2]s(m *%[2]s) {
+	%[3]s
+}
+`, o.name, message, assignment)
+		}
+	}
+	return format.Source(b.Bytes())
+}
+
+func fieldOption(message string, field reflect.StructField) (*option, error) {
+	name := field.Name
+	if override, ok := optionNames[message+"."+field.Name]; ok {
+		name = override
+	}
+	o := &option{
+		name:     name,
+		field:    field.Name,
+		goType:   strings.ReplaceAll(field.Type.String(), "v1beta2.", ""),
+		messages: []string{message},
+	}
+	switch field.Type.Kind() {
+	case reflect.Slice:
+		o.variadic = field.Type.Elem().Kind() != reflect.Uint8
+	case reflect.Pointer:
+		o.pointer = field.Type.Elem().Kind() != reflect.Struct
+	case reflect.Interface:
+		return nil, fmt.Errorf("%s.%s is a oneof, which genconstructors does not support", message, field.Name)
+	}
+	return o, nil
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_upToDate(t *testing.T) {
+	generated, err := generate()
+	require.NoError(t, err)
+
+	committed, err := os.ReadFile("../../constructors.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(committed), string(generated), "constructors.go is stale; run go generate ./kessel/inventory/v1beta2")
+}
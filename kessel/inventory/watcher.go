@@ -0,0 +1,177 @@
+package inventory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultWatchInterval = 30 * time.Second
+
+// WatchEventType is the kind of change a WatchEvent reports.
+type WatchEventType int
+
+const (
+	// ObjectAdded reports an object that was not in the previous listing.
+	ObjectAdded WatchEventType = iota
+	// ObjectRemoved reports an object that is no longer listed.
+	ObjectRemoved
+	// ObjectChanged reports an object listed again under the same type and ID
+	// but with a different reporter.
+	ObjectChanged
+	// WatchFailed reports a listing that failed. The previous listing is kept
+	// and compared against the next successful one.
+	WatchFailed
+)
+
+func (t WatchEventType) String() string {
+	switch t {
+	case ObjectAdded:
+		return "added"
+	case ObjectRemoved:
+		return "removed"
+	case ObjectChanged:
+		return "changed"
+	default:
+		return "failed"
+	}
+}
+
+// WatchEvent is a single value received from Watch.
+type WatchEvent struct {
+	Type WatchEventType
+	// Object is the added or changed object as now listed, or the removed
+	// object as last listed. Nil for WatchFailed.
+	Object *v1beta2.ResourceReference
+	// Previous is the object as last listed, for ObjectChanged only.
+	Previous *v1beta2.ResourceReference
+	// Err is the listing error, for WatchFailed only.
+	Err error
+}
+
+type WatchOptions struct {
+	// How long to wait between listings. Defaults to 30s.
+	Interval time.Duration
+	// Capacity of the returned channel. Defaults to 0, unbuffered; a slow
+	// consumer delays the next listing.
+	BufferSize int
+	// Optionally configure each listing, e.g. with WithPageSize, WithResume or
+	// WithPageRateLimit. WithMaxItems would report the objects past the limit
+	// as removed, and WithPageCallback is replaced by the watcher's own.
+	ListOptions []CollectObjectsOption
+}
+
+type watchKey struct {
+	resourceType string
+	resourceId   string
+}
+
+// Watch re-runs the StreamedListObjects request (e.g. every workspace a
+// subject can view) every options.Interval and sends the difference from the
+// previous listing as events, so consumers can invalidate caches as access
+// changes instead of polling and diffing themselves. Objects are matched by
+// resource type and ID. The first listing reports every object as added.
+// Events of one listing are sent ordered by type and ID.
+//
+// The channel is closed once ctx ends; consumers that stop reading must cancel
+// ctx so the watching goroutine exits. Failed listings are sent as WatchFailed
+// events and watching continues.
+func Watch(ctx context.Context, client v1beta2.KesselInventoryServiceClient, request *v1beta2.StreamedListObjectsRequest, options WatchOptions) <-chan WatchEvent {
+	interval := options.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	events := make(chan WatchEvent, max(options.BufferSize, 0))
+
+	go func() {
+		defer close(events)
+		send := func(event WatchEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var previous map[watchKey]*v1beta2.ResourceReference
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			current, err := listSnapshot(ctx, client, request, options.ListOptions)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				if !send(WatchEvent{Type: WatchFailed, Err: err}) {
+					return
+				}
+			default:
+				for _, event := range diffSnapshots(previous, current) {
+					if !send(event) {
+						return
+					}
+				}
+				previous = current
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events
+}
+
+func listSnapshot(ctx context.Context, client v1beta2.KesselInventoryServiceClient, request *v1beta2.StreamedListObjectsRequest, listOptions []CollectObjectsOption) (map[watchKey]*v1beta2.ResourceReference, error) {
+	snapshot := map[watchKey]*v1beta2.ResourceReference{}
+	opts := append(slices.Clone(listOptions), WithPageCallback(func(page []*v1beta2.StreamedListObjectsResponse) error {
+		for _, response := range page {
+			object := response.GetObject()
+			snapshot[watchKey{resourceType: object.GetResourceType(), resourceId: object.GetResourceId()}] = object
+		}
+		return nil
+	}))
+	if _, err := CollectObjects(ctx, client, request, opts...); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// diffSnapshots returns the events that turn previous into current, ordered by
+// resource type and ID.
+func diffSnapshots(previous, current map[watchKey]*v1beta2.ResourceReference) []WatchEvent {
+	keys := make([]watchKey, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b watchKey) int {
+		return cmp.Or(cmp.Compare(a.resourceType, b.resourceType), cmp.Compare(a.resourceId, b.resourceId))
+	})
+
+	var events []WatchEvent
+	for _, key := range keys {
+		before, wasListed := previous[key]
+		after, isListed := current[key]
+		switch {
+		case !wasListed:
+			events = append(events, WatchEvent{Type: ObjectAdded, Object: after})
+		case !isListed:
+			events = append(events, WatchEvent{Type: ObjectRemoved, Object: before})
+		case !proto.Equal(before, after):
+			events = append(events, WatchEvent{Type: ObjectChanged, Object: after, Previous: before})
+		}
+	}
+	return events
+}
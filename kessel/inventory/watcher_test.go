@@ -0,0 +1,99 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// snapshotListObjectsClient lists the next of its snapshots on every call,
+// repeating the last one, or fails when the snapshot is nil.
+type snapshotListObjectsClient struct {
+	v1beta2.KesselInventoryServiceClient
+	mu        sync.Mutex
+	snapshots [][]*v1beta2.ResourceReference
+	calls     int
+}
+
+func (c *snapshotListObjectsClient) StreamedListObjects(ctx context.Context, in *v1beta2.StreamedListObjectsRequest, opts ...grpc.CallOption) (v1beta2.KesselInventoryService_StreamedListObjectsClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	snapshot := c.snapshots[min(c.calls, len(c.snapshots)-1)]
+	c.calls++
+	if snapshot == nil {
+		return nil, errors.New("listing failed")
+	}
+	stream := &mockObjectsStream{}
+	for _, object := range snapshot {
+		stream.responses = append(stream.responses, &v1beta2.StreamedListObjectsResponse{Object: object})
+	}
+	return stream, nil
+}
+
+func workspace(id string) *v1beta2.ResourceReference {
+	return &v1beta2.ResourceReference{ResourceType: "workspace", ResourceId: id, Reporter: &v1beta2.ReporterReference{Type: "rbac"}}
+}
+
+func receiveEvents(t *testing.T, events <-chan WatchEvent, n int) []WatchEvent {
+	t.Helper()
+	var received []WatchEvent
+	for range n {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out after %d of %d events", len(received), n)
+		}
+	}
+	return received
+}
+
+func TestWatch(t *testing.T) {
+	moved := workspace("ws-2")
+	moved.Reporter.Type = "hbi"
+	client := &snapshotListObjectsClient{snapshots: [][]*v1beta2.ResourceReference{
+		{workspace("ws-2"), workspace("ws-1")},
+		nil,
+		{workspace("ws-1"), moved, workspace("ws-3")},
+		{moved, workspace("ws-3")},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := Watch(ctx, client, &v1beta2.StreamedListObjectsRequest{}, WatchOptions{Interval: time.Millisecond})
+	received := receiveEvents(t, events, 6)
+
+	var types []WatchEventType
+	var ids []string
+	for _, event := range received {
+		types = append(types, event.Type)
+		ids = append(ids, event.Object.GetResourceId())
+	}
+	assert.Equal(t, []WatchEventType{ObjectAdded, ObjectAdded, WatchFailed, ObjectChanged, ObjectAdded, ObjectRemoved}, types)
+	assert.Equal(t, []string{"ws-1", "ws-2", "", "ws-2", "ws-3", "ws-1"}, ids)
+	assert.ErrorContains(t, received[2].Err, "listing failed")
+	assert.Equal(t, "rbac", received[3].Previous.GetReporter().GetType())
+	assert.Equal(t, "hbi", received[3].Object.GetReporter().GetType())
+}
+
+func TestWatch_closesOnCancel(t *testing.T) {
+	client := &snapshotListObjectsClient{snapshots: [][]*v1beta2.ResourceReference{{workspace("ws-1")}}}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events := Watch(ctx, client, &v1beta2.StreamedListObjectsRequest{}, WatchOptions{Interval: time.Millisecond})
+	receiveEvents(t, events, 1)
+	cancel()
+
+	require.Eventually(t, func() bool {
+		_, open := <-events
+		return !open
+	}, 5*time.Second, time.Millisecond)
+}
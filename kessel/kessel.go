@@ -0,0 +1,224 @@
+// Package kessel provides a process-wide default client for small services
+// and scripts that do not want to manage builders and connections:
+//
+//	client, err := kessel.Default()
+//	if err != nil {
+//	    return err
+//	}
+//	response, err := client.Inventory.Check(ctx, request)
+//
+// The client is configured from the environment (see ConfigFromEnv) unless
+// Init is called first. Larger services should build their own clients with
+// v1beta2.NewClientBuilder.
+package kessel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"github.com/project-kessel/kessel-sdk-go/kessel/inventory"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	v2 "github.com/project-kessel/kessel-sdk-go/kessel/rbac/v2"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EndpointEnv     = "KESSEL_ENDPOINT"
+	InsecureEnv     = "KESSEL_INSECURE"
+	RBACEndpointEnv = "KESSEL_RBAC_ENDPOINT"
+	IssuerUrlEnv    = "AUTH_DISCOVERY_ISSUER_URL"
+	ClientIdEnv     = "AUTH_CLIENT_ID"
+	ClientSecretEnv = "AUTH_CLIENT_SECRET"
+)
+
+// discoveryTimeout bounds the OIDC discovery made when the client is built.
+const discoveryTimeout = 10 * time.Second
+
+// ErrAlreadyInitialized is returned by Init once the default client exists.
+var ErrAlreadyInitialized = errors.New("kessel: default client already initialized")
+
+// Config configures a Client.
+type Config struct {
+	// Endpoint is the Kessel Inventory gRPC address (host:port). Required.
+	Endpoint string
+	// Insecure disables transport security and authentication, for any
+	// endpoint. The client logs a warning when it is built.
+	Insecure bool
+	// IssuerUrl, ClientId and ClientSecret enable OAuth2 client credentials;
+	// the token endpoint is found with OIDC discovery on IssuerUrl. Without
+	// a ClientId calls are unauthenticated.
+	IssuerUrl    string
+	ClientId     string
+	ClientSecret string
+	// RBACBaseEndpoint is the RBAC base endpoint (e.g. "https://rbac:8000")
+	// for the workspace calls. Optional.
+	RBACBaseEndpoint string
+	// TransportCredentials secures the gRPC connection. Defaults to TLS with
+	// the system roots.
+	TransportCredentials credentials.TransportCredentials
+	// HttpClient is used for OIDC discovery, token requests made for RBAC
+	// calls, and RBAC calls. Defaults to http.DefaultClient.
+	HttpClient *http.Client
+}
+
+// ConfigFromEnv reads a Config from KESSEL_ENDPOINT, KESSEL_INSECURE,
+// KESSEL_RBAC_ENDPOINT, AUTH_DISCOVERY_ISSUER_URL, AUTH_CLIENT_ID and
+// AUTH_CLIENT_SECRET.
+func ConfigFromEnv() Config {
+	insecure, _ := strconv.ParseBool(os.Getenv(InsecureEnv))
+	return Config{
+		Endpoint:         os.Getenv(EndpointEnv),
+		Insecure:         insecure,
+		IssuerUrl:        os.Getenv(IssuerUrlEnv),
+		ClientId:         os.Getenv(ClientIdEnv),
+		ClientSecret:     os.Getenv(ClientSecretEnv),
+		RBACBaseEndpoint: os.Getenv(RBACEndpointEnv),
+	}
+}
+
+// Client holds an Inventory client and the settings for the RBAC workspace
+// calls, which share its credentials.
+type Client struct {
+	Inventory v1beta2.KesselInventoryServiceClient
+	// RBACBaseEndpoint and WorkspaceOptions are the arguments of the
+	// rbac/v2 workspace functions, e.g.
+	// v2.FetchDefaultWorkspace(ctx, c.RBACBaseEndpoint, orgId, c.WorkspaceOptions).
+	RBACBaseEndpoint string
+	WorkspaceOptions v2.FetchWorkspaceOptions
+
+	conn *grpc.ClientConn
+}
+
+// New builds a Client from config. With OAuth2 configured it fetches the
+// issuer's OIDC discovery document; tokens are fetched on first use.
+func New(ctx context.Context, config Config) (*Client, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("kessel: endpoint is required")
+	}
+
+	client := &Client{
+		RBACBaseEndpoint: config.RBACBaseEndpoint,
+		WorkspaceOptions: v2.FetchWorkspaceOptions{HttpClient: config.HttpClient},
+	}
+	builder := v1beta2.NewClientBuilder(config.Endpoint)
+	switch {
+	case config.Insecure:
+		builder.Insecure().AllowInsecureAuth()
+	case config.ClientId != "":
+		discovered, err := auth.FetchOIDCDiscovery(ctx, config.IssuerUrl, auth.FetchOIDCDiscoveryOptions{HttpClient: config.HttpClient})
+		if err != nil {
+			return nil, err
+		}
+		credentials := auth.NewOAuth2ClientCredentials(config.ClientId, config.ClientSecret, discovered.TokenEndpoint)
+		builder.OAuth2ClientAuthenticated(&credentials, config.TransportCredentials)
+		client.WorkspaceOptions.Auth = auth.OAuth2AuthRequest(&credentials, auth.OAuth2AuthRequestOptions{HttpClient: config.HttpClient})
+	default:
+		builder.Unauthenticated(config.TransportCredentials)
+	}
+
+	inventoryClient, conn, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	client.Inventory = inventoryClient
+	client.conn = conn
+	return client, nil
+}
+
+// AuthorizeDeps returns the dependencies of v2.Authorize for this client.
+func (c *Client) AuthorizeDeps() v2.AuthorizeDeps {
+	return v2.AuthorizeDeps{
+		Inventory:        c.Inventory,
+		RBACBaseEndpoint: c.RBACBaseEndpoint,
+		WorkspaceOptions: c.WorkspaceOptions,
+	}
+}
+
+// WaitForReady blocks until the Inventory connection is ready or ctx ends.
+// See inventory.WaitForReady.
+func (c *Client) WaitForReady(ctx context.Context) error {
+	return inventory.WaitForReady(ctx, c.conn)
+}
+
+// ConnectivityState returns the current state of the Inventory connection.
+func (c *Client) ConnectivityState() connectivity.State {
+	return c.conn.GetState()
+}
+
+// WatchConnectivityState reports the state changes of the Inventory
+// connection until ctx ends. See inventory.WatchConnectivityState.
+func (c *Client) WatchConnectivityState(ctx context.Context) <-chan connectivity.State {
+	return inventory.WatchConnectivityState(ctx, c.conn)
+}
+
+// Close closes the Inventory connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+var defaultClient struct {
+	mu     sync.Mutex
+	config *Config
+	client *Client
+}
+
+// Init sets the configuration of the default client instead of the
+// environment. It returns ErrAlreadyInitialized once Default has built the
+// client; call it at startup, before any use of Default.
+func Init(config Config) error {
+	defaultClient.mu.Lock()
+	defer defaultClient.mu.Unlock()
+	if defaultClient.client != nil {
+		return ErrAlreadyInitialized
+	}
+	defaultClient.config = &config
+	return nil
+}
+
+// Default returns the process-wide client, building it on first use from the
+// configuration given to Init or else from ConfigFromEnv. It is safe for
+// concurrent use; every caller gets the same client. If building fails, the
+// error is returned and the next call tries again.
+func Default() (*Client, error) {
+	defaultClient.mu.Lock()
+	defer defaultClient.mu.Unlock()
+	if defaultClient.client != nil {
+		return defaultClient.client, nil
+	}
+
+	config := ConfigFromEnv()
+	if defaultClient.config != nil {
+		config = *defaultClient.config
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+	client, err := New(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	defaultClient.client = client
+	return client, nil
+}
+
+// Close closes the default client, if it was built, for a graceful shutdown.
+// A later Default builds a new client.
+func Close() error {
+	defaultClient.mu.Lock()
+	defer defaultClient.mu.Unlock()
+	if defaultClient.client == nil {
+		return nil
+	}
+	err := defaultClient.client.Close()
+	defaultClient.client = nil
+	return err
+}
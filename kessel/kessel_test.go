@@ -0,0 +1,140 @@
+package kessel
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/connectivity"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"github.com/project-kessel/kessel-sdk-go/kessel/testing/integration"
+)
+
+type allowServer struct {
+	v1beta2.UnimplementedKesselInventoryServiceServer
+}
+
+func (s *allowServer) Check(ctx context.Context, in *v1beta2.CheckRequest) (*v1beta2.CheckResponse, error) {
+	return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}, nil
+}
+
+func resetDefault(t *testing.T) {
+	t.Cleanup(func() {
+		_ = Close()
+		defaultClient.mu.Lock()
+		defaultClient.config = nil
+		defaultClient.mu.Unlock()
+	})
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EndpointEnv, "kessel:9000")
+	t.Setenv(InsecureEnv, "true")
+	t.Setenv(RBACEndpointEnv, "http://rbac:8000")
+	t.Setenv(IssuerUrlEnv, "https://sso/realms/test")
+	t.Setenv(ClientIdEnv, "id")
+	t.Setenv(ClientSecretEnv, "secret")
+
+	assert.Equal(t, Config{
+		Endpoint:         "kessel:9000",
+		Insecure:         true,
+		IssuerUrl:        "https://sso/realms/test",
+		ClientId:         "id",
+		ClientSecret:     "secret",
+		RBACBaseEndpoint: "http://rbac:8000",
+	}, ConfigFromEnv())
+}
+
+func TestNew_oauth2(t *testing.T) {
+	issuer := integration.StartFakeIssuer(t, integration.Realm{Clients: map[string]string{"service": "secret"}})
+	inventory := integration.StartInventory(t, &allowServer{}, issuer)
+
+	client, err := New(testContext(t), Config{
+		Endpoint:             inventory.Target(),
+		IssuerUrl:            issuer.IssuerUrl(),
+		ClientId:             "service",
+		ClientSecret:         "secret",
+		RBACBaseEndpoint:     "http://rbac:8000",
+		TransportCredentials: inventory.TransportCredentials(),
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.WaitForReady(testContext(t)))
+	assert.Equal(t, connectivity.Ready, client.ConnectivityState())
+	response, err := client.Inventory.Check(testContext(t), &v1beta2.CheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, v1beta2.Allowed_ALLOWED_TRUE, response.GetAllowed())
+	assert.NotNil(t, client.WorkspaceOptions.Auth)
+	assert.Equal(t, "http://rbac:8000", client.AuthorizeDeps().RBACBaseEndpoint)
+}
+
+func TestNew_requiresEndpoint(t *testing.T) {
+	_, err := New(context.Background(), Config{})
+
+	assert.ErrorContains(t, err, "endpoint is required")
+}
+
+func TestDefault_fromEnv(t *testing.T) {
+	resetDefault(t)
+	t.Setenv(EndpointEnv, "localhost:9000")
+	t.Setenv(InsecureEnv, "true")
+	t.Setenv(ClientIdEnv, "")
+
+	var wg sync.WaitGroup
+	clients := make([]*Client, 8)
+	for i := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := Default()
+			assert.NoError(t, err)
+			clients[i] = client
+		}()
+	}
+	wg.Wait()
+
+	for _, client := range clients {
+		assert.Same(t, clients[0], client)
+	}
+	assert.Nil(t, clients[0].WorkspaceOptions.Auth)
+}
+
+func TestDefault_retriesAfterError(t *testing.T) {
+	resetDefault(t)
+	t.Setenv(EndpointEnv, "")
+
+	_, err := Default()
+	require.Error(t, err)
+
+	t.Setenv(EndpointEnv, "localhost:9000")
+	t.Setenv(InsecureEnv, "true")
+	client, err := Default()
+	require.NoError(t, err)
+	assert.NotNil(t, client.Inventory)
+}
+
+func TestInit(t *testing.T) {
+	resetDefault(t)
+	t.Setenv(EndpointEnv, "")
+
+	require.NoError(t, Init(Config{Endpoint: "localhost:9000", Insecure: true}))
+	first, err := Default()
+	require.NoError(t, err)
+	assert.ErrorIs(t, Init(Config{Endpoint: "other:9000"}), ErrAlreadyInitialized)
+
+	require.NoError(t, Close())
+	second, err := Default()
+	require.NoError(t, err)
+	assert.NotSame(t, first, second)
+}
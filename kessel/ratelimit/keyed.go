@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const sweepInterval = time.Minute
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant (e.g. org ID) that a
+// KeyedLimiter created with the default key function limits calls by.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set with WithTenant, or "".
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// KeyFunc picks the bucket a call is limited by.
+type KeyFunc func(ctx context.Context) string
+
+// KeyedLimiter keeps a separate token bucket per key so that one noisy tenant
+// in a multi-tenant gateway cannot use up the budget of the others. Calls
+// without a key share a single bucket. It is safe for concurrent use.
+type KeyedLimiter struct {
+	rps   float64
+	burst int
+	key   KeyFunc
+	now   func() time.Time
+
+	mu        sync.Mutex
+	limiters  map[string]*Limiter
+	lastSweep time.Time
+}
+
+// NewKeyedLimiter creates a KeyedLimiter allowing each key rps calls per
+// second with bursts of up to burst calls. When key is nil the tenant set
+// with WithTenant is used.
+func NewKeyedLimiter(rps float64, burst int, key KeyFunc) *KeyedLimiter {
+	if key == nil {
+		key = TenantFromContext
+	}
+	return &KeyedLimiter{
+		rps:      rps,
+		burst:    burst,
+		key:      key,
+		now:      time.Now,
+		limiters: map[string]*Limiter{},
+	}
+}
+
+// Wait blocks until the bucket for the key of ctx has a token or ctx is done.
+// A nil KeyedLimiter never waits.
+func (k *KeyedLimiter) Wait(ctx context.Context) error {
+	if k == nil {
+		return nil
+	}
+	return k.limiter(k.key(ctx)).Wait(ctx)
+}
+
+func (k *KeyedLimiter) limiter(key string) *Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := k.now()
+	if now.Sub(k.lastSweep) >= sweepInterval {
+		k.sweep(now)
+		k.lastSweep = now
+	}
+
+	l, ok := k.limiters[key]
+	if !ok {
+		l = NewLimiter(k.rps, k.burst)
+		l.now = k.now
+		k.limiters[key] = l
+	}
+	return l
+}
+
+// sweep drops buckets that have refilled completely; they behave exactly like
+// a new bucket, so idle tenants do not accumulate.
+func (k *KeyedLimiter) sweep(now time.Time) {
+	for key, l := range k.limiters {
+		l.mu.Lock()
+		l.advance(now)
+		full := l.tokens >= l.burst
+		l.mu.Unlock()
+		if full {
+			delete(k.limiters, key)
+		}
+	}
+}
+
+// KeyedUnaryClientInterceptor returns a gRPC interceptor that waits on the
+// bucket for the call's key before every unary call.
+func KeyedUnaryClientInterceptor(k *KeyedLimiter) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := k.Wait(ctx); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// KeyedStreamClientInterceptor returns a gRPC interceptor that waits on the
+// bucket for the call's key before opening every stream.
+func KeyedStreamClientInterceptor(k *KeyedLimiter) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := k.Wait(ctx); err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
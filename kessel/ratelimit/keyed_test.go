@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestKeyedLimiter(rps float64, burst int, now *time.Time) *KeyedLimiter {
+	k := NewKeyedLimiter(rps, burst, nil)
+	k.now = func() time.Time { return *now }
+	return k
+}
+
+func TestKeyedLimiter_separateBuckets(t *testing.T) {
+	now := time.Unix(0, 0)
+	k := newTestKeyedLimiter(1, 1, &now)
+
+	if d := k.limiter("org-a").reserve(); d != 0 {
+		t.Errorf("Expected first call for org-a to proceed, got delay %v", d)
+	}
+	if d := k.limiter("org-a").reserve(); d == 0 {
+		t.Error("Expected second call for org-a to wait")
+	}
+	if d := k.limiter("org-b").reserve(); d != 0 {
+		t.Errorf("Expected org-b not to be limited by org-a, got delay %v", d)
+	}
+}
+
+func TestKeyedLimiter_Wait(t *testing.T) {
+	k := NewKeyedLimiter(0.001, 1, nil)
+	noisy := WithTenant(context.Background(), "noisy")
+
+	if err := k.Wait(noisy); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(noisy, 10*time.Millisecond)
+	defer cancel()
+	if err := k.Wait(ctx); err == nil {
+		t.Error("Expected the noisy tenant to be limited")
+	}
+
+	quiet := WithTenant(context.Background(), "quiet")
+	if err := k.Wait(quiet); err != nil {
+		t.Errorf("Expected another tenant to proceed, got %v", err)
+	}
+
+	var nilLimiter *KeyedLimiter
+	if err := nilLimiter.Wait(noisy); err != nil {
+		t.Errorf("Expected nil limiter not to wait, got %v", err)
+	}
+}
+
+func TestKeyedLimiter_customKey(t *testing.T) {
+	type orgKey struct{}
+	k := NewKeyedLimiter(1, 1, func(ctx context.Context) string {
+		org, _ := ctx.Value(orgKey{}).(string)
+		return org
+	})
+
+	_ = k.Wait(context.WithValue(context.Background(), orgKey{}, "org-a"))
+	if _, ok := k.limiters["org-a"]; !ok {
+		t.Errorf("Expected a bucket for the custom key, got %v", k.limiters)
+	}
+}
+
+func TestKeyedLimiter_sweepsFullBuckets(t *testing.T) {
+	now := time.Unix(0, 0)
+	k := newTestKeyedLimiter(1, 1, &now)
+
+	k.limiter("idle").reserve()
+	now = now.Add(2 * sweepInterval)
+	k.limiter("active").reserve()
+
+	if _, ok := k.limiters["idle"]; ok {
+		t.Error("Expected the refilled bucket to be swept")
+	}
+	if _, ok := k.limiters["active"]; !ok {
+		t.Error("Expected the active bucket to be kept")
+	}
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func newTestLimiter(rps float64, burst int, now *time.Time) *Limiter {
+	l := NewLimiter(rps, burst)
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestLimiter_reserve(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newTestLimiter(10, 2, &now)
+
+	if d := l.reserve(); d != 0 {
+		t.Errorf("Expected first call within burst to proceed, got delay %v", d)
+	}
+	if d := l.reserve(); d != 0 {
+		t.Errorf("Expected second call within burst to proceed, got delay %v", d)
+	}
+	if d := l.reserve(); d != 100*time.Millisecond {
+		t.Errorf("Expected 100ms delay once burst is exhausted, got %v", d)
+	}
+
+	now = now.Add(time.Second)
+	if d := l.reserve(); d != 0 {
+		t.Errorf("Expected bucket to refill after one second, got delay %v", d)
+	}
+	if l.tokens != 1 {
+		t.Errorf("Expected refill to be capped at burst, got %v tokens remaining", l.tokens)
+	}
+}
+
+func TestNewLimiter_burstDefaultsToOne(t *testing.T) {
+	l := NewLimiter(1, 0)
+	if l.burst != 1 {
+		t.Errorf("Expected burst of 1, got %v", l.burst)
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	tests := []struct {
+		name          string
+		limiter       *Limiter
+		expectedError bool
+	}{
+		{
+			name:    "nil limiter does not block",
+			limiter: nil,
+		},
+		{
+			name:    "non-positive rate disables limiting",
+			limiter: NewLimiter(0, 1),
+		},
+		{
+			name:    "token available",
+			limiter: NewLimiter(1, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limiter.Wait(context.Background())
+			if (err != nil) != tt.expectedError {
+				t.Errorf("Wait() error = %v, expectedError %v", err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestLimiter_Wait_contextCancelledReturnsToken(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newTestLimiter(0.001, 1, &now)
+	l.reserve()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := l.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if l.tokens != 0 {
+		t.Errorf("Expected cancelled reservation to be returned, got %v tokens", l.tokens)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newTestLimiter(0.001, 1, &now)
+	interceptor := UnaryClientInterceptor(l)
+
+	calls := 0
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return nil
+	}
+
+	if err := interceptor(context.Background(), "/test/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("Expected first call to succeed, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := interceptor(ctx, "/test/Method", nil, nil, nil, invoker); err == nil {
+		t.Error("Expected rate limited call to fail when context expires")
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected invoker to be called once, got %d", calls)
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newTestLimiter(0.001, 1, &now)
+	interceptor := StreamClientInterceptor(l)
+
+	calls := 0
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		calls++
+		return nil, nil
+	}
+
+	if _, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/test/Stream", streamer); err != nil {
+		t.Fatalf("Expected first stream to open, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := interceptor(ctx, &grpc.StreamDesc{}, nil, "/test/Stream", streamer); err == nil {
+		t.Error("Expected rate limited stream to fail when context expires")
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected streamer to be called once, got %d", calls)
+	}
+}
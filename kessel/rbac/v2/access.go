@@ -0,0 +1,214 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/grpc/status"
+)
+
+// PermissionSet records which of the relations asked about were granted.
+type PermissionSet map[string]bool
+
+// Has reports whether relation was granted.
+func (p PermissionSet) Has(relation string) bool {
+	return p[relation]
+}
+
+// Granted returns the granted relations, sorted.
+func (p PermissionSet) Granted() []string {
+	var granted []string
+	for relation, allowed := range p {
+		if allowed {
+			granted = append(granted, relation)
+		}
+	}
+	slices.Sort(granted)
+	return granted
+}
+
+// GetPrincipalAccess checks which of relations the principal has on the
+// workspace, e.g. to decide which actions a UI offers. All relations are
+// checked in a single CheckBulk request. Relations whose check failed are
+// left out of the set and reported in a *errors.Multi keyed by relation,
+// returned together with the set of the relations that were checked.
+//
+//	access, _, err := v2.GetPrincipalAccess(ctx, client, "alice", "redhat", workspaceId,
+//		[]string{v2.RelationInventoryHostView, v2.RelationInventoryHostUpdate})
+func GetPrincipalAccess(
+	ctx context.Context,
+	inventory v1beta2.KesselInventoryServiceClient,
+	principalId string,
+	domain string,
+	workspaceId string,
+	relations []string,
+	opts ...CheckOption,
+) (PermissionSet, *v1beta2.ConsistencyToken, error) {
+	options := checkOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	access := PermissionSet{}
+	if len(relations) == 0 {
+		return access, nil, nil
+	}
+
+	object := WorkspaceResource(workspaceId)
+	subject := PrincipalSubject(principalId, domain)
+	items := make([]*v1beta2.CheckBulkRequestItem, len(relations))
+	for i, relation := range relations {
+		items[i] = &v1beta2.CheckBulkRequestItem{
+			Object:   object,
+			Relation: relation,
+			Subject:  subject,
+		}
+	}
+
+	response, err := inventory.CheckBulk(ctx, &v1beta2.CheckBulkRequest{
+		Items:       items,
+		Consistency: options.consistency,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(response.GetPairs()) != len(relations) {
+		return nil, nil, fmt.Errorf("unexpected number of CheckBulk results: got %d, expected %d", len(response.GetPairs()), len(relations))
+	}
+
+	errs := &kesselerrors.Multi{}
+	for i, pair := range response.GetPairs() {
+		if pair.GetError() != nil {
+			errs.Append(i, relations[i], status.ErrorProto(pair.GetError()))
+			continue
+		}
+		access[relations[i]] = pair.GetItem().GetAllowed() == v1beta2.Allowed_ALLOWED_TRUE
+	}
+	return access, response.GetConsistencyToken(), errs.ErrorOrNil()
+}
+
+// permissionMatrixChunkSize is the number of checks sent in each CheckBulk
+// request by GetPermissionMatrix, well below the server's limit so that large
+// matrices are spread over several requests in parallel.
+const permissionMatrixChunkSize = 100
+
+// defaultPermissionMatrixConcurrency is the number of CheckBulk requests
+// GetPermissionMatrix has in flight unless WithCheckConcurrency is given.
+const defaultPermissionMatrixConcurrency = 4
+
+// WorkspacePermission is one cell of a permission matrix: a permission on a
+// workspace.
+type WorkspacePermission struct {
+	WorkspaceId string
+	Permission  string
+}
+
+// PermissionMatrix records, per workspace ID, which of the permissions asked
+// about were granted.
+type PermissionMatrix map[string]PermissionSet
+
+// Has reports whether permission was granted on the workspace.
+func (m PermissionMatrix) Has(workspaceId string, permission string) bool {
+	return m[workspaceId].Has(permission)
+}
+
+// GetPermissionMatrix checks every workspace and permission pair for the
+// principal, e.g. to render which actions a UI offers on each workspace of a
+// list. The checks are split into CheckBulk requests of at most 100 items,
+// sent in parallel (4 at a time, see WithCheckConcurrency). Pairs whose check
+// failed are left out of the matrix and reported in a *errors.Multi keyed by
+// "<workspace>/<permission>", returned together with the matrix of the pairs
+// that were checked. If a request fails, the remaining ones are canceled and
+// its error is returned with a nil matrix.
+//
+//	matrix, err := v2.GetPermissionMatrix(ctx, client, "alice", "redhat", []v2.WorkspacePermission{
+//		{WorkspaceId: "ws-1", Permission: v2.RelationInventoryHostView},
+//		{WorkspaceId: "ws-2", Permission: v2.RelationInventoryHostUpdate},
+//	})
+//	canEdit := matrix.Has("ws-2", v2.RelationInventoryHostUpdate)
+func GetPermissionMatrix(
+	ctx context.Context,
+	inventory v1beta2.KesselInventoryServiceClient,
+	principalId string,
+	domain string,
+	checks []WorkspacePermission,
+	opts ...CheckOption,
+) (PermissionMatrix, error) {
+	options := checkOptions{concurrency: defaultPermissionMatrixConcurrency}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	subject := PrincipalSubject(principalId, domain)
+	items := make([]*v1beta2.CheckBulkRequestItem, len(checks))
+	for i, check := range checks {
+		items[i] = &v1beta2.CheckBulkRequestItem{
+			Object:   WorkspaceResource(check.WorkspaceId),
+			Relation: check.Permission,
+			Subject:  subject,
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var once sync.Once
+	var failure error
+	pairs := make([]*v1beta2.CheckBulkResponsePair, len(items))
+	starts := make(chan int)
+	var wg sync.WaitGroup
+	for range min(max(options.concurrency, 1), (len(items)+permissionMatrixChunkSize-1)/permissionMatrixChunkSize) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				if ctx.Err() != nil {
+					continue
+				}
+				chunk := items[start:min(start+permissionMatrixChunkSize, len(items))]
+				response, err := inventory.CheckBulk(ctx, &v1beta2.CheckBulkRequest{
+					Items:       chunk,
+					Consistency: options.consistency,
+				}, options.callOptions...)
+				if err == nil && len(response.GetPairs()) != len(chunk) {
+					err = fmt.Errorf("unexpected number of CheckBulk results: got %d, expected %d", len(response.GetPairs()), len(chunk))
+				}
+				if err != nil {
+					once.Do(func() {
+						failure = err
+						cancel()
+					})
+					continue
+				}
+				copy(pairs[start:], response.GetPairs())
+			}
+		}()
+	}
+	for start := 0; start < len(items); start += permissionMatrixChunkSize {
+		starts <- start
+	}
+	close(starts)
+	wg.Wait()
+	if failure != nil {
+		return nil, failure
+	}
+
+	matrix := PermissionMatrix{}
+	errs := &kesselerrors.Multi{}
+	for i, pair := range pairs {
+		check := checks[i]
+		if pair.GetError() != nil {
+			errs.Append(i, check.WorkspaceId+"/"+check.Permission, status.ErrorProto(pair.GetError()))
+			continue
+		}
+		if matrix[check.WorkspaceId] == nil {
+			matrix[check.WorkspaceId] = PermissionSet{}
+		}
+		matrix[check.WorkspaceId][check.Permission] = pair.GetItem().GetAllowed() == v1beta2.Allowed_ALLOWED_TRUE
+	}
+	return matrix, errs.ErrorOrNil()
+}
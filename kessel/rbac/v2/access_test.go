@@ -0,0 +1,198 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type mockCheckBulkClient struct {
+	v1beta2.KesselInventoryServiceClient
+	response        *v1beta2.CheckBulkResponse
+	err             error
+	capturedRequest *v1beta2.CheckBulkRequest
+}
+
+func (m *mockCheckBulkClient) CheckBulk(ctx context.Context, in *v1beta2.CheckBulkRequest, opts ...grpc.CallOption) (*v1beta2.CheckBulkResponse, error) {
+	m.capturedRequest = in
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.response, nil
+}
+
+func allowedPair(allowed v1beta2.Allowed) *v1beta2.CheckBulkResponsePair {
+	return &v1beta2.CheckBulkResponsePair{
+		Response: &v1beta2.CheckBulkResponsePair_Item{Item: &v1beta2.CheckBulkResponseItem{Allowed: allowed}},
+	}
+}
+
+func TestGetPrincipalAccess(t *testing.T) {
+	token := &v1beta2.ConsistencyToken{Token: "token-1"}
+	relations := []string{"inventory_host_view", "inventory_host_update", "inventory_host_delete"}
+
+	client := &mockCheckBulkClient{response: &v1beta2.CheckBulkResponse{
+		Pairs: []*v1beta2.CheckBulkResponsePair{
+			allowedPair(v1beta2.Allowed_ALLOWED_TRUE),
+			allowedPair(v1beta2.Allowed_ALLOWED_FALSE),
+			allowedPair(v1beta2.Allowed_ALLOWED_TRUE),
+		},
+		ConsistencyToken: token,
+	}}
+	consistency := &v1beta2.Consistency{Requirement: &v1beta2.Consistency_MinimizeLatency{MinimizeLatency: true}}
+
+	access, gotToken, err := GetPrincipalAccess(context.Background(), client, "alice", "redhat", "ws-1", relations, WithCheckConsistency(consistency))
+	require.NoError(t, err)
+	assert.Equal(t, token, gotToken)
+	assert.True(t, access.Has("inventory_host_view"))
+	assert.False(t, access.Has("inventory_host_update"))
+	assert.False(t, access.Has("unknown"))
+	assert.Equal(t, []string{"inventory_host_delete", "inventory_host_view"}, access.Granted())
+
+	require.Len(t, client.capturedRequest.GetItems(), 3)
+	assert.Equal(t, consistency, client.capturedRequest.GetConsistency())
+	for i, item := range client.capturedRequest.GetItems() {
+		assert.Equal(t, relations[i], item.GetRelation())
+		assert.Equal(t, "ws-1", item.GetObject().GetResourceId())
+		assert.Equal(t, "workspace", item.GetObject().GetResourceType())
+		assert.Equal(t, "redhat/alice", item.GetSubject().GetResource().GetResourceId())
+	}
+}
+
+func TestGetPrincipalAccess_pairError(t *testing.T) {
+	client := &mockCheckBulkClient{response: &v1beta2.CheckBulkResponse{
+		Pairs: []*v1beta2.CheckBulkResponsePair{
+			allowedPair(v1beta2.Allowed_ALLOWED_TRUE),
+			{Response: &v1beta2.CheckBulkResponsePair_Error{Error: &rpcstatus.Status{Code: int32(codes.Internal), Message: "boom"}}},
+		},
+	}}
+
+	access, _, err := GetPrincipalAccess(context.Background(), client, "alice", "redhat", "ws-1", []string{"view", "update"})
+	require.Error(t, err)
+	var multi *kesselerrors.Multi
+	require.ErrorAs(t, err, &multi)
+	assert.Equal(t, 1, multi.Len())
+	assert.Contains(t, err.Error(), "update")
+	assert.True(t, access.Has("view"))
+	_, checked := access["update"]
+	assert.False(t, checked)
+}
+
+func TestGetPrincipalAccess_errors(t *testing.T) {
+	client := &mockCheckBulkClient{err: status.Error(codes.Unavailable, "down")}
+	access, token, err := GetPrincipalAccess(context.Background(), client, "alice", "redhat", "ws-1", []string{"view"})
+	require.Error(t, err)
+	assert.Nil(t, access)
+	assert.Nil(t, token)
+
+	client = &mockCheckBulkClient{response: &v1beta2.CheckBulkResponse{}}
+	_, _, err = GetPrincipalAccess(context.Background(), client, "alice", "redhat", "ws-1", []string{"view"})
+	assert.ErrorContains(t, err, "unexpected number of CheckBulk results")
+
+	client = &mockCheckBulkClient{}
+	access, _, err = GetPrincipalAccess(context.Background(), client, "alice", "redhat", "ws-1", nil)
+	require.NoError(t, err)
+	assert.Empty(t, access.Granted())
+	assert.Nil(t, client.capturedRequest)
+}
+
+// matrixClient grants view permissions, fails checks of "broken" and records
+// the size of every CheckBulk request.
+type matrixClient struct {
+	v1beta2.KesselInventoryServiceClient
+	mu       sync.Mutex
+	requests []int
+	err      error
+}
+
+func (m *matrixClient) CheckBulk(ctx context.Context, in *v1beta2.CheckBulkRequest, opts ...grpc.CallOption) (*v1beta2.CheckBulkResponse, error) {
+	m.mu.Lock()
+	m.requests = append(m.requests, len(in.GetItems()))
+	m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	response := &v1beta2.CheckBulkResponse{}
+	for _, item := range in.GetItems() {
+		switch {
+		case item.GetRelation() == "broken":
+			response.Pairs = append(response.Pairs, &v1beta2.CheckBulkResponsePair{
+				Response: &v1beta2.CheckBulkResponsePair_Error{Error: &rpcstatus.Status{Code: int32(codes.Internal), Message: "boom"}},
+			})
+		case strings.HasSuffix(item.GetRelation(), "_view"):
+			response.Pairs = append(response.Pairs, allowedPair(v1beta2.Allowed_ALLOWED_TRUE))
+		default:
+			response.Pairs = append(response.Pairs, allowedPair(v1beta2.Allowed_ALLOWED_FALSE))
+		}
+	}
+	return response, nil
+}
+
+func TestGetPermissionMatrix(t *testing.T) {
+	var checks []WorkspacePermission
+	for i := range 120 {
+		workspace := fmt.Sprintf("ws-%d", i)
+		checks = append(checks,
+			WorkspacePermission{WorkspaceId: workspace, Permission: "inventory_host_view"},
+			WorkspacePermission{WorkspaceId: workspace, Permission: "inventory_host_update"},
+		)
+	}
+	client := &matrixClient{}
+
+	matrix, err := GetPermissionMatrix(context.Background(), client, "alice", "redhat", checks, WithCheckConcurrency(2))
+	require.NoError(t, err)
+	assert.Len(t, matrix, 120)
+	assert.True(t, matrix.Has("ws-119", "inventory_host_view"))
+	assert.False(t, matrix.Has("ws-119", "inventory_host_update"))
+	assert.False(t, matrix.Has("ws-unknown", "inventory_host_view"))
+	assert.Equal(t, []string{"inventory_host_view"}, matrix["ws-0"].Granted())
+	assert.ElementsMatch(t, []int{100, 100, 40}, client.requests)
+}
+
+func TestGetPermissionMatrix_pairErrors(t *testing.T) {
+	checks := []WorkspacePermission{
+		{WorkspaceId: "ws-1", Permission: "inventory_host_view"},
+		{WorkspaceId: "ws-1", Permission: "broken"},
+	}
+
+	matrix, err := GetPermissionMatrix(context.Background(), &matrixClient{}, "alice", "redhat", checks)
+
+	var multi *kesselerrors.Multi
+	require.ErrorAs(t, err, &multi)
+	require.Equal(t, 1, multi.Len())
+	assert.Equal(t, "ws-1/broken", multi.Errors[0].Key)
+	assert.True(t, matrix.Has("ws-1", "inventory_host_view"))
+	_, checked := matrix["ws-1"]["broken"]
+	assert.False(t, checked)
+}
+
+func TestGetPermissionMatrix_requestError(t *testing.T) {
+	client := &matrixClient{err: status.Error(codes.Unavailable, "down")}
+
+	matrix, err := GetPermissionMatrix(context.Background(), client, "alice", "redhat", []WorkspacePermission{{WorkspaceId: "ws-1", Permission: "inventory_host_view"}})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Nil(t, matrix)
+}
+
+func TestGetPermissionMatrix_empty(t *testing.T) {
+	client := &matrixClient{}
+
+	matrix, err := GetPermissionMatrix(context.Background(), client, "alice", "redhat", nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, matrix)
+	assert.Empty(t, client.requests)
+}
@@ -0,0 +1,176 @@
+package v2
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/invalidation"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+const defaultMaxCachedWorkspaces = 10000
+
+// WorkspaceCache caches default workspaces per org ID for a fixed TTL, keeping
+// at most maxEntries and evicting the least recently used org first. It is
+// safe for concurrent use; share one instance for the application's lifetime.
+type WorkspaceCache struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List
+}
+
+type workspaceCacheEntry struct {
+	orgId     string
+	workspace *Workspace
+	expiresAt time.Time
+}
+
+// NewWorkspaceCache creates a WorkspaceCache whose entries expire after ttl
+// and which holds at most maxEntries orgs (10000 when maxEntries <= 0).
+func NewWorkspaceCache(ttl time.Duration, maxEntries int) *WorkspaceCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxCachedWorkspaces
+	}
+	return &WorkspaceCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+func (c *WorkspaceCache) get(orgId string) (*Workspace, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[orgId]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*workspaceCacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.workspace, true
+}
+
+func (c *WorkspaceCache) set(orgId string, workspace *Workspace) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &workspaceCacheEntry{orgId: orgId, workspace: workspace, expiresAt: c.now().Add(c.ttl)}
+	if element, ok := c.entries[orgId]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[orgId] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *WorkspaceCache) remove(element *list.Element) {
+	delete(c.entries, c.lru.Remove(element).(*workspaceCacheEntry).orgId)
+}
+
+// Invalidate drops the default workspace of event.OrgId and, for a workspace
+// resource, any cached workspace with that ID. It makes WorkspaceCache an
+// invalidation.Invalidator.
+func (c *WorkspaceCache) Invalidate(event invalidation.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[event.OrgId]; ok && event.OrgId != "" {
+		c.remove(element)
+	}
+	if event.Resource.GetResourceType() == "workspace" {
+		for element := c.lru.Front(); element != nil; {
+			next := element.Next()
+			if workspace := element.Value.(*workspaceCacheEntry).workspace; workspace != nil && workspace.Id == event.Resource.GetResourceId() {
+				c.remove(element)
+			}
+			element = next
+		}
+	}
+}
+
+// AuthorizeDeps holds the clients and settings used by Authorize.
+type AuthorizeDeps struct {
+	Inventory        v1beta2.KesselInventoryServiceClient
+	RBACBaseEndpoint string
+	WorkspaceOptions FetchWorkspaceOptions
+	// Optionally cache default workspaces. When nil the default workspace is
+	// fetched from RBAC on every call.
+	WorkspaceCache *WorkspaceCache
+	// Optionally set the consistency of the check.
+	Consistency *v1beta2.Consistency
+}
+
+// Decision is the outcome of Authorize.
+type Decision struct {
+	Allowed          bool
+	WorkspaceId      string
+	ConsistencyToken *v1beta2.ConsistencyToken
+}
+
+// Authorize checks whether principal has relation on the default workspace of
+// orgId. It resolves the default workspace (through deps.WorkspaceCache when
+// set) and performs the Check in one call:
+//
+//	decision, err := v2.Authorize(ctx, deps, orgId, v2.PrincipalSubject("alice", "redhat"), v2.RelationInventoryHostView)
+//	if err != nil {
+//	    return err
+//	}
+//	if !decision.Allowed {
+//	    return errForbidden
+//	}
+func Authorize(ctx context.Context, deps AuthorizeDeps, orgId string, principal *v1beta2.SubjectReference, relation string) (Decision, error) {
+	workspace, err := defaultWorkspace(ctx, deps, orgId)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	response, err := deps.Inventory.Check(ctx, &v1beta2.CheckRequest{
+		Object:      WorkspaceResource(workspace.Id),
+		Relation:    relation,
+		Subject:     principal,
+		Consistency: deps.Consistency,
+	})
+	if err != nil {
+		return Decision{}, err
+	}
+
+	return Decision{
+		Allowed:          response.GetAllowed() == v1beta2.Allowed_ALLOWED_TRUE,
+		WorkspaceId:      workspace.Id,
+		ConsistencyToken: response.GetConsistencyToken(),
+	}, nil
+}
+
+func defaultWorkspace(ctx context.Context, deps AuthorizeDeps, orgId string) (*Workspace, error) {
+	orgId = resolveOrgId(ctx, orgId)
+	if deps.WorkspaceCache != nil {
+		if workspace, ok := deps.WorkspaceCache.get(orgId); ok {
+			return workspace, nil
+		}
+	}
+
+	workspace, err := FetchDefaultWorkspace(ctx, deps.RBACBaseEndpoint, orgId, deps.WorkspaceOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if deps.WorkspaceCache != nil {
+		deps.WorkspaceCache.set(orgId, workspace)
+	}
+	return workspace, nil
+}
@@ -0,0 +1,229 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/invalidation"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type mockCheckClient struct {
+	v1beta2.KesselInventoryServiceClient
+	response         *v1beta2.CheckResponse
+	err              error
+	capturedRequests []*v1beta2.CheckRequest
+	capturedOptions  []grpc.CallOption
+}
+
+func (m *mockCheckClient) Check(ctx context.Context, in *v1beta2.CheckRequest, opts ...grpc.CallOption) (*v1beta2.CheckResponse, error) {
+	m.capturedRequests = append(m.capturedRequests, in)
+	m.capturedOptions = opts
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.response, nil
+}
+
+func newDefaultWorkspaceServer(t *testing.T, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		response := workspaceAPIResponse{
+			Data: []Workspace{{Id: "default-ws", Name: "Default", Type: "default"}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode test response: %v", err)
+		}
+	}))
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name            string
+		response        *v1beta2.CheckResponse
+		checkErr        error
+		expectedError   bool
+		expectedAllowed bool
+	}{
+		{
+			name: "allowed",
+			response: &v1beta2.CheckResponse{
+				Allowed:          v1beta2.Allowed_ALLOWED_TRUE,
+				ConsistencyToken: &v1beta2.ConsistencyToken{Token: "tok"},
+			},
+			expectedAllowed: true,
+		},
+		{
+			name:            "denied",
+			response:        &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_FALSE},
+			expectedAllowed: false,
+		},
+		{
+			name:          "check error",
+			checkErr:      status.Error(codes.Unavailable, "down"),
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := newDefaultWorkspaceServer(t, &requests)
+			defer server.Close()
+
+			client := &mockCheckClient{response: tt.response, err: tt.checkErr}
+			deps := AuthorizeDeps{Inventory: client, RBACBaseEndpoint: server.URL}
+			principal := PrincipalSubject("alice", "redhat")
+
+			decision, err := Authorize(context.Background(), deps, "org123", principal, "inventory_host_view")
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.Equal(t, Decision{}, decision)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedAllowed, decision.Allowed)
+			assert.Equal(t, "default-ws", decision.WorkspaceId)
+			assert.Equal(t, tt.response.GetConsistencyToken(), decision.ConsistencyToken)
+
+			require.Len(t, client.capturedRequests, 1)
+			req := client.capturedRequests[0]
+			assert.Equal(t, "workspace", req.Object.ResourceType)
+			assert.Equal(t, "default-ws", req.Object.ResourceId)
+			assert.Equal(t, "inventory_host_view", req.Relation)
+			assert.Same(t, principal, req.Subject)
+		})
+	}
+}
+
+func TestAuthorize_workspaceCache(t *testing.T) {
+	requests := 0
+	server := newDefaultWorkspaceServer(t, &requests)
+	defer server.Close()
+
+	now := time.Unix(0, 0)
+	cache := NewWorkspaceCache(time.Minute, 0)
+	cache.now = func() time.Time { return now }
+
+	client := &mockCheckClient{response: &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}}
+	deps := AuthorizeDeps{Inventory: client, RBACBaseEndpoint: server.URL, WorkspaceCache: cache}
+
+	for i := 0; i < 3; i++ {
+		_, err := Authorize(context.Background(), deps, "org123", PrincipalSubject("alice", "redhat"), "view")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, requests, "workspace should be fetched once while cached")
+
+	_, err := Authorize(context.Background(), deps, "org456", PrincipalSubject("alice", "redhat"), "view")
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests, "cache should be keyed by org ID")
+
+	now = now.Add(time.Minute)
+	_, err = Authorize(context.Background(), deps, "org123", PrincipalSubject("alice", "redhat"), "view")
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests, "expired entries should be refetched")
+}
+
+func TestWorkspaceCache_maxEntries(t *testing.T) {
+	cache := NewWorkspaceCache(time.Minute, 2)
+	cache.set("org1", &Workspace{Id: "ws-1"})
+	cache.set("org2", &Workspace{Id: "ws-2"})
+	_, ok := cache.get("org1")
+	require.True(t, ok)
+
+	cache.set("org3", &Workspace{Id: "ws-3"})
+
+	_, ok = cache.get("org2")
+	assert.False(t, ok, "least recently used org should be evicted")
+	for _, orgId := range []string{"org1", "org3"} {
+		_, ok := cache.get(orgId)
+		assert.True(t, ok, orgId)
+	}
+	assert.Equal(t, 2, cache.lru.Len())
+}
+
+func TestWorkspaceCache_expiredEntriesRemoved(t *testing.T) {
+	now := time.Unix(0, 0)
+	cache := NewWorkspaceCache(time.Minute, 0)
+	cache.now = func() time.Time { return now }
+	cache.set("org1", &Workspace{Id: "ws-1"})
+
+	now = now.Add(time.Minute)
+	_, ok := cache.get("org1")
+
+	assert.False(t, ok)
+	assert.Empty(t, cache.entries, "expired entry should be deleted on lookup")
+	assert.Zero(t, cache.lru.Len())
+}
+
+func TestWorkspaceCache_Invalidate(t *testing.T) {
+	tests := []struct {
+		name           string
+		event          invalidation.Event
+		expectedCached []string
+	}{
+		{name: "org", event: invalidation.Event{OrgId: "org1"}, expectedCached: []string{"org2"}},
+		{name: "workspace", event: invalidation.Event{Resource: WorkspaceResource("ws-2")}, expectedCached: []string{"org1"}},
+		{name: "other resource", event: invalidation.Event{Resource: &v1beta2.ResourceReference{ResourceType: "host", ResourceId: "ws-2"}}, expectedCached: []string{"org1", "org2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewWorkspaceCache(time.Minute, 0)
+			cache.set("org1", &Workspace{Id: "ws-1"})
+			cache.set("org2", &Workspace{Id: "ws-2"})
+
+			cache.Invalidate(tt.event)
+
+			var cached []string
+			for _, orgId := range []string{"org1", "org2"} {
+				if _, ok := cache.get(orgId); ok {
+					cached = append(cached, orgId)
+				}
+			}
+			assert.Equal(t, tt.expectedCached, cached)
+		})
+	}
+}
+
+func TestAuthorize_workspaceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := &mockCheckClient{}
+	_, err := Authorize(context.Background(), AuthorizeDeps{Inventory: client, RBACBaseEndpoint: server.URL}, "org123", PrincipalSubject("alice", "redhat"), "view")
+
+	assert.Error(t, err)
+	assert.Empty(t, client.capturedRequests)
+}
+
+func TestAuthorize_orgIdFromContext(t *testing.T) {
+	requests := 0
+	server := newDefaultWorkspaceServer(t, &requests)
+	defer server.Close()
+
+	cache := NewWorkspaceCache(time.Minute, 0)
+	client := &mockCheckClient{response: &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}}
+	deps := AuthorizeDeps{Inventory: client, RBACBaseEndpoint: server.URL, WorkspaceCache: cache}
+
+	_, err := Authorize(WithOrgId(context.Background(), "org123"), deps, "", PrincipalSubject("alice", "redhat"), "view")
+	require.NoError(t, err)
+	_, err = Authorize(context.Background(), deps, "org123", PrincipalSubject("alice", "redhat"), "view")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, requests, "cache should be keyed by the org ID of the context")
+}
@@ -0,0 +1,103 @@
+package v2
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// CheckOption configures a CheckWorkspaceAccess or
+// CheckWorkspaceAccessForUpdate call.
+type CheckOption func(*checkOptions)
+
+type checkOptions struct {
+	consistency *v1beta2.Consistency
+	callOptions []grpc.CallOption
+	concurrency int
+}
+
+// WithCheckConsistency sets the consistency requirement for the check.
+func WithCheckConsistency(c *v1beta2.Consistency) CheckOption {
+	return func(o *checkOptions) {
+		o.consistency = c
+	}
+}
+
+// WithCheckCallOptions passes opts to the gRPC call, e.g. grpc.WaitForReady,
+// grpc.Header or grpc.UseCompressor.
+func WithCheckCallOptions(opts ...grpc.CallOption) CheckOption {
+	return func(o *checkOptions) {
+		o.callOptions = append(o.callOptions, opts...)
+	}
+}
+
+// WithCheckConcurrency sets how many CheckBulk requests GetPermissionMatrix
+// sends in parallel. Other calls make a single request and ignore it.
+func WithCheckConcurrency(n int) CheckOption {
+	return func(o *checkOptions) {
+		o.concurrency = n
+	}
+}
+
+// CheckWorkspaceAccess reports whether the principal (e.g. "alice" in the
+// "redhat" domain) has permission on the workspace, returning the consistency
+// token of the check so later reads can be made at least as fresh.
+//
+//	allowed, token, err := v2.CheckWorkspaceAccess(ctx, client, "alice", "redhat", v2.RelationInventoryHostView, workspaceId)
+func CheckWorkspaceAccess(
+	ctx context.Context,
+	inventory v1beta2.KesselInventoryServiceClient,
+	principalId string,
+	domain string,
+	permission string,
+	workspaceId string,
+	opts ...CheckOption,
+) (bool, *v1beta2.ConsistencyToken, error) {
+	options := checkOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	response, err := inventory.Check(ctx, &v1beta2.CheckRequest{
+		Object:      WorkspaceResource(workspaceId),
+		Relation:    permission,
+		Subject:     PrincipalSubject(principalId, domain),
+		Consistency: options.consistency,
+	}, options.callOptions...)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return response.GetAllowed() == v1beta2.Allowed_ALLOWED_TRUE, response.GetConsistencyToken(), nil
+}
+
+// CheckWorkspaceAccessForUpdate is CheckWorkspaceAccess for write paths. It
+// uses CheckForUpdate, which always evaluates against the latest state, so
+// WithCheckConsistency is ignored.
+func CheckWorkspaceAccessForUpdate(
+	ctx context.Context,
+	inventory v1beta2.KesselInventoryServiceClient,
+	principalId string,
+	domain string,
+	permission string,
+	workspaceId string,
+	opts ...CheckOption,
+) (bool, *v1beta2.ConsistencyToken, error) {
+	options := checkOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	response, err := inventory.CheckForUpdate(ctx, &v1beta2.CheckForUpdateRequest{
+		Object:   WorkspaceResource(workspaceId),
+		Relation: permission,
+		Subject:  PrincipalSubject(principalId, domain),
+	}, options.callOptions...)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return response.GetAllowed() == v1beta2.Allowed_ALLOWED_TRUE, response.GetConsistencyToken(), nil
+}
@@ -0,0 +1,134 @@
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+type mockCheckForUpdateClient struct {
+	v1beta2.KesselInventoryServiceClient
+	response        *v1beta2.CheckForUpdateResponse
+	err             error
+	capturedRequest *v1beta2.CheckForUpdateRequest
+	capturedOptions []grpc.CallOption
+}
+
+func (m *mockCheckForUpdateClient) CheckForUpdate(ctx context.Context, in *v1beta2.CheckForUpdateRequest, opts ...grpc.CallOption) (*v1beta2.CheckForUpdateResponse, error) {
+	m.capturedRequest = in
+	m.capturedOptions = opts
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.response, nil
+}
+
+func TestCheckWorkspaceAccess(t *testing.T) {
+	token := &v1beta2.ConsistencyToken{Token: "token-1"}
+	consistency := &v1beta2.Consistency{Requirement: &v1beta2.Consistency_MinimizeLatency{MinimizeLatency: true}}
+
+	tests := []struct {
+		name            string
+		response        *v1beta2.CheckResponse
+		err             error
+		opts            []CheckOption
+		expectedAllowed bool
+		expectedError   bool
+	}{
+		{
+			name:            "allowed",
+			response:        &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE, ConsistencyToken: token},
+			expectedAllowed: true,
+		},
+		{
+			name:     "denied",
+			response: &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_FALSE, ConsistencyToken: token},
+		},
+		{
+			name:     "with consistency",
+			response: &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_FALSE, ConsistencyToken: token},
+			opts:     []CheckOption{WithCheckConsistency(consistency)},
+		},
+		{
+			name:          "check error",
+			err:           status.Error(codes.Unavailable, "down"),
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockCheckClient{response: tt.response, err: tt.err}
+
+			allowed, gotToken, err := CheckWorkspaceAccess(context.Background(), client, "alice", "redhat", "inventory_host_view", "ws-1", tt.opts...)
+
+			require.Len(t, client.capturedRequests, 1)
+			request := client.capturedRequests[0]
+			assert.Equal(t, "ws-1", request.Object.ResourceId)
+			assert.Equal(t, "workspace", request.Object.ResourceType)
+			assert.Equal(t, "rbac", request.Object.Reporter.Type)
+			assert.Equal(t, "inventory_host_view", request.Relation)
+			assert.Equal(t, "redhat/alice", request.Subject.Resource.ResourceId)
+			if len(tt.opts) > 0 {
+				assert.Equal(t, consistency, request.Consistency)
+			} else {
+				assert.Nil(t, request.Consistency)
+			}
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.False(t, allowed)
+				assert.Nil(t, gotToken)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAllowed, allowed)
+			assert.Equal(t, token, gotToken)
+		})
+	}
+}
+
+func TestCheckWorkspaceAccessForUpdate(t *testing.T) {
+	token := &v1beta2.ConsistencyToken{Token: "token-1"}
+	client := &mockCheckForUpdateClient{
+		response: &v1beta2.CheckForUpdateResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE, ConsistencyToken: token},
+	}
+
+	allowed, gotToken, err := CheckWorkspaceAccessForUpdate(context.Background(), client, "alice", "redhat", "inventory_host_update", "ws-1")
+
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, token, gotToken)
+	require.NotNil(t, client.capturedRequest)
+	assert.Equal(t, "inventory_host_update", client.capturedRequest.Relation)
+	assert.Equal(t, "redhat/alice", client.capturedRequest.Subject.Resource.ResourceId)
+
+	client.err = status.Error(codes.PermissionDenied, "nope")
+	allowed, gotToken, err = CheckWorkspaceAccessForUpdate(context.Background(), client, "alice", "redhat", "inventory_host_update", "ws-1")
+	assert.Error(t, err)
+	assert.False(t, allowed)
+	assert.Nil(t, gotToken)
+}
+
+func TestCheckWorkspaceAccess_callOptions(t *testing.T) {
+	waitForReady := grpc.WaitForReady(true)
+	client := &mockCheckClient{response: &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}}
+
+	_, _, err := CheckWorkspaceAccess(context.Background(), client, "alice", "redhat", "inventory_host_view", "ws-1", WithCheckCallOptions(waitForReady))
+
+	require.NoError(t, err)
+	assert.Equal(t, []grpc.CallOption{waitForReady}, client.capturedOptions)
+
+	updateClient := &mockCheckForUpdateClient{response: &v1beta2.CheckForUpdateResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}}
+	_, _, err = CheckWorkspaceAccessForUpdate(context.Background(), updateClient, "alice", "redhat", "inventory_host_update", "ws-1", WithCheckCallOptions(waitForReady))
+
+	require.NoError(t, err)
+	assert.Equal(t, []grpc.CallOption{waitForReady}, updateClient.capturedOptions)
+}
@@ -0,0 +1,80 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const clowderConfigEnv = "ACG_CONFIG"
+
+// EndpointResolver returns the RBAC base endpoint (e.g. "https://rbac:8000").
+// Set FetchWorkspaceOptions.EndpointResolver to resolve the endpoint from a
+// central configuration instead of passing it to every call.
+type EndpointResolver func(ctx context.Context) (string, error)
+
+// StaticEndpoint returns an EndpointResolver that always resolves to endpoint.
+func StaticEndpoint(endpoint string) EndpointResolver {
+	return func(ctx context.Context) (string, error) {
+		return endpoint, nil
+	}
+}
+
+type clowderConfig struct {
+	Endpoints        []clowderEndpoint `json:"endpoints"`
+	PrivateEndpoints []clowderEndpoint `json:"privateEndpoints"`
+}
+
+type clowderEndpoint struct {
+	App      string `json:"app"`
+	Name     string `json:"name"`
+	Hostname string `json:"hostname"`
+	Port     int    `json:"port"`
+	TLSPort  int    `json:"tlsPort"`
+}
+
+// ClowderEndpointResolver returns an EndpointResolver that reads the Clowder
+// app config file named by the ACG_CONFIG environment variable and resolves
+// the endpoint of the given app and deployment name (typically "rbac" and
+// "service"). Public endpoints are searched before private ones. The TLS port
+// is used with https when Clowder provides one. The file is read on every
+// call so config updates are picked up.
+func ClowderEndpointResolver(app string, name string) EndpointResolver {
+	return func(ctx context.Context) (string, error) {
+		path := os.Getenv(clowderConfigEnv)
+		if path == "" {
+			return "", fmt.Errorf("%s is not set", clowderConfigEnv)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("error reading clowder config: %v", err)
+		}
+
+		var config clowderConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return "", fmt.Errorf("error unmarshalling clowder config: %v", err)
+		}
+
+		endpoints := append(config.Endpoints, config.PrivateEndpoints...)
+		for _, endpoint := range endpoints {
+			if endpoint.App != app || endpoint.Name != name {
+				continue
+			}
+			if endpoint.TLSPort > 0 {
+				return fmt.Sprintf("https://%s:%d", endpoint.Hostname, endpoint.TLSPort), nil
+			}
+			return fmt.Sprintf("http://%s:%d", endpoint.Hostname, endpoint.Port), nil
+		}
+
+		return "", fmt.Errorf("no clowder endpoint found for app %q name %q", app, name)
+	}
+}
+
+func resolveEndpoint(ctx context.Context, rbacBaseEndpoint string, resolver EndpointResolver) (string, error) {
+	if rbacBaseEndpoint != "" || resolver == nil {
+		return rbacBaseEndpoint, nil
+	}
+	return resolver(ctx)
+}
@@ -0,0 +1,137 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeClowderConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cdappconfig.json")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestClowderEndpointResolver(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        string
+		setEnv        bool
+		app           string
+		endpointName  string
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:         "resolves plain http endpoint",
+			config:       `{"endpoints":[{"app":"rbac","name":"service","hostname":"rbac-service","port":8000}]}`,
+			setEnv:       true,
+			app:          "rbac",
+			endpointName: "service",
+			expected:     "http://rbac-service:8000",
+		},
+		{
+			name:         "prefers tls port",
+			config:       `{"endpoints":[{"app":"rbac","name":"service","hostname":"rbac-service","port":8000,"tlsPort":8443}]}`,
+			setEnv:       true,
+			app:          "rbac",
+			endpointName: "service",
+			expected:     "https://rbac-service:8443",
+		},
+		{
+			name:         "falls back to private endpoints",
+			config:       `{"endpoints":[],"privateEndpoints":[{"app":"rbac","name":"service","hostname":"rbac-private","port":10000}]}`,
+			setEnv:       true,
+			app:          "rbac",
+			endpointName: "service",
+			expected:     "http://rbac-private:10000",
+		},
+		{
+			name:          "missing endpoint",
+			config:        `{"endpoints":[{"app":"inventory","name":"api","hostname":"inv","port":8000}]}`,
+			setEnv:        true,
+			app:           "rbac",
+			endpointName:  "service",
+			expectedError: true,
+		},
+		{
+			name:          "invalid config",
+			config:        `not json`,
+			setEnv:        true,
+			app:           "rbac",
+			endpointName:  "service",
+			expectedError: true,
+		},
+		{
+			name:          "env not set",
+			setEnv:        false,
+			app:           "rbac",
+			endpointName:  "service",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setEnv {
+				t.Setenv(clowderConfigEnv, writeClowderConfig(t, tt.config))
+			} else {
+				t.Setenv(clowderConfigEnv, "")
+			}
+
+			endpoint, err := ClowderEndpointResolver(tt.app, tt.endpointName)(context.Background())
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, endpoint)
+		})
+	}
+}
+
+func TestFetchWorkspace_EndpointResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := workspaceAPIResponse{
+			Data: []Workspace{{Id: "ws1", Name: "WS1", Type: "default"}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode test response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	resolverCalls := 0
+	resolver := func(ctx context.Context) (string, error) {
+		resolverCalls++
+		return server.URL, nil
+	}
+
+	ws, err := FetchDefaultWorkspace(context.Background(), "", "org123", FetchWorkspaceOptions{
+		EndpointResolver: resolver,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ws1", ws.Id)
+	assert.Equal(t, 1, resolverCalls)
+
+	_, err = FetchDefaultWorkspace(context.Background(), server.URL, "org123", FetchWorkspaceOptions{
+		EndpointResolver: resolver,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resolverCalls, "explicit endpoint should take precedence over the resolver")
+}
+
+func TestStaticEndpoint(t *testing.T) {
+	endpoint, err := StaticEndpoint("http://rbac:8000")(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "http://rbac:8000", endpoint)
+}
@@ -0,0 +1,131 @@
+// Command genrelations writes relations.go in the v2 package: a Relation<Name>
+// constant for every relation and permission in schema.zed, a hand-maintained
+// subset of the RBAC schema, and the list ValidateRelation compares against.
+// Update schema.zed and run it via go generate.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	definitionPattern = regexp.MustCompile(`^definition\s+(\w+)/(\w+)\s*\{`)
+	memberPattern     = regexp.MustCompile(`^(relation|permission)\s+(\w+)\s*[:=]`)
+)
+
+// relation is one relation or permission name and the definitions that
+// declare it.
+type relation struct {
+	name        string
+	definitions []string
+}
+
+func main() {
+	schema := flag.String("schema", "internal/genrelations/schema.zed", "schema snapshot to read")
+	output := flag.String("output", "relations.go", "file to write")
+	flag.Parse()
+
+	file, err := os.Open(*schema)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	source, err := generate(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(schema io.Reader) ([]byte, error) {
+	relations, err := parse(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by genrelations. DO NOT EDIT.\n\npackage v2\n\n")
+	b.WriteString("// Relations and permissions of the RBAC v2 schema, for Check, ListWorkspaces\n// and the other helpers that take a relation.\nconst (\n")
+	for _, r := range relations {
+		fmt.Fprintf(&b, "\t// Relation%s is declared by %s.\n\tRelation%s = %q\n", constantName(r.name), strings.Join(r.definitions, ", "), constantName(r.name), r.name)
+	}
+	b.WriteString(")\n\n// schemaRelations lists every relation constant, sorted.\nvar schemaRelations = []string{\n")
+	for _, r := range relations {
+		fmt.Fprintf(&b, "\tRelation%s,\n", constantName(r.name))
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
+
+// parse returns the relations and permissions declared in schema, sorted by
+// name, with the definitions ("rbac/workspace") that declare each.
+func parse(schema io.Reader) ([]relation, error) {
+	byName := map[string]*relation{}
+	definition := ""
+	scanner := bufio.NewScanner(schema)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if match := definitionPattern.FindStringSubmatch(text); match != nil {
+			definition = match[1] + "/" + match[2]
+			if strings.HasSuffix(text, "}") {
+				definition = ""
+			}
+			continue
+		}
+		if text == "}" {
+			definition = ""
+			continue
+		}
+		match := memberPattern.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		if definition == "" {
+			return nil, fmt.Errorf("line %d: %s %s outside a definition", line, match[1], match[2])
+		}
+		r, ok := byName[match[2]]
+		if !ok {
+			r = &relation{name: match[2]}
+			byName[match[2]] = r
+		}
+		if !slices.Contains(r.definitions, definition) {
+			r.definitions = append(r.definitions, definition)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	relations := make([]relation, 0, len(byName))
+	for _, r := range byName {
+		relations = append(relations, *r)
+	}
+	slices.SortFunc(relations, func(a, b relation) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return relations, nil
+}
+
+// constantName turns "inventory_host_view" into "InventoryHostView".
+func constantName(relation string) string {
+	var name strings.Builder
+	for _, part := range strings.Split(relation, "_") {
+		if part != "" {
+			name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return name.String()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_upToDate(t *testing.T) {
+	schema, err := os.Open("schema.zed")
+	require.NoError(t, err)
+	defer schema.Close()
+	generated, err := generate(schema)
+	require.NoError(t, err)
+
+	committed, err := os.ReadFile("../../relations.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(committed), string(generated), "relations.go is stale; run go generate ./kessel/rbac/v2")
+}
+
+func TestParse(t *testing.T) {
+	relations, err := parse(strings.NewReader(`
+definition rbac/principal {}
+
+definition rbac/group {
+    relation member: rbac/principal | rbac/group#member
+}
+
+definition rbac/workspace {
+    relation member: rbac/principal
+    permission view_widget = member
+}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []relation{
+		{name: "member", definitions: []string{"rbac/group", "rbac/workspace"}},
+		{name: "view_widget", definitions: []string{"rbac/workspace"}},
+	}, relations)
+}
+
+func TestParse_outsideDefinition(t *testing.T) {
+	_, err := parse(strings.NewReader("relation member: rbac/principal\n"))
+	assert.ErrorContains(t, err, "line 1")
+}
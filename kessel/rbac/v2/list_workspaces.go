@@ -6,6 +6,9 @@ import (
 	"io"
 	"iter"
 
+	"google.golang.org/grpc"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/resume"
 	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
 )
 
@@ -14,6 +17,8 @@ type ListWorkspacesOption func(*listWorkspacesOptions)
 
 type listWorkspacesOptions struct {
 	consistency *v1beta2.Consistency
+	maxResumes  int
+	callOptions []grpc.CallOption
 }
 
 // WithConsistency sets the consistency requirement for the listing request.
@@ -23,13 +28,33 @@ func WithConsistency(c *v1beta2.Consistency) ListWorkspacesOption {
 	}
 }
 
+// WithCallOptions passes opts to every StreamedListObjects call, including
+// the ones that fetch further pages or resume the stream.
+func WithCallOptions(opts ...grpc.CallOption) ListWorkspacesOption {
+	return func(o *listWorkspacesOptions) {
+		o.callOptions = append(o.callOptions, opts...)
+	}
+}
+
+// WithResume reopens the stream from the last received continuation token when
+// it breaks with Unavailable or a stream reset, e.g. during a rolling restart
+// of the server, waiting with exponential backoff between attempts. Up to
+// maxAttempts resumes are made in a row; receiving a workspace resets the
+// count. A stream that breaks after delivering workspaces without a
+// continuation token is not resumed, since that would repeat them.
+func WithResume(maxAttempts int) ListWorkspacesOption {
+	return func(o *listWorkspacesOptions) {
+		o.maxResumes = maxAttempts
+	}
+}
+
 // ListWorkspaces returns a lazy iterator over all workspaces that the given
 // subject has the specified relation to. It wraps the StreamedListObjects gRPC
 // call and automatically handles continuation-token pagination across pages.
 //
 // Iterate one-by-one (lazy, low memory):
 //
-//	for resp, err := range v2.ListWorkspaces(ctx, client, subject, "viewer", "") {
+//	for resp, err := range v2.ListWorkspaces(ctx, client, subject, v2.RelationInventoryHostView, "") {
 //	    if err != nil {
 //	        log.Fatal(err)
 //	    }
@@ -38,7 +63,7 @@ func WithConsistency(c *v1beta2.Consistency) ListWorkspacesOption {
 //
 // With a consistency requirement:
 //
-//	for resp, err := range v2.ListWorkspaces(ctx, client, subject, "viewer", "",
+//	for resp, err := range v2.ListWorkspaces(ctx, client, subject, v2.RelationInventoryHostView, "",
 //	    v2.WithConsistency(&v1beta2.Consistency{
 //	        Requirement: &v1beta2.Consistency_MinimizeLatency{MinimizeLatency: true},
 //	    })) {
@@ -48,7 +73,7 @@ func WithConsistency(c *v1beta2.Consistency) ListWorkspacesOption {
 // Materialise into a slice (eager, all results in memory):
 //
 //	var all []*v1beta2.StreamedListObjectsResponse
-//	for resp, err := range v2.ListWorkspaces(ctx, client, subject, "viewer", "") {
+//	for resp, err := range v2.ListWorkspaces(ctx, client, subject, v2.RelationInventoryHostView, "") {
 //	    if err != nil {
 //	        log.Fatal(err)
 //	    }
@@ -68,6 +93,7 @@ func ListWorkspaces(
 	}
 
 	return func(yield func(*v1beta2.StreamedListObjectsResponse, error) bool) {
+		resumes := 0
 		for {
 			var pagination *v1beta2.RequestPagination
 			if continuationToken != "" {
@@ -85,22 +111,29 @@ func ListWorkspaces(
 				Consistency: options.consistency,
 			}
 
-			stream, err := inventory.StreamedListObjects(ctx, request)
+			stream, err := inventory.StreamedListObjects(ctx, request, options.callOptions...)
 			if err != nil {
+				if resume.Retry(ctx, err, &resumes, options.maxResumes) {
+					continue
+				}
 				yield(nil, fmt.Errorf("failed to start stream: %w", err))
 				return
 			}
 
 			var lastToken string
+			received := false
+			var recvErr error
 			for {
 				response, err := stream.Recv()
 				if err == io.EOF {
 					break
 				}
 				if err != nil {
-					yield(nil, fmt.Errorf("error receiving from stream: %w", err))
-					return
+					recvErr = err
+					break
 				}
+				received = true
+				resumes = 0
 
 				// stop fetching if loop broke early
 				if !yield(response, nil) {
@@ -112,6 +145,17 @@ func ListWorkspaces(
 				}
 			}
 
+			if recvErr != nil {
+				if (lastToken != "" || !received) && resume.Retry(ctx, recvErr, &resumes, options.maxResumes) {
+					if lastToken != "" {
+						continuationToken = lastToken
+					}
+					continue
+				}
+				yield(nil, fmt.Errorf("error receiving from stream: %w", recvErr))
+				return
+			}
+
 			if lastToken == "" {
 				break
 			}
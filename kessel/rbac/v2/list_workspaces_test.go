@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
 
 	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
 )
@@ -17,6 +19,7 @@ type mockInventoryClient struct {
 	responses        []*v1beta2.StreamedListObjectsResponse
 	err              error
 	capturedRequests []*v1beta2.StreamedListObjectsRequest
+	capturedOptions  [][]grpc.CallOption
 }
 
 type mockStream struct {
@@ -36,6 +39,7 @@ func (m *mockStream) Recv() (*v1beta2.StreamedListObjectsResponse, error) {
 
 func (m *mockInventoryClient) StreamedListObjects(ctx context.Context, in *v1beta2.StreamedListObjectsRequest, opts ...grpc.CallOption) (v1beta2.KesselInventoryService_StreamedListObjectsClient, error) {
 	m.capturedRequests = append(m.capturedRequests, in)
+	m.capturedOptions = append(m.capturedOptions, opts)
 
 	if m.err != nil {
 		return nil, m.err
@@ -246,3 +250,159 @@ type mockStreamError struct {
 func (e *mockStreamError) Error() string {
 	return e.message
 }
+
+// scriptedStream returns responses, then err (io.EOF when nil).
+type scriptedStream struct {
+	grpc.ServerStreamingClient[v1beta2.StreamedListObjectsResponse]
+	responses []*v1beta2.StreamedListObjectsResponse
+	err       error
+}
+
+func (s *scriptedStream) Recv() (*v1beta2.StreamedListObjectsResponse, error) {
+	if len(s.responses) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	return response, nil
+}
+
+// scriptedInventoryClient serves one scripted stream per StreamedListObjects
+// call.
+type scriptedInventoryClient struct {
+	v1beta2.KesselInventoryServiceClient
+	streams          []*scriptedStream
+	capturedRequests []*v1beta2.StreamedListObjectsRequest
+}
+
+func (c *scriptedInventoryClient) StreamedListObjects(ctx context.Context, in *v1beta2.StreamedListObjectsRequest, opts ...grpc.CallOption) (v1beta2.KesselInventoryService_StreamedListObjectsClient, error) {
+	c.capturedRequests = append(c.capturedRequests, in)
+	stream := c.streams[0]
+	c.streams = c.streams[1:]
+	return stream, nil
+}
+
+func workspaceResponse(id string, token string) *v1beta2.StreamedListObjectsResponse {
+	return &v1beta2.StreamedListObjectsResponse{
+		Object:     WorkspaceResource(id),
+		Pagination: &v1beta2.ResponsePagination{ContinuationToken: token},
+	}
+}
+
+func TestListWorkspaces_resume(t *testing.T) {
+	unavailable := grpcstatus.Error(codes.Unavailable, "server restarting")
+
+	tests := []struct {
+		name           string
+		streams        []*scriptedStream
+		maxResumes     int
+		expectedIds    []string
+		expectedTokens []string
+		expectedError  bool
+	}{
+		{
+			name: "resumes from last token",
+			streams: []*scriptedStream{
+				{responses: []*v1beta2.StreamedListObjectsResponse{workspaceResponse("ws-1", "t1")}, err: unavailable},
+				{responses: []*v1beta2.StreamedListObjectsResponse{workspaceResponse("ws-2", "")}},
+			},
+			maxResumes:     1,
+			expectedIds:    []string{"ws-1", "ws-2"},
+			expectedTokens: []string{"", "t1"},
+		},
+		{
+			name: "retries a page that delivered nothing",
+			streams: []*scriptedStream{
+				{err: unavailable},
+				{err: unavailable},
+				{responses: []*v1beta2.StreamedListObjectsResponse{workspaceResponse("ws-1", "")}},
+			},
+			maxResumes:     2,
+			expectedIds:    []string{"ws-1"},
+			expectedTokens: []string{"", "", ""},
+		},
+		{
+			name: "gives up after max attempts",
+			streams: []*scriptedStream{
+				{err: unavailable},
+				{err: unavailable},
+			},
+			maxResumes:     1,
+			expectedTokens: []string{"", ""},
+			expectedError:  true,
+		},
+		{
+			name: "disabled by default",
+			streams: []*scriptedStream{
+				{responses: []*v1beta2.StreamedListObjectsResponse{workspaceResponse("ws-1", "t1")}, err: unavailable},
+			},
+			expectedIds:    []string{"ws-1"},
+			expectedTokens: []string{""},
+			expectedError:  true,
+		},
+		{
+			name: "does not resume without a token",
+			streams: []*scriptedStream{
+				{responses: []*v1beta2.StreamedListObjectsResponse{workspaceResponse("ws-1", "")}, err: unavailable},
+			},
+			maxResumes:     3,
+			expectedIds:    []string{"ws-1"},
+			expectedTokens: []string{""},
+			expectedError:  true,
+		},
+		{
+			name: "does not resume other errors",
+			streams: []*scriptedStream{
+				{err: grpcstatus.Error(codes.PermissionDenied, "denied")},
+			},
+			maxResumes:     3,
+			expectedTokens: []string{""},
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &scriptedInventoryClient{streams: tt.streams}
+
+			var ids []string
+			var err error
+			for response, e := range ListWorkspaces(context.Background(), client, PrincipalSubject("alice", "redhat"), "member", "", WithResume(tt.maxResumes)) {
+				if e != nil {
+					err = e
+					break
+				}
+				ids = append(ids, response.GetObject().GetResourceId())
+			}
+
+			if tt.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedIds, ids)
+			var tokens []string
+			for _, request := range client.capturedRequests {
+				tokens = append(tokens, request.GetPagination().GetContinuationToken())
+			}
+			assert.Equal(t, tt.expectedTokens, tokens)
+		})
+	}
+}
+
+func TestListWorkspaces_callOptions(t *testing.T) {
+	waitForReady := grpc.WaitForReady(true)
+	client := &mockInventoryClient{responses: []*v1beta2.StreamedListObjectsResponse{
+		{Object: WorkspaceResource("ws-1")},
+	}}
+
+	for _, err := range ListWorkspaces(context.Background(), client, PrincipalSubject("alice", "redhat"), "view", "", WithCallOptions(waitForReady)) {
+		require.NoError(t, err)
+	}
+
+	require.Len(t, client.capturedOptions, 1)
+	assert.Equal(t, []grpc.CallOption{waitForReady}, client.capturedOptions[0])
+}
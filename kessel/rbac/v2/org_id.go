@@ -0,0 +1,30 @@
+package v2
+
+import "context"
+
+type orgIdKey struct{}
+
+// WithOrgId returns ctx carrying orgId for the RBAC calls made with it. The
+// workspace functions and Authorize use it when their orgId argument is
+// empty, so multi-call flows can set the organization once:
+//
+//	ctx = v2.WithOrgId(ctx, orgId)
+//	root, err := v2.FetchRootWorkspace(ctx, rbacBaseEndpoint, "", options)
+//	workspace, err := v2.CreateWorkspace(ctx, rbacBaseEndpoint, "", request, options)
+func WithOrgId(ctx context.Context, orgId string) context.Context {
+	return context.WithValue(ctx, orgIdKey{}, orgId)
+}
+
+// OrgIdFromContext returns the org ID set with WithOrgId, or "".
+func OrgIdFromContext(ctx context.Context) string {
+	orgId, _ := ctx.Value(orgIdKey{}).(string)
+	return orgId
+}
+
+// resolveOrgId returns orgId, or the org ID of ctx when orgId is empty.
+func resolveOrgId(ctx context.Context, orgId string) string {
+	if orgId != "" {
+		return orgId
+	}
+	return OrgIdFromContext(ctx)
+}
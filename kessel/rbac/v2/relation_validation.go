@@ -0,0 +1,47 @@
+package v2
+
+//go:generate go run ./internal/genrelations
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// relationName is the syntax of a schema relation or permission name.
+var relationName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Relations returns the relations and permissions the SDK declares as
+// Relation constants, sorted by name. It is a subset of the RBAC v2 schema,
+// not the full list of production permissions.
+func Relations() []string {
+	return slices.Clone(schemaRelations)
+}
+
+// ValidateRelation is an advisory check that catches mistakes before a check
+// is sent (the server answers malformed relations with a bare denial). It
+// returns an error for values that cannot be a relation name, for values that
+// differ only in case from a Relation constant, and for legacy RBAC v1
+// permissions ("inventory:hosts:read"), naming the relation to use instead.
+// Well-formed names that are not Relation constants are accepted, since the
+// constants cover only part of the production schema.
+func ValidateRelation(relation string) error {
+	if _, found := slices.BinarySearch(schemaRelations, relation); found {
+		return nil
+	}
+	for _, known := range schemaRelations {
+		if strings.EqualFold(known, relation) {
+			return fmt.Errorf("invalid relation %q, did you mean %q", relation, known)
+		}
+	}
+	if strings.Contains(relation, ":") {
+		if mapped, err := V1PermissionToRelation(relation, nil); err == nil {
+			return fmt.Errorf("invalid relation %q: RBAC v1 permissions map to %q (see V1PermissionToRelation)", relation, mapped)
+		}
+	}
+	if !relationName.MatchString(relation) {
+		return fmt.Errorf("invalid relation %q: expected lower-case letters, digits and underscores", relation)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package v2
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRelation(t *testing.T) {
+	tests := []struct {
+		relation      string
+		expectedError string
+	}{
+		{relation: RelationMember},
+		{relation: RelationInventoryHostView},
+		{relation: "Inventory_Host_View", expectedError: `did you mean "inventory_host_view"`},
+		{relation: "inventory:hosts:read", expectedError: `RBAC v1 permissions map to "inventory_hosts_read"`},
+		{relation: "inventory_hosts_read"},
+		{relation: "remediations_remediation_view"},
+		{relation: "host view", expectedError: `invalid relation "host view"`},
+		{relation: "", expectedError: `invalid relation ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.relation, func(t *testing.T) {
+			err := ValidateRelation(tt.relation)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestRelations(t *testing.T) {
+	relations := Relations()
+	assert.True(t, slices.IsSorted(relations))
+	assert.Contains(t, relations, RelationViewWidget)
+
+	relations[0] = "changed"
+	assert.NotEqual(t, "changed", Relations()[0], "Relations must return a copy")
+}
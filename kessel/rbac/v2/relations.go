@@ -0,0 +1,51 @@
+// Code generated by genrelations. DO NOT EDIT.
+
+package v2
+
+// Relations and permissions of the RBAC v2 schema, for Check, ListWorkspaces
+// and the other helpers that take a relation.
+const (
+	// RelationBinding is declared by rbac/workspace.
+	RelationBinding = "binding"
+	// RelationGranted is declared by rbac/role_binding.
+	RelationGranted = "granted"
+	// RelationInventoryAllAll is declared by rbac/role.
+	RelationInventoryAllAll = "inventory_all_all"
+	// RelationInventoryGroupsAll is declared by rbac/role.
+	RelationInventoryGroupsAll = "inventory_groups_all"
+	// RelationInventoryHostUpdate is declared by rbac/role, rbac/role_binding, rbac/workspace.
+	RelationInventoryHostUpdate = "inventory_host_update"
+	// RelationInventoryHostView is declared by rbac/role, rbac/role_binding, rbac/workspace.
+	RelationInventoryHostView = "inventory_host_view"
+	// RelationInventoryHostsRead is declared by rbac/role.
+	RelationInventoryHostsRead = "inventory_hosts_read"
+	// RelationInventoryHostsWrite is declared by rbac/role.
+	RelationInventoryHostsWrite = "inventory_hosts_write"
+	// RelationMember is declared by rbac/group.
+	RelationMember = "member"
+	// RelationParent is declared by rbac/workspace.
+	RelationParent = "parent"
+	// RelationSubject is declared by rbac/role_binding.
+	RelationSubject = "subject"
+	// RelationUseWidget is declared by rbac/role, rbac/role_binding, rbac/workspace.
+	RelationUseWidget = "use_widget"
+	// RelationViewWidget is declared by rbac/role, rbac/role_binding, rbac/workspace.
+	RelationViewWidget = "view_widget"
+)
+
+// schemaRelations lists every relation constant, sorted.
+var schemaRelations = []string{
+	RelationBinding,
+	RelationGranted,
+	RelationInventoryAllAll,
+	RelationInventoryGroupsAll,
+	RelationInventoryHostUpdate,
+	RelationInventoryHostView,
+	RelationInventoryHostsRead,
+	RelationInventoryHostsWrite,
+	RelationMember,
+	RelationParent,
+	RelationSubject,
+	RelationUseWidget,
+	RelationViewWidget,
+}
@@ -52,6 +52,29 @@ func WorkspaceResource(resourceId string) *v1beta2.ResourceReference {
 	}
 }
 
+func GroupResource(resourceId string) *v1beta2.ResourceReference {
+	return &v1beta2.ResourceReference{
+		ResourceType: "group",
+		ResourceId:   resourceId,
+		Reporter: &v1beta2.ReporterReference{
+			Type: "rbac",
+		},
+	}
+}
+
+// GroupSubject refers to the group's members when relation is "member", or to
+// the group itself when relation is empty.
+func GroupSubject(id string, relation string) *v1beta2.SubjectReference {
+	return Subject(GroupResource(id), relation)
+}
+
+// ServiceAccountPrincipal returns the subject for a service account. RBAC
+// models service accounts as principals, so id is the service account's user
+// ID and the result has the same shape as PrincipalSubject.
+func ServiceAccountPrincipal(id string, domain string) *v1beta2.SubjectReference {
+	return PrincipalSubject(id, domain)
+}
+
 func PrincipalSubject(id string, domain string) *v1beta2.SubjectReference {
 	return &v1beta2.SubjectReference{
 		Resource: PrincipalResource(id, domain),
@@ -73,6 +73,34 @@ func TestWorkspaceResource(t *testing.T) {
 	assert.Equal(t, "rbac", ref.Reporter.Type)
 }
 
+func TestGroupResource(t *testing.T) {
+	ref := GroupResource("group-1")
+
+	assert.Equal(t, "group", ref.ResourceType)
+	assert.Equal(t, "group-1", ref.ResourceId)
+	assert.Equal(t, "rbac", ref.Reporter.Type)
+}
+
+func TestGroupSubject(t *testing.T) {
+	subj := GroupSubject("group-1", "member")
+
+	assert.Equal(t, "group", subj.Resource.ResourceType)
+	assert.Equal(t, "group-1", subj.Resource.ResourceId)
+	assert.Equal(t, "rbac", subj.Resource.Reporter.Type)
+	assert.Equal(t, "member", subj.GetRelation())
+
+	assert.Nil(t, GroupSubject("group-1", "").Relation)
+}
+
+func TestServiceAccountPrincipal(t *testing.T) {
+	subj := ServiceAccountPrincipal("sa-123", "redhat")
+
+	assert.Equal(t, "principal", subj.Resource.ResourceType)
+	assert.Equal(t, "redhat/sa-123", subj.Resource.ResourceId)
+	assert.Equal(t, "rbac", subj.Resource.Reporter.Type)
+	assert.Nil(t, subj.Relation)
+}
+
 func TestPrincipalSubject(t *testing.T) {
 	subj := PrincipalSubject("user123", "redhat")
 
@@ -0,0 +1,75 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// V1PermissionMapping overrides how legacy RBAC v1 permission strings
+// ("app:resource:verb") map to Kessel workspace relations. Keys are v1
+// permissions and may use "*" for the resource and/or verb to match any value.
+type V1PermissionMapping map[string]string
+
+// V1PermissionToRelation returns the Kessel workspace relation for a legacy
+// RBAC v1 permission. An exact mapping entry wins, then entries with wildcard
+// verb, wildcard resource, and both wildcards. Without a matching entry, the
+// RBAC naming convention is applied: the parts are joined with "_" and "*"
+// becomes "all" (e.g. "inventory:hosts:read" -> "inventory_hosts_read",
+// "inventory:*:*" -> "inventory_all_all").
+func V1PermissionToRelation(permission string, mapping V1PermissionMapping) (string, error) {
+	parts := strings.Split(permission, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("invalid RBAC v1 permission %q: expected app:resource:verb", permission)
+	}
+	app, resource, verb := parts[0], parts[1], parts[2]
+
+	candidates := []string{
+		permission,
+		app + ":" + resource + ":*",
+		app + ":*:" + verb,
+		app + ":*:*",
+	}
+	for _, candidate := range candidates {
+		if relation, ok := mapping[candidate]; ok {
+			return relation, nil
+		}
+	}
+
+	for i, part := range parts {
+		if part == "*" {
+			parts[i] = "all"
+		}
+	}
+	return strings.Join(parts, "_"), nil
+}
+
+// CheckV1Permission evaluates a legacy RBAC v1 permission for subject on the
+// given workspace by checking the mapped Kessel relation. It eases incremental
+// migration of code that still reasons in v1 permission strings.
+func CheckV1Permission(
+	ctx context.Context,
+	inventory v1beta2.KesselInventoryServiceClient,
+	subject *v1beta2.SubjectReference,
+	workspaceId string,
+	permission string,
+	mapping V1PermissionMapping,
+) (bool, error) {
+	relation, err := V1PermissionToRelation(permission, mapping)
+	if err != nil {
+		return false, err
+	}
+
+	response, err := inventory.Check(ctx, &v1beta2.CheckRequest{
+		Object:   WorkspaceResource(workspaceId),
+		Relation: relation,
+		Subject:  subject,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return response.GetAllowed() == v1beta2.Allowed_ALLOWED_TRUE, nil
+}
@@ -0,0 +1,106 @@
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+func TestV1PermissionToRelation(t *testing.T) {
+	mapping := V1PermissionMapping{
+		"inventory:hosts:read":  "inventory_host_view",
+		"inventory:groups:*":    "inventory_groups_all",
+		"notifications:*:write": "notifications_write",
+		"cost-management:*:*":   "cost_management_all",
+	}
+
+	tests := []struct {
+		name          string
+		permission    string
+		expected      string
+		expectedError bool
+	}{
+		{name: "exact mapping", permission: "inventory:hosts:read", expected: "inventory_host_view"},
+		{name: "wildcard verb mapping", permission: "inventory:groups:write", expected: "inventory_groups_all"},
+		{name: "wildcard resource mapping", permission: "notifications:events:write", expected: "notifications_write"},
+		{name: "full wildcard mapping", permission: "cost-management:reports:read", expected: "cost_management_all"},
+		{name: "convention fallback", permission: "inventory:hosts:write", expected: "inventory_hosts_write"},
+		{name: "wildcards become all", permission: "patch:*:*", expected: "patch_all_all"},
+		{name: "too few parts", permission: "inventory:hosts", expectedError: true},
+		{name: "empty part", permission: "inventory::read", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relation, err := V1PermissionToRelation(tt.permission, mapping)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, relation)
+		})
+	}
+}
+
+func TestCheckV1Permission(t *testing.T) {
+	tests := []struct {
+		name            string
+		permission      string
+		response        *v1beta2.CheckResponse
+		checkErr        error
+		expectedError   bool
+		expectedAllowed bool
+		expectedCalls   int
+	}{
+		{
+			name:            "allowed",
+			permission:      "inventory:hosts:read",
+			response:        &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE},
+			expectedAllowed: true,
+			expectedCalls:   1,
+		},
+		{
+			name:          "denied",
+			permission:    "inventory:hosts:read",
+			response:      &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_FALSE},
+			expectedCalls: 1,
+		},
+		{
+			name:          "invalid permission is not checked",
+			permission:    "bad",
+			expectedError: true,
+			expectedCalls: 0,
+		},
+		{
+			name:          "check error",
+			permission:    "inventory:hosts:read",
+			checkErr:      status.Error(codes.Unavailable, "down"),
+			expectedError: true,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockCheckClient{response: tt.response, err: tt.checkErr}
+
+			allowed, err := CheckV1Permission(context.Background(), client, PrincipalSubject("alice", "redhat"), "ws-1", tt.permission, nil)
+			require.Len(t, client.capturedRequests, tt.expectedCalls)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAllowed, allowed)
+			assert.Equal(t, "inventory_hosts_read", client.capturedRequests[0].Relation)
+			assert.Equal(t, "ws-1", client.capturedRequests[0].Object.ResourceId)
+		})
+	}
+}
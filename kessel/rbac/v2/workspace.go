@@ -1,14 +1,21 @@
 package v2
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"github.com/project-kessel/kessel-sdk-go/kessel/circuitbreaker"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/callmetadata"
+	"github.com/project-kessel/kessel-sdk-go/kessel/ratelimit"
+	"github.com/project-kessel/kessel-sdk-go/kessel/version"
 )
 
 const workspaceEndpoint = "/api/rbac/v2/workspaces/"
@@ -18,6 +25,7 @@ type Workspace struct {
 	Name        string `json:"name"`
 	Type        string `json:"type"`
 	Description string `json:"description"`
+	ParentId    string `json:"parent_id,omitempty"`
 }
 
 type FetchWorkspaceOptions struct {
@@ -29,70 +37,172 @@ type FetchWorkspaceOptions struct {
 	// (root, default) are returned even when the caller lacks an explicit
 	// inventory permission grant.
 	DisableAncestry bool
+	// Optionally limit the rate of outgoing requests. Share a single limiter
+	// across calls so the limit applies to all of them.
+	RateLimiter *ratelimit.Limiter
+	// Optionally fail fast with errors.ErrCircuitOpen after repeated failures.
+	// Transport errors and 5xx responses count as failures.
+	CircuitBreaker *circuitbreaker.Breaker
+	// Optionally resolve the RBAC base endpoint when the rbacBaseEndpoint
+	// argument is empty, e.g. with ClowderEndpointResolver("rbac", "service").
+	EndpointResolver EndpointResolver
+	// GenerateRequestId sends a random x-rh-insights-request-id when ctx has
+	// none (see inventory.WithRequestId). Errors of calls that carry a request
+	// ID are returned as *errors.RequestIdError.
+	GenerateRequestId bool
 }
 
+// WorkspaceOptions configures the workspace write calls. It has the same
+// fields as FetchWorkspaceOptions; DisableAncestry is ignored.
+type WorkspaceOptions = FetchWorkspaceOptions
+
 type workspaceAPIResponse struct {
 	Data []Workspace `json:"data"`
 }
 
 func fetchWorkspace(ctx context.Context, rbacBaseEndpoint string, orgId string, workspaceType string, options FetchWorkspaceOptions) (*Workspace, error) {
+	var workspaceResponse workspaceAPIResponse
+	err := withRequestId(ctx, options, func(ctx context.Context) error {
+		return doWorkspaceRequest(ctx, rbacBaseEndpoint, orgId, workspaceRequest{
+			method: http.MethodGet,
+			query: func(query url.Values) {
+				query.Set("type", workspaceType)
+				if !options.DisableAncestry {
+					query.Set("with_ancestry", "true")
+				}
+			},
+			action: fmt.Sprintf("fetching %s workspace", workspaceType),
+		}, &workspaceResponse, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(workspaceResponse.Data) != 1 {
+		return nil, fmt.Errorf("unexpected number of %s workspaces: %d. %v", workspaceType, len(workspaceResponse.Data), workspaceResponse.Data)
+	}
+
+	return &workspaceResponse.Data[0], nil
+}
+
+// withRequestId runs call with a request ID on ctx when one is set or
+// GenerateRequestId asks for one, and annotates its error with that ID.
+func withRequestId(ctx context.Context, options FetchWorkspaceOptions, call func(context.Context) error) error {
+	requestId := callmetadata.RequestId(ctx)
+	if requestId == "" && options.GenerateRequestId {
+		ctx, requestId = callmetadata.EnsureRequestId(ctx)
+	}
+
+	err := call(ctx)
+	if err != nil && requestId != "" {
+		return &kesselerrors.RequestIdError{RequestId: requestId, Err: err}
+	}
+	return err
+}
+
+type workspaceRequest struct {
+	method string
+	// Path below workspaceEndpoint, e.g. "<id>/move/".
+	path  string
+	query func(url.Values)
+	body  any
+	// Describes the call in errors, e.g. "creating workspace".
+	action string
+}
+
+// doWorkspaceRequest sends a request to the RBAC workspace API and decodes the
+// response body into out, unless out is nil.
+func doWorkspaceRequest(ctx context.Context, rbacBaseEndpoint string, orgId string, workspaceRequest workspaceRequest, out any, options FetchWorkspaceOptions) error {
 	httpClient := options.HttpClient
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
-	url := strings.TrimRight(rbacBaseEndpoint, "/") + workspaceEndpoint
+	rbacBaseEndpoint, err := resolveEndpoint(ctx, rbacBaseEndpoint, options.EndpointResolver)
+	if err != nil {
+		return err
+	}
 
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	endpoint := strings.TrimRight(rbacBaseEndpoint, "/") + workspaceEndpoint + workspaceRequest.path
+
+	var body io.Reader
+	if workspaceRequest.body != nil {
+		encoded, err := json.Marshal(workspaceRequest.body)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, workspaceRequest.method, endpoint, body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	query := request.URL.Query()
-	query.Set("type", workspaceType)
-	if !options.DisableAncestry {
-		query.Set("with_ancestry", "true")
+	if workspaceRequest.query != nil {
+		query := request.URL.Query()
+		workspaceRequest.query(query)
+		request.URL.RawQuery = query.Encode()
 	}
-	request.URL.RawQuery = query.Encode()
 
-	request.Header.Set("x-rh-rbac-org-id", orgId)
+	callmetadata.SetHeaders(ctx, request.Header)
+	request.Header.Set("x-rh-rbac-org-id", resolveOrgId(ctx, orgId))
+	request.Header.Set("User-Agent", version.UserAgent())
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
 
 	if options.Auth != nil {
 		err = options.Auth.ConfigureRequest(ctx, request)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
+	if err := options.RateLimiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	done, err := options.CircuitBreaker.Allow()
+	if err != nil {
+		return err
+	}
+
 	response, err := httpClient.Do(request)
 	if err != nil {
-		return nil, err
+		done(err)
+		return err
 	}
 
 	defer func() { _ = response.Body.Close() }()
 
-	if response.StatusCode != 200 {
-		return nil, fmt.Errorf("error fetching %s workspace - http status %s", workspaceType, response.Status)
+	if apiErr := kesselerrors.FromHTTPResponse(response); apiErr != nil {
+		err = fmt.Errorf("error %s - %w", workspaceRequest.action, apiErr)
+		if response.StatusCode >= 500 {
+			done(err)
+		} else {
+			done(nil)
+		}
+		return err
 	}
+	done(nil)
 
-	body, err := io.ReadAll(response.Body)
-
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %v", err)
+	if out == nil {
+		return nil
 	}
 
-	var workspaceResponse workspaceAPIResponse
+	responseBody, err := io.ReadAll(response.Body)
 
-	err = json.Unmarshal(body, &workspaceResponse)
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling response: %v", err)
+		return fmt.Errorf("error reading response body: %v", err)
 	}
 
-	if len(workspaceResponse.Data) != 1 {
-		return nil, fmt.Errorf("unexpected number of %s workspaces: %d. %v", workspaceType, len(workspaceResponse.Data), workspaceResponse.Data)
+	err = json.Unmarshal(responseBody, out)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling response: %v", err)
 	}
 
-	return &workspaceResponse.Data[0], nil
+	return nil
 }
 
 func FetchRootWorkspace(ctx context.Context, rbacBaseEndpoint string, orgId string, options FetchWorkspaceOptions) (*Workspace, error) {
@@ -0,0 +1,86 @@
+package v2
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// workspaceListPageSize is the number of workspaces requested per page by
+// ListWorkspacesFiltered.
+const workspaceListPageSize = 100
+
+// WorkspaceFilter selects the workspaces returned by ListWorkspacesFiltered.
+// Empty fields match every workspace.
+type WorkspaceFilter struct {
+	// Type is sent to RBAC as the type query parameter, e.g. "standard",
+	// "root", "default" or "all", so the server does the filtering.
+	Type string
+	// NamePrefix matches workspaces whose name starts with it, ignoring
+	// case, for type-ahead search.
+	NamePrefix string
+	// ParentId matches the direct children of a workspace.
+	ParentId string
+}
+
+func (f WorkspaceFilter) matches(workspace Workspace) bool {
+	if f.NamePrefix != "" && !strings.HasPrefix(strings.ToLower(workspace.Name), strings.ToLower(f.NamePrefix)) {
+		return false
+	}
+	return f.ParentId == "" || workspace.ParentId == f.ParentId
+}
+
+// ListWorkspacesFiltered returns a lazy iterator over the workspaces of an
+// org from the RBAC workspace API that match filter. The type filter is
+// applied by the server; the name prefix and parent are applied to each page
+// before workspaces are yielded, since RBAC cannot filter on them. Pages are
+// fetched only as the caller iterates, so a type-ahead search that stops
+// after the first few matches does not fetch every workspace:
+//
+//	for workspace, err := range v2.ListWorkspacesFiltered(ctx, rbacBaseEndpoint, orgId,
+//	    v2.WorkspaceFilter{Type: "standard", NamePrefix: "eng"}, options) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(workspace.Name)
+//	}
+//
+// Unlike ListWorkspaces, which lists the workspaces a subject has a relation
+// to through Kessel Inventory, this lists every workspace the caller's
+// credentials may read in RBAC.
+func ListWorkspacesFiltered(ctx context.Context, rbacBaseEndpoint string, orgId string, filter WorkspaceFilter, options FetchWorkspaceOptions) iter.Seq2[*Workspace, error] {
+	return func(yield func(*Workspace, error) bool) {
+		for offset := 0; ; offset += workspaceListPageSize {
+			var page workspaceAPIResponse
+			err := withRequestId(ctx, options, func(ctx context.Context) error {
+				return doWorkspaceRequest(ctx, rbacBaseEndpoint, orgId, workspaceRequest{
+					method: http.MethodGet,
+					query: func(query url.Values) {
+						if filter.Type != "" {
+							query.Set("type", filter.Type)
+						}
+						query.Set("limit", strconv.Itoa(workspaceListPageSize))
+						query.Set("offset", strconv.Itoa(offset))
+					},
+					action: "listing workspaces",
+				}, &page, options)
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := range page.Data {
+				if filter.matches(page.Data[i]) && !yield(&page.Data[i], nil) {
+					return
+				}
+			}
+			if len(page.Data) < workspaceListPageSize {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+)
+
+// workspaceListServer serves workspaces from the RBAC list endpoint with
+// limit/offset paging and records the offsets requested.
+func workspaceListServer(t *testing.T, workspaces []Workspace, offsets *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		*offsets = append(*offsets, query.Get("offset"))
+		if r.Header.Get("x-rh-rbac-org-id") != "org123" {
+			t.Errorf("Expected org ID header org123, got %s", r.Header.Get("x-rh-rbac-org-id"))
+		}
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+
+		var matching []Workspace
+		for _, workspace := range workspaces {
+			if workspaceType := query.Get("type"); workspaceType == "" || workspace.Type == workspaceType {
+				matching = append(matching, workspace)
+			}
+		}
+		page := workspaceAPIResponse{Data: []Workspace{}}
+		if offset < len(matching) {
+			page.Data = matching[offset:min(offset+limit, len(matching))]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(page); err != nil {
+			t.Errorf("Failed to encode test response: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testWorkspaces() []Workspace {
+	workspaces := []Workspace{
+		{Id: "root", Name: "Root Workspace", Type: "root"},
+		{Id: "default", Name: "Default Workspace", Type: "default", ParentId: "root"},
+	}
+	for i := range 150 {
+		parent := "default"
+		if i%2 == 1 {
+			parent = "root"
+		}
+		workspaces = append(workspaces, Workspace{Id: fmt.Sprintf("ws-%d", i), Name: fmt.Sprintf("Team %d", i), Type: "standard", ParentId: parent})
+	}
+	return workspaces
+}
+
+func TestListWorkspacesFiltered(t *testing.T) {
+	tests := []struct {
+		name            string
+		filter          WorkspaceFilter
+		expectedCount   int
+		expectedOffsets int
+	}{
+		{name: "no filter", expectedCount: 152, expectedOffsets: 2},
+		{name: "server-side type", filter: WorkspaceFilter{Type: "root"}, expectedCount: 1, expectedOffsets: 1},
+		{name: "name prefix ignores case", filter: WorkspaceFilter{Type: "standard", NamePrefix: "team 1"}, expectedCount: 61, expectedOffsets: 2},
+		{name: "parent", filter: WorkspaceFilter{ParentId: "root"}, expectedCount: 76, expectedOffsets: 2},
+		{name: "no match", filter: WorkspaceFilter{NamePrefix: "finance"}, expectedCount: 0, expectedOffsets: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var offsets []string
+			server := workspaceListServer(t, testWorkspaces(), &offsets)
+
+			count := 0
+			for workspace, err := range ListWorkspacesFiltered(context.Background(), server.URL, "org123", tt.filter, FetchWorkspaceOptions{}) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if !tt.filter.matches(*workspace) || (tt.filter.Type != "" && workspace.Type != tt.filter.Type) {
+					t.Errorf("Workspace %+v does not match %+v", workspace, tt.filter)
+				}
+				count++
+			}
+			if count != tt.expectedCount {
+				t.Errorf("Expected %d workspaces, got %d", tt.expectedCount, count)
+			}
+			if len(offsets) != tt.expectedOffsets {
+				t.Errorf("Expected %d pages, got %v", tt.expectedOffsets, offsets)
+			}
+		})
+	}
+}
+
+func TestListWorkspacesFiltered_stopsEarly(t *testing.T) {
+	var offsets []string
+	server := workspaceListServer(t, testWorkspaces(), &offsets)
+
+	var names []string
+	for workspace, err := range ListWorkspacesFiltered(context.Background(), server.URL, "org123", WorkspaceFilter{NamePrefix: "team"}, FetchWorkspaceOptions{}) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		names = append(names, workspace.Name)
+		if len(names) == 3 {
+			break
+		}
+	}
+	if len(offsets) != 1 || offsets[0] != "0" {
+		t.Errorf("Expected only the first page to be fetched, got %v", offsets)
+	}
+	if names[0] != "Team 0" {
+		t.Errorf("Expected Team 0 first, got %v", names)
+	}
+}
+
+func TestListWorkspacesFiltered_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	for workspace, err := range ListWorkspacesFiltered(context.Background(), server.URL, "org123", WorkspaceFilter{}, FetchWorkspaceOptions{}) {
+		if workspace != nil {
+			t.Errorf("Expected no workspace, got %+v", workspace)
+		}
+		if !errors.Is(err, kesselerrors.ErrPermissionDenied) {
+			t.Errorf("Expected ErrPermissionDenied, got %v", err)
+		}
+	}
+}
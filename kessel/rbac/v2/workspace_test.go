@@ -3,12 +3,21 @@ package v2
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
 
 	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	"github.com/project-kessel/kessel-sdk-go/kessel/circuitbreaker"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"github.com/project-kessel/kessel-sdk-go/kessel/internal/callmetadata"
+	"github.com/project-kessel/kessel-sdk-go/kessel/ratelimit"
+	"github.com/project-kessel/kessel-sdk-go/kessel/version"
 )
 
 func TestFetchDefaultWorkspace(t *testing.T) {
@@ -64,6 +73,9 @@ func TestFetchDefaultWorkspace(t *testing.T) {
 				if r.Header.Get("x-rh-rbac-org-id") != "org123" {
 					t.Errorf("Expected org ID header org123, got %s", r.Header.Get("x-rh-rbac-org-id"))
 				}
+				if r.Header.Get("User-Agent") != version.UserAgent() {
+					t.Errorf("Expected user agent %s, got %s", version.UserAgent(), r.Header.Get("User-Agent"))
+				}
 			},
 		},
 		{
@@ -404,6 +416,151 @@ func TestFetchWorkspace_DisableAncestry(t *testing.T) {
 	}
 }
 
+func TestFetchWorkspace_RateLimiter(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		response := workspaceAPIResponse{
+			Data: []Workspace{{Id: "ws1", Name: "WS1", Type: "default"}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode test response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	options := FetchWorkspaceOptions{
+		HttpClient:  http.DefaultClient,
+		RateLimiter: ratelimit.NewLimiter(0.001, 1),
+	}
+
+	_, err := FetchDefaultWorkspace(context.Background(), server.URL, "org123", options)
+	if err != nil {
+		t.Fatalf("Unexpected error for first request: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = FetchDefaultWorkspace(ctx, server.URL, "org123", options)
+	if err == nil {
+		t.Error("Expected rate limited request to fail when context expires")
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d", requests)
+	}
+}
+
+func TestFetchWorkspace_CircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name              string
+		statusCode        int
+		expectedOpenError bool
+	}{
+		{
+			name:              "server errors open the circuit",
+			statusCode:        http.StatusServiceUnavailable,
+			expectedOpenError: true,
+		},
+		{
+			name:              "client errors do not open the circuit",
+			statusCode:        http.StatusForbidden,
+			expectedOpenError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			options := FetchWorkspaceOptions{
+				HttpClient:     http.DefaultClient,
+				CircuitBreaker: circuitbreaker.New(circuitbreaker.WithConsecutiveFailures(1)),
+			}
+
+			_, err := FetchDefaultWorkspace(context.Background(), server.URL, "org123", options)
+			if err == nil {
+				t.Fatal("Expected error for non-200 response")
+			}
+
+			_, err = FetchDefaultWorkspace(context.Background(), server.URL, "org123", options)
+			if errors.Is(err, kesselerrors.ErrCircuitOpen) != tt.expectedOpenError {
+				t.Errorf("Expected ErrCircuitOpen=%v, got %v", tt.expectedOpenError, err)
+			}
+		})
+	}
+}
+
+func TestFetchWorkspace_CallMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("x-rh-insights-request-id"); got != "req-1" {
+			t.Errorf("Expected request id header req-1, got %q", got)
+		}
+		if got := r.Header.Get("x-rh-rbac-org-id"); got != "org123" {
+			t.Errorf("Expected org ID argument to take precedence, got %q", got)
+		}
+		response := workspaceAPIResponse{
+			Data: []Workspace{{Id: "ws1", Name: "WS1", Type: "default"}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode test response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	ctx := callmetadata.NewContext(context.Background(), metadata.Pairs(
+		"x-rh-insights-request-id", "req-1",
+		"x-rh-rbac-org-id", "other-org",
+	))
+
+	_, err := FetchDefaultWorkspace(ctx, server.URL, "org123", FetchWorkspaceOptions{})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestFetchWorkspace_OrgIdFromContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		orgId    string
+		expected string
+	}{
+		{name: "empty argument uses the context", expected: "ctx-org"},
+		{name: "argument takes precedence", orgId: "org123", expected: "org123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("x-rh-rbac-org-id"); got != tt.expected {
+					t.Errorf("Expected org ID header %s, got %q", tt.expected, got)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			ctx := WithOrgId(context.Background(), "ctx-org")
+			if err := DeleteWorkspace(ctx, server.URL, tt.orgId, "ws1", WorkspaceOptions{}); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestOrgIdFromContext(t *testing.T) {
+	if got := OrgIdFromContext(context.Background()); got != "" {
+		t.Errorf("Expected no org ID, got %q", got)
+	}
+	if got := OrgIdFromContext(WithOrgId(context.Background(), "org123")); got != "org123" {
+		t.Errorf("Expected org123, got %q", got)
+	}
+}
+
 func TestFetchWorkspace_InvalidJSON(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -425,6 +582,89 @@ func TestFetchWorkspace_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestFetchWorkspace_ErrorCategory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		if _, err := w.Write([]byte(`{"errors":[{"detail":"You do not have permission to perform this action.","status":"403"}]}`)); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	_, err := FetchDefaultWorkspace(context.Background(), server.URL, "org123", FetchWorkspaceOptions{
+		HttpClient: http.DefaultClient,
+	})
+
+	if !errors.Is(err, kesselerrors.ErrPermissionDenied) {
+		t.Fatalf("Expected ErrPermissionDenied, got: %v", err)
+	}
+	var apiErr *kesselerrors.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusForbidden {
+		t.Fatalf("Expected *APIError with status 403, got: %v", err)
+	}
+	if apiErr.Message != "You do not have permission to perform this action." {
+		t.Errorf("Expected the RBAC detail message, got: %q", apiErr.Message)
+	}
+	if !strings.HasPrefix(apiErr.Url, server.URL+"/api/rbac/v2/workspaces/") {
+		t.Errorf("Expected the request URL, got: %q", apiErr.Url)
+	}
+	if len(apiErr.Details) != 1 || apiErr.Details[0].Status != "403" {
+		t.Errorf("Expected the RBAC error details, got: %+v", apiErr.Details)
+	}
+	if !kesselerrors.IsPermissionDenied(err) {
+		t.Error("Expected IsPermissionDenied to match")
+	}
+}
+
+func TestFetchWorkspace_RequestId(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		generate  bool
+		expectSet bool
+	}{
+		{name: "caller request id", ctx: callmetadata.WithRequestId(context.Background(), "req-1"), expectSet: true},
+		{name: "generated request id", ctx: context.Background(), generate: true, expectSet: true},
+		{name: "no request id", ctx: context.Background()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.Header.Get(callmetadata.RequestIdKey)
+				w.WriteHeader(http.StatusBadGateway)
+			}))
+			defer server.Close()
+
+			_, err := FetchDefaultWorkspace(tt.ctx, server.URL, "org123", FetchWorkspaceOptions{
+				HttpClient:        http.DefaultClient,
+				GenerateRequestId: tt.generate,
+			})
+
+			if err == nil {
+				t.Fatal("Expected error but got none")
+			}
+			if !tt.expectSet {
+				if received != "" || kesselerrors.RequestId(err) != "" {
+					t.Errorf("Expected no request ID, got header %q and error %v", received, err)
+				}
+				return
+			}
+			if received == "" {
+				t.Fatal("Expected a request ID header")
+			}
+			if got := kesselerrors.RequestId(err); got != received {
+				t.Errorf("Expected error to carry request ID %q, got %q", received, got)
+			}
+			if !errors.Is(err, kesselerrors.ErrServerError) {
+				t.Errorf("Expected the HTTP error category to be preserved, got %v", err)
+			}
+		})
+	}
+}
+
 func TestFetchWorkspace_URLConstruction(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -0,0 +1,96 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CreateWorkspaceRequest is the body of CreateWorkspace. ParentId defaults to
+// the organization's default workspace when empty.
+type CreateWorkspaceRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ParentId    string `json:"parent_id,omitempty"`
+}
+
+// UpdateWorkspaceRequest is the body of UpdateWorkspace. Only the fields that
+// are set are changed; set Description to a pointer to "" to clear it.
+type UpdateWorkspaceRequest struct {
+	Name        string  `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+type moveWorkspaceRequest struct {
+	ParentId string `json:"parent_id"`
+}
+
+// CreateWorkspace creates a standard workspace and returns it as stored by
+// RBAC.
+func CreateWorkspace(ctx context.Context, rbacBaseEndpoint string, orgId string, request CreateWorkspaceRequest, options WorkspaceOptions) (*Workspace, error) {
+	var workspace Workspace
+	err := withRequestId(ctx, options, func(ctx context.Context) error {
+		return doWorkspaceRequest(ctx, rbacBaseEndpoint, orgId, workspaceRequest{
+			method: http.MethodPost,
+			body:   request,
+			action: "creating workspace",
+		}, &workspace, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// UpdateWorkspace changes the name or description of a workspace and returns
+// the updated workspace.
+func UpdateWorkspace(ctx context.Context, rbacBaseEndpoint string, orgId string, workspaceId string, request UpdateWorkspaceRequest, options WorkspaceOptions) (*Workspace, error) {
+	var workspace Workspace
+	err := withRequestId(ctx, options, func(ctx context.Context) error {
+		return doWorkspaceRequest(ctx, rbacBaseEndpoint, orgId, workspaceRequest{
+			method: http.MethodPatch,
+			path:   workspacePath(workspaceId),
+			body:   request,
+			action: fmt.Sprintf("updating workspace %s", workspaceId),
+		}, &workspace, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// DeleteWorkspace deletes a workspace. RBAC refuses to delete workspaces that
+// still have children.
+func DeleteWorkspace(ctx context.Context, rbacBaseEndpoint string, orgId string, workspaceId string, options WorkspaceOptions) error {
+	return withRequestId(ctx, options, func(ctx context.Context) error {
+		return doWorkspaceRequest(ctx, rbacBaseEndpoint, orgId, workspaceRequest{
+			method: http.MethodDelete,
+			path:   workspacePath(workspaceId),
+			action: fmt.Sprintf("deleting workspace %s", workspaceId),
+		}, nil, options)
+	})
+}
+
+// MoveWorkspace moves a workspace under a new parent. The returned workspace
+// carries only Id and ParentId, which is all RBAC returns for a move.
+func MoveWorkspace(ctx context.Context, rbacBaseEndpoint string, orgId string, workspaceId string, parentId string, options WorkspaceOptions) (*Workspace, error) {
+	var workspace Workspace
+	err := withRequestId(ctx, options, func(ctx context.Context) error {
+		return doWorkspaceRequest(ctx, rbacBaseEndpoint, orgId, workspaceRequest{
+			method: http.MethodPost,
+			path:   workspacePath(workspaceId) + "move/",
+			body:   moveWorkspaceRequest{ParentId: parentId},
+			action: fmt.Sprintf("moving workspace %s", workspaceId),
+		}, &workspace, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+func workspacePath(workspaceId string) string {
+	return url.PathEscape(workspaceId) + "/"
+}
@@ -0,0 +1,158 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+)
+
+// workspaceWriteServer records the request it receives and answers with
+// status and body.
+func workspaceWriteServer(t *testing.T, status int, body string, received *http.Request, receivedBody *map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*received = *r.Clone(context.Background())
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read request body: %v", err)
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, receivedBody); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWorkspaceWrites(t *testing.T) {
+	description := "Team workspace"
+
+	tests := []struct {
+		name           string
+		status         int
+		responseBody   string
+		call           func(endpoint string) (*Workspace, error)
+		expectedMethod string
+		expectedPath   string
+		expectedBody   map[string]any
+		expectedWS     *Workspace
+	}{
+		{
+			name:         "create",
+			status:       http.StatusCreated,
+			responseBody: `{"id": "ws-1", "name": "Team", "type": "standard", "parent_id": "ws-default"}`,
+			call: func(endpoint string) (*Workspace, error) {
+				return CreateWorkspace(context.Background(), endpoint, "org123", CreateWorkspaceRequest{Name: "Team"}, WorkspaceOptions{})
+			},
+			expectedMethod: http.MethodPost,
+			expectedPath:   workspaceEndpoint,
+			expectedBody:   map[string]any{"name": "Team"},
+			expectedWS:     &Workspace{Id: "ws-1", Name: "Team", Type: "standard", ParentId: "ws-default"},
+		},
+		{
+			name:         "update",
+			status:       http.StatusOK,
+			responseBody: `{"id": "ws-1", "name": "Team", "type": "standard", "description": "Team workspace"}`,
+			call: func(endpoint string) (*Workspace, error) {
+				return UpdateWorkspace(context.Background(), endpoint, "org123", "ws-1", UpdateWorkspaceRequest{Description: &description}, WorkspaceOptions{})
+			},
+			expectedMethod: http.MethodPatch,
+			expectedPath:   workspaceEndpoint + "ws-1/",
+			expectedBody:   map[string]any{"description": "Team workspace"},
+			expectedWS:     &Workspace{Id: "ws-1", Name: "Team", Type: "standard", Description: "Team workspace"},
+		},
+		{
+			name:         "move",
+			status:       http.StatusOK,
+			responseBody: `{"id": "ws-1", "parent_id": "ws-2"}`,
+			call: func(endpoint string) (*Workspace, error) {
+				return MoveWorkspace(context.Background(), endpoint, "org123", "ws-1", "ws-2", WorkspaceOptions{})
+			},
+			expectedMethod: http.MethodPost,
+			expectedPath:   workspaceEndpoint + "ws-1/move/",
+			expectedBody:   map[string]any{"parent_id": "ws-2"},
+			expectedWS:     &Workspace{Id: "ws-1", ParentId: "ws-2"},
+		},
+		{
+			name:   "delete",
+			status: http.StatusNoContent,
+			call: func(endpoint string) (*Workspace, error) {
+				return nil, DeleteWorkspace(context.Background(), endpoint, "org123", "ws-1", WorkspaceOptions{})
+			},
+			expectedMethod: http.MethodDelete,
+			expectedPath:   workspaceEndpoint + "ws-1/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received http.Request
+			var receivedBody map[string]any
+			server := workspaceWriteServer(t, tt.status, tt.responseBody, &received, &receivedBody)
+
+			workspace, err := tt.call(server.URL)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if received.Method != tt.expectedMethod {
+				t.Errorf("Expected %s request, got %s", tt.expectedMethod, received.Method)
+			}
+			if received.URL.Path != tt.expectedPath {
+				t.Errorf("Expected path %s, got %s", tt.expectedPath, received.URL.Path)
+			}
+			if received.Header.Get("x-rh-rbac-org-id") != "org123" {
+				t.Errorf("Expected org ID header org123, got %s", received.Header.Get("x-rh-rbac-org-id"))
+			}
+			if tt.expectedBody != nil {
+				if received.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("Expected JSON content type, got %s", received.Header.Get("Content-Type"))
+				}
+				if len(receivedBody) != len(tt.expectedBody) {
+					t.Errorf("Expected body %v, got %v", tt.expectedBody, receivedBody)
+				}
+				for key, value := range tt.expectedBody {
+					if receivedBody[key] != value {
+						t.Errorf("Expected body %v, got %v", tt.expectedBody, receivedBody)
+					}
+				}
+			}
+			if tt.expectedWS != nil && *workspace != *tt.expectedWS {
+				t.Errorf("Expected workspace %+v, got %+v", tt.expectedWS, workspace)
+			}
+		})
+	}
+}
+
+func TestWorkspaceWrites_errors(t *testing.T) {
+	var received http.Request
+	var receivedBody map[string]any
+	server := workspaceWriteServer(t, http.StatusBadRequest, `{"errors": [{"detail": "Can't delete workspace with children", "status": "400"}]}`, &received, &receivedBody)
+
+	err := DeleteWorkspace(context.Background(), server.URL, "org123", "ws-1", WorkspaceOptions{})
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if !errors.Is(err, kesselerrors.ErrInvalidArgument) {
+		t.Errorf("Expected ErrInvalidArgument, got %v", err)
+	}
+	var apiErr *kesselerrors.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "Can't delete workspace with children" {
+		t.Errorf("Expected the RBAC detail message, got %v", err)
+	}
+	if expected := "error deleting workspace ws-1 - "; !strings.HasPrefix(err.Error(), expected) {
+		t.Errorf("Expected error to start with %q, got %q", expected, err.Error())
+	}
+}
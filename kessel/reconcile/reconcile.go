@@ -0,0 +1,295 @@
+// Package reconcile converges Kessel Inventory with a reporter's source of
+// truth: DiffResources compares the resources a reporter should have reported
+// with those it has, and ApplyDiff makes the ReportResource and
+// DeleteResource calls that close the gap. It is meant for periodic
+// reconciliation jobs that repair missed events.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"github.com/project-kessel/kessel-sdk-go/kessel/inventory"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// Update is a resource that exists on both sides but whose representations
+// differ.
+type Update struct {
+	Request *v1beta2.ReportResourceRequest
+	// Fields are the paths that differ, sorted: "metadata" for any change
+	// of the representation metadata, and "common.<path>" or
+	// "reporter.<path>" (see inventory.Diff) for the representations.
+	Fields []string
+}
+
+// Diff lists the calls that converge the actual resources with the desired
+// ones.
+type Diff struct {
+	// Create are the desired resources that do not exist.
+	Create []*v1beta2.ReportResourceRequest
+	// Update are the desired resources whose representations changed.
+	Update []Update
+	// Delete are the resources that exist but are no longer desired.
+	Delete []*v1beta2.DeleteResourceRequest
+	// Unchanged is the number of resources that need no call.
+	Unchanged int
+}
+
+// Len returns the number of calls ApplyDiff makes for d.
+func (d Diff) Len() int {
+	return len(d.Create) + len(d.Update) + len(d.Delete)
+}
+
+// Empty reports whether the resources have already converged.
+func (d Diff) Empty() bool {
+	return d.Len() == 0
+}
+
+// DiffResources compares the desired resources, from the source of truth,
+// with the actual ones, typically the snapshot of the previous run. Resources
+// are matched by type, reporter type, reporter instance and local resource
+// id; if a key appears more than once on one side, the last occurrence is
+// used. Creates and updates follow the order of desired and deletes the order
+// of actual.
+func DiffResources(desired, actual []*v1beta2.ReportResourceRequest) Diff {
+	actualByKey := map[string]*v1beta2.ReportResourceRequest{}
+	var actualKeys []string
+	for _, request := range actual {
+		key := resourceKey(request)
+		if _, ok := actualByKey[key]; !ok {
+			actualKeys = append(actualKeys, key)
+		}
+		actualByKey[key] = request
+	}
+
+	desiredByKey := map[string]*v1beta2.ReportResourceRequest{}
+	var desiredKeys []string
+	for _, request := range desired {
+		key := resourceKey(request)
+		if _, ok := desiredByKey[key]; !ok {
+			desiredKeys = append(desiredKeys, key)
+		}
+		desiredByKey[key] = request
+	}
+
+	var diff Diff
+	for _, key := range desiredKeys {
+		request := desiredByKey[key]
+		existing, ok := actualByKey[key]
+		if !ok {
+			diff.Create = append(diff.Create, request)
+			continue
+		}
+		if fields := changedFields(request.GetRepresentations(), existing.GetRepresentations()); len(fields) > 0 {
+			diff.Update = append(diff.Update, Update{Request: request, Fields: fields})
+		} else {
+			diff.Unchanged++
+		}
+	}
+	for _, key := range actualKeys {
+		if _, ok := desiredByKey[key]; !ok {
+			diff.Delete = append(diff.Delete, inventory.DeleteRequestFor(resourceReference(actualByKey[key])))
+		}
+	}
+	return diff
+}
+
+func changedFields(desired, actual *v1beta2.ResourceRepresentations) []string {
+	var fields []string
+	if !proto.Equal(desired.GetMetadata(), actual.GetMetadata()) {
+		fields = append(fields, "metadata")
+	}
+	for _, path := range inventory.Diff(desired.GetCommon(), actual.GetCommon()) {
+		fields = append(fields, "common."+path)
+	}
+	for _, path := range inventory.Diff(desired.GetReporter(), actual.GetReporter()) {
+		fields = append(fields, "reporter."+path)
+	}
+	return fields
+}
+
+func resourceKey(request *v1beta2.ReportResourceRequest) string {
+	return fmt.Sprintf("%s/%s/%s/%s", request.GetType(), request.GetReporterType(), request.GetReporterInstanceId(), request.GetRepresentations().GetMetadata().GetLocalResourceId())
+}
+
+func resourceReference(request *v1beta2.ReportResourceRequest) *v1beta2.ResourceReference {
+	reporter := &v1beta2.ReporterReference{Type: request.GetReporterType()}
+	if instanceId := request.GetReporterInstanceId(); instanceId != "" {
+		reporter.InstanceId = &instanceId
+	}
+	return &v1beta2.ResourceReference{
+		ResourceType: request.GetType(),
+		ResourceId:   request.GetRepresentations().GetMetadata().GetLocalResourceId(),
+		Reporter:     reporter,
+	}
+}
+
+// Operation is the kind of call ApplyDiff makes for a resource.
+type Operation int
+
+const (
+	OperationCreate Operation = iota
+	OperationUpdate
+	OperationDelete
+)
+
+func (o Operation) String() string {
+	switch o {
+	case OperationCreate:
+		return "create"
+	case OperationUpdate:
+		return "update"
+	case OperationDelete:
+		return "delete"
+	default:
+		return fmt.Sprintf("Operation(%d)", int(o))
+	}
+}
+
+// Progress describes one finished call of ApplyDiff.
+type Progress struct {
+	Operation Operation
+	// Key is "<type>/<local resource id>" of the resource.
+	Key string
+	// Err is the error of the call, always nil in dry-run mode.
+	Err error
+	// Stats are the totals so far, including this call.
+	Stats ApplyStats
+}
+
+// ApplyOptions configures ApplyDiff.
+type ApplyOptions struct {
+	// Maximum number of calls in flight. Values below 1 are treated as 1.
+	Concurrency int
+	// Reports the calls through OnProgress and the stats without making
+	// them.
+	DryRun bool
+	// Called after each call completes. Calls are serialized, so the
+	// callback does not need its own locking, but it should return quickly
+	// because it holds up the other workers.
+	OnProgress func(Progress)
+}
+
+// ApplyStats summarizes an ApplyDiff run. Failed calls are not counted in
+// Created, Updated or Deleted.
+type ApplyStats struct {
+	Total   int
+	Created int
+	Updated int
+	Deleted int
+	Failed  int
+	Elapsed time.Duration
+}
+
+type operation struct {
+	kind   Operation
+	key    string
+	report *v1beta2.ReportResourceRequest
+	delete *v1beta2.DeleteResourceRequest
+}
+
+// ApplyDiff makes the calls of diff using a pool of options.Concurrency
+// workers: a ReportResource for each create and update and a DeleteResource
+// for each delete. Every call is attempted even if some fail. It returns the
+// final stats together with a *errors.Multi of the failures, whose items
+// carry the index of the call in the order creates, updates, deletes. If ctx
+// ends, the calls that have not started yet fail with the context error.
+func ApplyDiff(ctx context.Context, client v1beta2.KesselInventoryServiceClient, diff Diff, options ApplyOptions) (ApplyStats, error) {
+	operations := make([]operation, 0, diff.Len())
+	for _, request := range diff.Create {
+		operations = append(operations, operation{kind: OperationCreate, key: reportKey(request), report: request})
+	}
+	for _, update := range diff.Update {
+		operations = append(operations, operation{kind: OperationUpdate, key: reportKey(update.Request), report: update.Request})
+	}
+	for _, request := range diff.Delete {
+		key := fmt.Sprintf("%s/%s", request.GetReference().GetResourceType(), request.GetReference().GetResourceId())
+		operations = append(operations, operation{kind: OperationDelete, key: key, delete: request})
+	}
+
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	start := time.Now()
+	stats := ApplyStats{Total: len(operations)}
+	errs := make([]error, len(operations))
+	var mu sync.Mutex
+
+	record := func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err != nil:
+			stats.Failed++
+			errs[i] = err
+		case operations[i].kind == OperationCreate:
+			stats.Created++
+		case operations[i].kind == OperationUpdate:
+			stats.Updated++
+		default:
+			stats.Deleted++
+		}
+		stats.Elapsed = time.Since(start)
+		if options.OnProgress != nil {
+			options.OnProgress(Progress{Operation: operations[i].kind, Key: operations[i].key, Err: err, Stats: stats})
+		}
+	}
+	apply := func(i int) {
+		if options.DryRun {
+			record(i, nil)
+			return
+		}
+		var err error
+		if operations[i].report != nil {
+			_, err = client.ReportResource(ctx, operations[i].report)
+		} else {
+			_, err = client.DeleteResource(ctx, operations[i].delete)
+		}
+		record(i, err)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(operations)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				apply(i)
+			}
+		}()
+	}
+
+	for i := range operations {
+		if err := ctx.Err(); err != nil {
+			record(i, err)
+			continue
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			record(i, ctx.Err())
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	stats.Elapsed = time.Since(start)
+	result := &kesselerrors.Multi{}
+	for i, err := range errs {
+		result.Append(i, operations[i].kind.String()+" "+operations[i].key, err)
+	}
+	return stats, result.ErrorOrNil()
+}
+
+func reportKey(request *v1beta2.ReportResourceRequest) string {
+	return fmt.Sprintf("%s/%s", request.GetType(), request.GetRepresentations().GetMetadata().GetLocalResourceId())
+}
@@ -0,0 +1,171 @@
+package reconcile
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+func host(id string, common map[string]any) *v1beta2.ReportResourceRequest {
+	commonStruct, err := structpb.NewStruct(common)
+	if err != nil {
+		panic(err)
+	}
+	return &v1beta2.ReportResourceRequest{
+		Type:               "host",
+		ReporterType:       "hbi",
+		ReporterInstanceId: "hbi-1",
+		Representations: &v1beta2.ResourceRepresentations{
+			Metadata: &v1beta2.RepresentationMetadata{LocalResourceId: id, ApiHref: "https://example.com/" + id},
+			Common:   commonStruct,
+		},
+	}
+}
+
+func TestDiffResources(t *testing.T) {
+	desired := []*v1beta2.ReportResourceRequest{
+		host("new", map[string]any{"workspace_id": "ws-1"}),
+		host("same", map[string]any{"workspace_id": "ws-1"}),
+		host("moved", map[string]any{"workspace_id": "ws-2"}),
+	}
+	actual := []*v1beta2.ReportResourceRequest{
+		host("gone", map[string]any{"workspace_id": "ws-1"}),
+		host("same", map[string]any{"workspace_id": "ws-1"}),
+		host("moved", map[string]any{"workspace_id": "ws-1"}),
+	}
+
+	diff := DiffResources(desired, actual)
+
+	require.Len(t, diff.Create, 1)
+	assert.Equal(t, "new", diff.Create[0].GetRepresentations().GetMetadata().GetLocalResourceId())
+	require.Len(t, diff.Update, 1)
+	assert.Equal(t, "moved", diff.Update[0].Request.GetRepresentations().GetMetadata().GetLocalResourceId())
+	assert.Equal(t, []string{"common.workspace_id"}, diff.Update[0].Fields)
+	require.Len(t, diff.Delete, 1)
+	reference := diff.Delete[0].GetReference()
+	assert.Equal(t, "host", reference.GetResourceType())
+	assert.Equal(t, "gone", reference.GetResourceId())
+	assert.Equal(t, "hbi", reference.GetReporter().GetType())
+	assert.Equal(t, "hbi-1", reference.GetReporter().GetInstanceId())
+	assert.Equal(t, 1, diff.Unchanged)
+	assert.Equal(t, 3, diff.Len())
+}
+
+func TestDiffResources_matchesByReporterInstance(t *testing.T) {
+	other := host("a", nil)
+	other.ReporterInstanceId = "hbi-2"
+
+	diff := DiffResources([]*v1beta2.ReportResourceRequest{host("a", nil)}, []*v1beta2.ReportResourceRequest{other})
+
+	assert.Len(t, diff.Create, 1)
+	assert.Len(t, diff.Delete, 1)
+}
+
+func TestDiffResources_metadataChange(t *testing.T) {
+	changed := host("a", nil)
+	changed.Representations.Metadata.ApiHref = "https://example.com/moved"
+
+	diff := DiffResources([]*v1beta2.ReportResourceRequest{changed}, []*v1beta2.ReportResourceRequest{host("a", nil)})
+
+	require.Len(t, diff.Update, 1)
+	assert.Equal(t, []string{"metadata"}, diff.Update[0].Fields)
+}
+
+func TestDiffResources_converged(t *testing.T) {
+	resources := []*v1beta2.ReportResourceRequest{host("a", nil), host("b", nil)}
+
+	diff := DiffResources(resources, resources)
+
+	assert.True(t, diff.Empty())
+	assert.Equal(t, 2, diff.Unchanged)
+}
+
+// mockInventoryClient records calls and fails those for the resource "fail".
+type mockInventoryClient struct {
+	v1beta2.KesselInventoryServiceClient
+	mu      sync.Mutex
+	reports []string
+	deletes []string
+}
+
+func (m *mockInventoryClient) ReportResource(ctx context.Context, in *v1beta2.ReportResourceRequest, opts ...grpc.CallOption) (*v1beta2.ReportResourceResponse, error) {
+	id := in.GetRepresentations().GetMetadata().GetLocalResourceId()
+	if id == "fail" {
+		return nil, status.Error(codes.Internal, "boom")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reports = append(m.reports, id)
+	return &v1beta2.ReportResourceResponse{}, nil
+}
+
+func (m *mockInventoryClient) DeleteResource(ctx context.Context, in *v1beta2.DeleteResourceRequest, opts ...grpc.CallOption) (*v1beta2.DeleteResourceResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deletes = append(m.deletes, in.GetReference().GetResourceId())
+	return &v1beta2.DeleteResourceResponse{}, nil
+}
+
+func TestApplyDiff(t *testing.T) {
+	client := &mockInventoryClient{}
+	diff := DiffResources(
+		[]*v1beta2.ReportResourceRequest{host("new", nil), host("fail", nil), host("moved", map[string]any{"workspace_id": "ws-2"})},
+		[]*v1beta2.ReportResourceRequest{host("moved", map[string]any{"workspace_id": "ws-1"}), host("gone", nil)},
+	)
+	var progress []Progress
+
+	stats, err := ApplyDiff(context.Background(), client, diff, ApplyOptions{Concurrency: 2, OnProgress: func(p Progress) {
+		progress = append(progress, p)
+	}})
+
+	assert.ElementsMatch(t, []string{"new", "moved"}, client.reports)
+	assert.Equal(t, []string{"gone"}, client.deletes)
+	assert.Equal(t, ApplyStats{Total: 4, Created: 1, Updated: 1, Deleted: 1, Failed: 1, Elapsed: stats.Elapsed}, stats)
+	require.Len(t, progress, 4)
+	assert.Equal(t, 4, progress[3].Stats.Created+progress[3].Stats.Updated+progress[3].Stats.Deleted+progress[3].Stats.Failed)
+
+	var multi *kesselerrors.Multi
+	require.ErrorAs(t, err, &multi)
+	require.Equal(t, 1, multi.Len())
+	assert.Equal(t, 1, multi.Errors[0].Index)
+	assert.Equal(t, "create host/fail", multi.Errors[0].Key)
+}
+
+func TestApplyDiff_dryRun(t *testing.T) {
+	client := &mockInventoryClient{}
+	diff := DiffResources([]*v1beta2.ReportResourceRequest{host("new", nil)}, []*v1beta2.ReportResourceRequest{host("gone", nil)})
+	var operations []string
+
+	stats, err := ApplyDiff(context.Background(), client, diff, ApplyOptions{DryRun: true, OnProgress: func(p Progress) {
+		operations = append(operations, p.Operation.String()+" "+p.Key)
+	}})
+
+	require.NoError(t, err)
+	assert.Empty(t, client.reports)
+	assert.Empty(t, client.deletes)
+	assert.Equal(t, []string{"create host/new", "delete host/gone"}, operations)
+	assert.Equal(t, 1, stats.Created)
+	assert.Equal(t, 1, stats.Deleted)
+}
+
+func TestApplyDiff_canceled(t *testing.T) {
+	client := &mockInventoryClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stats, err := ApplyDiff(ctx, client, Diff{Create: []*v1beta2.ReportResourceRequest{host("a", nil), host("b", nil)}}, ApplyOptions{})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, client.reports)
+	assert.Equal(t, 2, stats.Failed)
+}
@@ -0,0 +1,188 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// ErrNoInteraction is returned for a call that matches no recorded
+// interaction.
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+var unmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// Conn is a grpc.ClientConnInterface that serves calls from a Cassette
+// instead of a server, so any generated client can be built on it. A call
+// matches an interaction with the same method and an equal request.
+// Interactions are served in recorded order, so repeated calls with the same
+// request see the responses in the order they were recorded; once all
+// matching interactions are used, the last one is served again. Conn is safe
+// for concurrent use.
+type Conn struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+var _ grpc.ClientConnInterface = (*Conn)(nil)
+
+// NewConn creates a Conn serving the interactions in cassette.
+func NewConn(cassette *Cassette) *Conn {
+	return &Conn{
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// NewInventoryClient loads the golden file at path and returns an inventory
+// client that replays it.
+func NewInventoryClient(path string) (v1beta2.KesselInventoryServiceClient, error) {
+	cassette, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return v1beta2.NewKesselInventoryServiceClient(NewConn(cassette)), nil
+}
+
+// Unused returns the interactions that have not been served, so tests can
+// assert that every recorded call was made.
+func (c *Conn) Unused() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range c.interactions {
+		if !c.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// Invoke serves a unary call.
+func (c *Conn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	interaction, err := c.match(method, args)
+	if err != nil {
+		return err
+	}
+	if err := interaction.Err(); err != nil {
+		return err
+	}
+	if len(interaction.Responses) > 0 {
+		return unmarshal(interaction.Responses[0], reply)
+	}
+	return nil
+}
+
+// NewStream opens a replayed stream. The interaction is matched against the
+// first message sent on it.
+func (c *Conn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &replayStream{ctx: ctx, conn: c, method: method}, nil
+}
+
+func (c *Conn) match(method string, args any) (Interaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := -1
+	for i, interaction := range c.interactions {
+		if interaction.Method != method || !requestMatches(interaction, args) {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return interaction, nil
+		}
+		last = i
+	}
+	if last >= 0 {
+		return c.interactions[last], nil
+	}
+	return Interaction{}, fmt.Errorf("%w for %s", ErrNoInteraction, method)
+}
+
+// requestMatches reports whether args equals the recorded request. An
+// interaction without a request, recorded when opening a stream failed,
+// matches any request.
+func requestMatches(interaction Interaction, args any) bool {
+	if interaction.Request == nil {
+		return true
+	}
+	message, ok := args.(proto.Message)
+	if !ok {
+		return false
+	}
+	recorded := message.ProtoReflect().New().Interface()
+	if err := unmarshalOptions.Unmarshal(interaction.Request, recorded); err != nil {
+		return false
+	}
+	return proto.Equal(recorded, message)
+}
+
+func unmarshal(data []byte, m any) error {
+	message, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("cannot replay into %T: not a proto message", m)
+	}
+	return unmarshalOptions.Unmarshal(data, message)
+}
+
+// replayStream serves the responses of the interaction matched by the first
+// message sent, then the recorded status.
+type replayStream struct {
+	ctx         context.Context
+	conn        *Conn
+	method      string
+	request     any
+	interaction *Interaction
+	next        int
+}
+
+func (s *replayStream) Header() (metadata.MD, error) { return metadata.MD{}, nil }
+func (s *replayStream) Trailer() metadata.MD         { return metadata.MD{} }
+func (s *replayStream) CloseSend() error             { return nil }
+func (s *replayStream) Context() context.Context     { return s.ctx }
+
+func (s *replayStream) SendMsg(m any) error {
+	if s.request == nil {
+		s.request = m
+	}
+	return nil
+}
+
+func (s *replayStream) RecvMsg(m any) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	if s.interaction == nil {
+		interaction, err := s.conn.match(s.method, s.request)
+		if err != nil {
+			return err
+		}
+		s.interaction = &interaction
+	}
+	if s.next < len(s.interaction.Responses) {
+		s.next++
+		return unmarshal(s.interaction.Responses[s.next-1], m)
+	}
+	if err := s.interaction.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
@@ -0,0 +1,108 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+func TestConn_unary(t *testing.T) {
+	conn := NewConn(record(t).Cassette())
+	client := v1beta2.NewKesselInventoryServiceClient(conn)
+	ctx := context.Background()
+
+	response, err := client.Check(ctx, checkRequest("view"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.GetAllowed() != v1beta2.Allowed_ALLOWED_TRUE {
+		t.Errorf("Expected ALLOWED_TRUE, got %v", response.GetAllowed())
+	}
+
+	// Repeated calls are served the last matching interaction again.
+	if _, err := client.Check(ctx, checkRequest("view")); err != nil {
+		t.Errorf("Unexpected error on repeated call: %v", err)
+	}
+
+	if _, err := client.Check(ctx, checkRequest("delete")); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected recorded PermissionDenied, got %v", err)
+	}
+
+	if _, err := client.Check(ctx, checkRequest("update")); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction, got %v", err)
+	}
+
+	unused := conn.Unused()
+	if len(unused) != 1 || unused[0].Method != listMethod {
+		t.Errorf("Expected only the stream to be unused, got %+v", unused)
+	}
+}
+
+func TestConn_stream(t *testing.T) {
+	conn := NewConn(record(t).Cassette())
+	client := v1beta2.NewKesselInventoryServiceClient(conn)
+
+	stream, err := client.StreamedListObjects(context.Background(), &v1beta2.StreamedListObjectsRequest{Relation: "view"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var ids []string
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids = append(ids, response.GetObject().GetResourceId())
+	}
+	if len(ids) != 2 || ids[0] != "ws-1" || ids[1] != "ws-2" {
+		t.Errorf("Expected [ws-1 ws-2], got %v", ids)
+	}
+
+	stream, err = client.StreamedListObjects(context.Background(), &v1beta2.StreamedListObjectsRequest{Relation: "edit"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := stream.Recv(); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestConn_canceledContext(t *testing.T) {
+	client := v1beta2.NewKesselInventoryServiceClient(NewConn(&Cassette{}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.Check(ctx, checkRequest("view")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestNewInventoryClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	if err := record(t).Save(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client, err := NewInventoryClient(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	response, err := client.Check(context.Background(), checkRequest("view"))
+	if err != nil || response.GetAllowed() != v1beta2.Allowed_ALLOWED_TRUE {
+		t.Errorf("Expected replayed ALLOWED_TRUE, got %v, %v", response, err)
+	}
+
+	if _, err := NewInventoryClient(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
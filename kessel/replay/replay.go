@@ -0,0 +1,207 @@
+// Package replay records gRPC traffic to JSON golden files and serves it back,
+// so integration tests of code using the SDK can run without a Kessel server.
+//
+// Record once against a real server by adding the interceptors to a client:
+//
+//	recorder := replay.NewRecorder()
+//	client, conn, err := v1beta2.NewClientBuilder(target).
+//		WithUnaryInterceptor(replay.UnaryClientInterceptor(recorder)).
+//		WithStreamInterceptor(replay.StreamClientInterceptor(recorder)).
+//		Build()
+//	// ... exercise the client ...
+//	err = recorder.Save("testdata/inventory.json")
+//
+// and replay it in tests:
+//
+//	client, err := replay.NewInventoryClient("testdata/inventory.json")
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Interaction is one recorded call: the request, every response received and
+// the final status. Unary calls have at most one response.
+type Interaction struct {
+	Method    string            `json:"method"`
+	Request   json.RawMessage   `json:"request,omitempty"`
+	Responses []json.RawMessage `json:"responses,omitempty"`
+	Code      string            `json:"code"`
+	Message   string            `json:"message,omitempty"`
+}
+
+// Err returns the status error recorded for the interaction, or nil when the
+// call succeeded.
+func (i Interaction) Err() error {
+	code := parseCode(i.Code)
+	if code == codes.OK {
+		return nil
+	}
+	return status.Error(code, i.Message)
+}
+
+// Cassette is the contents of a golden file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load reads a golden file written by Recorder.Save.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, err
+	}
+	return cassette, nil
+}
+
+// Recorder captures calls made through its interceptors. Only messages are
+// recorded; metadata, including authorization headers, is not. Recorder is
+// safe for concurrent use.
+type Recorder struct {
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Cassette returns the interactions recorded so far, in completion order.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Cassette{Interactions: append([]Interaction{}, r.interactions...)}
+}
+
+// Save writes the recorded interactions to path as indented JSON.
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Cassette(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) record(interaction Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = append(r.interactions, interaction)
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that records every unary
+// call to r.
+func UnaryClientInterceptor(r *Recorder) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		interaction := Interaction{Method: method, Request: marshal(req)}
+		if err == nil {
+			if response := marshal(reply); response != nil {
+				interaction.Responses = []json.RawMessage{response}
+			}
+		}
+		setStatus(&interaction, err)
+		r.record(interaction)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a gRPC interceptor that records every
+// stream to r once it ends. Streams that are abandoned before they end are
+// not recorded.
+func StreamClientInterceptor(r *Recorder) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			interaction := Interaction{Method: method}
+			setStatus(&interaction, err)
+			r.record(interaction)
+			return nil, err
+		}
+		return &recordingStream{ClientStream: stream, recorder: r, interaction: Interaction{Method: method}}, nil
+	}
+}
+
+// recordingStream captures the request sent and the responses received on a
+// stream.
+type recordingStream struct {
+	grpc.ClientStream
+	recorder    *Recorder
+	interaction Interaction
+	once        sync.Once
+}
+
+func (s *recordingStream) SendMsg(m any) error {
+	if s.interaction.Request == nil {
+		s.interaction.Request = marshal(m)
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *recordingStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		if response := marshal(m); response != nil {
+			s.interaction.Responses = append(s.interaction.Responses, response)
+		}
+		return nil
+	}
+
+	s.once.Do(func() {
+		if errors.Is(err, io.EOF) {
+			setStatus(&s.interaction, nil)
+		} else {
+			setStatus(&s.interaction, err)
+		}
+		s.recorder.record(s.interaction)
+	})
+	return err
+}
+
+func marshal(m any) json.RawMessage {
+	message, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+	data, err := protojson.Marshal(message)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func setStatus(interaction *Interaction, err error) {
+	st := status.Convert(err)
+	interaction.Code = st.Code().String()
+	interaction.Message = st.Message()
+}
+
+func parseCode(name string) codes.Code {
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if code.String() == name {
+			return code
+		}
+	}
+	if name == "" {
+		return codes.OK
+	}
+	return codes.Unknown
+}
@@ -0,0 +1,171 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+const (
+	checkMethod = "/kessel.inventory.v1beta2.KesselInventoryService/Check"
+	listMethod  = "/kessel.inventory.v1beta2.KesselInventoryService/StreamedListObjects"
+)
+
+func checkRequest(relation string) *v1beta2.CheckRequest {
+	return &v1beta2.CheckRequest{
+		Object:   &v1beta2.ResourceReference{ResourceType: "host", ResourceId: "h1", Reporter: &v1beta2.ReporterReference{Type: "hbi"}},
+		Relation: relation,
+	}
+}
+
+// fakeStream serves responses then ends with err (io.EOF when nil).
+type fakeStream struct {
+	grpc.ClientStream
+	responses []*v1beta2.StreamedListObjectsResponse
+	err       error
+}
+
+func (f *fakeStream) SendMsg(m any) error { return nil }
+func (f *fakeStream) CloseSend() error    { return nil }
+
+func (f *fakeStream) RecvMsg(m any) error {
+	if len(f.responses) == 0 {
+		if f.err != nil {
+			return f.err
+		}
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), f.responses[0])
+	f.responses = f.responses[1:]
+	return nil
+}
+
+func record(t *testing.T) *Recorder {
+	t.Helper()
+	recorder := NewRecorder()
+	unary := UnaryClientInterceptor(recorder)
+	ctx := context.Background()
+
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if req.(*v1beta2.CheckRequest).GetRelation() == "delete" {
+			return status.Error(codes.PermissionDenied, "denied")
+		}
+		reply.(*v1beta2.CheckResponse).Allowed = v1beta2.Allowed_ALLOWED_TRUE
+		return nil
+	}
+	if err := unary(ctx, checkMethod, checkRequest("view"), &v1beta2.CheckResponse{}, nil, invoker); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := unary(ctx, checkMethod, checkRequest("delete"), &v1beta2.CheckResponse{}, nil, invoker); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Expected PermissionDenied, got %v", err)
+	}
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &fakeStream{responses: []*v1beta2.StreamedListObjectsResponse{
+			{Object: &v1beta2.ResourceReference{ResourceId: "ws-1"}},
+			{Object: &v1beta2.ResourceReference{ResourceId: "ws-2"}},
+		}}, nil
+	}
+	stream, err := StreamClientInterceptor(recorder)(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, listMethod, streamer)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := stream.SendMsg(&v1beta2.StreamedListObjectsRequest{Relation: "view"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for {
+		if err := stream.RecvMsg(&v1beta2.StreamedListObjectsResponse{}); err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("Expected io.EOF, got %v", err)
+			}
+			break
+		}
+	}
+	return recorder
+}
+
+func TestRecorder(t *testing.T) {
+	cassette := record(t).Cassette()
+	if len(cassette.Interactions) != 3 {
+		t.Fatalf("Expected 3 interactions, got %d", len(cassette.Interactions))
+	}
+
+	allowed := cassette.Interactions[0]
+	if allowed.Code != "OK" || len(allowed.Responses) != 1 {
+		t.Errorf("Expected one OK response, got %+v", allowed)
+	}
+	denied := cassette.Interactions[1]
+	if denied.Code != "PermissionDenied" || denied.Message != "denied" || len(denied.Responses) != 0 {
+		t.Errorf("Expected PermissionDenied without responses, got %+v", denied)
+	}
+	stream := cassette.Interactions[2]
+	if stream.Method != listMethod || stream.Code != "OK" || len(stream.Responses) != 2 || stream.Request == nil {
+		t.Errorf("Expected stream with request and two responses, got %+v", stream)
+	}
+}
+
+func TestRecorder_streamOpenError(t *testing.T) {
+	recorder := NewRecorder()
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, status.Error(codes.Unavailable, "down")
+	}
+	_, err := StreamClientInterceptor(recorder)(context.Background(), &grpc.StreamDesc{}, nil, listMethod, streamer)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("Expected Unavailable, got %v", err)
+	}
+
+	interactions := recorder.Cassette().Interactions
+	if len(interactions) != 1 || interactions[0].Code != "Unavailable" || interactions[0].Request != nil {
+		t.Errorf("Expected one Unavailable interaction without request, got %+v", interactions)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	recorder := record(t)
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cassette, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cassette.Interactions) != 3 {
+		t.Errorf("Expected 3 interactions, got %d", len(cassette.Interactions))
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestInteractionErr(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected codes.Code
+	}{
+		{code: "", expected: codes.OK},
+		{code: "OK", expected: codes.OK},
+		{code: "NotFound", expected: codes.NotFound},
+		{code: "bogus", expected: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			err := Interaction{Code: tt.code}.Err()
+			if status.Code(err) != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Package integration starts in-process stand-ins for the services the SDK
+// talks to, an OIDC issuer and Kessel Inventory, so consumers and the SDK
+// itself can run end-to-end tests of real SDK clients (OIDC discovery, token
+// requests, TLS and bearer tokens over gRPC) in CI without Docker.
+//
+// Neither is the real service. FakeIssuer serves Keycloak-style discovery and
+// client credentials endpoints and nothing else: it issues opaque random
+// tokens rather than signed JWTs, publishes no JWKS, and has no users, roles,
+// scopes, token exchange, refresh tokens or token expiry. Inventory serves a
+// caller-provided KesselInventoryServiceServer over TLS, rejecting calls
+// without a token from the issuer, so Kessel relations are not exercised.
+// Tests that need a real Keycloak or Kessel must start them separately, e.g.
+// with testcontainers.
+package integration
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+)
+
+// DefaultRealm is the realm name used when Realm.Name is empty.
+const DefaultRealm = "redhat-external"
+
+// tokenLifetime is the expires_in of issued tokens, in seconds.
+const tokenLifetime = 300
+
+// Realm configures the fake issuer's realm: its name, used in Keycloak-style
+// URL paths, and the confidential clients allowed to use the client
+// credentials grant.
+type Realm struct {
+	Name string
+	// Clients maps client ids to their secrets.
+	Clients map[string]string
+}
+
+// FakeIssuer is a running fake OIDC issuer for one realm. It is not Keycloak;
+// see the package documentation for what it does not emulate.
+type FakeIssuer struct {
+	realm  Realm
+	server *httptest.Server
+
+	mu     sync.Mutex
+	tokens map[string]string // access token -> client id
+}
+
+// StartFakeIssuer starts a fake issuer that is stopped when the test ends.
+func StartFakeIssuer(t testing.TB, realm Realm) *FakeIssuer {
+	t.Helper()
+	if realm.Name == "" {
+		realm.Name = DefaultRealm
+	}
+	f := &FakeIssuer{realm: realm, tokens: map[string]string{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /realms/"+realm.Name+auth.DefaultWellKnownPath, f.discovery)
+	mux.HandleFunc("POST /realms/"+realm.Name+"/protocol/openid-connect/token", f.token)
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// IssuerUrl returns the realm's issuer, the value for auth.FetchOIDCDiscovery
+// and the AUTH_DISCOVERY_ISSUER_URL environment variable.
+func (f *FakeIssuer) IssuerUrl() string {
+	return f.server.URL + "/realms/" + f.realm.Name
+}
+
+// TokenEndpoint returns the realm's token endpoint.
+func (f *FakeIssuer) TokenEndpoint() string {
+	return f.IssuerUrl() + "/protocol/openid-connect/token"
+}
+
+// Credentials returns client credentials for clientId, which must be one of
+// the realm's clients for its token requests to succeed.
+func (f *FakeIssuer) Credentials(clientId string, options ...auth.CredentialsOption) auth.OAuth2ClientCredentials {
+	return auth.NewOAuth2ClientCredentials(clientId, f.realm.Clients[clientId], f.TokenEndpoint(), options...)
+}
+
+// ClientForToken returns the client an access token was issued to, and
+// whether the realm issued it.
+func (f *FakeIssuer) ClientForToken(token string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clientId, ok := f.tokens[token]
+	return clientId, ok
+}
+
+// IssuedTokens returns the number of access tokens issued so far.
+func (f *FakeIssuer) IssuedTokens() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.tokens)
+}
+
+func (f *FakeIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                f.IssuerUrl(),
+		"token_endpoint":                        f.TokenEndpoint(),
+		"grant_types_supported":                 []string{"client_credentials"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+	})
+}
+
+func (f *FakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, tokenError("invalid_request"))
+		return
+	}
+	if grantType := r.PostForm.Get("grant_type"); grantType != "client_credentials" {
+		writeJSON(w, http.StatusBadRequest, tokenError("unsupported_grant_type"))
+		return
+	}
+	clientId, secret, ok := r.BasicAuth()
+	if !ok {
+		clientId, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if expected, known := f.realm.Clients[clientId]; !known || expected != secret {
+		writeJSON(w, http.StatusUnauthorized, tokenError("invalid_client"))
+		return
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+	f.mu.Lock()
+	f.tokens[token] = clientId
+	f.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   tokenLifetime,
+	})
+}
+
+func tokenError(code string) map[string]string {
+	return map[string]string{"error": code}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project-kessel/kessel-sdk-go/kessel/auth"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// checkServer allows the checks of the "reporter" client.
+type checkServer struct {
+	v1beta2.UnimplementedKesselInventoryServiceServer
+}
+
+func (s *checkServer) Check(ctx context.Context, in *v1beta2.CheckRequest) (*v1beta2.CheckResponse, error) {
+	if ClientId(ctx) == "reporter" {
+		return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}, nil
+	}
+	return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_FALSE}, nil
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestFakeIssuer_discovery(t *testing.T) {
+	issuer := StartFakeIssuer(t, Realm{})
+
+	metadata, err := auth.FetchOIDCDiscovery(testContext(t), issuer.IssuerUrl(), auth.FetchOIDCDiscoveryOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, issuer.TokenEndpoint(), metadata.TokenEndpoint)
+	assert.Contains(t, issuer.IssuerUrl(), "/realms/"+DefaultRealm)
+}
+
+func TestFakeIssuer_clientCredentials(t *testing.T) {
+	issuer := StartFakeIssuer(t, Realm{Name: "test", Clients: map[string]string{"reporter": "secret"}})
+
+	credentials := issuer.Credentials("reporter")
+	token, err := credentials.GetToken(testContext(t), auth.GetTokenOptions{})
+	require.NoError(t, err)
+	clientId, ok := issuer.ClientForToken(token.AccessToken)
+	assert.True(t, ok)
+	assert.Equal(t, "reporter", clientId)
+
+	unknown := auth.NewOAuth2ClientCredentials("reporter", "wrong", issuer.TokenEndpoint())
+	_, err = unknown.GetToken(testContext(t), auth.GetTokenOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, 1, issuer.IssuedTokens())
+}
+
+func TestInventory_authenticatedClient(t *testing.T) {
+	issuer := StartFakeIssuer(t, Realm{Clients: map[string]string{"reporter": "secret", "viewer": "secret"}})
+	inventory := StartInventory(t, &checkServer{}, issuer)
+
+	response, err := inventory.NewClient(t, "reporter").Check(testContext(t), &v1beta2.CheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, v1beta2.Allowed_ALLOWED_TRUE, response.GetAllowed())
+
+	response, err = inventory.NewClient(t, "viewer").Check(testContext(t), &v1beta2.CheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, v1beta2.Allowed_ALLOWED_FALSE, response.GetAllowed())
+}
+
+func TestInventory_rejectsUnauthenticatedCalls(t *testing.T) {
+	issuer := StartFakeIssuer(t, Realm{Clients: map[string]string{"reporter": "secret"}})
+	inventory := StartInventory(t, &checkServer{}, issuer)
+
+	client, conn, err := v1beta2.NewClientBuilder(inventory.Target()).Unauthenticated(inventory.TransportCredentials()).Build()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = client.Check(testContext(t), &v1beta2.CheckRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestInventory_withoutRealm(t *testing.T) {
+	inventory := StartInventory(t, &checkServer{}, nil)
+
+	response, err := inventory.NewClient(t, "").Check(testContext(t), &v1beta2.CheckRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, v1beta2.Allowed_ALLOWED_FALSE, response.GetAllowed())
+}
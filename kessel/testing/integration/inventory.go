@@ -0,0 +1,176 @@
+package integration
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// Inventory is a running Kessel Inventory gRPC server.
+type Inventory struct {
+	addr    string
+	rootCAs *x509.CertPool
+	issuer  *FakeIssuer
+}
+
+// StartInventory serves service over TLS on a local port until the test
+// ends. If issuer is not nil, calls must carry a bearer token issued by
+// its realm and fail with Unauthenticated otherwise; ClientId returns the
+// client a call's token was issued to.
+func StartInventory(t testing.TB, service v1beta2.KesselInventoryServiceServer, issuer *FakeIssuer) *Inventory {
+	t.Helper()
+	certificate, rootCAs, err := selfSignedCertificate()
+	if err != nil {
+		t.Fatalf("Failed to create a certificate: %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	i := &Inventory{addr: listener.Addr().String(), rootCAs: rootCAs, issuer: issuer}
+	server := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12})),
+		grpc.UnaryInterceptor(i.authenticateUnary),
+		grpc.StreamInterceptor(i.authenticateStream),
+	)
+	v1beta2.RegisterKesselInventoryServiceServer(server, service)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return i
+}
+
+// Target returns the server address, the value for v1beta2.NewClientBuilder
+// and the KESSEL_ENDPOINT environment variable.
+func (i *Inventory) Target() string {
+	return i.addr
+}
+
+// TransportCredentials returns TLS credentials that trust the server's
+// certificate.
+func (i *Inventory) TransportCredentials() credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{RootCAs: i.rootCAs, MinVersion: tls.VersionTLS12})
+}
+
+// NewClient builds an SDK client authenticated as clientId of the fake
+// issuer's realm, or an unauthenticated one if the server was started without
+// an issuer. The connection is closed when the test ends.
+func (i *Inventory) NewClient(t testing.TB, clientId string) v1beta2.KesselInventoryServiceClient {
+	t.Helper()
+	builder := v1beta2.NewClientBuilder(i.addr)
+	if i.issuer != nil {
+		credentials := i.issuer.Credentials(clientId)
+		builder = builder.OAuth2ClientAuthenticated(&credentials, i.TransportCredentials())
+	} else {
+		builder = builder.Unauthenticated(i.TransportCredentials())
+	}
+	client, conn, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Failed to build the client: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return client
+}
+
+type clientIdKey struct{}
+
+// ClientId returns the realm client that made the call served with ctx, or
+// "" if the server was started without a realm.
+func ClientId(ctx context.Context) string {
+	clientId, _ := ctx.Value(clientIdKey{}).(string)
+	return clientId
+}
+
+func (i *Inventory) authenticate(ctx context.Context) (context.Context, error) {
+	if i.issuer == nil {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization := md.Get("authorization")
+	if len(authorization) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	token, ok := strings.CutPrefix(authorization[0], "Bearer ")
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization is not a bearer token")
+	}
+	clientId, ok := i.issuer.ClientForToken(token)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "token was not issued by the realm")
+	}
+	return context.WithValue(ctx, clientIdKey{}, clientId), nil
+}
+
+func (i *Inventory) authenticateUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := i.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (i *Inventory) authenticateStream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := i.authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// selfSignedCertificate returns a certificate for 127.0.0.1 and localhost
+// and a pool that trusts it.
+func selfSignedCertificate() (tls.Certificate, *x509.CertPool, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kessel-inventory"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool, nil
+}
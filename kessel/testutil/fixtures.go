@@ -0,0 +1,59 @@
+package testutil
+
+import (
+	"fmt"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	v2 "github.com/project-kessel/kessel-sdk-go/kessel/rbac/v2"
+	"github.com/project-kessel/kessel-sdk-go/kessel/types"
+)
+
+// Domain is the principal domain of the fixture subjects.
+const Domain = "redhat"
+
+// Principal returns the subject for the user id in Domain.
+func Principal(id string) *v1beta2.SubjectReference {
+	return v2.PrincipalSubject(id, Domain)
+}
+
+// Workspace returns a reference to the RBAC workspace id.
+func Workspace(id string) *v1beta2.ResourceReference {
+	return v2.WorkspaceResource(id)
+}
+
+// Host returns a reference to the HBI host id.
+func Host(id string) *v1beta2.ResourceReference {
+	return &v1beta2.ResourceReference{
+		ResourceType: string(types.ResourceHost),
+		ResourceId:   id,
+		Reporter:     &v1beta2.ReporterReference{Type: string(types.ReporterHBI)},
+	}
+}
+
+// CheckRequest asks whether subject has relation on object.
+func CheckRequest(object *v1beta2.ResourceReference, relation string, subject *v1beta2.SubjectReference) *v1beta2.CheckRequest {
+	return &v1beta2.CheckRequest{Object: object, Relation: relation, Subject: subject}
+}
+
+// CheckBulkRequest returns n items asking whether principal user-<i> has
+// relation on workspace workspace-<i>, for i from 0 to n-1.
+func CheckBulkRequest(n int, relation string) *v1beta2.CheckBulkRequest {
+	request := &v1beta2.CheckBulkRequest{}
+	for i := range n {
+		request.Items = append(request.Items, &v1beta2.CheckBulkRequestItem{
+			Object:   Workspace(fmt.Sprintf("workspace-%d", i)),
+			Relation: relation,
+			Subject:  Principal(fmt.Sprintf("user-%d", i)),
+		})
+	}
+	return request
+}
+
+// CheckResponse returns the response a server sends for an allowed or
+// denied check.
+func CheckResponse(allowed bool) *v1beta2.CheckResponse {
+	if allowed {
+		return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_TRUE}
+	}
+	return &v1beta2.CheckResponse{Allowed: v1beta2.Allowed_ALLOWED_FALSE}
+}
@@ -0,0 +1,59 @@
+package testutil
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden
+// rewrite golden files instead of comparing against them.
+const UpdateGoldenEnv = "KESSEL_UPDATE_GOLDEN"
+
+// AssertGolden compares m with the snapshot in the golden file at path,
+// which holds the Normalize'd message in prototext format. With
+// KESSEL_UPDATE_GOLDEN=1 set, it writes the file instead, like the golden
+// files of gofmt's tests.
+func AssertGolden(t testing.TB, path string, m proto.Message) bool {
+	t.Helper()
+	m = Normalize(m)
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		encoded, err := prototext.MarshalOptions{Multiline: true}.Marshal(m)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0o755)
+		}
+		if err == nil {
+			err = os.WriteFile(path, encoded, 0o644)
+		}
+		if err != nil {
+			t.Errorf("Writing golden file %s: %v", path, err)
+			return false
+		}
+		return true
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Golden file %s does not exist; run with %s=1 to create it", path, UpdateGoldenEnv)
+		return false
+	}
+	if err != nil {
+		t.Errorf("Reading golden file %s: %v", path, err)
+		return false
+	}
+	expected := m.ProtoReflect().New().Interface()
+	if err := prototext.Unmarshal(data, expected); err != nil {
+		t.Errorf("Parsing golden file %s: %v", path, err)
+		return false
+	}
+	if proto.Equal(Normalize(expected), m) {
+		return true
+	}
+	t.Errorf("Message differs from golden file %s (run with %s=1 to update)\nexpected: %s\nactual:   %s", path, UpdateGoldenEnv, prototext.Format(expected), prototext.Format(m))
+	return false
+}
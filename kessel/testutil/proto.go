@@ -0,0 +1,92 @@
+// Package testutil provides assertions and fixtures for tests of code built
+// on the Kessel SDK. It depends only on the standard testing package, so it
+// works alongside any assertion library.
+package testutil
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EqualProto reports whether expected and actual are equal once unknown
+// fields are dropped and repeated fields are sorted, so the order of items
+// (e.g. in a CheckBulkRequest) does not matter.
+func EqualProto(expected, actual proto.Message) bool {
+	return proto.Equal(Normalize(expected), Normalize(actual))
+}
+
+// AssertProtoEqual fails t, showing both messages, unless EqualProto holds.
+func AssertProtoEqual(t testing.TB, expected, actual proto.Message) bool {
+	t.Helper()
+	expected, actual = Normalize(expected), Normalize(actual)
+	if proto.Equal(expected, actual) {
+		return true
+	}
+	t.Errorf("Proto messages differ\nexpected: %s\nactual:   %s", prototext.Format(expected), prototext.Format(actual))
+	return false
+}
+
+// Normalize returns a copy of m without unknown fields and with every
+// repeated field in a canonical order. m itself is not modified.
+func Normalize(m proto.Message) proto.Message {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return m
+	}
+	m = proto.Clone(m)
+	normalize(m.ProtoReflect())
+	return m
+}
+
+func normalize(m protoreflect.Message) {
+	m.SetUnknown(nil)
+	m.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.IsList():
+			list := value.List()
+			if field.Message() != nil {
+				for i := range list.Len() {
+					normalize(list.Get(i).Message())
+				}
+			}
+			sortList(list, field)
+		case field.IsMap():
+			if field.MapValue().Message() != nil {
+				value.Map().Range(func(_ protoreflect.MapKey, entry protoreflect.Value) bool {
+					normalize(entry.Message())
+					return true
+				})
+			}
+		case field.Message() != nil:
+			normalize(value.Message())
+		}
+		return true
+	})
+}
+
+// sortList orders list by the deterministic encoding of its elements, which
+// is only meant to be canonical, not meaningful.
+func sortList(list protoreflect.List, field protoreflect.FieldDescriptor) {
+	type element struct {
+		key   string
+		value protoreflect.Value
+	}
+	elements := make([]element, list.Len())
+	for i := range elements {
+		value := list.Get(i)
+		key := fmt.Sprint(value.Interface())
+		if field.Message() != nil {
+			encoded, _ := proto.MarshalOptions{Deterministic: true}.Marshal(value.Message().Interface())
+			key = string(encoded)
+		}
+		elements[i] = element{key: key, value: value}
+	}
+	sort.SliceStable(elements, func(i, j int) bool { return elements[i].key < elements[j].key })
+	for i, e := range elements {
+		list.Set(i, e.value)
+	}
+}
@@ -0,0 +1,38 @@
+package testutil
+
+import (
+	"errors"
+	"testing"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AssertCode fails t unless err carries the gRPC status code, directly, as
+// a wrapped status error or as a *kesselerrors.APIError. codes.OK matches
+// a nil error.
+func AssertCode(t testing.TB, err error, code codes.Code) bool {
+	t.Helper()
+	if got := status.Code(err); got != code {
+		t.Errorf("Expected gRPC code %s, got %s (error: %v)", code, got, err)
+		return false
+	}
+	return true
+}
+
+// AssertHTTPStatus fails t unless err is a *kesselerrors.APIError for the
+// HTTP status code, e.g. from the RBAC workspace client.
+func AssertHTTPStatus(t testing.TB, err error, httpStatus int) bool {
+	t.Helper()
+	var apiErr *kesselerrors.APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("Expected an APIError with HTTP status %d, got %v", httpStatus, err)
+		return false
+	}
+	if apiErr.HTTPStatus != httpStatus {
+		t.Errorf("Expected HTTP status %d, got %d (error: %v)", httpStatus, apiErr.HTTPStatus, err)
+		return false
+	}
+	return true
+}
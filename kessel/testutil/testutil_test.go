@@ -0,0 +1,147 @@
+package testutil
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// recorder collects the failures an assertion reports instead of failing
+// the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestEqualProto(t *testing.T) {
+	reordered := CheckBulkRequest(3, "view")
+	reordered.Items[0], reordered.Items[2] = reordered.Items[2], reordered.Items[0]
+
+	withUnknown := CheckRequest(Host("h1"), "view", Principal("alice"))
+	withUnknown.GetObject().ProtoReflect().SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 99, protowire.VarintType), 1))
+
+	tests := []struct {
+		name     string
+		expected *v1beta2.CheckBulkRequest
+		actual   *v1beta2.CheckBulkRequest
+		equal    bool
+	}{
+		{name: "equal", expected: CheckBulkRequest(3, "view"), actual: CheckBulkRequest(3, "view"), equal: true},
+		{name: "reordered items", expected: CheckBulkRequest(3, "view"), actual: reordered, equal: true},
+		{name: "different relation", expected: CheckBulkRequest(3, "view"), actual: CheckBulkRequest(3, "edit"), equal: false},
+		{name: "missing item", expected: CheckBulkRequest(3, "view"), actual: CheckBulkRequest(2, "view"), equal: false},
+		{name: "both nil", equal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualProto(tt.expected, tt.actual); got != tt.equal {
+				t.Errorf("Expected %v, got %v", tt.equal, got)
+			}
+		})
+	}
+
+	t.Run("unknown fields", func(t *testing.T) {
+		if !EqualProto(CheckRequest(Host("h1"), "view", Principal("alice")), withUnknown) {
+			t.Error("Expected unknown fields to be ignored")
+		}
+	})
+}
+
+func TestNormalize_doesNotModifyInput(t *testing.T) {
+	request := CheckBulkRequest(2, "view")
+	request.Items[0], request.Items[1] = request.Items[1], request.Items[0]
+
+	Normalize(request)
+
+	if request.Items[0].GetSubject().GetResource().GetResourceId() != "redhat/user-1" {
+		t.Error("Expected the input to keep its order")
+	}
+}
+
+func TestAssertProtoEqual(t *testing.T) {
+	r := &recorder{}
+	if !AssertProtoEqual(r, CheckResponse(true), CheckResponse(true)) || len(r.failures) != 0 {
+		t.Errorf("Expected equal messages to pass, got %v", r.failures)
+	}
+
+	if AssertProtoEqual(r, CheckResponse(true), CheckResponse(false)) {
+		t.Error("Expected different messages to fail")
+	}
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "ALLOWED_FALSE") {
+		t.Errorf("Expected a failure showing the actual message, got %v", r.failures)
+	}
+}
+
+func TestAssertCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		passes bool
+	}{
+		{name: "status error", err: status.Error(codes.NotFound, "gone"), code: codes.NotFound, passes: true},
+		{name: "wrapped status error", err: fmt.Errorf("checking: %w", status.Error(codes.PermissionDenied, "no")), code: codes.PermissionDenied, passes: true},
+		{name: "api error", err: kesselerrors.FromGRPCStatus(status.Error(codes.Unavailable, "down")), code: codes.Unavailable, passes: true},
+		{name: "nil error", err: nil, code: codes.OK, passes: true},
+		{name: "other code", err: status.Error(codes.Internal, "boom"), code: codes.NotFound, passes: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			if got := AssertCode(r, tt.err, tt.code); got != tt.passes {
+				t.Errorf("Expected %v, got %v (failures: %v)", tt.passes, got, r.failures)
+			}
+		})
+	}
+}
+
+func TestAssertHTTPStatus(t *testing.T) {
+	r := &recorder{}
+	if !AssertHTTPStatus(r, &kesselerrors.APIError{HTTPStatus: http.StatusForbidden, Status: "403 Forbidden"}, http.StatusForbidden) {
+		t.Errorf("Expected a matching status to pass, got %v", r.failures)
+	}
+	if AssertHTTPStatus(r, status.Error(codes.NotFound, "gone"), http.StatusNotFound) {
+		t.Error("Expected a gRPC error to fail")
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "check.golden")
+	request := CheckBulkRequest(2, "view")
+
+	r := &recorder{}
+	if AssertGolden(r, path, request) || len(r.failures) != 1 || !strings.Contains(r.failures[0], UpdateGoldenEnv) {
+		t.Errorf("Expected a missing golden file to fail with a hint, got %v", r.failures)
+	}
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	if !AssertGolden(r, path, request) {
+		t.Fatalf("Expected the golden file to be written, got %v", r.failures)
+	}
+
+	t.Setenv(UpdateGoldenEnv, "")
+	request.Items[0], request.Items[1] = request.Items[1], request.Items[0]
+	r = &recorder{}
+	if !AssertGolden(r, path, request) {
+		t.Errorf("Expected the reordered message to match, got %v", r.failures)
+	}
+	if AssertGolden(r, path, CheckBulkRequest(2, "edit")) {
+		t.Error("Expected a different message to fail")
+	}
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// FormatReference returns the canonical string form of ref, for logs, CLI
+// flags and message headers:
+//
+//	rbac:workspace/ws-123
+//	hbi@<instance id>:host/0b3a...
+//	workspace/ws-123          (no reporter)
+//
+// The instance ID is query-escaped; the resource ID follows the first "/"
+// verbatim, so it may contain any character. ParseReference reverses it. A
+// nil ref formats as "".
+func FormatReference(ref *v1beta2.ResourceReference) string {
+	if ref == nil {
+		return ""
+	}
+	var formatted strings.Builder
+	if reporter := ref.GetReporter(); reporter != nil {
+		formatted.WriteString(reporter.GetType())
+		if reporter.InstanceId != nil {
+			formatted.WriteString("@" + url.QueryEscape(reporter.GetInstanceId()))
+		}
+		formatted.WriteString(":")
+	}
+	formatted.WriteString(ref.GetResourceType() + "/" + ref.GetResourceId())
+	return formatted.String()
+}
+
+// ParseReference parses the form written by FormatReference. The reporter and
+// resource types must be in the registry (see Validate), and a reference
+// without a reporter must name a resource type that some reporter reports.
+func ParseReference(s string) (*v1beta2.ResourceReference, error) {
+	prefix, id, ok := strings.Cut(s, "/")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("resource reference %q must have the form [reporter:]type/id", s)
+	}
+	ref := &v1beta2.ResourceReference{ResourceId: id}
+
+	reporter, resource, hasReporter := strings.Cut(prefix, ":")
+	if !hasReporter {
+		resource = prefix
+		if !slices.ContainsFunc(Reporters(), func(known ReporterType) bool {
+			return Validate(known, ResourceType(resource)) == nil
+		}) {
+			return nil, fmt.Errorf("resource reference %q: unknown resource type %q", s, resource)
+		}
+		ref.ResourceType = resource
+		return ref, nil
+	}
+
+	reporterType, instance, hasInstance := strings.Cut(reporter, "@")
+	if err := Validate(ReporterType(reporterType), ResourceType(resource)); err != nil {
+		return nil, fmt.Errorf("resource reference %q: %w", s, err)
+	}
+	ref.ResourceType = resource
+	ref.Reporter = &v1beta2.ReporterReference{Type: reporterType}
+	if hasInstance {
+		instanceId, err := url.QueryUnescape(instance)
+		if err != nil {
+			return nil, fmt.Errorf("resource reference %q: invalid instance ID: %w", s, err)
+		}
+		ref.Reporter.InstanceId = &instanceId
+	}
+	return ref, nil
+}
@@ -0,0 +1,76 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFormatReference_roundTrip(t *testing.T) {
+	instance := "cluster a/b:c@d"
+	tests := []struct {
+		name     string
+		ref      *v1beta2.ResourceReference
+		expected string
+	}{
+		{name: "reporter", ref: ResourceReference(ResourceWorkspace, "ws-123", ReporterRBAC), expected: "rbac:workspace/ws-123"},
+		{name: "no reporter", ref: &v1beta2.ResourceReference{ResourceType: "host", ResourceId: "h1"}, expected: "host/h1"},
+		{name: "id with separators", ref: ResourceReference(ResourceHost, "a/b:c@d", ReporterHBI), expected: "hbi:host/a/b:c@d"},
+		{
+			name: "instance",
+			ref: &v1beta2.ResourceReference{
+				ResourceType: "k8s_cluster",
+				ResourceId:   "c1",
+				Reporter:     &v1beta2.ReporterReference{Type: "acm", InstanceId: &instance},
+			},
+			expected: "acm@cluster+a%2Fb%3Ac%40d:k8s_cluster/c1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatted := FormatReference(tt.ref)
+			if formatted != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, formatted)
+			}
+			parsed, err := ParseReference(formatted)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !proto.Equal(parsed, tt.ref) {
+				t.Errorf("Expected %v after round trip, got %v", tt.ref, parsed)
+			}
+		})
+	}
+}
+
+func TestParseReference_invalid(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{input: "workspace", expectedError: "must have the form"},
+		{input: "rbac:workspace/", expectedError: "must have the form"},
+		{input: "RBAC:workspace/ws-1", expectedError: `did you mean "rbac"`},
+		{input: "hbi:workspace/ws-1", expectedError: `is not reported by "hbi"`},
+		{input: "cluster/c1", expectedError: `unknown resource type "cluster"`},
+		{input: "acm@%zz:k8s_cluster/c1", expectedError: "invalid instance ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseReference(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestFormatReference_nil(t *testing.T) {
+	if got := FormatReference(nil); got != "" {
+		t.Errorf("Expected empty string, got %q", got)
+	}
+}
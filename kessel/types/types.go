@@ -0,0 +1,119 @@
+// Package types defines the reporter and resource types known to Kessel
+// Inventory, so callers can use constants instead of string literals and catch
+// typos such as "HBI" for "hbi" before a request is sent.
+//
+// Every constant is listed in the registry table below, which is the single
+// definition used by Validate, Reporters and ResourceTypes. Add new types to
+// the table together with their constant.
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	v1beta2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+)
+
+// ReporterType identifies the service reporting a resource, as sent in
+// ReporterReference.Type and ReportResourceRequest.ReporterType.
+type ReporterType string
+
+// ResourceType identifies the kind of resource, as sent in
+// ResourceReference.ResourceType and ReportResourceRequest.Type.
+type ResourceType string
+
+const (
+	ReporterHBI           ReporterType = "hbi"
+	ReporterRBAC          ReporterType = "rbac"
+	ReporterACM           ReporterType = "acm"
+	ReporterNotifications ReporterType = "notifications"
+)
+
+const (
+	ResourceHost        ResourceType = "host"
+	ResourceWorkspace   ResourceType = "workspace"
+	ResourceRole        ResourceType = "role"
+	ResourcePrincipal   ResourceType = "principal"
+	ResourceGroup       ResourceType = "group"
+	ResourceIntegration ResourceType = "integration"
+	ResourceK8sCluster  ResourceType = "k8s_cluster"
+	ResourceK8sPolicy   ResourceType = "k8s_policy"
+)
+
+// registry lists the resource types each reporter may report, in the order
+// returned by Reporters and ResourceTypes.
+var registry = []struct {
+	reporter  ReporterType
+	resources []ResourceType
+}{
+	{ReporterHBI, []ResourceType{ResourceHost}},
+	{ReporterRBAC, []ResourceType{ResourceWorkspace, ResourceRole, ResourcePrincipal, ResourceGroup}},
+	{ReporterACM, []ResourceType{ResourceK8sCluster, ResourceK8sPolicy}},
+	{ReporterNotifications, []ResourceType{ResourceIntegration}},
+}
+
+// Reporters returns the known reporter types.
+func Reporters() []ReporterType {
+	reporters := make([]ReporterType, 0, len(registry))
+	for _, entry := range registry {
+		reporters = append(reporters, entry.reporter)
+	}
+	return reporters
+}
+
+// ResourceTypes returns the resource types reporter may report, or nil for an
+// unknown reporter.
+func ResourceTypes(reporter ReporterType) []ResourceType {
+	for _, entry := range registry {
+		if entry.reporter == reporter {
+			return slices.Clone(entry.resources)
+		}
+	}
+	return nil
+}
+
+// Validate returns an error unless reporter is a known reporter type and
+// resource is one of the resource types it reports. Values that differ from a
+// known type only in case are reported with the expected spelling.
+func Validate(reporter ReporterType, resource ResourceType) error {
+	resources := ResourceTypes(reporter)
+	if resources == nil {
+		for _, known := range Reporters() {
+			if strings.EqualFold(string(known), string(reporter)) {
+				return fmt.Errorf("unknown reporter type %q, did you mean %q", reporter, known)
+			}
+		}
+		return fmt.Errorf("unknown reporter type %q", reporter)
+	}
+
+	if slices.Contains(resources, resource) {
+		return nil
+	}
+	for _, known := range resources {
+		if strings.EqualFold(string(known), string(resource)) {
+			return fmt.Errorf("unknown resource type %q for reporter %q, did you mean %q", resource, reporter, known)
+		}
+	}
+	return fmt.Errorf("resource type %q is not reported by %q", resource, reporter)
+}
+
+// ResourceReference returns a reference to the resource with the given id.
+func ResourceReference(resource ResourceType, id string, reporter ReporterType) *v1beta2.ResourceReference {
+	return &v1beta2.ResourceReference{
+		ResourceType: string(resource),
+		ResourceId:   id,
+		Reporter: &v1beta2.ReporterReference{
+			Type: string(reporter),
+		},
+	}
+}
+
+// RepresentationType returns the object type used by ListObjects requests.
+func RepresentationType(resource ResourceType, reporter ReporterType) *v1beta2.RepresentationType {
+	reporterType := string(reporter)
+	return &v1beta2.RepresentationType{
+		ResourceType: string(resource),
+		ReporterType: &reporterType,
+	}
+}
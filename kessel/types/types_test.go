@@ -0,0 +1,82 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		reporter      ReporterType
+		resource      ResourceType
+		expectedError string
+	}{
+		{name: "host", reporter: ReporterHBI, resource: ResourceHost},
+		{name: "workspace", reporter: ReporterRBAC, resource: ResourceWorkspace},
+		{name: "integration", reporter: ReporterNotifications, resource: ResourceIntegration},
+		{name: "reporter case", reporter: "HBI", resource: ResourceHost, expectedError: `did you mean "hbi"`},
+		{name: "unknown reporter", reporter: "inventory", resource: ResourceHost, expectedError: `unknown reporter type "inventory"`},
+		{name: "resource case", reporter: ReporterHBI, resource: "Host", expectedError: `did you mean "host"`},
+		{name: "wrong reporter", reporter: ReporterHBI, resource: ResourceWorkspace, expectedError: `is not reported by "hbi"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.reporter, tt.resource)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestRegistry_coversConstants(t *testing.T) {
+	resources := []ResourceType{
+		ResourceHost, ResourceWorkspace, ResourceRole, ResourcePrincipal,
+		ResourceGroup, ResourceIntegration, ResourceK8sCluster, ResourceK8sPolicy,
+	}
+	for _, resource := range resources {
+		found := false
+		for _, reporter := range Reporters() {
+			if Validate(reporter, resource) == nil {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Resource type %q has no reporter in the registry", resource)
+		}
+	}
+
+	if got := len(Reporters()); got != 4 {
+		t.Errorf("Expected 4 reporters, got %d", got)
+	}
+	if ResourceTypes("unknown") != nil {
+		t.Error("Expected nil resource types for an unknown reporter")
+	}
+}
+
+func TestResourceTypes_returnsCopy(t *testing.T) {
+	ResourceTypes(ReporterHBI)[0] = "changed"
+	if ResourceTypes(ReporterHBI)[0] != ResourceHost {
+		t.Error("Expected ResourceTypes to return a copy")
+	}
+}
+
+func TestResourceReference(t *testing.T) {
+	ref := ResourceReference(ResourceHost, "host-1", ReporterHBI)
+	if ref.GetResourceType() != "host" || ref.GetResourceId() != "host-1" || ref.GetReporter().GetType() != "hbi" {
+		t.Errorf("Unexpected reference: %v", ref)
+	}
+
+	objectType := RepresentationType(ResourceWorkspace, ReporterRBAC)
+	if objectType.GetResourceType() != "workspace" || objectType.GetReporterType() != "rbac" {
+		t.Errorf("Unexpected representation type: %v", objectType)
+	}
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryClientInterceptor returns a gRPC interceptor that validates each request
+// with Validate and returns the *errors.ValidationError instead of sending an
+// invalid request.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if message, ok := req.(proto.Message); ok {
+			if err := Validate(message); err != nil {
+				return err
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a gRPC interceptor that validates every
+// message sent on a stream before it is written.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &validatingStream{ClientStream: stream}, nil
+	}
+}
+
+type validatingStream struct {
+	grpc.ClientStream
+}
+
+func (s *validatingStream) SendMsg(m any) error {
+	if message, ok := m.(proto.Message); ok {
+		if err := Validate(message); err != nil {
+			return err
+		}
+	}
+	return s.ClientStream.SendMsg(m)
+}
@@ -0,0 +1,88 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type mockClientStream struct {
+	grpc.ClientStream
+	sent int
+}
+
+func (m *mockClientStream) SendMsg(any) error {
+	m.sent++
+	return nil
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	sampleDesc, _ := testDescriptors(t)
+
+	tests := []struct {
+		name          string
+		req           any
+		expectedError bool
+	}{
+		{name: "invalid request", req: dynamicpb.NewMessage(sampleDesc), expectedError: true},
+		{name: "message without rules", req: &structpb.Struct{}},
+		{name: "non-proto request", req: "request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoked := false
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				invoked = true
+				return nil
+			}
+
+			err := UnaryClientInterceptor()(context.Background(), "/test", tt.req, nil, nil, invoker)
+			var validationErr *kesselerrors.ValidationError
+			if tt.expectedError {
+				if !errors.As(err, &validationErr) {
+					t.Errorf("Expected *errors.ValidationError, got %v", err)
+				}
+				if invoked {
+					t.Error("Expected the invalid request not to be sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !invoked {
+				t.Error("Expected the request to be sent")
+			}
+		})
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	sampleDesc, _ := testDescriptors(t)
+
+	mock := &mockClientStream{}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return mock, nil
+	}
+
+	stream, err := StreamClientInterceptor()(context.Background(), &grpc.StreamDesc{}, nil, "/test", streamer)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := stream.SendMsg(dynamicpb.NewMessage(sampleDesc)); err == nil {
+		t.Error("Expected the invalid message to be rejected")
+	}
+	if err := stream.SendMsg(&structpb.Struct{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if mock.sent != 1 {
+		t.Errorf("Expected 1 message to be sent, got %d", mock.sent)
+	}
+}
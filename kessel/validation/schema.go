@@ -0,0 +1,372 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Schema is a JSON Schema for a resource representation. The keywords used by
+// reporter schemas are evaluated: type, properties, required,
+// additionalProperties, items, enum, minLength, maxLength, pattern, minimum,
+// maximum, minItems, maxItems, anyOf, oneOf, and the uuid and date-time
+// formats. Other keywords are ignored; the server remains the authority for
+// those.
+type Schema struct {
+	never    bool
+	types    []string
+	pattern  *regexp.Regexp
+	keywords schemaKeywords
+}
+
+type schemaKeywords struct {
+	Type                 json.RawMessage    `json:"type"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *Schema            `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	Format               string             `json:"format"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	AnyOf                []*Schema          `json:"anyOf"`
+	OneOf                []*Schema          `json:"oneOf"`
+}
+
+// ParseSchema parses a JSON Schema document.
+func ParseSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return &schema, nil
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var boolean bool
+	if err := json.Unmarshal(data, &boolean); err == nil {
+		s.never = !boolean
+		return nil
+	}
+	if err := json.Unmarshal(data, &s.keywords); err != nil {
+		return err
+	}
+	if len(s.keywords.Type) > 0 {
+		var single string
+		if err := json.Unmarshal(s.keywords.Type, &single); err == nil {
+			s.types = []string{single}
+		} else if err := json.Unmarshal(s.keywords.Type, &s.types); err != nil {
+			return fmt.Errorf("type must be a string or an array of strings")
+		}
+	}
+	if s.keywords.Pattern != "" {
+		pattern, err := regexp.Compile(s.keywords.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.keywords.Pattern, err)
+		}
+		s.pattern = pattern
+	}
+	return nil
+}
+
+// Validate checks value, a representation decoded as by structpb.Struct.AsMap,
+// returning a *errors.ValidationError listing every violation under path, or
+// nil.
+func (s *Schema) Validate(path string, value any) error {
+	var violations []kesselerrors.Violation
+	s.validate(&violations, path, value)
+	if len(violations) == 0 {
+		return nil
+	}
+	return &kesselerrors.ValidationError{Violations: violations}
+}
+
+func (s *Schema) validate(violations *[]kesselerrors.Violation, path string, value any) {
+	if s.never {
+		addViolation(violations, path, "schema.false", "value is not allowed")
+		return
+	}
+	k := s.keywords
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(t string) bool { return hasType(value, t) }) {
+		addViolation(violations, path, "schema.type", fmt.Sprintf("value must be of type %s", strings.Join(s.types, " or ")))
+		return
+	}
+	if len(k.Enum) > 0 && !slices.ContainsFunc(k.Enum, func(e any) bool { return reflect.DeepEqual(e, value) }) {
+		addViolation(violations, path, "schema.enum", fmt.Sprintf("value must be one of %v", k.Enum))
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if k.MinLength != nil && length < *k.MinLength {
+			addViolation(violations, path, "schema.min_length", fmt.Sprintf("value length must be at least %d characters", *k.MinLength))
+		}
+		if k.MaxLength != nil && length > *k.MaxLength {
+			addViolation(violations, path, "schema.max_length", fmt.Sprintf("value length must be at most %d characters", *k.MaxLength))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			addViolation(violations, path, "schema.pattern", fmt.Sprintf("value does not match regex pattern `%s`", k.Pattern))
+		}
+		if !matchesFormat(k.Format, v) {
+			addViolation(violations, path, "schema.format", fmt.Sprintf("value must be a valid %s", k.Format))
+		}
+	case float64:
+		if k.Minimum != nil && v < *k.Minimum {
+			addViolation(violations, path, "schema.minimum", fmt.Sprintf("value must be greater than or equal to %v", *k.Minimum))
+		}
+		if k.Maximum != nil && v > *k.Maximum {
+			addViolation(violations, path, "schema.maximum", fmt.Sprintf("value must be less than or equal to %v", *k.Maximum))
+		}
+	case []any:
+		if k.MinItems != nil && len(v) < *k.MinItems {
+			addViolation(violations, path, "schema.min_items", fmt.Sprintf("value must contain at least %d item(s)", *k.MinItems))
+		}
+		if k.MaxItems != nil && len(v) > *k.MaxItems {
+			addViolation(violations, path, "schema.max_items", fmt.Sprintf("value must contain no more than %d item(s)", *k.MaxItems))
+		}
+		if k.Items != nil {
+			for i, item := range v {
+				k.Items.validate(violations, fmt.Sprintf("%s[%d]", path, i), item)
+			}
+		}
+	case map[string]any:
+		for _, name := range k.Required {
+			if _, ok := v[name]; !ok {
+				addViolation(violations, joinPath(path, name), "schema.required", "value is required")
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			if property, ok := k.Properties[name]; ok {
+				property.validate(violations, joinPath(path, name), v[name])
+			} else if k.AdditionalProperties != nil {
+				k.AdditionalProperties.validate(violations, joinPath(path, name), v[name])
+			}
+		}
+	}
+
+	if len(k.AnyOf) > 0 && countMatches(k.AnyOf, value) == 0 {
+		addViolation(violations, path, "schema.any_of", "value must match at least one schema")
+	}
+	if len(k.OneOf) > 0 && countMatches(k.OneOf, value) != 1 {
+		addViolation(violations, path, "schema.one_of", "value must match exactly one schema")
+	}
+}
+
+func countMatches(schemas []*Schema, value any) int {
+	matches := 0
+	for _, schema := range schemas {
+		var violations []kesselerrors.Violation
+		schema.validate(&violations, "", value)
+		if len(violations) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func hasType(value any, name string) bool {
+	switch v := value.(type) {
+	case nil:
+		return name == "null"
+	case bool:
+		return name == "boolean"
+	case string:
+		return name == "string"
+	case float64:
+		return name == "number" || (name == "integer" && v == math.Trunc(v))
+	case []any:
+		return name == "array"
+	case map[string]any:
+		return name == "object"
+	}
+	return false
+}
+
+func matchesFormat(format string, value string) bool {
+	switch format {
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	}
+	return true
+}
+
+// SchemaRegistry holds the schemas reporters publish for their resource
+// representations: a common schema per resource type, and a reporter schema
+// per resource and reporter type. Validate checks ReportResource requests
+// against them locally, so a representation the server would reject fails
+// with field-level violations before it is sent. It is safe for concurrent
+// use.
+type SchemaRegistry struct {
+	mu       sync.RWMutex
+	common   map[string]*Schema
+	reporter map[reporterSchemaKey]*Schema
+}
+
+type reporterSchemaKey struct {
+	resourceType string
+	reporterType string
+}
+
+// NewSchemaRegistry returns an empty SchemaRegistry.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		common:   map[string]*Schema{},
+		reporter: map[reporterSchemaKey]*Schema{},
+	}
+}
+
+// RegisterCommonSchema sets the JSON Schema of the common representation of
+// resourceType, replacing any earlier one.
+func (r *SchemaRegistry) RegisterCommonSchema(resourceType string, schema []byte) error {
+	parsed, err := ParseSchema(schema)
+	if err != nil {
+		return fmt.Errorf("common schema for %s: %w", resourceType, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.common[resourceType] = parsed
+	return nil
+}
+
+// RegisterReporterSchema sets the JSON Schema of the representation
+// reporterType reports for resourceType, replacing any earlier one.
+func (r *SchemaRegistry) RegisterReporterSchema(resourceType string, reporterType string, schema []byte) error {
+	parsed, err := ParseSchema(schema)
+	if err != nil {
+		return fmt.Errorf("reporter schema for %s/%s: %w", resourceType, reporterType, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reporter[reporterSchemaKey{resourceType: resourceType, reporterType: reporterType}] = parsed
+	return nil
+}
+
+// ValidateRepresentations checks the common and reporter representations of
+// a resource against the registered schemas. A representation without a
+// registered schema is not checked; a missing representation is checked as
+// an empty object. Violations are reported under "representations.common"
+// and "representations.reporter".
+func (r *SchemaRegistry) ValidateRepresentations(resourceType string, reporterType string, common *structpb.Struct, reporter *structpb.Struct) error {
+	r.mu.RLock()
+	commonSchema := r.common[resourceType]
+	reporterSchema := r.reporter[reporterSchemaKey{resourceType: resourceType, reporterType: reporterType}]
+	r.mu.RUnlock()
+
+	var violations []kesselerrors.Violation
+	if commonSchema != nil {
+		commonSchema.validate(&violations, "representations.common", representation(common))
+	}
+	if reporterSchema != nil {
+		reporterSchema.validate(&violations, "representations.reporter", representation(reporter))
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &kesselerrors.ValidationError{Violations: violations}
+}
+
+func representation(value *structpb.Struct) map[string]any {
+	if value == nil {
+		return map[string]any{}
+	}
+	return value.AsMap()
+}
+
+// Validate checks message if it is a ReportResourceRequest of any Inventory
+// API version, with ValidateRepresentations. Other messages pass.
+func (r *SchemaRegistry) Validate(message proto.Message) error {
+	if message == nil {
+		return nil
+	}
+	request := message.ProtoReflect()
+	if request.Descriptor().Name() != "ReportResourceRequest" {
+		return nil
+	}
+	representations := messageField(request, "representations")
+	if representations == nil {
+		return r.ValidateRepresentations(stringField(request, "type"), stringField(request, "reporter_type"), nil, nil)
+	}
+	return r.ValidateRepresentations(
+		stringField(request, "type"),
+		stringField(request, "reporter_type"),
+		structField(representations, "common"),
+		structField(representations, "reporter"),
+	)
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that checks every request
+// with Validate and returns the *errors.ValidationError instead of sending an
+// invalid representation.
+func (r *SchemaRegistry) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if message, ok := req.(proto.Message); ok {
+			if err := r.Validate(message); err != nil {
+				return err
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func stringField(message protoreflect.Message, name protoreflect.Name) string {
+	field := message.Descriptor().Fields().ByName(name)
+	if field == nil || field.Kind() != protoreflect.StringKind {
+		return ""
+	}
+	return message.Get(field).String()
+}
+
+func messageField(message protoreflect.Message, name protoreflect.Name) protoreflect.Message {
+	field := message.Descriptor().Fields().ByName(name)
+	if field == nil || field.Message() == nil || field.IsList() || field.IsMap() || !message.Has(field) {
+		return nil
+	}
+	return message.Get(field).Message()
+}
+
+// structField returns the google.protobuf.Struct field name of message, or
+// nil when it is unset. Dynamic messages are converted through the wire
+// format.
+func structField(message protoreflect.Message, name protoreflect.Name) *structpb.Struct {
+	value := messageField(message, name)
+	if value == nil || value.Descriptor().FullName() != "google.protobuf.Struct" {
+		return nil
+	}
+	if s, ok := value.Interface().(*structpb.Struct); ok {
+		return s
+	}
+	data, err := proto.Marshal(value.Interface())
+	if err != nil {
+		return nil
+	}
+	s := &structpb.Struct{}
+	if err := proto.Unmarshal(data, s); err != nil {
+		return nil
+	}
+	return s
+}
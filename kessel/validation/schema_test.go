@@ -0,0 +1,284 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const hostReporterSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"properties": {
+		"satellite_id": {"oneOf": [{"type": "null"}, {"type": "string", "format": "uuid"}]},
+		"ansible_host": {"type": "string", "maxLength": 8},
+		"os": {"enum": ["rhel", "centos"]},
+		"cores": {"type": "integer", "minimum": 1},
+		"tags": {"type": "array", "maxItems": 2, "items": {"type": "string", "pattern": "^[a-z]+$"}},
+		"updated": {"type": "string", "format": "date-time"}
+	},
+	"required": ["ansible_host"],
+	"additionalProperties": false
+}`
+
+func violationFields(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var validationErr *kesselerrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	fields := map[string]string{}
+	for _, violation := range validationErr.Violations {
+		fields[violation.Field] = violation.Rule
+	}
+	return fields
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := ParseSchema([]byte(hostReporterSchema))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		value    map[string]any
+		expected map[string]string
+	}{
+		{
+			name: "valid",
+			value: map[string]any{
+				"satellite_id": "6f0c6bd2-1a0a-4d8b-9d4a-0c1e5e0f8a11",
+				"ansible_host": "host-1",
+				"os":           "rhel",
+				"cores":        float64(4),
+				"tags":         []any{"a", "b"},
+				"updated":      "2026-10-15T10:00:00Z",
+			},
+		},
+		{name: "null in oneOf", value: map[string]any{"ansible_host": "host-1", "satellite_id": nil}},
+		{name: "missing required", value: map[string]any{}, expected: map[string]string{"r.ansible_host": "schema.required"}},
+		{
+			name: "field violations",
+			value: map[string]any{
+				"satellite_id": "not-a-uuid",
+				"ansible_host": "much-too-long",
+				"os":           "windows",
+				"cores":        float64(1.5),
+				"tags":         []any{"a", "B", "c"},
+				"updated":      "yesterday",
+				"extra":        true,
+			},
+			expected: map[string]string{
+				"r.satellite_id": "schema.one_of",
+				"r.ansible_host": "schema.max_length",
+				"r.os":           "schema.enum",
+				"r.cores":        "schema.type",
+				"r.tags":         "schema.max_items",
+				"r.tags[1]":      "schema.pattern",
+				"r.updated":      "schema.format",
+				"r.extra":        "schema.false",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate("r", tt.value)
+			if tt.expected == nil {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			fields := violationFields(t, err)
+			if len(fields) != len(tt.expected) {
+				t.Errorf("Expected %d violations, got %v", len(tt.expected), fields)
+			}
+			for field, rule := range tt.expected {
+				if fields[field] != rule {
+					t.Errorf("Expected %s to fail %s, got %q", field, rule, fields[field])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSchema_invalid(t *testing.T) {
+	for _, schema := range []string{`{"type": 1}`, `{"pattern": "("}`, `not json`} {
+		if _, err := ParseSchema([]byte(schema)); err == nil {
+			t.Errorf("Expected %s to be rejected", schema)
+		}
+	}
+}
+
+// reportResourceDescriptor builds a ReportResourceRequest shaped like the
+// Inventory one, since v1beta2 cannot be imported here.
+func reportResourceDescriptor(t *testing.T) *dynamicpb.Message {
+	t.Helper()
+	field := func(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     kind.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("schema_test.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/struct.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("ResourceRepresentations"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("common", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Struct"),
+					field("reporter", 2, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Struct"),
+				},
+			},
+			{
+				Name: proto.String("ReportResourceRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("type", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("reporter_type", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("representations", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".test.ResourceRepresentations"),
+				},
+			},
+		},
+	}
+	descriptor, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("Failed to build descriptor: %v", err)
+	}
+	return dynamicpb.NewMessage(descriptor.Messages().ByName("ReportResourceRequest"))
+}
+
+func testRegistry(t *testing.T) *SchemaRegistry {
+	t.Helper()
+	registry := NewSchemaRegistry()
+	if err := registry.RegisterCommonSchema("host", []byte(`{"type": "object", "required": ["workspace_id"]}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := registry.RegisterReporterSchema("host", "hbi", []byte(hostReporterSchema)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return registry
+}
+
+func TestSchemaRegistry_Validate(t *testing.T) {
+	registry := testRegistry(t)
+
+	tests := []struct {
+		name     string
+		request  string
+		expected map[string]string
+	}{
+		{
+			name:    "valid",
+			request: `{"type": "host", "reporter_type": "hbi", "representations": {"common": {"workspace_id": "ws-1"}, "reporter": {"ansible_host": "h1"}}}`,
+		},
+		{
+			name:    "invalid representations",
+			request: `{"type": "host", "reporter_type": "hbi", "representations": {"common": {}, "reporter": {"ansible_host": "h1", "cores": 0}}}`,
+			expected: map[string]string{
+				"representations.common.workspace_id": "schema.required",
+				"representations.reporter.cores":      "schema.minimum",
+			},
+		},
+		{
+			name:    "missing representations",
+			request: `{"type": "host", "reporter_type": "hbi"}`,
+			expected: map[string]string{
+				"representations.common.workspace_id":   "schema.required",
+				"representations.reporter.ansible_host": "schema.required",
+			},
+		},
+		{
+			name:     "unregistered reporter",
+			request:  `{"type": "host", "reporter_type": "acm", "representations": {"common": {}, "reporter": {"anything": 1}}}`,
+			expected: map[string]string{"representations.common.workspace_id": "schema.required"},
+		},
+		{name: "unregistered type", request: `{"type": "cluster", "reporter_type": "acm"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := reportResourceDescriptor(t)
+			if err := protojson.Unmarshal([]byte(tt.request), request); err != nil {
+				t.Fatalf("Failed to build request: %v", err)
+			}
+			err := registry.Validate(request)
+			if tt.expected == nil {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			fields := violationFields(t, err)
+			if len(fields) != len(tt.expected) {
+				t.Errorf("Expected %d violations, got %v", len(tt.expected), fields)
+			}
+			for field, rule := range tt.expected {
+				if fields[field] != rule {
+					t.Errorf("Expected %s to fail %s, got %q", field, rule, fields[field])
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaRegistry_ValidateRepresentations(t *testing.T) {
+	registry := testRegistry(t)
+	common, _ := structpb.NewStruct(map[string]any{"workspace_id": "ws-1"})
+	reporter, _ := structpb.NewStruct(map[string]any{"ansible_host": "h1"})
+
+	if err := registry.ValidateRepresentations("host", "hbi", common, reporter); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := registry.RegisterReporterSchema("host", "hbi", []byte(`{"pattern": "["}`)); err == nil {
+		t.Error("Expected an invalid schema to be rejected")
+	}
+}
+
+func TestSchemaRegistry_UnaryClientInterceptor(t *testing.T) {
+	registry := testRegistry(t)
+	interceptor := registry.UnaryClientInterceptor()
+	invoked := false
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		invoked = true
+		return nil
+	}
+
+	request := reportResourceDescriptor(t)
+	if err := protojson.Unmarshal([]byte(`{"type": "host", "reporter_type": "hbi"}`), request); err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	err := interceptor(context.Background(), "/test/ReportResource", request, nil, nil, invoker)
+	if len(violationFields(t, err)) != 2 {
+		t.Errorf("Expected 2 violations, got %v", err)
+	}
+	if invoked {
+		t.Error("Expected the invalid request not to be sent")
+	}
+
+	other, _ := structpb.NewStruct(map[string]any{})
+	if err := interceptor(context.Background(), "/test/Other", other, nil, nil, invoker); err != nil || !invoked {
+		t.Errorf("Expected other messages to be sent, got %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+// Package validation checks requests against the buf.validate rules declared
+// in their protobuf definitions before they are sent, so malformed requests
+// fail without a network round trip.
+//
+// It evaluates the standard rules used by the Kessel APIs (required fields and
+// oneofs, string length, pattern, affixes and sets, bool and enum constants,
+// defined-only enums, integer bounds, and repeated item counts and item
+// rules). CEL expressions and well-known string formats are not evaluated
+// here; the server remains the authority for those.
+package validation
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var patterns sync.Map // pattern string -> *regexp.Regexp
+
+// Validate checks message against its buf.validate rules, returning a
+// *errors.ValidationError listing every violation, or nil.
+func Validate(message proto.Message) error {
+	if message == nil {
+		return nil
+	}
+	var violations []kesselerrors.Violation
+	validateMessage(&violations, "", message.ProtoReflect())
+	if len(violations) == 0 {
+		return nil
+	}
+	return &kesselerrors.ValidationError{Violations: violations}
+}
+
+func validateMessage(violations *[]kesselerrors.Violation, prefix string, message protoreflect.Message) {
+	descriptor := message.Descriptor()
+
+	oneofs := descriptor.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		oneof := oneofs.Get(i)
+		if oneof.IsSynthetic() {
+			continue
+		}
+		rules, _ := proto.GetExtension(oneof.Options(), validate.E_Oneof).(*validate.OneofRules)
+		if rules.GetRequired() && message.WhichOneof(oneof) == nil {
+			addViolation(violations, joinPath(prefix, string(oneof.Name())), "required", "exactly one field is required in oneof")
+		}
+	}
+
+	fields := descriptor.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		rules, _ := proto.GetExtension(field.Options(), validate.E_Field).(*validate.FieldRules)
+		validateField(violations, joinPath(prefix, string(field.Name())), message, field, rules)
+	}
+}
+
+func validateField(violations *[]kesselerrors.Violation, path string, message protoreflect.Message, field protoreflect.FieldDescriptor, rules *validate.FieldRules) {
+	if rules.GetIgnore() == validate.Ignore_IGNORE_ALWAYS {
+		return
+	}
+
+	populated := message.Has(field)
+	if rules.GetRequired() && !populated {
+		addViolation(violations, path, "required", "value is required")
+		return
+	}
+	if !populated && (field.HasPresence() || rules.GetIgnore() == validate.Ignore_IGNORE_IF_ZERO_VALUE) {
+		return
+	}
+
+	switch {
+	case field.IsList():
+		list := message.Get(field).List()
+		validateRepeated(violations, path, list.Len(), rules.GetRepeated())
+		for i := 0; i < list.Len(); i++ {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+			if items := rules.GetRepeated().GetItems(); items != nil {
+				validateValue(violations, elementPath, field, list.Get(i), items)
+			}
+			if field.Message() != nil {
+				validateMessage(violations, elementPath, list.Get(i).Message())
+			}
+		}
+	case field.IsMap():
+		if field.MapValue().Message() == nil {
+			return
+		}
+		message.Get(field).Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+			validateMessage(violations, fmt.Sprintf("%s[%v]", path, key.Interface()), value.Message())
+			return true
+		})
+	default:
+		value := message.Get(field)
+		validateValue(violations, path, field, value, rules)
+		if field.Message() != nil && populated {
+			validateMessage(violations, path, value.Message())
+		}
+	}
+}
+
+func validateValue(violations *[]kesselerrors.Violation, path string, field protoreflect.FieldDescriptor, value protoreflect.Value, rules *validate.FieldRules) {
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		if rules.HasString() {
+			validateString(violations, path, value.String(), rules.GetString())
+		}
+	case protoreflect.BoolKind:
+		if rules.GetBool().HasConst() && value.Bool() != rules.GetBool().GetConst() {
+			addViolation(violations, path, "bool.const", fmt.Sprintf("value must equal %t", rules.GetBool().GetConst()))
+		}
+	case protoreflect.EnumKind:
+		if rules.HasEnum() {
+			validateEnum(violations, path, field.Enum(), value.Enum(), rules.GetEnum())
+		}
+	case protoreflect.Int32Kind:
+		if rules.HasInt32() {
+			validateNumber(violations, path, "int32", int32(value.Int()), rules.GetInt32())
+		}
+	case protoreflect.Int64Kind:
+		if rules.HasInt64() {
+			validateNumber(violations, path, "int64", value.Int(), rules.GetInt64())
+		}
+	case protoreflect.Uint32Kind:
+		if rules.HasUint32() {
+			validateNumber(violations, path, "uint32", uint32(value.Uint()), rules.GetUint32())
+		}
+	case protoreflect.Uint64Kind:
+		if rules.HasUint64() {
+			validateNumber(violations, path, "uint64", value.Uint(), rules.GetUint64())
+		}
+	}
+}
+
+func validateString(violations *[]kesselerrors.Violation, path string, value string, rules *validate.StringRules) {
+	length := uint64(utf8.RuneCountInString(value))
+	if rules.HasConst() && value != rules.GetConst() {
+		addViolation(violations, path, "string.const", fmt.Sprintf("value must equal `%s`", rules.GetConst()))
+	}
+	if rules.HasLen() && length != rules.GetLen() {
+		addViolation(violations, path, "string.len", fmt.Sprintf("value length must be %d characters", rules.GetLen()))
+	}
+	if rules.HasMinLen() && length < rules.GetMinLen() {
+		addViolation(violations, path, "string.min_len", fmt.Sprintf("value length must be at least %d characters", rules.GetMinLen()))
+	}
+	if rules.HasMaxLen() && length > rules.GetMaxLen() {
+		addViolation(violations, path, "string.max_len", fmt.Sprintf("value length must be at most %d characters", rules.GetMaxLen()))
+	}
+	if rules.HasPattern() {
+		if pattern, err := compile(rules.GetPattern()); err == nil && !pattern.MatchString(value) {
+			addViolation(violations, path, "string.pattern", fmt.Sprintf("value does not match regex pattern `%s`", rules.GetPattern()))
+		}
+	}
+	if rules.HasPrefix() && !strings.HasPrefix(value, rules.GetPrefix()) {
+		addViolation(violations, path, "string.prefix", fmt.Sprintf("value does not have prefix `%s`", rules.GetPrefix()))
+	}
+	if rules.HasSuffix() && !strings.HasSuffix(value, rules.GetSuffix()) {
+		addViolation(violations, path, "string.suffix", fmt.Sprintf("value does not have suffix `%s`", rules.GetSuffix()))
+	}
+	if rules.HasContains() && !strings.Contains(value, rules.GetContains()) {
+		addViolation(violations, path, "string.contains", fmt.Sprintf("value does not contain substring `%s`", rules.GetContains()))
+	}
+	if len(rules.GetIn()) > 0 && !slices.Contains(rules.GetIn(), value) {
+		addViolation(violations, path, "string.in", fmt.Sprintf("value must be in list %v", rules.GetIn()))
+	}
+	if slices.Contains(rules.GetNotIn(), value) {
+		addViolation(violations, path, "string.not_in", fmt.Sprintf("value must not be in list %v", rules.GetNotIn()))
+	}
+}
+
+func validateEnum(violations *[]kesselerrors.Violation, path string, enum protoreflect.EnumDescriptor, value protoreflect.EnumNumber, rules *validate.EnumRules) {
+	if rules.HasConst() && int32(value) != rules.GetConst() {
+		addViolation(violations, path, "enum.const", fmt.Sprintf("value must equal %d", rules.GetConst()))
+	}
+	if rules.GetDefinedOnly() && enum.Values().ByNumber(value) == nil {
+		addViolation(violations, path, "enum.defined_only", "value must be one of the defined enum values")
+	}
+	if len(rules.GetIn()) > 0 && !slices.Contains(rules.GetIn(), int32(value)) {
+		addViolation(violations, path, "enum.in", fmt.Sprintf("value must be in list %v", rules.GetIn()))
+	}
+	if slices.Contains(rules.GetNotIn(), int32(value)) {
+		addViolation(violations, path, "enum.not_in", fmt.Sprintf("value must not be in list %v", rules.GetNotIn()))
+	}
+}
+
+type numberRules[T cmp.Ordered] interface {
+	HasConst() bool
+	GetConst() T
+	HasLt() bool
+	GetLt() T
+	HasLte() bool
+	GetLte() T
+	HasGt() bool
+	GetGt() T
+	HasGte() bool
+	GetGte() T
+}
+
+func validateNumber[T cmp.Ordered](violations *[]kesselerrors.Violation, path string, kind string, value T, rules numberRules[T]) {
+	if rules.HasConst() && value != rules.GetConst() {
+		addViolation(violations, path, kind+".const", fmt.Sprintf("value must equal %v", rules.GetConst()))
+	}
+	if rules.HasLt() && value >= rules.GetLt() {
+		addViolation(violations, path, kind+".lt", fmt.Sprintf("value must be less than %v", rules.GetLt()))
+	}
+	if rules.HasLte() && value > rules.GetLte() {
+		addViolation(violations, path, kind+".lte", fmt.Sprintf("value must be less than or equal to %v", rules.GetLte()))
+	}
+	if rules.HasGt() && value <= rules.GetGt() {
+		addViolation(violations, path, kind+".gt", fmt.Sprintf("value must be greater than %v", rules.GetGt()))
+	}
+	if rules.HasGte() && value < rules.GetGte() {
+		addViolation(violations, path, kind+".gte", fmt.Sprintf("value must be greater than or equal to %v", rules.GetGte()))
+	}
+}
+
+func validateRepeated(violations *[]kesselerrors.Violation, path string, length int, rules *validate.RepeatedRules) {
+	if rules.HasMinItems() && uint64(length) < rules.GetMinItems() {
+		addViolation(violations, path, "repeated.min_items", fmt.Sprintf("value must contain at least %d item(s)", rules.GetMinItems()))
+	}
+	if rules.HasMaxItems() && uint64(length) > rules.GetMaxItems() {
+		addViolation(violations, path, "repeated.max_items", fmt.Sprintf("value must contain no more than %d item(s)", rules.GetMaxItems()))
+	}
+}
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if compiled, ok := patterns.Load(pattern); ok {
+		return compiled.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, compiled)
+	return compiled, nil
+}
+
+func addViolation(violations *[]kesselerrors.Violation, path string, rule string, message string) {
+	*violations = append(*violations, kesselerrors.Violation{Field: path, Rule: rule, Message: message})
+}
+
+func joinPath(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
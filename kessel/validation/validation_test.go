@@ -0,0 +1,246 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	kesselerrors "github.com/project-kessel/kessel-sdk-go/kessel/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// testDescriptors builds the messages under test at runtime, since the real
+// v1beta2 requests cannot be imported here without an import cycle.
+func testDescriptors(t *testing.T) (protoreflect.MessageDescriptor, protoreflect.MessageDescriptor) {
+	t.Helper()
+
+	withRules := func(rules *validate.FieldRules) *descriptorpb.FieldOptions {
+		options := &descriptorpb.FieldOptions{}
+		proto.SetExtension(options, validate.E_Field, rules)
+		return options
+	}
+	field := func(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, rules *validate.FieldRules) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     kind.Enum(),
+			Options:  withRules(rules),
+		}
+	}
+	oneofOptions := &descriptorpb.OneofOptions{}
+	proto.SetExtension(oneofOptions, validate.E_Oneof, validate.OneofRules_builder{Required: proto.Bool(true)}.Build())
+
+	item := field("items", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, validate.FieldRules_builder{
+		Repeated: validate.RepeatedRules_builder{MinItems: proto.Uint64(1), MaxItems: proto.Uint64(2)}.Build(),
+	}.Build())
+	item.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	item.TypeName = proto.String(".test.Item")
+	ref := field("ref", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, validate.FieldRules_builder{Required: proto.Bool(true)}.Build())
+	ref.TypeName = proto.String(".test.Item")
+	first := field("first", 7, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil)
+	first.OneofIndex = proto.Int32(0)
+	second := field("second", 8, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil)
+	second.OneofIndex = proto.Int32(0)
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("validation_test.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Item"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("resource_id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, validate.FieldRules_builder{
+						String: validate.StringRules_builder{MinLen: proto.Uint64(1)}.Build(),
+					}.Build()),
+				},
+			},
+			{
+				Name: proto.String("Sample"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, validate.FieldRules_builder{
+						String: validate.StringRules_builder{MinLen: proto.Uint64(1), Pattern: proto.String("^[a-z]+$")}.Build(),
+					}.Build()),
+					field("limit", 2, descriptorpb.FieldDescriptorProto_TYPE_UINT32, validate.FieldRules_builder{
+						Ignore: validate.Ignore_IGNORE_IF_ZERO_VALUE.Enum(),
+						Uint32: validate.UInt32Rules_builder{Gt: proto.Uint32(0), Lte: proto.Uint32(100)}.Build(),
+					}.Build()),
+					item,
+					ref,
+					field("enabled", 5, descriptorpb.FieldDescriptorProto_TYPE_BOOL, validate.FieldRules_builder{
+						Bool: validate.BoolRules_builder{Const: proto.Bool(true)}.Build(),
+					}.Build()),
+					field("skipped", 6, descriptorpb.FieldDescriptorProto_TYPE_STRING, validate.FieldRules_builder{
+						Ignore: validate.Ignore_IGNORE_ALWAYS.Enum(),
+						String: validate.StringRules_builder{MinLen: proto.Uint64(1)}.Build(),
+					}.Build()),
+					first,
+					second,
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{
+					{Name: proto.String("choice"), Options: oneofOptions},
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("Failed to build test descriptors: %v", err)
+	}
+	return fd.Messages().ByName("Sample"), fd.Messages().ByName("Item")
+}
+
+func TestValidate(t *testing.T) {
+	sampleDesc, itemDesc := testDescriptors(t)
+
+	newItem := func(resourceId string) protoreflect.Value {
+		item := dynamicpb.NewMessage(itemDesc)
+		item.Set(itemDesc.Fields().ByName("resource_id"), protoreflect.ValueOfString(resourceId))
+		return protoreflect.ValueOfMessage(item)
+	}
+	newSample := func(modify func(m *dynamicpb.Message)) *dynamicpb.Message {
+		m := dynamicpb.NewMessage(sampleDesc)
+		fields := sampleDesc.Fields()
+		m.Set(fields.ByName("name"), protoreflect.ValueOfString("host"))
+		m.Mutable(fields.ByName("items")).List().Append(newItem("1"))
+		m.Set(fields.ByName("ref"), newItem("2"))
+		m.Set(fields.ByName("enabled"), protoreflect.ValueOfBool(true))
+		m.Set(fields.ByName("first"), protoreflect.ValueOfString("a"))
+		if modify != nil {
+			modify(m)
+		}
+		return m
+	}
+	fields := sampleDesc.Fields()
+
+	tests := []struct {
+		name               string
+		message            proto.Message
+		expectedViolations []kesselerrors.Violation
+	}{
+		{
+			name:    "valid message",
+			message: newSample(nil),
+		},
+		{
+			name:    "nil message",
+			message: nil,
+		},
+		{
+			name: "string too short and not matching pattern",
+			message: newSample(func(m *dynamicpb.Message) {
+				m.Clear(fields.ByName("name"))
+			}),
+			expectedViolations: []kesselerrors.Violation{
+				{Field: "name", Rule: "string.min_len"},
+				{Field: "name", Rule: "string.pattern"},
+			},
+		},
+		{
+			name: "pattern mismatch",
+			message: newSample(func(m *dynamicpb.Message) {
+				m.Set(fields.ByName("name"), protoreflect.ValueOfString("Host"))
+			}),
+			expectedViolations: []kesselerrors.Violation{{Field: "name", Rule: "string.pattern"}},
+		},
+		{
+			name: "zero value ignored",
+			message: newSample(func(m *dynamicpb.Message) {
+				m.Set(fields.ByName("limit"), protoreflect.ValueOfUint32(0))
+			}),
+		},
+		{
+			name: "integer above bound",
+			message: newSample(func(m *dynamicpb.Message) {
+				m.Set(fields.ByName("limit"), protoreflect.ValueOfUint32(101))
+			}),
+			expectedViolations: []kesselerrors.Violation{{Field: "limit", Rule: "uint32.lte"}},
+		},
+		{
+			name: "too few items",
+			message: newSample(func(m *dynamicpb.Message) {
+				m.Clear(fields.ByName("items"))
+			}),
+			expectedViolations: []kesselerrors.Violation{{Field: "items", Rule: "repeated.min_items"}},
+		},
+		{
+			name: "too many items",
+			message: newSample(func(m *dynamicpb.Message) {
+				list := m.Mutable(fields.ByName("items")).List()
+				list.Append(newItem("2"))
+				list.Append(newItem("3"))
+			}),
+			expectedViolations: []kesselerrors.Violation{{Field: "items", Rule: "repeated.max_items"}},
+		},
+		{
+			name: "nested item violation",
+			message: newSample(func(m *dynamicpb.Message) {
+				m.Mutable(fields.ByName("items")).List().Append(newItem(""))
+			}),
+			expectedViolations: []kesselerrors.Violation{{Field: "items[1].resource_id", Rule: "string.min_len"}},
+		},
+		{
+			name: "missing required message",
+			message: newSample(func(m *dynamicpb.Message) {
+				m.Clear(fields.ByName("ref"))
+			}),
+			expectedViolations: []kesselerrors.Violation{{Field: "ref", Rule: "required"}},
+		},
+		{
+			name: "bool const",
+			message: newSample(func(m *dynamicpb.Message) {
+				m.Clear(fields.ByName("enabled"))
+			}),
+			expectedViolations: []kesselerrors.Violation{{Field: "enabled", Rule: "bool.const"}},
+		},
+		{
+			name: "required oneof unset",
+			message: newSample(func(m *dynamicpb.Message) {
+				m.Clear(fields.ByName("first"))
+			}),
+			expectedViolations: []kesselerrors.Violation{{Field: "choice", Rule: "required"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.message)
+			if len(tt.expectedViolations) == 0 {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *kesselerrors.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected *errors.ValidationError, got %v", err)
+			}
+			if len(validationErr.Violations) != len(tt.expectedViolations) {
+				t.Fatalf("Expected %d violations, got %v", len(tt.expectedViolations), validationErr.Violations)
+			}
+			for i, expected := range tt.expectedViolations {
+				actual := validationErr.Violations[i]
+				if actual.Field != expected.Field || actual.Rule != expected.Rule {
+					t.Errorf("Expected violation %s (%s), got %s (%s)", expected.Field, expected.Rule, actual.Field, actual.Rule)
+				}
+				if actual.Message == "" {
+					t.Errorf("Expected a message for violation %s", actual.Field)
+				}
+			}
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected code InvalidArgument, got %v", status.Code(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package version reports the version of the Kessel SDK linked into the
+// binary, which the SDK sends as its user agent so Kessel operators can track
+// which client versions are in use.
+package version
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+const modulePath = "github.com/project-kessel/kessel-sdk-go"
+
+// product is the user agent product token.
+const product = "kessel-sdk-go"
+
+// version overrides the detected version when set at build time:
+//
+//	go build -ldflags "-X github.com/project-kessel/kessel-sdk-go/kessel/version.version=v1.2.3"
+var version string
+
+var detected = sync.OnceValue(func() string {
+	if version != "" {
+		return version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	return moduleVersion(info)
+})
+
+// Version returns the SDK version: the value set with -ldflags if any,
+// otherwise the module version recorded in the binary's build info (the tag
+// a consumer depends on), or "devel" for local builds of the SDK itself.
+func Version() string {
+	return detected()
+}
+
+// UserAgent returns the SDK's user agent product, "kessel-sdk-go/<version>".
+func UserAgent() string {
+	return product + "/" + Version()
+}
+
+func moduleVersion(info *debug.BuildInfo) string {
+	module := &info.Main
+	if info.Main.Path != modulePath {
+		module = nil
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				module = dep
+				break
+			}
+		}
+	}
+	if module == nil {
+		return "devel"
+	}
+	if module.Replace != nil && module.Replace.Version != "" {
+		module = module.Replace
+	}
+	if module.Version == "" || module.Version == "(devel)" {
+		return "devel"
+	}
+	return module.Version
+}
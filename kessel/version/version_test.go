@@ -0,0 +1,63 @@
+package version
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestModuleVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     *debug.BuildInfo
+		expected string
+	}{
+		{
+			name:     "dependency of the main module",
+			info:     &debug.BuildInfo{Main: debug.Module{Path: "example.com/app"}, Deps: []*debug.Module{{Path: modulePath, Version: "v0.9.0"}}},
+			expected: "v0.9.0",
+		},
+		{
+			name: "replaced dependency",
+			info: &debug.BuildInfo{Main: debug.Module{Path: "example.com/app"}, Deps: []*debug.Module{
+				{Path: modulePath, Version: "v0.9.0", Replace: &debug.Module{Path: "example.com/fork", Version: "v0.9.1"}},
+			}},
+			expected: "v0.9.1",
+		},
+		{
+			name:     "replaced by a local directory",
+			info:     &debug.BuildInfo{Main: debug.Module{Path: "example.com/app"}, Deps: []*debug.Module{{Path: modulePath, Version: "v0.9.0", Replace: &debug.Module{Path: "../kessel-sdk-go"}}}},
+			expected: "v0.9.0",
+		},
+		{
+			name:     "main module built from a tag",
+			info:     &debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "v1.0.0"}},
+			expected: "v1.0.0",
+		},
+		{
+			name:     "main module built locally",
+			info:     &debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "(devel)"}},
+			expected: "devel",
+		},
+		{
+			name:     "not linked",
+			info:     &debug.BuildInfo{Main: debug.Module{Path: "example.com/app"}},
+			expected: "devel",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := moduleVersion(tt.info); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	userAgent := UserAgent()
+	if !strings.HasPrefix(userAgent, "kessel-sdk-go/") || userAgent != "kessel-sdk-go/"+Version() {
+		t.Errorf("Unexpected user agent %q", userAgent)
+	}
+}